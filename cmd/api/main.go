@@ -2,22 +2,43 @@ package main
 
 import (
 	"HATCH_APP/config"
+	"HATCH_APP/db/migration"
 	"HATCH_APP/internal/note"
+	"HATCH_APP/internal/shared/auth"
+	"HATCH_APP/internal/webhook"
+	"HATCH_APP/pkg/blob"
+	"HATCH_APP/pkg/blob/local"
+	"HATCH_APP/pkg/blob/s3"
+	"HATCH_APP/pkg/cache"
+	"HATCH_APP/pkg/cache/memory"
+	cacheRedis "HATCH_APP/pkg/cache/redis"
 	"HATCH_APP/pkg/connection/postgres"
+	"HATCH_APP/pkg/connection/redis"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/lock"
+	pgLock "HATCH_APP/pkg/lock/postgres"
 	"HATCH_APP/pkg/o11y"
+	pgQueue "HATCH_APP/pkg/queue/postgres"
+	"HATCH_APP/pkg/scheduler"
+	pgStore "HATCH_APP/pkg/store/postgres"
+	"HATCH_APP/pkg/transport/grpcx"
 	"HATCH_APP/pkg/transport/httpx"
+	"HATCH_APP/pkg/transport/httpx/openapi"
+	"HATCH_APP/pkg/transport/httpx/sse"
 	"HATCH_APP/pkg/validator"
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
-
-	"github.com/jmoiron/sqlx"
 )
 
-const SHUTDOWN_TIMEOUT = 30 * time.Second
+const (
+	SHUTDOWN_TIMEOUT  = 30 * time.Second
+	MIGRATION_TIMEOUT = 5 * time.Minute
+)
 
 func main() {
 	if err := run(); err != nil {
@@ -48,28 +69,180 @@ func run() error {
 
 	log.Info("postgres: connecting...")
 
-	db, err := postgres.Connect(ctx, cfg.PostgresURL)
+	cluster, err := postgres.ConnectCluster(o11y.WithLogger(ctx, log), cfg.PostgresURL, postgres.ClusterConfig{
+		ReplicaURLs:    cfg.PostgresReplicaURLs,
+		HealthInterval: cfg.PostgresHealthInterval,
+		ReadYourWrites: cfg.PostgresReadYourWrites,
+	})
 	if err != nil {
 		log.Error("postgres: connection error", "error", err)
 		return err
 	}
 
-	log.Info("postgres: connected")
+	db := cluster.Primary()
+
+	log.Info("postgres: connected", "replicas", len(cfg.PostgresReplicaURLs))
+
+	locker := pgLock.NewLocker(db)
+
+	if cfg.MigrateOnStartup {
+		log.Info("migrations: running...")
+
+		if err := lock.With(ctx, locker, "migrations", MIGRATION_TIMEOUT, func(ctx context.Context) error {
+			return pgStore.Migrate(ctx, db, migration.Files)
+		}); err != nil {
+			log.Error("migrations: error running migrations", "error", err)
+			return err
+		}
+
+		log.Info("migrations: up to date")
+	}
+
+	appCache, closeCache, err := newCache(ctx, cfg)
+	if err != nil {
+		log.Error("cache: connection error", "error", err)
+		return err
+	}
+
+	blobs, err := newBlobStore(cfg)
+	if err != nil {
+		log.Error("blob: store error", "error", err)
+		return err
+	}
 
 	val := validator.New()
 
+	spec := openapi.New(openapi.Info{Title: "Hatch API", Version: "1.0.0"}, "/api", httpx.ErrorResponse{})
+
 	srv, r := httpx.NewServer(cfg.RestServerPort, val, httpx.External{
-		DB: db,
+		DB:      db,
+		OpenAPI: spec,
+	})
+
+	// Sessions scope read-your-writes to a request.
+	r.Use(httpx.WithContext(pgStore.WithSession))
+	r.Use(auth.Owner)
+
+	if cfg.OpenAPIValidateRequests {
+		r.Use(httpx.WithOpenAPIValidation(spec, httpx.OpenAPIValidation{
+			MaxBodySize: cfg.OpenAPIMaxBodySize,
+		}))
+	}
+
+	// Streams never finish on their own, they are closed as the server
+	// starts shutting down so Shutdown does not wait for them.
+	noteStream := sse.NewBroker(sse.Config{
+		ReplaySize: cfg.NoteStreamReplaySize,
+		Heartbeat:  cfg.NoteStreamHeartbeat,
+	})
+	srv.RegisterOnShutdown(noteStream.Close)
+
+	grpcSrv := grpcx.NewServer(cfg.GRPCServerPort, val, auth.OwnerFromMetadata)
+
+	sched := scheduler.New(scheduler.NewLockElector(locker, "api"))
+
+	worker := pgQueue.NewWorker(db, pgQueue.Config{
+		Concurrency:   cfg.QueueConcurrency,
+		Retention:     cfg.QueueRetention,
+		DeadRetention: cfg.QueueDeadRetention,
 	})
 
-	if err := note.Register(r, db); err != nil {
+	listener := pgStore.NewListener(cfg.PostgresURL, pgStore.ListenerConfig{})
+
+	clock := core.SystemClock()
+
+	if err := note.Register(r, note.External{
+		DB:        db,
+		Scheduler: sched,
+		Cache:     appCache,
+		Blob:      blobs,
+		GRPC:      grpcSrv,
+		OpenAPI:   spec,
+		Listener:  listener,
+		Stream:    noteStream,
+		Events:    webhook.Publisher{},
+		Reads:     cluster,
+		Clock:     clock,
+		IDs:       core.NewULIDGenerator(clock),
+	}, note.Config{
+		TrashRetention:         cfg.NoteTrashRetention,
+		PurgeSchedule:          cfg.NotePurgeSchedule,
+		PurgeTimeout:           cfg.NotePurgeTimeout,
+		PurgeBatchSize:         cfg.NotePurgeBatchSize,
+		BlobSweepBatchSize:     cfg.NoteBlobSweepBatchSize,
+		CacheTTL:               cfg.NoteCacheTTL,
+		RevisionRetention:      cfg.NoteRevisionRetention,
+		RenderCacheTTL:         cfg.NoteRenderCacheTTL,
+		AttachmentContentTypes: cfg.NoteAttachmentContentTypes,
+		AttachmentMaxSize:      cfg.NoteAttachmentMaxSize,
+		ImportMaxSize:          cfg.NoteImportMaxSize,
+		ImportMaxUnzippedSize:  cfg.NoteImportMaxUnzippedSize,
+		ImportMaxRows:          cfg.NoteImportMaxRows,
+	}); err != nil {
 		log.Error("note: module error", "error", err)
 		return err
 	}
 
+	if err := webhook.Register(r, webhook.External{
+		DB:      db,
+		Worker:  worker,
+		OpenAPI: spec,
+		Clock:   clock,
+		IDs:     core.NewULIDGenerator(clock),
+	}, webhook.Config{
+		EventTypes:        note.EventTypes,
+		MaxAttempts:       cfg.WebhookMaxAttempts,
+		Timeout:           cfg.WebhookTimeout,
+		DisableAfter:      cfg.WebhookDisableAfter,
+		DeliveryRetention: cfg.WebhookDeliveryRetention,
+	}); err != nil {
+		log.Error("webhook: module error", "error", err)
+		return err
+	}
+
+	if err := sched.Start(o11y.WithLogger(ctx, log)); err != nil {
+		log.Error("scheduler: start error", "error", err)
+		return err
+	}
+
+	log.Info("scheduler: running")
+
+	// The worker stops polling on Close, in-flight jobs get until the
+	// shutdown deadline instead of being canceled by the signal.
+	if err := worker.Start(o11y.WithLogger(context.WithoutCancel(ctx), log)); err != nil {
+		log.Error("queue: start error", "error", err)
+		return err
+	}
+
+	log.Info("queue: running")
+
+	if err := cluster.Start(o11y.WithLogger(ctx, log)); err != nil {
+		log.Error("postgres: replica health checks start error", "error", err)
+		return err
+	}
+
+	log.Info("listener: connecting...")
+
+	if err := listener.Start(o11y.WithLogger(ctx, log)); err != nil {
+		log.Error("listener: start error", "error", err)
+		return err
+	}
+
+	log.Info("listener: running")
+
 	shutdownErrCh := make(chan error, 1)
 
-	go shutdown(ctx, shutdownErrCh, srv, db)
+	go shutdown(ctx, shutdownErrCh, srv, grpcSrv, sched, worker, listener, cluster, closeCache)
+
+	go func() {
+		log.Info("grpc: running...", "port", cfg.GRPCServerPort)
+
+		if err := grpcSrv.Start(); err != nil {
+			log.Error("grpc: server start error", "error", err)
+
+			stop()
+		}
+	}()
 
 	log.Info("server: running...", "port", cfg.RestServerPort)
 
@@ -96,7 +269,12 @@ func shutdown(
 	ctx context.Context,
 	errCh chan error,
 	srv *httpx.Server,
-	db *sqlx.DB,
+	grpcSrv *grpcx.Server,
+	sched *scheduler.Scheduler,
+	worker *pgQueue.Worker,
+	listener *pgStore.Listener,
+	cluster *postgres.Cluster,
+	closeCache func() error,
 ) {
 	<-ctx.Done()
 
@@ -113,10 +291,75 @@ func shutdown(
 		return
 	}
 
-	if err := db.Close(); err != nil {
+	if err := grpcSrv.Close(ctxTimeout); err != nil {
+		errCh <- err
+		return
+	}
+
+	if err := sched.Close(ctxTimeout); err != nil {
+		errCh <- err
+		return
+	}
+
+	if err := worker.Close(ctxTimeout); err != nil {
+		errCh <- err
+		return
+	}
+
+	if err := listener.Close(ctxTimeout); err != nil {
+		errCh <- err
+		return
+	}
+
+	if err := cluster.Close(); err != nil {
+		errCh <- err
+		return
+	}
+
+	if err := closeCache(); err != nil {
 		errCh <- err
 		return
 	}
 
 	errCh <- nil
 }
+
+func newCache(ctx context.Context, cfg *config.Config) (cache.Cache, func() error, error) {
+	if cfg.RedisURL == "" {
+		o11y.Log.Info("cache: using in-memory lru")
+
+		return memory.NewLRU(cfg.CacheLRUSize), func() error { return nil }, nil
+	}
+
+	o11y.Log.Info("redis: connecting...")
+
+	client, err := redis.Connect(ctx, cfg.RedisURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	o11y.Log.Info("redis: connected")
+
+	return cacheRedis.New(client, "hatch:"), client.Close, nil
+}
+
+func newBlobStore(cfg *config.Config) (blob.Store, error) {
+	switch cfg.BlobDriver {
+	case "local":
+		o11y.Log.Info("blob: using local directory", "dir", cfg.BlobLocalDir)
+
+		return local.New(cfg.BlobLocalDir)
+	case "s3":
+		o11y.Log.Info("blob: using s3", "endpoint", cfg.S3Endpoint, "bucket", cfg.S3Bucket)
+
+		return s3.New(s3.Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		}, nil)
+	default:
+		return nil, fmt.Errorf("blob: unknown driver %q", cfg.BlobDriver)
+	}
+}
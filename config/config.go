@@ -1,13 +1,63 @@
 package config
 
 import (
+	"time"
+
 	"github.com/caarlos0/env"
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
 	RestServerPort string `env:"REST_SERVER_PORT,required"`
+	GRPCServerPort string `env:"GRPC_SERVER_PORT" envDefault:"50051"`
 	PostgresURL    string `env:"POSTGRES_URL,required"`
+	RedisURL       string `env:"REDIS_URL"`
+
+	PostgresReplicaURLs    []string      `env:"POSTGRES_REPLICA_URLS"     envSeparator:","`
+	PostgresHealthInterval time.Duration `env:"POSTGRES_HEALTH_INTERVAL"  envDefault:"5s"`
+	PostgresReadYourWrites bool          `env:"POSTGRES_READ_YOUR_WRITES" envDefault:"true"`
+
+	MigrateOnStartup        bool  `env:"MIGRATE_ON_STARTUP"         envDefault:"false"`
+	OpenAPIValidateRequests bool  `env:"OPENAPI_VALIDATE_REQUESTS" envDefault:"false"`
+	OpenAPIMaxBodySize      int64 `env:"OPENAPI_MAX_BODY_SIZE"     envDefault:"1048576"`
+
+	CacheLRUSize int `env:"CACHE_LRU_SIZE" envDefault:"10000"`
+
+	BlobDriver   string `env:"BLOB_DRIVER"    envDefault:"local"`
+	BlobLocalDir string `env:"BLOB_LOCAL_DIR" envDefault:"data/blobs"`
+	S3Endpoint   string `env:"S3_ENDPOINT"`
+	S3Region     string `env:"S3_REGION"      envDefault:"us-east-1"`
+	S3Bucket     string `env:"S3_BUCKET"`
+	S3AccessKey  string `env:"S3_ACCESS_KEY"`
+	S3SecretKey  string `env:"S3_SECRET_KEY"`
+
+	NoteTrashRetention     time.Duration `env:"NOTE_TRASH_RETENTION"       envDefault:"720h"`
+	NotePurgeSchedule      string        `env:"NOTE_PURGE_SCHEDULE"        envDefault:"@every 1h"`
+	NotePurgeTimeout       time.Duration `env:"NOTE_PURGE_TIMEOUT"         envDefault:"10m"`
+	NotePurgeBatchSize     int           `env:"NOTE_PURGE_BATCH_SIZE"      envDefault:"500"`
+	NoteBlobSweepBatchSize int           `env:"NOTE_BLOB_SWEEP_BATCH_SIZE" envDefault:"500"`
+	NoteCacheTTL           time.Duration `env:"NOTE_CACHE_TTL"             envDefault:"5m"`
+	NoteRevisionRetention  int           `env:"NOTE_REVISION_RETENTION"    envDefault:"50"`
+	NoteRenderCacheTTL     time.Duration `env:"NOTE_RENDER_CACHE_TTL"      envDefault:"24h"`
+
+	NoteAttachmentMaxSize      int64    `env:"NOTE_ATTACHMENT_MAX_SIZE"      envDefault:"10485760"`
+	NoteAttachmentContentTypes []string `env:"NOTE_ATTACHMENT_CONTENT_TYPES" envDefault:"image/*,application/pdf,text/plain" envSeparator:","`
+
+	NoteImportMaxSize         int64 `env:"NOTE_IMPORT_MAX_SIZE"          envDefault:"10485760"`
+	NoteImportMaxUnzippedSize int64 `env:"NOTE_IMPORT_MAX_UNZIPPED_SIZE" envDefault:"104857600"`
+	NoteImportMaxRows         int   `env:"NOTE_IMPORT_MAX_ROWS"          envDefault:"10000"`
+
+	NoteStreamReplaySize int           `env:"NOTE_STREAM_REPLAY_SIZE" envDefault:"1000"`
+	NoteStreamHeartbeat  time.Duration `env:"NOTE_STREAM_HEARTBEAT"   envDefault:"15s"`
+
+	QueueConcurrency   int           `env:"QUEUE_CONCURRENCY"    envDefault:"4"`
+	QueueRetention     time.Duration `env:"QUEUE_RETENTION"      envDefault:"24h"`
+	QueueDeadRetention time.Duration `env:"QUEUE_DEAD_RETENTION" envDefault:"168h"`
+
+	WebhookMaxAttempts       int           `env:"WEBHOOK_MAX_ATTEMPTS"       envDefault:"8"`
+	WebhookTimeout           time.Duration `env:"WEBHOOK_TIMEOUT"            envDefault:"10s"`
+	WebhookDisableAfter      int           `env:"WEBHOOK_DISABLE_AFTER"      envDefault:"25"`
+	WebhookDeliveryRetention int           `env:"WEBHOOK_DELIVERY_RETENTION" envDefault:"100"`
 }
 
 func Load() (*Config, error) {
@@ -0,0 +1,9 @@
+ALTER TABLE notes ADD COLUMN archived BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE notes SET archived = (status = 'archived');
+
+ALTER TABLE notes ALTER COLUMN archived DROP DEFAULT;
+
+ALTER TABLE notes DROP COLUMN status;
+
+DROP TYPE IF EXISTS note_status;
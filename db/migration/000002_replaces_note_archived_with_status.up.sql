@@ -0,0 +1,7 @@
+CREATE TYPE note_status AS ENUM ('active', 'archived', 'trashed', 'purged');
+
+ALTER TABLE notes ADD COLUMN status note_status NOT NULL DEFAULT 'active';
+
+UPDATE notes SET status = 'archived' WHERE archived;
+
+ALTER TABLE notes DROP COLUMN archived;
@@ -0,0 +1,5 @@
+ALTER TABLE notes ADD COLUMN deleted_at TIMESTAMP;
+
+UPDATE notes SET deleted_at = COALESCE(updated_at, created_at) WHERE status = 'trashed';
+
+CREATE INDEX IF NOT EXISTS notes_deleted_at_idx ON notes (deleted_at) WHERE deleted_at IS NOT NULL;
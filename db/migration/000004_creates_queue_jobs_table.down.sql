@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS queue_jobs;
+
+DROP TYPE IF EXISTS queue_job_state;
@@ -0,0 +1,31 @@
+CREATE TYPE queue_job_state AS ENUM ('pending', 'running', 'succeeded', 'dead');
+
+CREATE TABLE IF NOT EXISTS queue_jobs (
+    id VARCHAR PRIMARY KEY,
+    queue VARCHAR NOT NULL,
+    kind VARCHAR NOT NULL,
+    payload JSONB NOT NULL,
+    priority INT NOT NULL DEFAULT 0,
+    state queue_job_state NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL,
+    unique_key VARCHAR,
+    last_error TEXT,
+    run_at TIMESTAMP NOT NULL,
+    locked_at TIMESTAMP,
+    finished_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS queue_jobs_fetch_idx
+    ON queue_jobs (queue, priority DESC, run_at)
+    WHERE state = 'pending';
+
+CREATE INDEX IF NOT EXISTS queue_jobs_running_idx
+    ON queue_jobs (locked_at)
+    WHERE state = 'running';
+
+CREATE UNIQUE INDEX IF NOT EXISTS queue_jobs_unique_key_idx
+    ON queue_jobs (kind, unique_key)
+    WHERE unique_key IS NOT NULL AND state IN ('pending', 'running');
@@ -0,0 +1,6 @@
+ALTER TABLE notes
+    DROP CONSTRAINT IF EXISTS notes_content_no_control,
+    DROP CONSTRAINT IF EXISTS notes_content_length,
+    DROP CONSTRAINT IF EXISTS notes_title_no_control,
+    DROP CONSTRAINT IF EXISTS notes_title_length,
+    DROP CONSTRAINT IF EXISTS notes_title_not_blank;
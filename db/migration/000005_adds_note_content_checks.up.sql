@@ -0,0 +1,18 @@
+UPDATE notes SET title = regexp_replace(title, '[\x01-\x1F\x7F-\x9F]', ' ', 'g')
+WHERE title ~ '[\x01-\x1F\x7F-\x9F]';
+
+UPDATE notes SET content = regexp_replace(content, '[\x01-\x08\x0B\x0C\x0E-\x1F\x7F-\x9F]', '', 'g')
+WHERE content ~ '[\x01-\x08\x0B\x0C\x0E-\x1F\x7F-\x9F]';
+
+UPDATE notes SET title = 'Untitled' WHERE title !~ '\S';
+
+UPDATE notes SET title = left(btrim(title), 200) WHERE char_length(title) > 200;
+
+UPDATE notes SET content = left(content, 100000) WHERE char_length(content) > 100000;
+
+ALTER TABLE notes
+    ADD CONSTRAINT notes_title_not_blank CHECK (title ~ '\S'),
+    ADD CONSTRAINT notes_title_length CHECK (char_length(title) <= 200),
+    ADD CONSTRAINT notes_title_no_control CHECK (title !~ '[\x01-\x1F\x7F-\x9F]'),
+    ADD CONSTRAINT notes_content_length CHECK (char_length(content) <= 100000),
+    ADD CONSTRAINT notes_content_no_control CHECK (content !~ '[\x01-\x08\x0B\x0C\x0E-\x1F\x7F-\x9F]');
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS tags (
+    id VARCHAR PRIMARY KEY,
+    name VARCHAR NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    CONSTRAINT tags_name_key UNIQUE (name),
+    CONSTRAINT tags_name_length CHECK (char_length(name) BETWEEN 1 AND 50),
+    CONSTRAINT tags_name_normalized CHECK (name = lower(name) AND name !~ '\s')
+);
+
+CREATE TABLE IF NOT EXISTS note_tags (
+    note_id VARCHAR NOT NULL REFERENCES notes (id) ON DELETE CASCADE,
+    tag_id VARCHAR NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (note_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS note_tags_tag_id_idx ON note_tags (tag_id);
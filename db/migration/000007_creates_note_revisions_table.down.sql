@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS note_revisions;
+
+ALTER TABLE notes DROP COLUMN IF EXISTS version;
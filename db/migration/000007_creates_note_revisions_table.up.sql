@@ -0,0 +1,14 @@
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS note_revisions (
+    note_id VARCHAR NOT NULL REFERENCES notes (id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    title VARCHAR NOT NULL,
+    content VARCHAR NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (note_id, version)
+);
+
+INSERT INTO note_revisions (note_id, version, title, content, created_at)
+SELECT id, version, title, content, COALESCE(updated_at, created_at) FROM notes
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+ALTER TABLE notes DROP CONSTRAINT IF EXISTS notes_format_valid;
+
+ALTER TABLE notes DROP COLUMN IF EXISTS format;
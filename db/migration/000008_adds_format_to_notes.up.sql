@@ -0,0 +1,3 @@
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS format VARCHAR NOT NULL DEFAULT 'plain';
+
+ALTER TABLE notes ADD CONSTRAINT notes_format_valid CHECK (format IN ('plain', 'markdown'));
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS note_attachments_deleted ON note_attachments;
+DROP FUNCTION IF EXISTS record_attachment_deletion();
+DROP TABLE IF EXISTS attachment_deletions;
+DROP TABLE IF EXISTS note_attachments;
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS note_attachments (
+    id VARCHAR PRIMARY KEY,
+    note_id VARCHAR NOT NULL REFERENCES notes (id) ON DELETE CASCADE,
+    filename VARCHAR NOT NULL,
+    content_type VARCHAR NOT NULL,
+    size BIGINT NOT NULL CHECK (size >= 0),
+    checksum VARCHAR NOT NULL,
+    storage_key VARCHAR NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS note_attachments_note_id_idx ON note_attachments (note_id, created_at);
+
+-- Blobs live outside the database, deleted rows leave their storage key
+-- behind so the blobs can be removed once the transaction committed, even
+-- when the rows went away with a purged note.
+CREATE TABLE IF NOT EXISTS attachment_deletions (
+    storage_key VARCHAR PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE OR REPLACE FUNCTION record_attachment_deletion() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO attachment_deletions (storage_key) VALUES (OLD.storage_key)
+    ON CONFLICT DO NOTHING;
+
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS note_attachments_deleted ON note_attachments;
+
+CREATE TRIGGER note_attachments_deleted
+AFTER DELETE ON note_attachments
+FOR EACH ROW EXECUTE FUNCTION record_attachment_deletion();
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id VARCHAR PRIMARY KEY,
+    url VARCHAR NOT NULL,
+    description VARCHAR NOT NULL DEFAULT '',
+    secret VARCHAR NOT NULL,
+    event_types VARCHAR[] NOT NULL,
+    status VARCHAR NOT NULL DEFAULT 'enabled',
+    failure_count INTEGER NOT NULL DEFAULT 0,
+    disabled_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP,
+    CONSTRAINT webhook_endpoints_status_valid CHECK (status IN ('enabled', 'disabled'))
+);
+
+CREATE INDEX IF NOT EXISTS webhook_endpoints_event_types_idx
+    ON webhook_endpoints USING GIN (event_types)
+    WHERE status = 'enabled';
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id VARCHAR PRIMARY KEY,
+    endpoint_id VARCHAR NOT NULL REFERENCES webhook_endpoints (id) ON DELETE CASCADE,
+    event_id VARCHAR NOT NULL,
+    event_type VARCHAR NOT NULL,
+    payload JSONB NOT NULL,
+    attempt INTEGER NOT NULL,
+    status_code INTEGER,
+    response_body VARCHAR NOT NULL DEFAULT '',
+    error VARCHAR NOT NULL DEFAULT '',
+    succeeded BOOLEAN NOT NULL,
+    duration_ms BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_endpoint_id_idx
+    ON webhook_deliveries (endpoint_id, created_at DESC);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS queue_jobs_finished_idx;
@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS queue_jobs_finished_idx
+    ON queue_jobs (queue, finished_at)
+    WHERE state IN ('succeeded', 'dead');
@@ -0,0 +1,21 @@
+ALTER TABLE tags DROP CONSTRAINT IF EXISTS tags_owner_id_name_key;
+
+-- Tags of several owners sharing a name are merged into the oldest one.
+WITH kept AS (
+    SELECT DISTINCT ON (name) id, name FROM tags ORDER BY name, created_at, id
+)
+INSERT INTO note_tags (note_id, tag_id, created_at)
+SELECT note_tags.note_id, kept.id, note_tags.created_at FROM note_tags
+JOIN tags ON tags.id = note_tags.tag_id
+JOIN kept ON kept.name = tags.name AND kept.id <> tags.id
+ON CONFLICT DO NOTHING;
+
+DELETE FROM tags WHERE id NOT IN (
+    SELECT DISTINCT ON (name) id FROM tags ORDER BY name, created_at, id
+);
+
+ALTER TABLE tags ADD CONSTRAINT tags_name_key UNIQUE (name);
+
+ALTER TABLE tags DROP COLUMN IF EXISTS owner_id;
+
+ALTER TABLE notes DROP COLUMN IF EXISTS owner_id;
@@ -0,0 +1,7 @@
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS owner_id VARCHAR NOT NULL DEFAULT '';
+
+ALTER TABLE tags ADD COLUMN IF NOT EXISTS owner_id VARCHAR NOT NULL DEFAULT '';
+
+ALTER TABLE tags DROP CONSTRAINT IF EXISTS tags_name_key;
+
+ALTER TABLE tags ADD CONSTRAINT tags_owner_id_name_key UNIQUE (owner_id, name);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS webhook_endpoints_owner_id_idx;
+
+ALTER TABLE webhook_endpoints DROP COLUMN IF EXISTS owner_id;
@@ -0,0 +1,4 @@
+ALTER TABLE webhook_endpoints ADD COLUMN IF NOT EXISTS owner_id VARCHAR NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS webhook_endpoints_owner_id_idx
+    ON webhook_endpoints (owner_id, created_at, id);
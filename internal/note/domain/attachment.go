@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"HATCH_APP/pkg/core"
+)
+
+// MaxFilenameLength is counted in bytes, the limit of most filesystems.
+const MaxFilenameLength = 255
+
+// Attachment is the metadata of a file attached to a note, its content is
+// kept in blob storage under StorageKey.
+type Attachment struct {
+	CreatedAt   time.Time `json:"created_at"   db:"created_at"`
+	ID          core.ID   `json:"id"           db:"id"`
+	NoteID      core.ID   `json:"note_id"      db:"note_id"`
+	Filename    string    `json:"filename"     db:"filename"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	// Checksum is the hex encoded SHA-256 of the content.
+	Checksum   string `json:"checksum"     db:"checksum"`
+	StorageKey string `json:"-"            db:"storage_key"`
+	Size       int64  `json:"size"         db:"size"`
+}
+
+type AttachmentRepository interface {
+	CreateAttachment(ctx context.Context, attachment *Attachment) error
+	// ListAttachments returns the attachments of a note, oldest first.
+	ListAttachments(ctx context.Context, noteID core.ID) ([]*Attachment, error)
+	FindAttachment(ctx context.Context, noteID, id core.ID) (*Attachment, error)
+	DeleteAttachment(ctx context.Context, id core.ID) error
+	// ListDeletedBlobs returns up to limit storage keys of deleted
+	// attachments, including those purged along with their note, whose blobs
+	// have not been forgotten yet.
+	ListDeletedBlobs(ctx context.Context, limit int) ([]string, error)
+	ForgetDeletedBlobs(ctx context.Context, keys []string) error
+}
+
+// NewAttachment keeps the base name of filename, as some clients send the
+// full path of the uploaded file.
+func NewAttachment(
+	id, noteID core.ID,
+	now time.Time,
+	filename, contentType, checksum string,
+	size int64,
+) (*Attachment, error) {
+	filename = strings.TrimSpace(filename[strings.LastIndexAny(filename, `/\`)+1:])
+
+	if err := ValidateFilename(filename); err != nil {
+		return nil, err
+	}
+
+	return &Attachment{
+		ID:          id,
+		NoteID:      noteID,
+		Filename:    filename,
+		ContentType: contentType,
+		Checksum:    checksum,
+		StorageKey:  "notes/" + noteID.String() + "/attachments/" + id.String(),
+		Size:        size,
+		CreatedAt:   now,
+	}, nil
+}
+
+// ValidateFilename expects an already trimmed base name.
+func ValidateFilename(filename string) error {
+	if filename == "" || filename == "." || filename == ".." || len(filename) > MaxFilenameLength {
+		return ErrNoteAttachmentFilenameInvalid.New().WithDetails(map[string]int{"max": MaxFilenameLength})
+	}
+
+	return validateText("filename", filename, false)
+}
@@ -0,0 +1,44 @@
+package domain_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/core"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAttachment(t *testing.T) {
+	id := core.MustParseID("01JWN3V0G0000000000000000B")
+	noteID := core.MustParseID("01JWN3V0G0000000000000000A")
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should keep the base name and derive the storage key", func(t *testing.T) {
+		for _, filename := range []string{"report.pdf", " report.pdf ", "docs/report.pdf", `C:\Users\me\report.pdf`} {
+			a, err := domain.NewAttachment(id, noteID, now, filename, "application/pdf", "abc", 42)
+
+			require.NoError(t, err, filename)
+			assert.Equal(t, "report.pdf", a.Filename, filename)
+			assert.Equal(t, "notes/"+noteID.String()+"/attachments/"+id.String(), a.StorageKey)
+			assert.Equal(t, int64(42), a.Size)
+		}
+	})
+
+	t.Run("should reject invalid filenames", func(t *testing.T) {
+		for _, filename := range []string{"", "  ", "dir/", ".", "..", strings.Repeat("a", 256)} {
+			_, err := domain.NewAttachment(id, noteID, now, filename, "text/plain", "abc", 1)
+
+			require.Error(t, err, filename)
+			assert.True(t, domain.ErrNoteAttachmentFilenameInvalid.Is(err), filename)
+		}
+
+		_, err := domain.NewAttachment(id, noteID, now, "bell\u0007.txt", "text/plain", "abc", 1)
+		assert.True(t, domain.ErrNoteControlCharacter.Is(err))
+
+		_, err = domain.NewAttachment(id, noteID, now, "bad\xff.txt", "text/plain", "abc", 1)
+		assert.True(t, domain.ErrNoteInvalidEncoding.Is(err))
+	})
+}
@@ -0,0 +1,143 @@
+package domain
+
+import "HATCH_APP/pkg/core/apperr"
+
+var Codes = apperr.NewRegistry("NOTE")
+
+var (
+	ErrNoteNotFound = Codes.Register("NOTE_NOT_FOUND", apperr.TypeNotFound,
+		"note not found",
+		"The requested note does not exist.")
+	ErrNoteFindFailed = Codes.Register("NOTE_FIND_FAILED", apperr.TypeInternal,
+		"failed to find note",
+		"The note could not be loaded from the datasource.")
+	ErrNoteCreateFailed = Codes.Register("NOTE_CREATE_FAILED", apperr.TypeInternal,
+		"failed to create note",
+		"The note could not be persisted.")
+	ErrNoteSaveFailed = Codes.Register("NOTE_SAVE_FAILED", apperr.TypeInternal,
+		"failed to save note",
+		"Changes to the note could not be persisted.")
+	ErrNoteAlreadyArchived = Codes.Register("NOTE_ALREADY_ARCHIVED", apperr.TypeInvalidOperation,
+		"note is already archived",
+		"The note cannot be archived again.")
+	ErrNoteInvalidTransition = Codes.Register("NOTE_INVALID_TRANSITION", apperr.TypeInvalidOperation,
+		"note status transition not allowed",
+		"The note lifecycle does not allow moving from its current status to the requested one.")
+	ErrNoteNotInTrash = Codes.Register("NOTE_NOT_IN_TRASH", apperr.TypeNotFound,
+		"note not found in trash",
+		"The note does not exist or has not been moved to the trash.")
+	ErrNotePurgeFailed = Codes.Register("NOTE_PURGE_FAILED", apperr.TypeInternal,
+		"failed to purge trashed notes",
+		"Trashed notes past their retention period could not be removed.")
+	ErrNoteListFailed = Codes.Register("NOTE_LIST_FAILED", apperr.TypeInternal,
+		"failed to list notes",
+		"Notes could not be listed from the datasource.")
+	ErrNoteTitleBlank = Codes.Register("NOTE_TITLE_BLANK", apperr.TypeValidation,
+		"note title must not be blank",
+		"The title is empty or only whitespace once trimmed.")
+	ErrNoteTitleTooLong = Codes.Register("NOTE_TITLE_TOO_LONG", apperr.TypeValidation,
+		"note title is too long",
+		"The title exceeds the maximum number of characters, given in details.max.")
+	ErrNoteContentTooLong = Codes.Register("NOTE_CONTENT_TOO_LONG", apperr.TypeValidation,
+		"note content is too long",
+		"The content exceeds the maximum number of characters, given in details.max.")
+	ErrNoteInvalidEncoding = Codes.Register("NOTE_INVALID_ENCODING", apperr.TypeValidation,
+		"note text is not valid UTF-8",
+		"The field named in details.field holds bytes that are not valid UTF-8.")
+	ErrNoteControlCharacter = Codes.Register("NOTE_CONTROL_CHARACTER", apperr.TypeValidation,
+		"note text contains control characters",
+		"The field named in details.field holds a control character at byte details.offset. Content may contain tabs and line breaks, titles may not.")
+	ErrNoteTagInvalid = Codes.Register("NOTE_TAG_INVALID", apperr.TypeValidation,
+		"invalid tag",
+		"Tags are 1 to 50 letters, digits, dashes or underscores once lowercased and with spaces turned into dashes. details.reason says what is wrong.")
+	ErrNoteTooManyTags = Codes.Register("NOTE_TOO_MANY_TAGS", apperr.TypeValidation,
+		"note has too many tags",
+		"A note cannot have more tags than details.max.")
+	ErrNoteFilterInvalid = Codes.Register("NOTE_FILTER_INVALID", apperr.TypeValidation,
+		"invalid note filter",
+		"The tag match must be one of details.allowed.")
+	ErrNoteTagsSaveFailed = Codes.Register("NOTE_TAGS_SAVE_FAILED", apperr.TypeInternal,
+		"failed to save note tags",
+		"The tags of the note could not be persisted.")
+	ErrNoteTagListFailed = Codes.Register("NOTE_TAG_LIST_FAILED", apperr.TypeInternal,
+		"failed to list tags",
+		"Tags could not be listed from the datasource.")
+	ErrNoteRevisionNotFound = Codes.Register("NOTE_REVISION_NOT_FOUND", apperr.TypeNotFound,
+		"note revision not found",
+		"The note has no revision with the requested version, it may have been pruned by retention.")
+	ErrNoteRevisionSaveFailed = Codes.Register("NOTE_REVISION_SAVE_FAILED", apperr.TypeInternal,
+		"failed to save note revision",
+		"The revision of the note could not be persisted, so the change was rolled back.")
+	ErrNoteRevisionFindFailed = Codes.Register("NOTE_REVISION_FIND_FAILED", apperr.TypeInternal,
+		"failed to find note revisions",
+		"Revisions of the note could not be loaded from the datasource.")
+	ErrNoteRevisionVersionInvalid = Codes.Register("NOTE_REVISION_VERSION_INVALID", apperr.TypeValidation,
+		"invalid note revision version",
+		"Revision versions are positive integers, starting at 1 when the note is created.")
+	ErrNoteFormatInvalid = Codes.Register("NOTE_FORMAT_INVALID", apperr.TypeValidation,
+		"invalid note format",
+		"The format of a note is either plain or markdown.")
+	ErrNoteRenderInvalid = Codes.Register("NOTE_RENDER_INVALID", apperr.TypeValidation,
+		"invalid note render option",
+		"Notes can only be rendered as html.")
+	ErrNoteRenderFailed = Codes.Register("NOTE_RENDER_FAILED", apperr.TypeInternal,
+		"failed to render note",
+		"The content of the note could not be rendered.")
+	ErrNoteAttachmentNotFound = Codes.Register("NOTE_ATTACHMENT_NOT_FOUND", apperr.TypeNotFound,
+		"note attachment not found",
+		"The note has no attachment with the requested id.")
+	ErrNoteAttachmentInvalid = Codes.Register("NOTE_ATTACHMENT_INVALID", apperr.TypeValidation,
+		"invalid attachment upload",
+		"Attachments are uploaded as multipart/form-data with the content in the file field. details.reason says what is wrong.")
+	ErrNoteAttachmentFilenameInvalid = Codes.Register("NOTE_ATTACHMENT_FILENAME_INVALID", apperr.TypeValidation,
+		"invalid attachment filename",
+		"Filenames are 1 to details.max bytes of text without control characters, and cannot be . or ..")
+	ErrNoteAttachmentTooLarge = Codes.Register("NOTE_ATTACHMENT_TOO_LARGE", apperr.TypePayloadTooLarge,
+		"note attachment is too large",
+		"The attachment exceeds the maximum size in bytes, given in details.max.")
+	ErrNoteAttachmentTypeNotAllowed = Codes.Register("NOTE_ATTACHMENT_TYPE_NOT_ALLOWED", apperr.TypeValidation,
+		"attachment content type not allowed",
+		"The content type sniffed from the first bytes of the attachment, given in details.content_type, is not one of details.allowed.")
+	ErrNoteAttachmentChecksumMismatch = Codes.Register("NOTE_ATTACHMENT_CHECKSUM_MISMATCH", apperr.TypeValidation,
+		"attachment checksum mismatch",
+		"The hex encoded SHA-256 sent in X-Checksum-Sha256 does not match the uploaded content, whose checksum is given in details.actual.")
+	ErrNoteAttachmentSaveFailed = Codes.Register("NOTE_ATTACHMENT_SAVE_FAILED", apperr.TypeInternal,
+		"failed to save note attachment",
+		"The attachment could not be stored, nothing was kept.")
+	ErrNoteAttachmentFindFailed = Codes.Register("NOTE_ATTACHMENT_FIND_FAILED", apperr.TypeInternal,
+		"failed to find note attachments",
+		"Attachments of the note could not be loaded from the datasource.")
+	ErrNoteAttachmentReadFailed = Codes.Register("NOTE_ATTACHMENT_READ_FAILED", apperr.TypeInternal,
+		"failed to read note attachment",
+		"The content of the attachment could not be read from blob storage.")
+	ErrNoteAttachmentDeleteFailed = Codes.Register("NOTE_ATTACHMENT_DELETE_FAILED", apperr.TypeInternal,
+		"failed to delete note attachment",
+		"The attachment could not be removed from the datasource.")
+	ErrNoteAlreadyExists = Codes.Register("NOTE_ALREADY_EXISTS", apperr.TypeConflict,
+		"note already exists",
+		"A note with the id given in details.id already exists, in the trash or not.")
+	ErrNoteTransferFormatInvalid = Codes.Register("NOTE_TRANSFER_FORMAT_INVALID", apperr.TypeValidation,
+		"invalid export format",
+		"Notes are exported and imported in one of details.allowed.")
+	ErrNoteExportFailed = Codes.Register("NOTE_EXPORT_FAILED", apperr.TypeInternal,
+		"failed to export notes",
+		"Notes could not be read from the datasource or written to the export.")
+	ErrNoteImportInvalid = Codes.Register("NOTE_IMPORT_INVALID", apperr.TypeValidation,
+		"invalid import file",
+		"The file could not be read in the requested format, details.reason says why. Rows that cannot be read are reported in the result instead.")
+	ErrNoteImportRowInvalid = Codes.Register("NOTE_IMPORT_ROW_INVALID", apperr.TypeValidation,
+		"invalid import row",
+		"The row could not be read as a note, details.reason says why.")
+	ErrNoteImportTooLarge = Codes.Register("NOTE_IMPORT_TOO_LARGE", apperr.TypePayloadTooLarge,
+		"import file is too large",
+		"The file exceeds the maximum size in bytes, given in details.max.")
+	ErrNoteImportUnzippedTooLarge = Codes.Register("NOTE_IMPORT_UNZIPPED_TOO_LARGE", apperr.TypePayloadTooLarge,
+		"import archive is too large once decompressed",
+		"The files of the archive exceed the maximum size in bytes once decompressed, given in details.max.")
+	ErrNoteImportTooManyRows = Codes.Register("NOTE_IMPORT_TOO_MANY_ROWS", apperr.TypePayloadTooLarge,
+		"import file has too many rows",
+		"The file exceeds the maximum number of rows, given in details.max.")
+	ErrNoteEventPublishFailed = Codes.Register("NOTE_EVENT_PUBLISH_FAILED", apperr.TypeInternal,
+		"failed to publish note event",
+		"The event describing the change could not be published, the change was not applied.")
+)
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"HATCH_APP/pkg/core"
+)
+
+const (
+	EventNoteCreated  = "note.created"
+	EventNoteUpdated  = "note.updated"
+	EventNoteArchived = "note.archived"
+	EventNoteTrashed  = "note.trashed"
+	EventNoteRestored = "note.restored"
+	EventNoteTagged   = "note.tagged"
+	EventNoteUntagged = "note.untagged"
+	// EventNotePurged carries the note as it was deleted, without its tags.
+	EventNotePurged = "note.purged"
+)
+
+// EventTypes lists the events published by the module.
+var EventTypes = []string{
+	EventNoteArchived,
+	EventNoteCreated,
+	EventNotePurged,
+	EventNoteRestored,
+	EventNoteTagged,
+	EventNoteTrashed,
+	EventNoteUntagged,
+	EventNoteUpdated,
+}
+
+// Event describes a change of a note, carrying the note as it is after the
+// change.
+type Event struct {
+	CreatedAt time.Time
+	Note      *Note
+	ID        core.ID
+	Type      string
+}
+
+// EventNotice announces a committed event to every replica. Notifications
+// are bounded in size, so it only carries ids and receivers load the note.
+type EventNotice struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        core.ID   `json:"id"`
+	NoteID    core.ID   `json:"note_id"`
+	OwnerID   string    `json:"owner_id"`
+	Type      string    `json:"type"`
+}
+
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+func NewEvent(id core.ID, eventType string, note *Note, now time.Time) Event {
+	return Event{
+		ID:        id,
+		Type:      eventType,
+		Note:      note,
+		CreatedAt: now,
+	}
+}
+
+func (e Event) Notice() EventNotice {
+	return EventNotice{
+		ID:        e.ID,
+		Type:      e.Type,
+		NoteID:    e.Note.ID,
+		OwnerID:   e.Note.OwnerID,
+		CreatedAt: e.CreatedAt,
+	}
+}
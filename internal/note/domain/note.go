@@ -1,32 +1,150 @@
 package domain
 
 import (
+	"encoding/json"
+	"strings"
 	"time"
 
 	"HATCH_APP/pkg/core"
 )
 
 type Note struct {
-	UpdatedAt *time.Time `json:"updated_at" db:"updated_at"`
-	CreatedAt time.Time  `json:"created_at" db:"created_at"`
-	ID        string     `json:"id"         db:"id"`
-	Title     string     `json:"title"      db:"title"`
-	Content   string     `json:"content"    db:"content"`
-	Archived  bool       `json:"archived"   db:"archived"`
+	UpdatedAt *time.Time `json:"updated_at"           db:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	CreatedAt time.Time  `json:"created_at"           db:"created_at"`
+	ID        core.ID    `json:"id"                   db:"id"`
+	Title     string     `json:"title"                db:"title"`
+	Content   string     `json:"content"              db:"content"`
+	Status    NoteStatus `json:"status"               db:"status"`
+	Format    NoteFormat `json:"format"               db:"format"`
+	Version   int        `json:"version"              db:"version"`
+	// OwnerID is whom the note belongs to, its tags and events are scoped to it.
+	OwnerID string `json:"owner_id" db:"owner_id"`
+	// Tags are sorted normalized names, loaded by the repository.
+	Tags []string `json:"tags" db:"-"`
 }
 
-func NewNote(title, content string) *Note {
+// plainNote has the fields of Note without its MarshalJSON.
+type plainNote Note
+
+// noteJSON is how notes are written. Archived is derived from the status, the
+// API had it before notes had one.
+type noteJSON struct {
+	plainNote
+	Archived bool `json:"archived"`
+}
+
+func (n Note) MarshalJSON() ([]byte, error) {
+	return json.Marshal(noteJSON{plainNote: plainNote(n), Archived: n.IsArchived()})
+}
+
+// JSONShape documents the archived field in the OpenAPI spec.
+func (Note) JSONShape() any {
+	return noteJSON{}
+}
+
+// NewNote trims the title and rejects notes breaking the content invariants,
+// the notes table enforces the same rules with CHECK constraints.
+func NewNote(id core.ID, now time.Time, title, content string) (*Note, error) {
+	title = strings.TrimSpace(title)
+
+	if err := ValidateTitle(title); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateContent(content); err != nil {
+		return nil, err
+	}
+
 	return &Note{
-		ID:        core.NewID(),
+		ID:        id,
 		Title:     title,
 		Content:   content,
-		Archived:  false,
-		CreatedAt: time.Now(),
+		Status:    NoteStatusActive,
+		Format:    NoteFormatPlain,
+		Version:   1,
+		Tags:      []string{},
+		CreatedAt: now,
 		UpdatedAt: nil,
+		DeletedAt: nil,
+	}, nil
+}
+
+// Edit replaces the title and content under the same invariants as NewNote
+// and reports whether they changed, bumping the version when they did.
+func (n *Note) Edit(title, content string, now time.Time) (bool, error) {
+	title = strings.TrimSpace(title)
+
+	if err := ValidateTitle(title); err != nil {
+		return false, err
+	}
+
+	if err := ValidateContent(content); err != nil {
+		return false, err
+	}
+
+	if title == n.Title && content == n.Content {
+		return false, nil
+	}
+
+	n.Title = title
+	n.Content = content
+	n.Version++
+	n.UpdatedAt = new(now)
+
+	return true, nil
+}
+
+func (n *Note) IsArchived() bool {
+	return n.Status == NoteStatusArchived
+}
+
+func (n *Note) Archive(now time.Time) error {
+	if n.Status == NoteStatusArchived {
+		return ErrNoteAlreadyArchived.New()
+	}
+
+	return n.TransitionTo(NoteStatusArchived, now)
+}
+
+func (n *Note) IsTrashed() bool {
+	return n.Status == NoteStatusTrashed
+}
+
+func (n *Note) Trash(now time.Time) error {
+	if err := n.TransitionTo(NoteStatusTrashed, now); err != nil {
+		return err
+	}
+
+	n.DeletedAt = n.UpdatedAt
+
+	return nil
+}
+
+func (n *Note) Restore(now time.Time) error {
+	if !n.IsTrashed() {
+		return ErrNoteNotInTrash.New()
+	}
+
+	if err := n.TransitionTo(NoteStatusActive, now); err != nil {
+		return err
 	}
+
+	n.DeletedAt = nil
+
+	return nil
 }
 
-func (n *Note) Archive() {
-	n.Archived = true
-	n.UpdatedAt = new(time.Now())
+func (n *Note) TransitionTo(to NoteStatus, now time.Time) error {
+	if !n.Status.CanTransitionTo(to) {
+		return ErrNoteInvalidTransition.New().WithDetails(map[string]NoteStatus{
+			"from": n.Status,
+			"to":   to,
+		})
+	}
+
+	n.Status = to
+	n.UpdatedAt = new(now)
+
+	return nil
 }
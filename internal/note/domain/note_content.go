@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Lengths are counted in characters, like char_length in Postgres.
+const (
+	MaxTitleLength   = 200
+	MaxContentLength = 100_000
+)
+
+// ValidateTitle expects an already trimmed title.
+func ValidateTitle(title string) error {
+	if title == "" {
+		return ErrNoteTitleBlank.New()
+	}
+
+	if err := validateText("title", title, false); err != nil {
+		return err
+	}
+
+	if utf8.RuneCountInString(title) > MaxTitleLength {
+		return ErrNoteTitleTooLong.New().WithDetails(map[string]int{"max": MaxTitleLength})
+	}
+
+	return nil
+}
+
+func ValidateContent(content string) error {
+	if err := validateText("content", content, true); err != nil {
+		return err
+	}
+
+	if utf8.RuneCountInString(content) > MaxContentLength {
+		return ErrNoteContentTooLong.New().WithDetails(map[string]int{"max": MaxContentLength})
+	}
+
+	return nil
+}
+
+// validateText rejects invalid UTF-8 and control characters, multiline text
+// may still hold tabs and line breaks.
+func validateText(field, s string, multiline bool) error {
+	if !utf8.ValidString(s) {
+		return ErrNoteInvalidEncoding.New().WithDetails(map[string]string{"field": field})
+	}
+
+	for i, r := range s {
+		if multiline && (r == '\n' || r == '\r' || r == '\t') {
+			continue
+		}
+
+		if unicode.IsControl(r) {
+			return ErrNoteControlCharacter.New().WithDetails(map[string]any{
+				"field":  field,
+				"offset": i,
+			})
+		}
+	}
+
+	return nil
+}
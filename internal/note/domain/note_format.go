@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// NoteFormat tells how the content of a note is meant to be rendered.
+type NoteFormat string
+
+const (
+	NoteFormatPlain    NoteFormat = "plain"
+	NoteFormatMarkdown NoteFormat = "markdown"
+)
+
+// Enum lists the formats for the API schema.
+func (NoteFormat) Enum() []any {
+	return []any{NoteFormatPlain, NoteFormatMarkdown}
+}
+
+// ParseNoteFormat defaults an empty format to plain.
+func ParseNoteFormat(raw string) (NoteFormat, error) {
+	switch format := NoteFormat(raw); format {
+	case "":
+		return NoteFormatPlain, nil
+	case NoteFormatPlain, NoteFormatMarkdown:
+		return format, nil
+	default:
+		return "", ErrNoteFormatInvalid.New().WithDetails(map[string]any{
+			"format":  raw,
+			"allowed": NoteFormat("").Enum(),
+		})
+	}
+}
+
+// ChangeFormat reports whether the format changed. The format is not part of
+// revisions, so it leaves the version alone.
+func (n *Note) ChangeFormat(format NoteFormat, now time.Time) bool {
+	if n.Format == format {
+		return false
+	}
+
+	n.Format = format
+	n.UpdatedAt = new(now)
+
+	return true
+}
@@ -2,11 +2,35 @@ package domain
 
 import (
 	"context"
+	"iter"
+	"time"
+
+	"HATCH_APP/pkg/core"
 )
 
 type NoteRepository interface {
-	FindByID(ctx context.Context, id string) (*Note, error)
+	FindByID(ctx context.Context, id core.ID) (*Note, error)
 	Create(ctx context.Context, note *Note) error
-	List(ctx context.Context) ([]*Note, error)
+	List(ctx context.Context, filter NoteFilter) ([]*Note, error)
+	// Iterate streams the notes List would return, oldest first, a batch at
+	// a time so memory stays constant. Breaking out of the loop or cancelling
+	// ctx releases the rows, an error ends the sequence.
+	Iterate(ctx context.Context, filter NoteFilter) iter.Seq2[*Note, error]
 	Save(ctx context.Context, note *Note) error
+	FindTrashedByID(ctx context.Context, id core.ID) (*Note, error)
+	// FindIDOwners returns the owner of each of ids taken by a note, trashed
+	// or not. It always reads from the primary, an id taken a moment ago must
+	// not be reused.
+	FindIDOwners(ctx context.Context, ids []core.ID) (map[core.ID]string, error)
+	ListTrashed(ctx context.Context) ([]*Note, error)
+	// PurgeTrashed deletes up to limit notes trashed before before and
+	// returns them as they were, without their tags.
+	PurgeTrashed(ctx context.Context, before time.Time, limit int) ([]*Note, error)
+	// AddTags creates the tags the owner of the note does not have yet and
+	// links them to the note.
+	AddTags(ctx context.Context, noteID core.ID, tags []Tag) error
+	RemoveTags(ctx context.Context, noteID core.ID, names []string) error
+	// ListTags counts the notes out of the trash using each tag of owner,
+	// unused tags are left out.
+	ListTags(ctx context.Context, owner string) ([]TagCount, error)
 }
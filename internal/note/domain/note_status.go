@@ -0,0 +1,33 @@
+package domain
+
+import "slices"
+
+type NoteStatus string
+
+const (
+	NoteStatusActive   NoteStatus = "active"
+	NoteStatusArchived NoteStatus = "archived"
+	NoteStatusTrashed  NoteStatus = "trashed"
+	NoteStatusPurged   NoteStatus = "purged"
+)
+
+var noteStatusTransitions = map[NoteStatus][]NoteStatus{
+	NoteStatusActive:   {NoteStatusArchived, NoteStatusTrashed},
+	NoteStatusArchived: {NoteStatusActive, NoteStatusTrashed},
+	NoteStatusTrashed:  {NoteStatusActive, NoteStatusPurged},
+	NoteStatusPurged:   {},
+}
+
+func (s NoteStatus) IsValid() bool {
+	_, ok := noteStatusTransitions[s]
+	return ok
+}
+
+// Enum lists the statuses for the API schema.
+func (NoteStatus) Enum() []any {
+	return []any{NoteStatusActive, NoteStatusArchived, NoteStatusTrashed, NoteStatusPurged}
+}
+
+func (s NoteStatus) CanTransitionTo(to NoteStatus) bool {
+	return slices.Contains(noteStatusTransitions[s], to)
+}
@@ -0,0 +1,283 @@
+package domain_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/core/apperr"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoteTransitionTo(t *testing.T) {
+	statuses := []domain.NoteStatus{
+		domain.NoteStatusActive,
+		domain.NoteStatusArchived,
+		domain.NoteStatusTrashed,
+		domain.NoteStatusPurged,
+	}
+
+	allowed := map[domain.NoteStatus][]domain.NoteStatus{
+		domain.NoteStatusActive:   {domain.NoteStatusArchived, domain.NoteStatusTrashed},
+		domain.NoteStatusArchived: {domain.NoteStatusActive, domain.NoteStatusTrashed},
+		domain.NoteStatusTrashed:  {domain.NoteStatusActive, domain.NoteStatusPurged},
+		domain.NoteStatusPurged:   {},
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			ok := slices.Contains(allowed[from], to)
+
+			t.Run(string(from)+" to "+string(to), func(t *testing.T) {
+				n, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+				require.NoError(t, err)
+				n.Status = from
+
+				err = n.TransitionTo(to, time.Now())
+
+				if ok {
+					require.NoError(t, err)
+					assert.Equal(t, to, n.Status)
+					assert.NotNil(t, n.UpdatedAt)
+
+					return
+				}
+
+				require.Error(t, err)
+				assert.True(t, apperr.IsInvalidOperation(err))
+				assert.True(t, domain.ErrNoteInvalidTransition.Is(err))
+				assert.Equal(t, from, n.Status)
+				assert.Nil(t, n.UpdatedAt)
+			})
+		}
+	}
+}
+
+func TestNoteArchive(t *testing.T) {
+	tests := []struct {
+		assert func(t *testing.T, n *domain.Note, err error)
+		name   string
+		from   domain.NoteStatus
+	}{
+		{
+			name: "should archive an active note",
+			from: domain.NoteStatusActive,
+			assert: func(t *testing.T, n *domain.Note, err error) {
+				require.NoError(t, err)
+				assert.True(t, n.IsArchived())
+				assert.NotNil(t, n.UpdatedAt)
+			},
+		},
+		{
+			name: "should reject archiving an archived note",
+			from: domain.NoteStatusArchived,
+			assert: func(t *testing.T, n *domain.Note, err error) {
+				require.Error(t, err)
+				assert.True(t, apperr.IsInvalidOperation(err))
+				assert.True(t, domain.ErrNoteAlreadyArchived.Is(err))
+				assert.Nil(t, n.UpdatedAt)
+			},
+		},
+		{
+			name: "should reject archiving a purged note",
+			from: domain.NoteStatusPurged,
+			assert: func(t *testing.T, n *domain.Note, err error) {
+				require.Error(t, err)
+				assert.True(t, domain.ErrNoteInvalidTransition.Is(err))
+				assert.Equal(t, domain.NoteStatusPurged, n.Status)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			n, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+			require.NoError(t, err)
+			n.Status = tc.from
+
+			err = n.Archive(time.Now())
+
+			tc.assert(t, n, err)
+		})
+	}
+}
+
+func TestNoteMarshalJSON(t *testing.T) {
+	n, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+	require.NoError(t, err)
+
+	active, err := json.Marshal(n)
+	require.NoError(t, err)
+	assert.Contains(t, string(active), `"status":"active"`)
+	assert.Contains(t, string(active), `"archived":false`)
+
+	require.NoError(t, n.Archive(time.Now()))
+
+	archived, err := json.Marshal(n)
+	require.NoError(t, err)
+	assert.Contains(t, string(archived), `"status":"archived"`)
+	assert.Contains(t, string(archived), `"archived":true`)
+
+	var decoded domain.Note
+	require.NoError(t, json.Unmarshal(archived, &decoded))
+	assert.Equal(t, domain.NoteStatusArchived, decoded.Status)
+}
+
+func TestNoteTrashAndRestore(t *testing.T) {
+	t.Run("should set deleted at when trashed", func(t *testing.T) {
+		n, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+		require.NoError(t, err)
+
+		require.NoError(t, n.Trash(time.Now()))
+
+		assert.True(t, n.IsTrashed())
+		require.NotNil(t, n.DeletedAt)
+		assert.Equal(t, n.UpdatedAt, n.DeletedAt)
+	})
+
+	t.Run("should clear deleted at when restored", func(t *testing.T) {
+		n, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+		require.NoError(t, err)
+		require.NoError(t, n.Trash(time.Now()))
+
+		require.NoError(t, n.Restore(time.Now()))
+
+		assert.Equal(t, domain.NoteStatusActive, n.Status)
+		assert.Nil(t, n.DeletedAt)
+	})
+
+	t.Run("should reject restoring a note that is not trashed", func(t *testing.T) {
+		n, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+		require.NoError(t, err)
+
+		err = n.Restore(time.Now())
+
+		require.Error(t, err)
+		assert.True(t, domain.ErrNoteNotInTrash.Is(err))
+	})
+}
+
+func TestNewNote(t *testing.T) {
+	tests := []struct {
+		wantErr   *apperr.Code
+		name      string
+		title     string
+		content   string
+		wantTitle string
+	}{
+		{name: "should trim the title", title: "  Title \n", content: "body", wantTitle: "Title"},
+		{name: "should allow line breaks and tabs in content", title: "Title", content: "a\n\tb\r\n", wantTitle: "Title"},
+		{name: "should allow empty content", title: "Title", wantTitle: "Title"},
+		{name: "should allow titles at the limit", title: strings.Repeat("é", domain.MaxTitleLength), wantTitle: strings.Repeat("é", domain.MaxTitleLength)},
+		{name: "should reject blank titles", title: " \t ", wantErr: &domain.ErrNoteTitleBlank},
+		{name: "should reject long titles", title: strings.Repeat("a", domain.MaxTitleLength+1), wantErr: &domain.ErrNoteTitleTooLong},
+		{name: "should reject long content", title: "Title", content: strings.Repeat("a", domain.MaxContentLength+1), wantErr: &domain.ErrNoteContentTooLong},
+		{name: "should reject invalid utf-8", title: "Title", content: "a\xffb", wantErr: &domain.ErrNoteInvalidEncoding},
+		{name: "should reject control characters in content", title: "Title", content: "a\x00b", wantErr: &domain.ErrNoteControlCharacter},
+		{name: "should reject line breaks inside the title", title: "Ti\ntle", wantErr: &domain.ErrNoteControlCharacter},
+		{name: "should reject c1 control characters", title: "Title\u0085x", wantErr: &domain.ErrNoteControlCharacter},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			n, err := domain.NewNote(core.NewID(), time.Now(), tc.title, tc.content)
+
+			if tc.wantErr != nil {
+				require.Error(t, err)
+				assert.Nil(t, n)
+				assert.True(t, tc.wantErr.Is(err))
+				assert.True(t, apperr.IsValidation(err))
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantTitle, n.Title)
+			assert.Equal(t, tc.content, n.Content)
+		})
+	}
+}
+
+func TestNoteEdit(t *testing.T) {
+	created := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	edited := created.Add(time.Hour)
+
+	t.Run("should bump the version when title or content change", func(t *testing.T) {
+		n, err := domain.NewNote(core.NewID(), created, "Title", "body")
+		require.NoError(t, err)
+
+		changed, err := n.Edit(" New title ", "new body", edited)
+
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, "New title", n.Title)
+		assert.Equal(t, "new body", n.Content)
+		assert.Equal(t, 2, n.Version)
+		require.NotNil(t, n.UpdatedAt)
+		assert.True(t, n.UpdatedAt.Equal(edited))
+	})
+
+	t.Run("should keep the version when nothing changes", func(t *testing.T) {
+		n, err := domain.NewNote(core.NewID(), created, "Title", "body")
+		require.NoError(t, err)
+
+		changed, err := n.Edit("Title  ", "body", edited)
+
+		require.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, 1, n.Version)
+		assert.Nil(t, n.UpdatedAt)
+	})
+
+	t.Run("should reject invalid edits untouched", func(t *testing.T) {
+		n, err := domain.NewNote(core.NewID(), created, "Title", "body")
+		require.NoError(t, err)
+
+		changed, err := n.Edit("   ", "new body", edited)
+
+		require.Error(t, err)
+		assert.True(t, domain.ErrNoteTitleBlank.Is(err))
+		assert.False(t, changed)
+		assert.Equal(t, "body", n.Content)
+		assert.Equal(t, 1, n.Version)
+	})
+}
+
+func TestParseNoteFormat(t *testing.T) {
+	for raw, want := range map[string]domain.NoteFormat{
+		"":         domain.NoteFormatPlain,
+		"plain":    domain.NoteFormatPlain,
+		"markdown": domain.NoteFormatMarkdown,
+	} {
+		got, err := domain.ParseNoteFormat(raw)
+
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	_, err := domain.ParseNoteFormat("html")
+
+	require.Error(t, err)
+	assert.True(t, domain.ErrNoteFormatInvalid.Is(err))
+}
+
+func TestNoteChangeFormat(t *testing.T) {
+	n, err := domain.NewNote(core.NewID(), time.Now(), "Title", "body")
+	require.NoError(t, err)
+	assert.Equal(t, domain.NoteFormatPlain, n.Format)
+
+	assert.False(t, n.ChangeFormat(domain.NoteFormatPlain, time.Now()))
+	assert.Nil(t, n.UpdatedAt)
+
+	assert.True(t, n.ChangeFormat(domain.NoteFormatMarkdown, time.Now()))
+	assert.Equal(t, domain.NoteFormatMarkdown, n.Format)
+	assert.NotNil(t, n.UpdatedAt)
+	assert.Equal(t, 1, n.Version)
+}
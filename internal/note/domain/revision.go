@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"HATCH_APP/pkg/core"
+)
+
+// NoteRevision is the title and content of a note at one of its versions.
+type NoteRevision struct {
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	NoteID    core.ID   `json:"note_id"    db:"note_id"`
+	Title     string    `json:"title"      db:"title"`
+	Content   string    `json:"content"    db:"content"`
+	Version   int       `json:"version"    db:"version"`
+}
+
+type RevisionRepository interface {
+	CreateRevision(ctx context.Context, revision NoteRevision) error
+	// ListRevisions returns the revisions of a note, newest first.
+	ListRevisions(ctx context.Context, noteID core.ID) ([]NoteRevision, error)
+	FindRevision(ctx context.Context, noteID core.ID, version int) (*NoteRevision, error)
+	// PruneRevisions deletes the revisions of a note older than version.
+	PruneRevisions(ctx context.Context, noteID core.ID, version int) error
+}
+
+// ParseRevisionVersion parses a version as given in a path or query.
+func ParseRevisionVersion(raw string) (int, error) {
+	version, err := strconv.Atoi(raw)
+	if err != nil || version < 1 {
+		return 0, ErrNoteRevisionVersionInvalid.New().WithDetails(map[string]string{
+			"version": raw,
+		})
+	}
+
+	return version, nil
+}
+
+// Revision snapshots the current version of the note.
+func (n *Note) Revision() NoteRevision {
+	createdAt := n.CreatedAt
+	if n.UpdatedAt != nil {
+		createdAt = *n.UpdatedAt
+	}
+
+	return NoteRevision{
+		NoteID:    n.ID,
+		Version:   n.Version,
+		Title:     n.Title,
+		Content:   n.Content,
+		CreatedAt: createdAt,
+	}
+}
+
+// RecordRevision stores the current version of the note and drops the
+// revisions beyond the newest keep ones. keep <= 0 keeps them all.
+func RecordRevision(ctx context.Context, repo RevisionRepository, n *Note, keep int) error {
+	if err := repo.CreateRevision(ctx, n.Revision()); err != nil {
+		return ErrNoteRevisionSaveFailed.Propagate(err)
+	}
+
+	if keep <= 0 || n.Version <= keep {
+		return nil
+	}
+
+	if err := repo.PruneRevisions(ctx, n.ID, n.Version-keep+1); err != nil {
+		return ErrNoteRevisionSaveFailed.Propagate(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package domain_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/mocks"
+	"HATCH_APP/pkg/core"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRevisionVersion(t *testing.T) {
+	version, err := domain.ParseRevisionVersion("3")
+	require.NoError(t, err)
+	assert.Equal(t, 3, version)
+
+	for _, raw := range []string{"", "0", "-1", "1.5", "latest"} {
+		_, err := domain.ParseRevisionVersion(raw)
+
+		require.Error(t, err, raw)
+		assert.True(t, domain.ErrNoteRevisionVersionInvalid.Is(err), raw)
+	}
+}
+
+func TestRecordRevision(t *testing.T) {
+	created := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	newNote := func(t *testing.T, edits int) *domain.Note {
+		n, err := domain.NewNote(core.NewID(), created, "Title", "body")
+		require.NoError(t, err)
+
+		for i := range edits {
+			_, err := n.Edit("Title", "body "+string(rune('a'+i)), created.Add(time.Duration(i+1)*time.Minute))
+			require.NoError(t, err)
+		}
+
+		return n
+	}
+
+	t.Run("should snapshot the current version", func(t *testing.T) {
+		repo := mocks.NewRevisionRepository(t)
+		n := newNote(t, 1)
+
+		repo.On("CreateRevision", t.Context(), domain.NoteRevision{
+			NoteID:    n.ID,
+			Version:   2,
+			Title:     "Title",
+			Content:   "body a",
+			CreatedAt: created.Add(time.Minute),
+		}).
+			Return(nil).
+			Once()
+
+		require.NoError(t, domain.RecordRevision(t.Context(), repo, n, 2))
+	})
+
+	t.Run("should prune revisions beyond retention", func(t *testing.T) {
+		repo := mocks.NewRevisionRepository(t)
+		n := newNote(t, 4)
+
+		repo.On("CreateRevision", t.Context(), mock.Anything).
+			Return(nil).
+			Once()
+
+		repo.On("PruneRevisions", t.Context(), n.ID, 3).
+			Return(nil).
+			Once()
+
+		require.NoError(t, domain.RecordRevision(t.Context(), repo, n, 3))
+	})
+
+	t.Run("should keep every revision without retention", func(t *testing.T) {
+		repo := mocks.NewRevisionRepository(t)
+		n := newNote(t, 4)
+
+		repo.On("CreateRevision", t.Context(), mock.Anything).
+			Return(nil).
+			Once()
+
+		require.NoError(t, domain.RecordRevision(t.Context(), repo, n, 0))
+	})
+
+	t.Run("should wrap datasource errors", func(t *testing.T) {
+		repo := mocks.NewRevisionRepository(t)
+		n := newNote(t, 0)
+
+		repo.On("CreateRevision", t.Context(), mock.Anything).
+			Return(errors.New("db error")).
+			Once()
+
+		err := domain.RecordRevision(t.Context(), repo, n, 1)
+
+		require.Error(t, err)
+		assert.True(t, domain.ErrNoteRevisionSaveFailed.Is(err))
+	})
+}
@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"HATCH_APP/pkg/core"
+)
+
+const (
+	MaxTagLength   = 50
+	MaxTagsPerNote = 20
+)
+
+type Tag struct {
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID        core.ID   `json:"id"         db:"id"`
+	Name      string    `json:"name"       db:"name"`
+}
+
+type TagCount struct {
+	Name  string `json:"name"  db:"name"`
+	Count int    `json:"count" db:"count"`
+}
+
+type TagMatch string
+
+const (
+	TagMatchAny TagMatch = "any"
+	TagMatchAll TagMatch = "all"
+)
+
+// Enum lists the matches for the API schema.
+func (TagMatch) Enum() []any {
+	return []any{TagMatchAny, TagMatchAll}
+}
+
+// NoteFilter narrows List down to notes tagged with any or all of Tags, and
+// to the notes of Owner when set.
+type NoteFilter struct {
+	Owner *string
+	Match TagMatch
+	Tags  []string
+}
+
+func NewNoteFilter(tags []string, match TagMatch) (NoteFilter, error) {
+	switch match {
+	case "":
+		match = TagMatchAny
+	case TagMatchAny, TagMatchAll:
+	default:
+		return NoteFilter{}, ErrNoteFilterInvalid.New().WithDetails(map[string]any{
+			"match":   match,
+			"allowed": TagMatch("").Enum(),
+		})
+	}
+
+	names, err := NormalizeTags(tags)
+	if err != nil {
+		return NoteFilter{}, err
+	}
+
+	return NoteFilter{Tags: names, Match: match}, nil
+}
+
+// NormalizeTag lowercases and trims name and joins its words with dashes, so
+// "Work Items" and "work-items" are the same tag.
+func NormalizeTag(name string) (string, error) {
+	normalized := strings.Join(strings.Fields(strings.ToLower(name)), "-")
+
+	invalid := func(reason string) error {
+		return ErrNoteTagInvalid.New().WithDetails(map[string]string{
+			"tag":    name,
+			"reason": reason,
+		})
+	}
+
+	if normalized == "" {
+		return "", invalid("blank")
+	}
+
+	if utf8.RuneCountInString(normalized) > MaxTagLength {
+		return "", invalid("too long")
+	}
+
+	for _, r := range normalized {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return "", invalid("only letters, digits, dashes and underscores are allowed")
+		}
+	}
+
+	return normalized, nil
+}
+
+// NormalizeTags normalizes names and drops duplicates, keeping their order.
+func NormalizeTags(names []string) ([]string, error) {
+	normalized := make([]string, 0, len(names))
+
+	for _, name := range names {
+		tag, err := NormalizeTag(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+
+	return normalized, nil
+}
+
+// AddTags tags the note and returns the normalized names it did not have.
+func (n *Note) AddTags(names ...string) ([]string, error) {
+	tags, err := NormalizeTags(names)
+	if err != nil {
+		return nil, err
+	}
+
+	added := slices.DeleteFunc(tags, func(tag string) bool {
+		return slices.Contains(n.Tags, tag)
+	})
+
+	if len(n.Tags)+len(added) > MaxTagsPerNote {
+		return nil, ErrNoteTooManyTags.New().WithDetails(map[string]int{"max": MaxTagsPerNote})
+	}
+
+	n.Tags = append(n.Tags, added...)
+	slices.Sort(n.Tags)
+
+	return added, nil
+}
+
+// RemoveTags untags the note and returns the normalized names it had.
+func (n *Note) RemoveTags(names ...string) ([]string, error) {
+	tags, err := NormalizeTags(names)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := slices.DeleteFunc(tags, func(tag string) bool {
+		return !slices.Contains(n.Tags, tag)
+	})
+
+	n.Tags = slices.DeleteFunc(n.Tags, func(tag string) bool {
+		return slices.Contains(removed, tag)
+	})
+
+	return removed, nil
+}
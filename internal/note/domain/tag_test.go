@@ -0,0 +1,107 @@
+package domain_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/core"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "work", want: "work"},
+		{input: "  Work ", want: "work"},
+		{input: "Side  Project", want: "side-project"},
+		{input: "año_2025", want: "año_2025"},
+		{input: "   ", wantErr: true},
+		{input: "c++", wantErr: true},
+		{input: "a/b", wantErr: true},
+		{input: strings.Repeat("a", domain.MaxTagLength+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := domain.NormalizeTag(tc.input)
+
+			if tc.wantErr {
+				require.Error(t, err)
+				assert.True(t, domain.ErrNoteTagInvalid.Is(err))
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestNoteTags(t *testing.T) {
+	newNote := func(t *testing.T) *domain.Note {
+		n, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+		require.NoError(t, err)
+
+		return n
+	}
+
+	t.Run("should add new tags only and keep them sorted", func(t *testing.T) {
+		n := newNote(t)
+
+		added, err := n.AddTags("work", "Ideas")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"work", "ideas"}, added)
+
+		added, err = n.AddTags("WORK", "home")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"home"}, added)
+
+		assert.Equal(t, []string{"home", "ideas", "work"}, n.Tags)
+	})
+
+	t.Run("should reject going over the tag limit", func(t *testing.T) {
+		n := newNote(t)
+
+		names := make([]string, 0, domain.MaxTagsPerNote+1)
+		for i := range domain.MaxTagsPerNote + 1 {
+			names = append(names, "tag-"+string(rune('a'+i)))
+		}
+
+		_, err := n.AddTags(names...)
+
+		require.Error(t, err)
+		assert.True(t, domain.ErrNoteTooManyTags.Is(err))
+		assert.Empty(t, n.Tags)
+	})
+
+	t.Run("should remove only the tags the note has", func(t *testing.T) {
+		n := newNote(t)
+
+		_, err := n.AddTags("work", "home")
+		require.NoError(t, err)
+
+		removed, err := n.RemoveTags("Work", "missing")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"work"}, removed)
+		assert.Equal(t, []string{"home"}, n.Tags)
+	})
+}
+
+func TestNewNoteFilter(t *testing.T) {
+	f, err := domain.NewNoteFilter([]string{"Work", "work"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, domain.NoteFilter{Tags: []string{"work"}, Match: domain.TagMatchAny}, f)
+
+	_, err = domain.NewNoteFilter(nil, "most")
+	require.Error(t, err)
+	assert.True(t, domain.ErrNoteFilterInvalid.Is(err))
+}
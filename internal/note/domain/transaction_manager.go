@@ -1,5 +1,14 @@
 package domain
 
 type TransactionManagerInput struct {
-	NoteRepository NoteRepository
+	NoteRepository     NoteRepository
+	RevisionRepository RevisionRepository
+	// Events are published along with the changes, dropped on rollback.
+	Events EventPublisher
+}
+
+// TransactionManager runs fn with repositories bound to one transaction,
+// committed when fn returns nil and rolled back otherwise.
+type TransactionManager interface {
+	Transact(fn func(input TransactionManagerInput) error) error
 }
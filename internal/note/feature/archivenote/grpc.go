@@ -0,0 +1,24 @@
+package archivenote
+
+import (
+	"HATCH_APP/internal/note/pb"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/transport/grpcx"
+	"context"
+)
+
+func (f *Feature) ArchiveNoteRPC(ctx context.Context, req *pb.ArchiveNoteRequest) (*pb.ArchiveNoteResponse, error) {
+	log := o11y.LoggerFromContext(ctx).With("rpc", "ArchiveNote")
+
+	id, err := core.ParseID(req.GetId())
+	if err != nil {
+		return nil, grpcx.Error(log, err)
+	}
+
+	if err := f.service.ArchiveNote(ctx, id); err != nil {
+		return nil, grpcx.Error(log, err)
+	}
+
+	return &pb.ArchiveNoteResponse{}, nil
+}
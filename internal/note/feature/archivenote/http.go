@@ -9,13 +9,17 @@ import (
 func (f *Feature) ArchiveNoteEndpoint(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	id := r.PathValue("id")
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "ArchiveNote")
 
-	log := o11y.LoggerFromContext(ctx).
-		With("endpoint", "ArchiveNote").
-		With("note_id", id)
+	id, err := httpx.PathID(w, r, "id")
+	if err != nil {
+		log.WarnContext(ctx, "invalid id", "error", err)
+		return
+	}
+
+	log = log.With("note_id", id)
 
-	if err := f.service.ArchiveNote(ctx, id); err != nil {
+	if err = f.service.ArchiveNote(ctx, id); err != nil {
 		httpx.WriteError(log, w, err)
 		return
 	}
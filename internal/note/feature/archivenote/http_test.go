@@ -4,11 +4,13 @@ import (
 	"HATCH_APP/internal/note/domain"
 	"HATCH_APP/internal/note/feature/archivenote"
 	"HATCH_APP/internal/note/infra/store/postgres"
+	"HATCH_APP/pkg/core"
 	"HATCH_APP/pkg/transport/httpx"
 	"HATCH_APP/test/container"
 	"HATCH_APP/test/httptest"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -31,7 +33,7 @@ func setupHTTPSuite(t *testing.T) *httpSuite {
 
 	return &httpSuite{
 		repo: repo,
-		feat: archivenote.New(repo),
+		feat: archivenote.New(postgres.NewTransactionManager(db), core.NewULIDGenerator(core.SystemClock()), core.SystemClock()),
 	}
 }
 
@@ -47,15 +49,16 @@ func TestArchiveNoteEndpoint(t *testing.T) {
 			name: "should archive note successfully",
 			tc: httptest.Case{
 				ArrangeRequest: func() *http.Request {
-					note := domain.NewNote("Test Note", "Test Content")
+					note, err := domain.NewNote(core.NewID(), time.Now(), "Test Note", "Test Content")
+					require.NoError(t, err)
 
-					err := s.repo.Create(t.Context(), note)
+					err = s.repo.Create(t.Context(), note)
 					require.NoError(t, err)
 
 					return httptest.WithParam(
-						httptest.NewRequest(http.MethodPatch, "/api/v1/notes/"+note.ID),
+						httptest.NewRequest(http.MethodPatch, "/api/v1/notes/"+note.ID.String()),
 						"id",
-						note.ID,
+						note.ID.String(),
 					)
 				},
 				ExpectStatus: http.StatusNoContent,
@@ -64,15 +67,62 @@ func TestArchiveNoteEndpoint(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "should return 400 when note is already archived",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					note, err := domain.NewNote(core.NewID(), time.Now(), "Test Note", "Test Content")
+					require.NoError(t, err)
+					require.NoError(t, note.Archive(time.Now()))
+
+					err = s.repo.Create(t.Context(), note)
+					require.NoError(t, err)
+
+					return httptest.WithParam(
+						httptest.NewRequest(http.MethodPatch, "/api/v1/notes/"+note.ID.String()),
+						"id",
+						note.ID.String(),
+					)
+				},
+				ExpectStatus: http.StatusBadRequest,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrNoteAlreadyArchived.ID, resp.Code)
+				},
+			},
+		},
 		{
 			name: "should return 404 when note not found",
 			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					id := core.NewID().String()
+
+					return httptest.WithParam(httptest.NewRequest(http.MethodPatch, "/api/v1/notes/"+id), "id", id)
+				},
 				ExpectStatus: http.StatusNotFound,
 				CheckResponse: func(t *testing.T, body []byte) {
 					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
 
 					require.NoError(t, err)
 					assert.Equal(t, "note not found", resp.Message)
+					assert.Equal(t, domain.ErrNoteNotFound.ID, resp.Code)
+				},
+			},
+		},
+		{
+			name: "should return 400 when id is malformed",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					return httptest.WithParam(httptest.NewRequest(http.MethodPatch, "/api/v1/notes/"+"not-an-id"), "id", "not-an-id")
+				},
+				ExpectStatus: http.StatusBadRequest,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, core.ErrInvalidID.ID, resp.Code)
 				},
 			},
 		},
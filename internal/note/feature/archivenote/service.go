@@ -2,36 +2,49 @@ package archivenote
 
 import (
 	"HATCH_APP/internal/note/domain"
-	"HATCH_APP/pkg/core/apperr"
+	"HATCH_APP/pkg/core"
 	"context"
 )
 
 type Service struct {
-	noteRepo domain.NoteRepository
+	txManager domain.TransactionManager
+	ids       core.IDGenerator
+	clock     core.Clock
 }
 
-func NewService(noteRepo domain.NoteRepository) *Service {
+func NewService(txManager domain.TransactionManager, ids core.IDGenerator, clock core.Clock) *Service {
 	return &Service{
-		noteRepo: noteRepo,
+		txManager: txManager,
+		ids:       ids,
+		clock:     clock,
 	}
 }
 
-func (s *Service) ArchiveNote(ctx context.Context, id string) error {
-	note, err := s.noteRepo.FindByID(ctx, id)
+func (s *Service) ArchiveNote(ctx context.Context, id core.ID) error {
+	return s.txManager.Transact(func(input domain.TransactionManagerInput) error {
+		note, err := input.NoteRepository.FindByID(ctx, id)
+		if err != nil {
+			return domain.ErrNoteFindFailed.Propagate(err)
+		}
 
-	if err != nil {
-		return apperr.Internal("failed to find note", err)
-	}
+		if note == nil {
+			return domain.ErrNoteNotFound.New()
+		}
 
-	if note == nil {
-		return apperr.NotFound("note not found")
-	}
+		now := s.clock.Now()
 
-	note.Archive()
+		if err := note.Archive(now); err != nil {
+			return err
+		}
 
-	if err := s.noteRepo.Save(ctx, note); err != nil {
-		return apperr.Internal("failed to save note", err)
-	}
+		if err := input.NoteRepository.Save(ctx, note); err != nil {
+			return domain.ErrNoteSaveFailed.Propagate(err)
+		}
+
+		if err := input.Events.Publish(ctx, domain.NewEvent(s.ids.NewID(), domain.EventNoteArchived, note, now)); err != nil {
+			return domain.ErrNoteEventPublishFailed.Wrap(err)
+		}
 
-	return nil
+		return nil
+	})
 }
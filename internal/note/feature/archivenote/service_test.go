@@ -4,6 +4,7 @@ import (
 	"HATCH_APP/internal/note/domain"
 	"HATCH_APP/internal/note/feature/archivenote"
 	"HATCH_APP/internal/note/mocks"
+	"HATCH_APP/pkg/core"
 	"HATCH_APP/pkg/core/apperr"
 	"errors"
 	"testing"
@@ -16,30 +17,49 @@ import (
 
 type serviceSuite struct {
 	repo    *mocks.NoteRepository
+	events  *mocks.EventPublisher
 	service *archivenote.Service
 }
 
+var (
+	now     = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	eventID = core.MustParseID("01JWN3V0G0000000000000000E")
+)
+
 func setupSuite(t *testing.T) *serviceSuite {
 	repo := mocks.NewNoteRepository(t)
+	events := mocks.NewEventPublisher(t)
+	txManager := mocks.NewTransactionManager(t)
+
+	txManager.On("Transact", mock.Anything).
+		Return(func(fn func(domain.TransactionManagerInput) error) error {
+			return fn(domain.TransactionManagerInput{
+				NoteRepository: repo,
+				Events:         events,
+			})
+		}).
+		Maybe()
 
-	service := archivenote.NewService(repo)
+	service := archivenote.NewService(txManager, core.FixedIDs(eventID), core.FixedClock(now))
 
 	return &serviceSuite{
 		repo:    repo,
+		events:  events,
 		service: service,
 	}
 }
 
 func TestServiceArchiveNote(t *testing.T) {
 	tests := []struct {
-		arrange   func(t *testing.T, s *serviceSuite) string
+		arrange   func(t *testing.T, s *serviceSuite) core.ID
 		assertErr func(t *testing.T, err error)
 		name      string
 	}{
 		{
 			name: "should archive successfully",
-			arrange: func(t *testing.T, s *serviceSuite) string {
-				n := domain.NewNote("title", "content")
+			arrange: func(t *testing.T, s *serviceSuite) core.ID {
+				n, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+				require.NoError(t, err)
 
 				s.repo.On("FindByID", t.Context(), n.ID).
 					Return(n, nil).
@@ -47,9 +67,18 @@ func TestServiceArchiveNote(t *testing.T) {
 
 				s.repo.On("Save", t.Context(), mock.MatchedBy(func(note *domain.Note) bool {
 					return note.ID == n.ID &&
-						note.Archived &&
+						note.IsArchived() &&
 						note.UpdatedAt != nil &&
-						time.Since(*note.UpdatedAt) < time.Second
+						note.UpdatedAt.Equal(now)
+				})).
+					Return(nil).
+					Once()
+
+				s.events.On("Publish", t.Context(), mock.MatchedBy(func(e domain.Event) bool {
+					return e.ID == eventID &&
+						e.Type == domain.EventNoteArchived &&
+						e.Note.ID == n.ID &&
+						e.CreatedAt.Equal(now)
 				})).
 					Return(nil).
 					Once()
@@ -60,14 +89,41 @@ func TestServiceArchiveNote(t *testing.T) {
 				require.NoError(t, err)
 			},
 		},
+		{
+			name: "should fail when the event cannot be published",
+			arrange: func(t *testing.T, s *serviceSuite) core.ID {
+				n, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+				require.NoError(t, err)
+
+				s.repo.On("FindByID", t.Context(), n.ID).
+					Return(n, nil).
+					Once()
+
+				s.repo.On("Save", t.Context(), mock.Anything).
+					Return(nil).
+					Once()
+
+				s.events.On("Publish", t.Context(), mock.Anything).
+					Return(errors.New("queue down")).
+					Once()
+
+				return n.ID
+			},
+			assertErr: func(t *testing.T, err error) {
+				require.Error(t, err)
+				assert.True(t, domain.ErrNoteEventPublishFailed.Is(err))
+			},
+		},
 		{
 			name: "should return error when FindByID fails",
-			arrange: func(t *testing.T, s *serviceSuite) string {
-				s.repo.On("FindByID", t.Context(), "nonexistent").
+			arrange: func(t *testing.T, s *serviceSuite) core.ID {
+				id := core.NewID()
+
+				s.repo.On("FindByID", t.Context(), id).
 					Return((*domain.Note)(nil), errors.New("repo down")).
 					Once()
 
-				return "nonexistent"
+				return id
 			},
 			assertErr: func(t *testing.T, err error) {
 				require.Error(t, err)
@@ -75,8 +131,9 @@ func TestServiceArchiveNote(t *testing.T) {
 		},
 		{
 			name: "should return error when Save fails",
-			arrange: func(t *testing.T, s *serviceSuite) string {
-				n := domain.NewNote("title", "content")
+			arrange: func(t *testing.T, s *serviceSuite) core.ID {
+				n, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+				require.NoError(t, err)
 
 				s.repo.On("FindByID", t.Context(), n.ID).
 					Return(n, nil).
@@ -92,18 +149,40 @@ func TestServiceArchiveNote(t *testing.T) {
 				require.Error(t, err)
 			},
 		},
+		{
+			name: "should return invalid operation when note is already archived",
+			arrange: func(t *testing.T, s *serviceSuite) core.ID {
+				n, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+				require.NoError(t, err)
+				require.NoError(t, n.Archive(time.Now()))
+
+				s.repo.On("FindByID", t.Context(), n.ID).
+					Return(n, nil).
+					Once()
+
+				return n.ID
+			},
+			assertErr: func(t *testing.T, err error) {
+				require.Error(t, err)
+				assert.True(t, apperr.IsInvalidOperation(err))
+				assert.True(t, domain.ErrNoteAlreadyArchived.Is(err))
+			},
+		},
 		{
 			name: "should return not found when note does not exist",
-			arrange: func(t *testing.T, s *serviceSuite) string {
-				s.repo.On("FindByID", mock.Anything, "invalid-id").
+			arrange: func(t *testing.T, s *serviceSuite) core.ID {
+				id := core.NewID()
+
+				s.repo.On("FindByID", mock.Anything, id).
 					Return((*domain.Note)(nil), nil).
 					Once()
 
-				return "invalid-id"
+				return id
 			},
 			assertErr: func(t *testing.T, err error) {
 				require.Error(t, err)
 				assert.True(t, apperr.IsNotFound(err))
+				assert.True(t, domain.ErrNoteNotFound.Is(err))
 			},
 		},
 	}
@@ -2,14 +2,15 @@ package createnote
 
 import (
 	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/core"
 )
 
 type Feature struct {
 	service *Service
 }
 
-func New(noteRepo domain.NoteRepository) *Feature {
+func New(txManager domain.TransactionManager, ids core.IDGenerator, clock core.Clock) *Feature {
 	return &Feature{
-		service: NewService(noteRepo),
+		service: NewService(txManager, ids, clock),
 	}
 }
@@ -0,0 +1,31 @@
+package createnote
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/pb"
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/transport/grpcx"
+	"context"
+)
+
+func (f *Feature) CreateNoteRPC(ctx context.Context, req *pb.CreateNoteRequest) (*pb.CreateNoteResponse, error) {
+	log := o11y.LoggerFromContext(ctx).With("rpc", "CreateNote")
+
+	in := Request{
+		Title:   req.GetTitle(),
+		Content: req.GetContent(),
+		Format:  domain.NoteFormat(req.GetFormat()),
+	}
+
+	if err := grpcx.Validate(ctx, in); err != nil {
+		log.WarnContext(ctx, "invalid payload", "error", err)
+		return nil, err
+	}
+
+	id, err := f.service.CreateNote(ctx, in.Title, in.Content, string(in.Format))
+	if err != nil {
+		return nil, grpcx.Error(log, err)
+	}
+
+	return &pb.CreateNoteResponse{Id: id.String()}, nil
+}
@@ -1,14 +1,19 @@
 package createnote
 
 import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/core"
 	"HATCH_APP/pkg/o11y"
 	"HATCH_APP/pkg/transport/httpx"
 	"net/http"
 )
 
+// Request mirrors domain.MaxTitleLength and domain.MaxContentLength, the
+// domain still has the final say.
 type Request struct {
-	Title   string `json:"title"   validate:"required,gt=0"`
-	Content string `json:"content" validate:"required,gt=0"`
+	Title   string            `json:"title"            validate:"required,notblank,max=200"`
+	Content string            `json:"content"          validate:"required,max=100000"`
+	Format  domain.NoteFormat `json:"format,omitempty" validate:"omitempty,oneof=plain markdown"`
 }
 
 type Response struct {
@@ -17,7 +22,7 @@ type Response struct {
 }
 
 type ResponseData struct {
-	ID string `json:"id"`
+	ID core.ID `json:"id"`
 }
 
 func (f *Feature) CreateNoteEndpoint(w http.ResponseWriter, r *http.Request) {
@@ -31,7 +36,7 @@ func (f *Feature) CreateNoteEndpoint(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	id, err := f.service.CreateNote(ctx, req.Title, req.Content)
+	id, err := f.service.CreateNote(ctx, req.Title, req.Content, string(req.Format))
 	if err != nil {
 		httpx.WriteError(log, w, err)
 		return
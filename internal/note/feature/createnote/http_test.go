@@ -1,8 +1,10 @@
 package createnote_test
 
 import (
+	"HATCH_APP/internal/note/domain"
 	"HATCH_APP/internal/note/feature/createnote"
 	"HATCH_APP/internal/note/infra/store/postgres"
+	"HATCH_APP/pkg/core"
 	"HATCH_APP/pkg/transport/httpx"
 	"HATCH_APP/test/container"
 	"HATCH_APP/test/httptest"
@@ -33,7 +35,7 @@ func setupHTTPSuite(t *testing.T) *httpSuite {
 
 	return &httpSuite{
 		repo: repo,
-		feat: createnote.New(repo),
+		feat: createnote.New(postgres.NewTransactionManager(db), core.NewULIDGenerator(core.SystemClock()), core.SystemClock()),
 	}
 }
 
@@ -139,6 +141,29 @@ func TestCreateNoteEndpoint(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "should return 400 when content has control characters",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					p := payload
+					p.Content = "bell\u0007"
+
+					body, _ := json.Marshal(p)
+
+					return stdhttptest.NewRequest(http.MethodPost, "/api/v1/notes", bytes.NewReader(body))
+				},
+				Headers: map[string]string{
+					"Content-Type": "application/json",
+				},
+				ExpectStatus: http.StatusBadRequest,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrNoteControlCharacter.ID, resp.Code)
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
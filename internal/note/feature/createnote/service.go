@@ -2,25 +2,60 @@ package createnote
 
 import (
 	"HATCH_APP/internal/note/domain"
-	"HATCH_APP/pkg/core/apperr"
+	"HATCH_APP/internal/shared/auth"
+	"HATCH_APP/pkg/core"
 	"context"
 )
 
 type Service struct {
-	noteRepo domain.NoteRepository
+	txManager domain.TransactionManager
+	ids       core.IDGenerator
+	clock     core.Clock
 }
 
-func NewService(noteRepo domain.NoteRepository) *Service {
+func NewService(txManager domain.TransactionManager, ids core.IDGenerator, clock core.Clock) *Service {
 	return &Service{
-		noteRepo: noteRepo,
+		txManager: txManager,
+		ids:       ids,
+		clock:     clock,
 	}
 }
 
-func (s *Service) CreateNote(ctx context.Context, title, content string) (string, error) {
-	note := domain.NewNote(title, content)
+// CreateNote defaults an empty format to plain, the note belongs to the owner
+// of ctx.
+func (s *Service) CreateNote(ctx context.Context, title, content, format string) (core.ID, error) {
+	noteFormat, err := domain.ParseNoteFormat(format)
+	if err != nil {
+		return core.ID{}, err
+	}
+
+	note, err := domain.NewNote(s.ids.NewID(), s.clock.Now(), title, content)
+	if err != nil {
+		return core.ID{}, err
+	}
+
+	note.Format = noteFormat
+	note.OwnerID = auth.OwnerFromContext(ctx)
+
+	err = s.txManager.Transact(func(input domain.TransactionManagerInput) error {
+		if err := input.NoteRepository.Create(ctx, note); err != nil {
+			return domain.ErrNoteCreateFailed.Propagate(err)
+		}
+
+		// The first version never exceeds any retention.
+		if err := domain.RecordRevision(ctx, input.RevisionRepository, note, 0); err != nil {
+			return err
+		}
+
+		event := domain.NewEvent(s.ids.NewID(), domain.EventNoteCreated, note, note.CreatedAt)
+		if err := input.Events.Publish(ctx, event); err != nil {
+			return domain.ErrNoteEventPublishFailed.Wrap(err)
+		}
 
-	if err := s.noteRepo.Create(ctx, note); err != nil {
-		return "", apperr.Internal("failed to create note", err)
+		return nil
+	})
+	if err != nil {
+		return core.ID{}, err
 	}
 
 	return note.ID, nil
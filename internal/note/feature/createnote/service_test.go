@@ -4,8 +4,13 @@ import (
 	"HATCH_APP/internal/note/domain"
 	"HATCH_APP/internal/note/feature/createnote"
 	"HATCH_APP/internal/note/mocks"
+	"HATCH_APP/internal/shared/auth"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/core/apperr"
+	"HATCH_APP/pkg/store/postgres"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -13,18 +18,40 @@ import (
 )
 
 type serviceSuite struct {
-	repo    *mocks.NoteRepository
-	service *createnote.Service
+	repo         *mocks.NoteRepository
+	revisionRepo *mocks.RevisionRepository
+	events       *mocks.EventPublisher
+	service      *createnote.Service
 }
 
+var (
+	now    = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	noteID = core.MustParseID("01JWN3V0G0000000000000000A")
+)
+
 func setupServiceSuite(t *testing.T) *serviceSuite {
 	repo := mocks.NewNoteRepository(t)
+	revisionRepo := mocks.NewRevisionRepository(t)
+	events := mocks.NewEventPublisher(t)
+	txManager := mocks.NewTransactionManager(t)
+
+	txManager.On("Transact", mock.Anything).
+		Return(func(fn func(domain.TransactionManagerInput) error) error {
+			return fn(domain.TransactionManagerInput{
+				NoteRepository:     repo,
+				RevisionRepository: revisionRepo,
+				Events:             events,
+			})
+		}).
+		Maybe()
 
-	service := createnote.NewService(repo)
+	service := createnote.NewService(txManager, core.FixedIDs(noteID), core.FixedClock(now))
 
 	return &serviceSuite{
-		repo:    repo,
-		service: service,
+		repo:         repo,
+		revisionRepo: revisionRepo,
+		events:       events,
+		service:      service,
 	}
 }
 
@@ -34,22 +61,80 @@ func TestServiceCreateNote(t *testing.T) {
 
 	tests := []struct {
 		arrange func(t *testing.T, s *serviceSuite)
-		assert  func(t *testing.T, id string, err error)
+		assert  func(t *testing.T, id core.ID, err error)
 		name    string
 	}{
 		{
 			name: "should create successfully",
 			arrange: func(t *testing.T, s *serviceSuite) {
 				s.repo.On("Create", t.Context(), mock.MatchedBy(func(n *domain.Note) bool {
-					return n.Title == title &&
+					return n.ID == noteID &&
+						n.CreatedAt.Equal(now) &&
+						n.Title == title &&
 						n.Content == content
 				})).
 					Return(nil).
 					Once()
+
+				s.revisionRepo.On("CreateRevision", t.Context(), domain.NoteRevision{
+					NoteID:    noteID,
+					Version:   1,
+					Title:     title,
+					Content:   content,
+					CreatedAt: now,
+				}).
+					Return(nil).
+					Once()
+
+				s.events.On("Publish", t.Context(), mock.MatchedBy(func(e domain.Event) bool {
+					return e.Type == domain.EventNoteCreated &&
+						e.Note.ID == noteID &&
+						e.CreatedAt.Equal(now)
+				})).
+					Return(nil).
+					Once()
 			},
-			assert: func(t *testing.T, id string, err error) {
+			assert: func(t *testing.T, id core.ID, err error) {
 				require.NoError(t, err)
-				assert.NotEmpty(t, id)
+				assert.Equal(t, noteID, id)
+			},
+		},
+		{
+			name: "should fail when the event cannot be published",
+			arrange: func(t *testing.T, s *serviceSuite) {
+				s.repo.On("Create", mock.Anything, mock.Anything).
+					Return(nil).
+					Once()
+
+				s.revisionRepo.On("CreateRevision", mock.Anything, mock.Anything).
+					Return(nil).
+					Once()
+
+				s.events.On("Publish", mock.Anything, mock.Anything).
+					Return(errors.New("queue down")).
+					Once()
+			},
+			assert: func(t *testing.T, id core.ID, err error) {
+				assert.Empty(t, id)
+				require.Error(t, err)
+				assert.True(t, domain.ErrNoteEventPublishFailed.Is(err))
+			},
+		},
+		{
+			name: "should fail when the first revision cannot be recorded",
+			arrange: func(t *testing.T, s *serviceSuite) {
+				s.repo.On("Create", mock.Anything, mock.Anything).
+					Return(nil).
+					Once()
+
+				s.revisionRepo.On("CreateRevision", mock.Anything, mock.Anything).
+					Return(errors.New("unhealthy repo")).
+					Once()
+			},
+			assert: func(t *testing.T, id core.ID, err error) {
+				assert.Empty(t, id)
+				require.Error(t, err)
+				assert.True(t, domain.ErrNoteRevisionSaveFailed.Is(err))
 			},
 		},
 		{
@@ -59,9 +144,23 @@ func TestServiceCreateNote(t *testing.T) {
 					Return(errors.New("unhealthy repo")).
 					Once()
 			},
-			assert: func(t *testing.T, id string, err error) {
+			assert: func(t *testing.T, id core.ID, err error) {
 				assert.Empty(t, id)
 				require.Error(t, err)
+				assert.True(t, domain.ErrNoteCreateFailed.Is(err))
+			},
+		},
+		{
+			name: "should propagate translated datasource errors",
+			arrange: func(t *testing.T, s *serviceSuite) {
+				s.repo.On("Create", mock.Anything, mock.Anything).
+					Return(postgres.ErrUniqueViolation.New()).
+					Once()
+			},
+			assert: func(t *testing.T, id core.ID, err error) {
+				assert.Empty(t, id)
+				require.Error(t, err)
+				assert.True(t, apperr.IsConflict(err))
 			},
 		},
 	}
@@ -75,9 +174,78 @@ func TestServiceCreateNote(t *testing.T) {
 				tc.arrange(t, s)
 			}
 
-			id, err := s.service.CreateNote(t.Context(), title, content)
+			id, err := s.service.CreateNote(t.Context(), title, content, "")
 
 			tc.assert(t, id, err)
 		})
 	}
 }
+
+func TestServiceCreateNoteRejectsInvalidNotes(t *testing.T) {
+	s := setupServiceSuite(t)
+
+	id, err := s.service.CreateNote(t.Context(), "   ", "content", "")
+
+	assert.Empty(t, id)
+	require.Error(t, err)
+	assert.True(t, domain.ErrNoteTitleBlank.Is(err))
+	s.repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestServiceCreateNoteFormat(t *testing.T) {
+	t.Run("should store the requested format", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		s.repo.On("Create", t.Context(), mock.MatchedBy(func(n *domain.Note) bool {
+			return n.Format == domain.NoteFormatMarkdown
+		})).
+			Return(nil).
+			Once()
+
+		s.revisionRepo.On("CreateRevision", t.Context(), mock.Anything).
+			Return(nil).
+			Once()
+
+		s.events.On("Publish", t.Context(), mock.Anything).
+			Return(nil).
+			Once()
+
+		_, err := s.service.CreateNote(t.Context(), "Title", "# Content", "markdown")
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should reject unknown formats", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		_, err := s.service.CreateNote(t.Context(), "Title", "Content", "html")
+
+		require.Error(t, err)
+		assert.True(t, domain.ErrNoteFormatInvalid.Is(err))
+		s.repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestServiceCreateNoteOwner(t *testing.T) {
+	s := setupServiceSuite(t)
+
+	ctx := auth.WithOwner(t.Context(), "acme")
+
+	s.repo.On("Create", ctx, mock.MatchedBy(func(n *domain.Note) bool {
+		return n.OwnerID == "acme"
+	})).
+		Return(nil).
+		Once()
+
+	s.revisionRepo.On("CreateRevision", ctx, mock.Anything).
+		Return(nil).
+		Once()
+
+	s.events.On("Publish", ctx, mock.Anything).
+		Return(nil).
+		Once()
+
+	_, err := s.service.CreateNote(ctx, "Title", "Content", "")
+
+	require.NoError(t, err)
+}
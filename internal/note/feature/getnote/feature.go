@@ -0,0 +1,18 @@
+package getnote
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/cache"
+	"HATCH_APP/pkg/markdown"
+	"time"
+)
+
+type Feature struct {
+	service *Service
+}
+
+func New(noteRepo domain.NoteRepository, renderer *markdown.Renderer, c cache.Cache, renderTTL time.Duration) *Feature {
+	return &Feature{
+		service: NewService(noteRepo, renderer, c, renderTTL),
+	}
+}
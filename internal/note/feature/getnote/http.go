@@ -0,0 +1,77 @@
+package getnote
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/markdown"
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/transport/httpx"
+	"net/http"
+)
+
+// RenderHTML is the only render option, the content is returned raw without
+// it.
+const RenderHTML = "html"
+
+type Params struct {
+	ID     core.ID `path:"id"`
+	Render string  `query:"render" validate:"omitempty,oneof=html"`
+}
+
+type Response struct {
+	Message string       `json:"message"`
+	Data    ResponseData `json:"data"`
+}
+
+type ResponseData struct {
+	*domain.Note
+	// Rendered is only set with render=html.
+	Rendered *markdown.Document `json:"rendered,omitempty"`
+}
+
+func (f *Feature) GetNoteEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "GetNote")
+
+	id, err := httpx.PathID(w, r, "id")
+	if err != nil {
+		log.WarnContext(ctx, "invalid id", "error", err)
+		return
+	}
+
+	log = log.With("note_id", id)
+
+	render := r.URL.Query().Get("render")
+	if render != "" && render != RenderHTML {
+		httpx.WriteError(log, w, domain.ErrNoteRenderInvalid.New().WithDetails(map[string]any{
+			"render":  render,
+			"allowed": []string{RenderHTML},
+		}))
+
+		return
+	}
+
+	note, err := f.service.GetNote(ctx, id)
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	data := ResponseData{Note: note}
+
+	if render == RenderHTML {
+		doc, err := f.service.RenderNote(ctx, note)
+		if err != nil {
+			httpx.WriteError(log, w, err)
+			return
+		}
+
+		data.Rendered = &doc
+	}
+
+	httpx.WriteOKResponse(w, Response{
+		Message: "note found",
+		Data:    data,
+	})
+}
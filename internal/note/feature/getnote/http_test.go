@@ -0,0 +1,148 @@
+package getnote_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/feature/getnote"
+	"HATCH_APP/internal/note/infra/store/postgres"
+	"HATCH_APP/pkg/cache/memory"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/markdown"
+	"HATCH_APP/pkg/transport/httpx"
+	"HATCH_APP/test/container"
+	"HATCH_APP/test/httptest"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type httpSuite struct {
+	repo *postgres.NoteRepository
+	feat *getnote.Feature
+}
+
+func setupHTTPSuite(t *testing.T) *httpSuite {
+	db, dbTeardown := container.SetupPostgres(t)
+
+	t.Cleanup(func() {
+		dbTeardown()
+	})
+
+	repo, err := postgres.NewNoteRepository(db)
+	require.NoError(t, err)
+
+	return &httpSuite{
+		repo: repo,
+		feat: getnote.New(repo, markdown.NewRenderer(), memory.NewLRU(10), time.Hour),
+	}
+}
+
+func (s *httpSuite) createNote(t *testing.T, format domain.NoteFormat, content string) *domain.Note {
+	n, err := domain.NewNote(core.NewID(), time.Now(), "Test Note", content)
+	require.NoError(t, err)
+
+	n.Format = format
+
+	require.NoError(t, s.repo.Create(t.Context(), n))
+
+	return n
+}
+
+func TestGetNoteEndpoint(t *testing.T) {
+	s := setupHTTPSuite(t)
+	httptest.Init()
+
+	get := func(id, query string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/notes/"+id+query)
+
+		return httptest.WithParam(req, "id", id)
+	}
+
+	tests := []struct {
+		tc   httptest.Case
+		name string
+	}{
+		{
+			name: "should return the raw note",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					n := s.createNote(t, domain.NoteFormatMarkdown, "# Heading")
+
+					return get(n.ID.String(), "")
+				},
+				ExpectStatus: http.StatusOK,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[getnote.Response](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, "# Heading", resp.Data.Content)
+					assert.Equal(t, domain.NoteFormatMarkdown, resp.Data.Format)
+					assert.Nil(t, resp.Data.Rendered)
+				},
+			},
+		},
+		{
+			name: "should render sanitized html with metadata",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					n := s.createNote(t, domain.NoteFormatMarkdown,
+						"# Heading\n\n[link](https://example.com) <img src=x onerror=alert(1)>")
+
+					return get(n.ID.String(), "?render=html")
+				},
+				ExpectStatus: http.StatusOK,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[getnote.Response](body)
+
+					require.NoError(t, err)
+					require.NotNil(t, resp.Data.Rendered)
+					assert.Contains(t, resp.Data.Rendered.HTML, `rel="nofollow"`)
+					assert.NotContains(t, resp.Data.Rendered.HTML, "onerror")
+					assert.Equal(t, []markdown.Heading{{Level: 1, Text: "Heading", ID: "heading"}}, resp.Data.Rendered.Headings)
+					assert.Equal(t, 2, resp.Data.Rendered.WordCount)
+				},
+			},
+		},
+		{
+			name: "should return 400 when render is unknown",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					n := s.createNote(t, domain.NoteFormatPlain, "content")
+
+					return get(n.ID.String(), "?render=pdf")
+				},
+				ExpectStatus: http.StatusBadRequest,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrNoteRenderInvalid.ID, resp.Code)
+				},
+			},
+		},
+		{
+			name: "should return 404 when note not found",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					return get(core.NewID().String(), "")
+				},
+				ExpectStatus: http.StatusNotFound,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrNoteNotFound.ID, resp.Code)
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			httptest.Run(t, s.feat.GetNoteEndpoint, tc.tc)
+		})
+	}
+}
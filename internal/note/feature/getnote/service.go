@@ -0,0 +1,68 @@
+package getnote
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/cache"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/markdown"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+const renderKeyPrefix = "note:render:"
+
+type Service struct {
+	noteRepo  domain.NoteRepository
+	renderer  *markdown.Renderer
+	cache     cache.Cache
+	renderTTL time.Duration
+}
+
+func NewService(noteRepo domain.NoteRepository, renderer *markdown.Renderer, c cache.Cache, renderTTL time.Duration) *Service {
+	return &Service{
+		noteRepo:  noteRepo,
+		renderer:  renderer,
+		cache:     c,
+		renderTTL: renderTTL,
+	}
+}
+
+func (s *Service) GetNote(ctx context.Context, id core.ID) (*domain.Note, error) {
+	note, err := s.noteRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, domain.ErrNoteFindFailed.Propagate(err)
+	}
+
+	if note == nil {
+		return nil, domain.ErrNoteNotFound.New()
+	}
+
+	return note, nil
+}
+
+// RenderNote renders the content of the note as HTML according to its format.
+// Documents are cached by content hash, so edits never need to invalidate
+// them and notes sharing a content share the entry.
+func (s *Service) RenderNote(ctx context.Context, note *domain.Note) (markdown.Document, error) {
+	doc, err := cache.GetOrLoadJSON(ctx, s.cache, renderKey(note), s.renderTTL,
+		func(context.Context) (markdown.Document, error) {
+			if note.Format == domain.NoteFormatMarkdown {
+				return s.renderer.Markdown(note.Content)
+			}
+
+			return s.renderer.Plain(note.Content), nil
+		})
+	if err != nil {
+		return markdown.Document{}, domain.ErrNoteRenderFailed.Propagate(err)
+	}
+
+	return doc, nil
+}
+
+func renderKey(note *domain.Note) string {
+	sum := sha256.Sum256([]byte(markdown.Version + ":" + string(note.Format) + ":" + note.Content))
+
+	return renderKeyPrefix + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,122 @@
+package getnote_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/feature/getnote"
+	"HATCH_APP/internal/note/mocks"
+	"HATCH_APP/pkg/cache/memory"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/markdown"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type serviceSuite struct {
+	repo    *mocks.NoteRepository
+	service *getnote.Service
+}
+
+func setupServiceSuite(t *testing.T) *serviceSuite {
+	repo := mocks.NewNoteRepository(t)
+
+	return &serviceSuite{
+		repo:    repo,
+		service: getnote.NewService(repo, markdown.NewRenderer(), memory.NewLRU(10), time.Hour),
+	}
+}
+
+func TestServiceGetNote(t *testing.T) {
+	t.Run("should return the note", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		n, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+		require.NoError(t, err)
+
+		s.repo.On("FindByID", t.Context(), n.ID).
+			Return(n, nil).
+			Once()
+
+		found, err := s.service.GetNote(t.Context(), n.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, n, found)
+	})
+
+	t.Run("should return not found when note does not exist", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		id := core.NewID()
+
+		s.repo.On("FindByID", t.Context(), id).
+			Return((*domain.Note)(nil), nil).
+			Once()
+
+		_, err := s.service.GetNote(t.Context(), id)
+
+		require.Error(t, err)
+		assert.True(t, domain.ErrNoteNotFound.Is(err))
+	})
+
+	t.Run("should return error when FindByID fails", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		id := core.NewID()
+
+		s.repo.On("FindByID", t.Context(), id).
+			Return((*domain.Note)(nil), errors.New("db error")).
+			Once()
+
+		_, err := s.service.GetNote(t.Context(), id)
+
+		require.Error(t, err)
+		assert.True(t, domain.ErrNoteFindFailed.Is(err))
+	})
+}
+
+func TestServiceRenderNote(t *testing.T) {
+	newNote := func(t *testing.T, format domain.NoteFormat, content string) *domain.Note {
+		n, err := domain.NewNote(core.NewID(), time.Now(), "title", content)
+		require.NoError(t, err)
+
+		n.Format = format
+
+		return n
+	}
+
+	t.Run("should render markdown notes", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		doc, err := s.service.RenderNote(t.Context(), newNote(t, domain.NoteFormatMarkdown, "# Title\n\n<script>x</script>body"))
+
+		require.NoError(t, err)
+		assert.Contains(t, doc.HTML, `<h1 id="title">Title</h1>`)
+		assert.NotContains(t, doc.HTML, "<script")
+		assert.Equal(t, []markdown.Heading{{Level: 1, Text: "Title", ID: "title"}}, doc.Headings)
+	})
+
+	t.Run("should escape plain notes", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		doc, err := s.service.RenderNote(t.Context(), newNote(t, domain.NoteFormatPlain, "# <b>not markdown</b>"))
+
+		require.NoError(t, err)
+		assert.Equal(t, "<p># &lt;b&gt;not markdown&lt;/b&gt;</p>\n", doc.HTML)
+	})
+
+	t.Run("should key the cache by format and content", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		plain, err := s.service.RenderNote(t.Context(), newNote(t, domain.NoteFormatPlain, "# same"))
+		require.NoError(t, err)
+
+		md, err := s.service.RenderNote(t.Context(), newNote(t, domain.NoteFormatMarkdown, "# same"))
+		require.NoError(t, err)
+
+		cached, err := s.service.RenderNote(t.Context(), newNote(t, domain.NoteFormatMarkdown, "# same"))
+		require.NoError(t, err)
+
+		assert.NotEqual(t, plain.HTML, md.HTML)
+		assert.Equal(t, md, cached)
+	})
+}
@@ -0,0 +1,48 @@
+package listnotes
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/pb"
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/transport/grpcx"
+	"context"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (f *Feature) ListNotesRPC(ctx context.Context, req *pb.ListNotesRequest) (*pb.ListNotesResponse, error) {
+	log := o11y.LoggerFromContext(ctx).With("rpc", "ListNotes")
+
+	notes, err := f.service.ListNotes(ctx, req.GetTags(), domain.TagMatch(req.GetMatch()))
+	if err != nil {
+		return nil, grpcx.Error(log, err)
+	}
+
+	resp := &pb.ListNotesResponse{
+		Notes: make([]*pb.Note, 0, len(notes)),
+	}
+
+	for _, n := range notes {
+		resp.Notes = append(resp.Notes, toProto(n))
+	}
+
+	return resp, nil
+}
+
+func toProto(n *domain.Note) *pb.Note {
+	note := &pb.Note{
+		Id:        n.ID.String(),
+		Title:     n.Title,
+		Content:   n.Content,
+		Status:    string(n.Status),
+		Format:    string(n.Format),
+		CreatedAt: timestamppb.New(n.CreatedAt),
+		Tags:      n.Tags,
+	}
+
+	if n.UpdatedAt != nil {
+		note.UpdatedAt = timestamppb.New(*n.UpdatedAt)
+	}
+
+	return note
+}
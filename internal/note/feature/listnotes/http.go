@@ -8,6 +8,13 @@ import (
 	"net/http"
 )
 
+// Params filters the listed notes by tag, matching any of the tags unless
+// match is all.
+type Params struct {
+	Match domain.TagMatch `query:"match"`
+	Tags  []string        `query:"tag"`
+}
+
 type Response struct {
 	Message string         `json:"message"`
 	Data    []*domain.Note `json:"data"`
@@ -18,7 +25,9 @@ func (f *Feature) ListNotesEndpoint(w http.ResponseWriter, r *http.Request) {
 
 	log := o11y.LoggerFromContext(ctx).With("endpoint", "ListNotes")
 
-	notes, err := f.service.ListNotes(ctx)
+	query := r.URL.Query()
+
+	notes, err := f.service.ListNotes(ctx, query["tag"], domain.TagMatch(query.Get("match")))
 	if err != nil {
 		httpx.WriteError(log, w, err)
 		return
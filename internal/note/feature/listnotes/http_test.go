@@ -4,10 +4,13 @@ import (
 	"HATCH_APP/internal/note/domain"
 	"HATCH_APP/internal/note/feature/listnotes"
 	"HATCH_APP/internal/note/infra/store/postgres"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/transport/httpx"
 	"HATCH_APP/test/container"
 	"HATCH_APP/test/httptest"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -62,10 +65,12 @@ func TestListNotesEndpoint(t *testing.T) {
 			name: "should list notes successfully",
 			tc: httptest.Case{
 				ArrangeRequest: func() *http.Request {
-					note1 := domain.NewNote("First Note", "First Content")
-					note2 := domain.NewNote("Second Note", "Second Content")
+					note1, err := domain.NewNote(core.NewID(), time.Now(), "First Note", "First Content")
+					require.NoError(t, err)
+					note2, err := domain.NewNote(core.NewID(), time.Now(), "Second Note", "Second Content")
+					require.NoError(t, err)
 
-					err := s.repo.Create(t.Context(), note1)
+					err = s.repo.Create(t.Context(), note1)
 					require.NoError(t, err)
 
 					err = s.repo.Create(t.Context(), note2)
@@ -83,6 +88,50 @@ func TestListNotesEndpoint(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "should filter notes by any or all tags",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					for _, names := range [][]string{{"work", "urgent"}, {"work"}, {"home"}} {
+						n, err := domain.NewNote(core.NewID(), time.Now(), "Tagged Note", "Content")
+						require.NoError(t, err)
+						require.NoError(t, s.repo.Create(t.Context(), n))
+
+						tags := make([]domain.Tag, 0, len(names))
+						for _, name := range names {
+							tags = append(tags, domain.Tag{ID: core.NewID(), Name: name, CreatedAt: time.Now()})
+						}
+
+						require.NoError(t, s.repo.AddTags(t.Context(), n.ID, tags))
+					}
+
+					return httptest.NewRequest(http.MethodGet, "/api/v1/notes?tag=Work&tag=urgent&match=all")
+				},
+				ExpectStatus: http.StatusOK,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[listnotes.Response](body)
+
+					require.NoError(t, err)
+					require.Len(t, resp.Data, 1)
+					assert.Equal(t, []string{"urgent", "work"}, resp.Data[0].Tags)
+				},
+			},
+		},
+		{
+			name: "should return 400 when match is unknown",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					return httptest.NewRequest(http.MethodGet, "/api/v1/notes?tag=work&match=some")
+				},
+				ExpectStatus: http.StatusBadRequest,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrNoteFilterInvalid.ID, resp.Code)
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -2,7 +2,6 @@ package listnotes
 
 import (
 	"HATCH_APP/internal/note/domain"
-	"HATCH_APP/pkg/core/apperr"
 	"context"
 )
 
@@ -16,10 +15,15 @@ func NewService(noteRepo domain.NoteRepository) *Service {
 	}
 }
 
-func (s *Service) ListNotes(ctx context.Context) ([]*domain.Note, error) {
-	notes, err := s.noteRepo.List(ctx)
+func (s *Service) ListNotes(ctx context.Context, tags []string, match domain.TagMatch) ([]*domain.Note, error) {
+	filter, err := domain.NewNoteFilter(tags, match)
 	if err != nil {
-		return nil, apperr.Internal("failed to list notes", err)
+		return nil, err
+	}
+
+	notes, err := s.noteRepo.List(ctx, filter)
+	if err != nil {
+		return nil, domain.ErrNoteListFailed.Propagate(err)
 	}
 
 	return notes, nil
@@ -4,10 +4,13 @@ import (
 	"HATCH_APP/internal/note/domain"
 	"HATCH_APP/internal/note/feature/listnotes"
 	"HATCH_APP/internal/note/mocks"
+	"HATCH_APP/pkg/core"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -32,16 +35,21 @@ func TestServiceListNotes(t *testing.T) {
 		arrange func(t *testing.T, s *suite)
 		assert  func(t *testing.T, notes []*domain.Note, err error)
 		name    string
+		match   domain.TagMatch
+		tags    []string
 	}{
 		{
 			name: "should list notes successfully",
 			arrange: func(t *testing.T, s *suite) {
-				ns := []*domain.Note{
-					domain.NewNote("title1", "content1"),
-					domain.NewNote("title2", "content2"),
-				}
+				n1, err := domain.NewNote(core.NewID(), time.Now(), "title1", "content1")
+				require.NoError(t, err)
+
+				n2, err := domain.NewNote(core.NewID(), time.Now(), "title2", "content2")
+				require.NoError(t, err)
 
-				s.repo.On("List", t.Context()).
+				ns := []*domain.Note{n1, n2}
+
+				s.repo.On("List", t.Context(), domain.NoteFilter{Tags: []string{}, Match: domain.TagMatchAny}).
 					Return(ns, nil).
 					Once()
 			},
@@ -55,13 +63,50 @@ func TestServiceListNotes(t *testing.T) {
 		{
 			name: "should return error when List fails",
 			arrange: func(t *testing.T, s *suite) {
-				s.repo.On("List", t.Context()).
+				s.repo.On("List", t.Context(), mock.Anything).
 					Return(nil, errors.New("db error")).
 					Once()
 			},
 			assert: func(t *testing.T, notes []*domain.Note, err error) {
 				assert.Zero(t, notes)
 				require.Error(t, err)
+				assert.True(t, domain.ErrNoteListFailed.Is(err))
+			},
+		},
+		{
+			name:  "should pass normalized tags to the repository",
+			tags:  []string{" Work ", "work", "Side Project"},
+			match: domain.TagMatchAll,
+			arrange: func(t *testing.T, s *suite) {
+				s.repo.On("List", t.Context(), domain.NoteFilter{
+					Tags:  []string{"work", "side-project"},
+					Match: domain.TagMatchAll,
+				}).
+					Return([]*domain.Note{}, nil).
+					Once()
+			},
+			assert: func(t *testing.T, notes []*domain.Note, err error) {
+				require.NoError(t, err)
+				assert.Empty(t, notes)
+			},
+		},
+		{
+			name:  "should reject unknown matches",
+			tags:  []string{"work"},
+			match: "some",
+			assert: func(t *testing.T, notes []*domain.Note, err error) {
+				assert.Nil(t, notes)
+				require.Error(t, err)
+				assert.True(t, domain.ErrNoteFilterInvalid.Is(err))
+			},
+		},
+		{
+			name: "should reject invalid tags",
+			tags: []string{"no!"},
+			assert: func(t *testing.T, notes []*domain.Note, err error) {
+				assert.Nil(t, notes)
+				require.Error(t, err)
+				assert.True(t, domain.ErrNoteTagInvalid.Is(err))
 			},
 		},
 	}
@@ -75,7 +120,7 @@ func TestServiceListNotes(t *testing.T) {
 				tc.arrange(t, s)
 			}
 
-			notes, err := s.service.ListNotes(t.Context())
+			notes, err := s.service.ListNotes(t.Context(), tc.tags, tc.match)
 
 			tc.assert(t, notes, err)
 		})
@@ -0,0 +1,31 @@
+package listtags
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/transport/httpx"
+	"fmt"
+	"net/http"
+)
+
+type Response struct {
+	Message string            `json:"message"`
+	Data    []domain.TagCount `json:"data"`
+}
+
+func (f *Feature) ListTagsEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "ListTags")
+
+	tags, err := f.service.ListTags(ctx)
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	httpx.WriteOKResponse(w, Response{
+		Message: fmt.Sprintf("%d tags listed", len(tags)),
+		Data:    tags,
+	})
+}
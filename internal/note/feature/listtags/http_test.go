@@ -0,0 +1,65 @@
+package listtags_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/feature/listtags"
+	"HATCH_APP/internal/note/infra/store/postgres"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/test/container"
+	"HATCH_APP/test/httptest"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListTagsEndpoint(t *testing.T) {
+	db, dbTeardown := container.SetupPostgres(t)
+
+	t.Cleanup(func() {
+		dbTeardown()
+	})
+
+	repo, err := postgres.NewNoteRepository(db)
+	require.NoError(t, err)
+
+	feat := listtags.New(repo)
+
+	httptest.Init()
+
+	httptest.Run(t, feat.ListTagsEndpoint, httptest.Case{
+		ArrangeRequest: func() *http.Request {
+			for i, names := range [][]string{{"work", "home"}, {"work"}, {"trashed"}} {
+				n, err := domain.NewNote(core.NewID(), time.Now(), "Note", "Content")
+				require.NoError(t, err)
+
+				if i == 2 {
+					require.NoError(t, n.Trash(time.Now()))
+				}
+
+				require.NoError(t, repo.Create(t.Context(), n))
+
+				tags := make([]domain.Tag, 0, len(names))
+				for _, name := range names {
+					tags = append(tags, domain.Tag{ID: core.NewID(), Name: name, CreatedAt: time.Now()})
+				}
+
+				require.NoError(t, repo.AddTags(t.Context(), n.ID, tags))
+			}
+
+			return httptest.NewRequest(http.MethodGet, "/api/v1/notes/tags")
+		},
+		ExpectStatus: http.StatusOK,
+		CheckResponse: func(t *testing.T, body []byte) {
+			resp, err := httptest.ParseResponse[listtags.Response](body)
+
+			require.NoError(t, err)
+			assert.Equal(t, []domain.TagCount{
+				{Name: "home", Count: 1},
+				{Name: "work", Count: 2},
+			}, resp.Data, "tags only used by trashed notes are left out")
+		},
+	})
+}
@@ -0,0 +1,27 @@
+package listtags
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/shared/auth"
+	"context"
+)
+
+type Service struct {
+	noteRepo domain.NoteRepository
+}
+
+func NewService(noteRepo domain.NoteRepository) *Service {
+	return &Service{
+		noteRepo: noteRepo,
+	}
+}
+
+// ListTags lists the tags of the owner of ctx.
+func (s *Service) ListTags(ctx context.Context) ([]domain.TagCount, error) {
+	tags, err := s.noteRepo.ListTags(ctx, auth.OwnerFromContext(ctx))
+	if err != nil {
+		return nil, domain.ErrNoteTagListFailed.Propagate(err)
+	}
+
+	return tags, nil
+}
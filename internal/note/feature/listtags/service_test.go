@@ -0,0 +1,81 @@
+package listtags_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/feature/listtags"
+	"HATCH_APP/internal/note/mocks"
+	"HATCH_APP/internal/shared/auth"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type suite struct {
+	repo    *mocks.NoteRepository
+	service *listtags.Service
+}
+
+func setupSuite(t *testing.T) *suite {
+	repo := mocks.NewNoteRepository(t)
+
+	service := listtags.NewService(repo)
+
+	return &suite{
+		repo:    repo,
+		service: service,
+	}
+}
+
+const owner = "acme"
+
+func TestServiceListTags(t *testing.T) {
+	tests := []struct {
+		arrange func(t *testing.T, s *suite)
+		assert  func(t *testing.T, tags []domain.TagCount, err error)
+		name    string
+	}{
+		{
+			name: "should list tags with counts",
+			arrange: func(t *testing.T, s *suite) {
+				s.repo.On("ListTags", mock.Anything, owner).
+					Return([]domain.TagCount{{Name: "home", Count: 1}, {Name: "work", Count: 3}}, nil).
+					Once()
+			},
+			assert: func(t *testing.T, tags []domain.TagCount, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, []domain.TagCount{{Name: "home", Count: 1}, {Name: "work", Count: 3}}, tags)
+			},
+		},
+		{
+			name: "should return error when ListTags fails",
+			arrange: func(t *testing.T, s *suite) {
+				s.repo.On("ListTags", mock.Anything, owner).
+					Return(nil, errors.New("db error")).
+					Once()
+			},
+			assert: func(t *testing.T, tags []domain.TagCount, err error) {
+				assert.Nil(t, tags)
+				require.Error(t, err)
+				assert.True(t, domain.ErrNoteTagListFailed.Is(err))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			s := setupSuite(t)
+
+			if tc.arrange != nil {
+				tc.arrange(t, s)
+			}
+
+			tags, err := s.service.ListTags(auth.WithOwner(t.Context(), owner))
+
+			tc.assert(t, tags, err)
+		})
+	}
+}
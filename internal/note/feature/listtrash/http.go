@@ -0,0 +1,31 @@
+package listtrash
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/transport/httpx"
+	"fmt"
+	"net/http"
+)
+
+type Response struct {
+	Message string         `json:"message"`
+	Data    []*domain.Note `json:"data"`
+}
+
+func (f *Feature) ListTrashEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "ListTrash")
+
+	notes, err := f.service.ListTrash(ctx)
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	httpx.WriteOKResponse(w, Response{
+		Message: fmt.Sprintf("%d notes in trash", len(notes)),
+		Data:    notes,
+	})
+}
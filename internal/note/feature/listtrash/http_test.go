@@ -0,0 +1,81 @@
+package listtrash_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/feature/listtrash"
+	"HATCH_APP/internal/note/infra/store/postgres"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/test/container"
+	"HATCH_APP/test/httptest"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type httpSuite struct {
+	repo *postgres.NoteRepository
+	feat *listtrash.Feature
+}
+
+func setupHTTPSuite(t *testing.T) *httpSuite {
+	db, dbTeardown := container.SetupPostgres(t)
+
+	t.Cleanup(func() {
+		dbTeardown()
+	})
+
+	repo, err := postgres.NewNoteRepository(db)
+	require.NoError(t, err)
+
+	return &httpSuite{
+		repo: repo,
+		feat: listtrash.New(repo),
+	}
+}
+
+func TestListTrashEndpoint(t *testing.T) {
+	s := setupHTTPSuite(t)
+	httptest.Init()
+
+	tests := []struct {
+		tc   httptest.Case
+		name string
+	}{
+		{
+			name: "should only list trashed notes",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					active, err := domain.NewNote(core.NewID(), time.Now(), "Active Note", "Content")
+					require.NoError(t, err)
+					trashed, err := domain.NewNote(core.NewID(), time.Now(), "Trashed Note", "Content")
+					require.NoError(t, err)
+					require.NoError(t, trashed.Trash(time.Now()))
+
+					require.NoError(t, s.repo.Create(t.Context(), active))
+					require.NoError(t, s.repo.Create(t.Context(), trashed))
+
+					return httptest.NewRequest(http.MethodGet, "/api/v1/notes/trash")
+				},
+				ExpectStatus: http.StatusOK,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[listtrash.Response](body)
+
+					require.NoError(t, err)
+					require.Len(t, resp.Data, 1)
+					assert.Equal(t, "Trashed Note", resp.Data[0].Title)
+					assert.Equal(t, "1 notes in trash", resp.Message)
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			httptest.Run(t, s.feat.ListTrashEndpoint, tc.tc)
+		})
+	}
+}
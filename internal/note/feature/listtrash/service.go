@@ -0,0 +1,25 @@
+package listtrash
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"context"
+)
+
+type Service struct {
+	noteRepo domain.NoteRepository
+}
+
+func NewService(noteRepo domain.NoteRepository) *Service {
+	return &Service{
+		noteRepo: noteRepo,
+	}
+}
+
+func (s *Service) ListTrash(ctx context.Context) ([]*domain.Note, error) {
+	notes, err := s.noteRepo.ListTrashed(ctx)
+	if err != nil {
+		return nil, domain.ErrNoteListFailed.Propagate(err)
+	}
+
+	return notes, nil
+}
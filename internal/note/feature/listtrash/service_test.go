@@ -0,0 +1,84 @@
+package listtrash_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/feature/listtrash"
+	"HATCH_APP/internal/note/mocks"
+	"HATCH_APP/pkg/core"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type suite struct {
+	repo    *mocks.NoteRepository
+	service *listtrash.Service
+}
+
+func setupSuite(t *testing.T) *suite {
+	repo := mocks.NewNoteRepository(t)
+
+	service := listtrash.NewService(repo)
+
+	return &suite{
+		repo:    repo,
+		service: service,
+	}
+}
+
+func TestServiceListTrash(t *testing.T) {
+	tests := []struct {
+		arrange func(t *testing.T, s *suite)
+		assert  func(t *testing.T, notes []*domain.Note, err error)
+		name    string
+	}{
+		{
+			name: "should list trashed notes successfully",
+			arrange: func(t *testing.T, s *suite) {
+				n, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+				require.NoError(t, err)
+				require.NoError(t, n.Trash(time.Now()))
+
+				s.repo.On("ListTrashed", t.Context()).
+					Return([]*domain.Note{n}, nil).
+					Once()
+			},
+			assert: func(t *testing.T, notes []*domain.Note, err error) {
+				require.NoError(t, err)
+				assert.Len(t, notes, 1)
+				assert.True(t, notes[0].IsTrashed())
+			},
+		},
+		{
+			name: "should return error when ListTrashed fails",
+			arrange: func(t *testing.T, s *suite) {
+				s.repo.On("ListTrashed", t.Context()).
+					Return(nil, errors.New("db error")).
+					Once()
+			},
+			assert: func(t *testing.T, notes []*domain.Note, err error) {
+				assert.Zero(t, notes)
+				require.Error(t, err)
+				assert.True(t, domain.ErrNoteListFailed.Is(err))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			s := setupSuite(t)
+
+			if tc.arrange != nil {
+				tc.arrange(t, s)
+			}
+
+			notes, err := s.service.ListTrash(t.Context())
+
+			tc.assert(t, notes, err)
+		})
+	}
+}
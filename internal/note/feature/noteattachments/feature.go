@@ -0,0 +1,27 @@
+package noteattachments
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/blob"
+	"HATCH_APP/pkg/core"
+)
+
+type Feature struct {
+	service *Service
+	limits  Limits
+}
+
+func New(
+	noteRepo domain.NoteRepository,
+	attachmentRepo domain.AttachmentRepository,
+	blobs blob.Store,
+	ids core.IDGenerator,
+	clock core.Clock,
+	limits Limits,
+	batchSize int,
+) *Feature {
+	return &Feature{
+		service: NewService(noteRepo, attachmentRepo, blobs, ids, clock, limits, batchSize),
+		limits:  limits,
+	}
+}
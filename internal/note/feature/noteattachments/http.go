@@ -0,0 +1,215 @@
+package noteattachments
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/transport/httpx"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// FileField is the multipart form field holding the uploaded file.
+	FileField = "file"
+	// ChecksumHeader carries the hex encoded SHA-256 of the content, sent by
+	// clients on upload and answered on download.
+	ChecksumHeader = "X-Checksum-Sha256"
+	// multipartOverhead bounds the boundaries, part headers and other fields
+	// around the file.
+	multipartOverhead = 64 << 10
+	// transferTimeout replaces the server timeouts, which are sized for JSON
+	// bodies, on uploads and downloads.
+	transferTimeout = 5 * time.Minute
+)
+
+type Response struct {
+	Message string             `json:"message"`
+	Data    *domain.Attachment `json:"data"`
+}
+
+type ListResponse struct {
+	Message string               `json:"message"`
+	Data    []*domain.Attachment `json:"data"`
+}
+
+func (f *Feature) UploadAttachmentEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "UploadAttachment")
+
+	id, err := httpx.PathID(w, r, "id")
+	if err != nil {
+		log.WarnContext(ctx, "invalid id", "error", err)
+		return
+	}
+
+	log = log.With("note_id", id)
+
+	_ = http.NewResponseController(w).SetReadDeadline(time.Now().Add(transferTimeout))
+
+	r.Body = http.MaxBytesReader(w, r.Body, f.limits.MaxSize+multipartOverhead)
+
+	part, err := f.filePart(r)
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+	defer part.Close()
+
+	attachment, err := f.service.UploadAttachment(ctx, id, Upload{
+		Content:  part,
+		Filename: part.FileName(),
+		Checksum: r.Header.Get(ChecksumHeader),
+	})
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	httpx.WriteCreatedResponse(w, Response{
+		Message: "attachment uploaded",
+		Data:    attachment,
+	})
+}
+
+// filePart skips the form fields preceding the file.
+func (f *Feature) filePart(r *http.Request) (*multipart.Part, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, domain.ErrNoteAttachmentInvalid.Wrap(err).WithDetails(map[string]string{
+			"reason": "the body is not multipart/form-data",
+		})
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, domain.ErrNoteAttachmentInvalid.New().WithDetails(map[string]string{
+				"reason": fmt.Sprintf("the %s field is missing", FileField),
+			})
+		}
+
+		if _, ok := errors.AsType[*http.MaxBytesError](err); ok {
+			return nil, f.service.tooLarge()
+		}
+
+		if err != nil {
+			return nil, domain.ErrNoteAttachmentInvalid.Wrap(err).WithDetails(map[string]string{
+				"reason": "the multipart body is malformed",
+			})
+		}
+
+		if part.FormName() == FileField {
+			return part, nil
+		}
+
+		_ = part.Close()
+	}
+}
+
+func (f *Feature) ListAttachmentsEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "ListAttachments")
+
+	id, err := httpx.PathID(w, r, "id")
+	if err != nil {
+		log.WarnContext(ctx, "invalid id", "error", err)
+		return
+	}
+
+	log = log.With("note_id", id)
+
+	attachments, err := f.service.ListAttachments(ctx, id)
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	httpx.WriteOKResponse(w, ListResponse{
+		Message: fmt.Sprintf("%d attachments listed", len(attachments)),
+		Data:    attachments,
+	})
+}
+
+func (f *Feature) DownloadAttachmentEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "DownloadAttachment")
+
+	id, err := httpx.PathID(w, r, "id")
+	if err != nil {
+		log.WarnContext(ctx, "invalid id", "error", err)
+		return
+	}
+
+	attachmentID, err := httpx.PathID(w, r, "attachmentID")
+	if err != nil {
+		log.WarnContext(ctx, "invalid attachment id", "error", err)
+		return
+	}
+
+	log = log.With("note_id", id, "attachment_id", attachmentID)
+
+	attachment, content, err := f.service.OpenAttachment(ctx, id, attachmentID)
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+	defer content.Close()
+
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(transferTimeout))
+
+	header := w.Header()
+	header.Set("Content-Type", attachment.ContentType)
+	header.Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": attachment.Filename,
+	}))
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("ETag", `"`+attachment.Checksum+`"`)
+	header.Set(ChecksumHeader, attachment.Checksum)
+
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, content); err != nil {
+		log.ErrorContext(ctx, "attachment download aborted", "error", err)
+
+		// The status is sent, dropping the connection is the only way left
+		// to tell the client.
+		panic(http.ErrAbortHandler)
+	}
+}
+
+func (f *Feature) DeleteAttachmentEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "DeleteAttachment")
+
+	id, err := httpx.PathID(w, r, "id")
+	if err != nil {
+		log.WarnContext(ctx, "invalid id", "error", err)
+		return
+	}
+
+	attachmentID, err := httpx.PathID(w, r, "attachmentID")
+	if err != nil {
+		log.WarnContext(ctx, "invalid attachment id", "error", err)
+		return
+	}
+
+	log = log.With("note_id", id, "attachment_id", attachmentID)
+
+	if err := f.service.DeleteAttachment(ctx, id, attachmentID); err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	httpx.WriteEmptyResponse(w)
+}
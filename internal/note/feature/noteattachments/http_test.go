@@ -0,0 +1,336 @@
+package noteattachments_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/feature/noteattachments"
+	"HATCH_APP/internal/note/infra/store/postgres"
+	"HATCH_APP/pkg/blob"
+	"HATCH_APP/pkg/blob/local"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/transport/httpx"
+	"HATCH_APP/test/container"
+	"HATCH_APP/test/httptest"
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type httpSuite struct {
+	repo           *postgres.NoteRepository
+	attachmentRepo *postgres.AttachmentRepository
+	blobs          blob.Store
+	feat           *noteattachments.Feature
+}
+
+func setupHTTPSuite(t *testing.T) *httpSuite {
+	db, dbTeardown := container.SetupPostgres(t)
+
+	t.Cleanup(func() {
+		dbTeardown()
+	})
+
+	repo, err := postgres.NewNoteRepository(db)
+	require.NoError(t, err)
+
+	attachmentRepo, err := postgres.NewAttachmentRepository(db)
+	require.NoError(t, err)
+
+	blobs, err := local.New(t.TempDir())
+	require.NoError(t, err)
+
+	clock := core.SystemClock()
+
+	return &httpSuite{
+		repo:           repo,
+		attachmentRepo: attachmentRepo,
+		blobs:          blobs,
+		feat: noteattachments.New(repo, attachmentRepo, blobs, core.NewULIDGenerator(clock), clock, noteattachments.Limits{
+			ContentTypes: []string{"image/*", "text/plain"},
+			MaxSize:      1 << 10,
+		}, 10),
+	}
+}
+
+func (s *httpSuite) createNote(t *testing.T) *domain.Note {
+	n, err := domain.NewNote(core.NewID(), time.Now(), "Test Note", "Test Content")
+	require.NoError(t, err)
+	require.NoError(t, s.repo.Create(t.Context(), n))
+
+	return n
+}
+
+// upload stores content through the endpoint and returns the attachment.
+func (s *httpSuite) upload(t *testing.T, noteID core.ID, content string) *domain.Attachment {
+	rec := stdhttptest.NewRecorder()
+	s.feat.UploadAttachmentEndpoint(rec, uploadRequest(noteID, "file", "hello.txt", content))
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	resp, err := httptest.ParseResponse[noteattachments.Response](rec.Body.Bytes())
+	require.NoError(t, err)
+
+	return resp.Data
+}
+
+func uploadRequest(noteID core.ID, field, filename, content string) *http.Request {
+	var body bytes.Buffer
+
+	form := multipart.NewWriter(&body)
+	_ = form.WriteField("description", "ignored")
+	part, _ := form.CreateFormFile(field, filename)
+	_, _ = part.Write([]byte(content))
+	_ = form.Close()
+
+	req := stdhttptest.NewRequest(http.MethodPost, "/api/v1/notes/"+noteID.String()+"/attachments", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	return httptest.WithParam(req, "id", noteID.String())
+}
+
+func attachmentRequest(method string, noteID, id core.ID) *http.Request {
+	req := httptest.NewRequest(method, "/api/v1/notes/"+noteID.String()+"/attachments/"+id.String())
+	req = httptest.WithParam(req, "id", noteID.String())
+
+	return httptest.WithParam(req, "attachmentID", id.String())
+}
+
+func TestUploadAttachmentEndpoint(t *testing.T) {
+	s := setupHTTPSuite(t)
+	httptest.Init()
+
+	n := s.createNote(t)
+
+	tests := []struct {
+		tc   httptest.Case
+		name string
+	}{
+		{
+			name: "should upload the file",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					return uploadRequest(n.ID, "file", "hello.txt", "hello")
+				},
+				Headers: map[string]string{
+					noteattachments.ChecksumHeader: checksum([]byte("hello")),
+				},
+				ExpectStatus: http.StatusCreated,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[noteattachments.Response](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, "attachment uploaded", resp.Message)
+					assert.Equal(t, n.ID, resp.Data.NoteID)
+					assert.Equal(t, "hello.txt", resp.Data.Filename)
+					assert.Equal(t, "text/plain; charset=utf-8", resp.Data.ContentType)
+					assert.Equal(t, int64(5), resp.Data.Size)
+					assert.NotContains(t, string(body), "storage_key")
+				},
+			},
+		},
+		{
+			name: "should return 400 when the body is not multipart",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					req := stdhttptest.NewRequest(http.MethodPost, "/", strings.NewReader("hello"))
+
+					return httptest.WithParam(req, "id", n.ID.String())
+				},
+				Headers: map[string]string{
+					"Content-Type": "text/plain",
+				},
+				ExpectStatus: http.StatusBadRequest,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrNoteAttachmentInvalid.ID, resp.Code)
+				},
+			},
+		},
+		{
+			name: "should return 400 when the file field is missing",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					return uploadRequest(n.ID, "other", "hello.txt", "hello")
+				},
+				ExpectStatus: http.StatusBadRequest,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrNoteAttachmentInvalid.ID, resp.Code)
+				},
+			},
+		},
+		{
+			name: "should return 413 when the file is too large",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					return uploadRequest(n.ID, "file", "big.txt", strings.Repeat("a", 2<<10))
+				},
+				ExpectStatus: http.StatusRequestEntityTooLarge,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrNoteAttachmentTooLarge.ID, resp.Code)
+				},
+			},
+		},
+		{
+			name: "should return 400 when the content type is not allowed",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					return uploadRequest(n.ID, "file", "notes.txt", "%PDF-1.7")
+				},
+				ExpectStatus: http.StatusBadRequest,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrNoteAttachmentTypeNotAllowed.ID, resp.Code)
+				},
+			},
+		},
+		{
+			name: "should return 404 when the note does not exist",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					return uploadRequest(core.NewID(), "file", "hello.txt", "hello")
+				},
+				ExpectStatus: http.StatusNotFound,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			httptest.Run(t, s.feat.UploadAttachmentEndpoint, tc.tc)
+		})
+	}
+}
+
+func TestListAttachmentsEndpoint(t *testing.T) {
+	s := setupHTTPSuite(t)
+	httptest.Init()
+
+	n := s.createNote(t)
+	first := s.upload(t, n.ID, "first")
+	second := s.upload(t, n.ID, "second")
+
+	httptest.Run(t, s.feat.ListAttachmentsEndpoint, httptest.Case{
+		ArrangeRequest: func() *http.Request {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/notes/"+n.ID.String()+"/attachments")
+
+			return httptest.WithParam(req, "id", n.ID.String())
+		},
+		ExpectStatus: http.StatusOK,
+		CheckResponse: func(t *testing.T, body []byte) {
+			resp, err := httptest.ParseResponse[noteattachments.ListResponse](body)
+
+			require.NoError(t, err)
+			require.Len(t, resp.Data, 2)
+			assert.Equal(t, first.ID, resp.Data[0].ID)
+			assert.Equal(t, second.ID, resp.Data[1].ID)
+		},
+	})
+}
+
+func TestDownloadAttachmentEndpoint(t *testing.T) {
+	s := setupHTTPSuite(t)
+	httptest.Init()
+
+	n := s.createNote(t)
+	a := s.upload(t, n.ID, "hello")
+
+	t.Run("should stream the file with its metadata", func(t *testing.T) {
+		rec := stdhttptest.NewRecorder()
+		s.feat.DownloadAttachmentEndpoint(rec, attachmentRequest(http.MethodGet, n.ID, a.ID))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "hello", rec.Body.String())
+		assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "5", rec.Header().Get("Content-Length"))
+		assert.Equal(t, `attachment; filename=hello.txt`, rec.Header().Get("Content-Disposition"))
+		assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, checksum([]byte("hello")), rec.Header().Get(noteattachments.ChecksumHeader))
+	})
+
+	t.Run("should return 404 when the attachment belongs to another note", func(t *testing.T) {
+		other := s.createNote(t)
+
+		httptest.Run(t, s.feat.DownloadAttachmentEndpoint, httptest.Case{
+			ArrangeRequest: func() *http.Request {
+				return attachmentRequest(http.MethodGet, other.ID, a.ID)
+			},
+			ExpectStatus: http.StatusNotFound,
+		})
+	})
+}
+
+func TestDeleteAttachmentEndpoint(t *testing.T) {
+	s := setupHTTPSuite(t)
+	httptest.Init()
+
+	n := s.createNote(t)
+	a := s.upload(t, n.ID, "hello")
+
+	httptest.Run(t, s.feat.DeleteAttachmentEndpoint, httptest.Case{
+		ArrangeRequest: func() *http.Request {
+			return attachmentRequest(http.MethodDelete, n.ID, a.ID)
+		},
+		ExpectStatus: http.StatusNoContent,
+	})
+
+	httptest.Run(t, s.feat.DeleteAttachmentEndpoint, httptest.Case{
+		ArrangeRequest: func() *http.Request {
+			return attachmentRequest(http.MethodDelete, n.ID, a.ID)
+		},
+		ExpectStatus: http.StatusNotFound,
+	})
+
+	keys, err := s.attachmentRepo.ListDeletedBlobs(t.Context(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestSweepDeletedBlobsJobIntegration(t *testing.T) {
+	s := setupHTTPSuite(t)
+	httptest.Init()
+
+	n := s.createNote(t)
+	first := s.upload(t, n.ID, "first")
+	second := s.upload(t, n.ID, "second")
+
+	require.NoError(t, n.Trash(time.Now()))
+	require.NoError(t, s.repo.Save(t.Context(), n))
+
+	purged, err := s.repo.PurgeTrashed(t.Context(), time.Now().Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, purged, 1)
+
+	keys, err := s.attachmentRepo.ListDeletedBlobs(t.Context(), 10)
+	require.NoError(t, err)
+	assert.Len(t, keys, 2, "purging the note records the blobs of its attachments")
+
+	require.NoError(t, s.feat.SweepDeletedBlobsJob(t.Context()))
+
+	for _, a := range []*domain.Attachment{first, second} {
+		key, err := domain.NewAttachment(a.ID, a.NoteID, a.CreatedAt, a.Filename, a.ContentType, a.Checksum, a.Size)
+		require.NoError(t, err)
+
+		_, err = readBlob(t, s.blobs, key.StorageKey)
+		assert.ErrorIs(t, err, blob.ErrNotFound)
+	}
+
+	keys, err = s.attachmentRepo.ListDeletedBlobs(t.Context(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
@@ -0,0 +1,14 @@
+package noteattachments
+
+import (
+	"HATCH_APP/pkg/o11y"
+	"context"
+)
+
+func (f *Feature) SweepDeletedBlobsJob(ctx context.Context) error {
+	swept, err := f.service.SweepDeletedBlobs(ctx)
+
+	o11y.LoggerFromContext(ctx).InfoContext(ctx, "deleted attachment blobs swept", "swept", swept)
+
+	return err
+}
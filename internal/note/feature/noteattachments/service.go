@@ -0,0 +1,407 @@
+package noteattachments
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/blob"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/o11y"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// sniffLength is the number of bytes http.DetectContentType looks at.
+const sniffLength = 512
+
+type Limits struct {
+	// ContentTypes lists the media types allowed once sniffed from the
+	// content, type/* allows a whole type and an empty list anything.
+	ContentTypes []string
+	MaxSize      int64
+}
+
+func (l Limits) allows(contentType string) bool {
+	if len(l.ContentTypes) == 0 {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, allowed := range l.ContentTypes {
+		if prefix, ok := strings.CutSuffix(allowed, "*"); ok && strings.HasSuffix(prefix, "/") {
+			if strings.HasPrefix(mediaType, prefix) {
+				return true
+			}
+
+			continue
+		}
+
+		if mediaType == allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+type Service struct {
+	noteRepo       domain.NoteRepository
+	attachmentRepo domain.AttachmentRepository
+	blobs          blob.Store
+	ids            core.IDGenerator
+	clock          core.Clock
+	limits         Limits
+	batchSize      int
+}
+
+func NewService(
+	noteRepo domain.NoteRepository,
+	attachmentRepo domain.AttachmentRepository,
+	blobs blob.Store,
+	ids core.IDGenerator,
+	clock core.Clock,
+	limits Limits,
+	batchSize int,
+) *Service {
+	return &Service{
+		noteRepo:       noteRepo,
+		attachmentRepo: attachmentRepo,
+		blobs:          blobs,
+		ids:            ids,
+		clock:          clock,
+		limits:         limits,
+		batchSize:      batchSize,
+	}
+}
+
+type Upload struct {
+	Content  io.Reader
+	Filename string
+	// Checksum is the hex encoded SHA-256 announced by the client, the
+	// content is checked against it when set.
+	Checksum string
+}
+
+// UploadAttachment spools the content to a temporary file first, its size,
+// type and checksum have to be known before anything is stored.
+func (s *Service) UploadAttachment(ctx context.Context, noteID core.ID, upload Upload) (*domain.Attachment, error) {
+	if err := s.findNote(ctx, noteID); err != nil {
+		return nil, err
+	}
+
+	spooled, err := s.spool(upload.Content)
+	if err != nil {
+		return nil, err
+	}
+	defer spooled.remove()
+
+	if upload.Checksum != "" && !strings.EqualFold(upload.Checksum, spooled.checksum) {
+		return nil, domain.ErrNoteAttachmentChecksumMismatch.New().WithDetails(map[string]string{
+			"actual": spooled.checksum,
+		})
+	}
+
+	attachment, err := domain.NewAttachment(
+		s.ids.NewID(),
+		noteID,
+		s.clock.Now(),
+		upload.Filename,
+		spooled.contentType,
+		spooled.checksum,
+		spooled.size,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.blobs.Put(ctx, attachment.StorageKey, spooled.file, spooled.size, spooled.contentType); err != nil {
+		return nil, domain.ErrNoteAttachmentSaveFailed.Propagate(err)
+	}
+
+	if err := s.attachmentRepo.CreateAttachment(ctx, attachment); err != nil {
+		// Without its row nothing points to the blob anymore.
+		if delErr := s.blobs.Delete(context.WithoutCancel(ctx), attachment.StorageKey); delErr != nil {
+			o11y.LoggerFromContext(ctx).WarnContext(ctx, "orphan attachment blob left behind",
+				"storage_key", attachment.StorageKey, "error", delErr)
+		}
+
+		return nil, domain.ErrNoteAttachmentSaveFailed.Propagate(err)
+	}
+
+	return attachment, nil
+}
+
+func (s *Service) ListAttachments(ctx context.Context, noteID core.ID) ([]*domain.Attachment, error) {
+	if err := s.findNote(ctx, noteID); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.attachmentRepo.ListAttachments(ctx, noteID)
+	if err != nil {
+		return nil, domain.ErrNoteAttachmentFindFailed.Propagate(err)
+	}
+
+	return attachments, nil
+}
+
+// OpenAttachment returns the content of the attachment checked against its
+// size and checksum as it is read, the caller closes it.
+func (s *Service) OpenAttachment(ctx context.Context, noteID, id core.ID) (*domain.Attachment, io.ReadCloser, error) {
+	attachment, err := s.findAttachment(ctx, noteID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, _, err := s.blobs.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, domain.ErrNoteAttachmentReadFailed.Propagate(err)
+	}
+
+	return attachment, &verifiedReader{
+		r:         blob.ExactReader(content, attachment.Size),
+		closer:    content,
+		hash:      sha256.New(),
+		checksum:  attachment.Checksum,
+		remaining: attachment.Size,
+	}, nil
+}
+
+// DeleteAttachment removes the blob right away, when that fails the sweep
+// retries later.
+func (s *Service) DeleteAttachment(ctx context.Context, noteID, id core.ID) error {
+	attachment, err := s.findAttachment(ctx, noteID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.attachmentRepo.DeleteAttachment(ctx, attachment.ID); err != nil {
+		return domain.ErrNoteAttachmentDeleteFailed.Propagate(err)
+	}
+
+	if _, err := s.deleteBlobs(ctx, []string{attachment.StorageKey}); err != nil {
+		o11y.LoggerFromContext(ctx).WarnContext(ctx, "attachment blob left for the sweep",
+			"storage_key", attachment.StorageKey, "error", err)
+	}
+
+	return nil
+}
+
+// SweepDeletedBlobs removes the blobs of deleted attachments, including the
+// ones purged along with their notes, and returns how many it removed.
+func (s *Service) SweepDeletedBlobs(ctx context.Context) (int, error) {
+	total := 0
+
+	for {
+		keys, err := s.attachmentRepo.ListDeletedBlobs(ctx, s.batchSize)
+		if err != nil {
+			return total, domain.ErrNoteAttachmentDeleteFailed.Propagate(err)
+		}
+
+		// An empty batch also ends sweeps whose batch size is not positive.
+		if len(keys) == 0 {
+			return total, nil
+		}
+
+		deleted, err := s.deleteBlobs(ctx, keys)
+		total += deleted
+
+		if err != nil {
+			return total, err
+		}
+
+		if len(keys) < s.batchSize {
+			return total, nil
+		}
+
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+	}
+}
+
+// deleteBlobs forgets the keys whose blobs are gone, keeping the others for
+// the next sweep.
+func (s *Service) deleteBlobs(ctx context.Context, keys []string) (int, error) {
+	deleted := make([]string, 0, len(keys))
+
+	var errs []error
+
+	for _, key := range keys {
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		deleted = append(deleted, key)
+	}
+
+	if err := s.attachmentRepo.ForgetDeletedBlobs(ctx, deleted); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return len(deleted), domain.ErrNoteAttachmentDeleteFailed.Propagate(err)
+	}
+
+	return len(deleted), nil
+}
+
+func (s *Service) findNote(ctx context.Context, id core.ID) error {
+	note, err := s.noteRepo.FindByID(ctx, id)
+	if err != nil {
+		return domain.ErrNoteFindFailed.Propagate(err)
+	}
+
+	if note == nil {
+		return domain.ErrNoteNotFound.New()
+	}
+
+	return nil
+}
+
+func (s *Service) findAttachment(ctx context.Context, noteID, id core.ID) (*domain.Attachment, error) {
+	attachment, err := s.attachmentRepo.FindAttachment(ctx, noteID, id)
+	if err != nil {
+		return nil, domain.ErrNoteAttachmentFindFailed.Propagate(err)
+	}
+
+	if attachment == nil {
+		return nil, domain.ErrNoteAttachmentNotFound.New()
+	}
+
+	return attachment, nil
+}
+
+type spooledFile struct {
+	file        *os.File
+	contentType string
+	checksum    string
+	size        int64
+}
+
+func (f *spooledFile) remove() {
+	_ = f.file.Close()
+	_ = os.Remove(f.file.Name())
+}
+
+// spool copies r to a temporary file rewound for reading, hashing it and
+// sniffing its content type on the way.
+func (s *Service) spool(r io.Reader) (*spooledFile, error) {
+	file, err := os.CreateTemp("", "attachment-*")
+	if err != nil {
+		return nil, domain.ErrNoteAttachmentSaveFailed.Wrap(err)
+	}
+
+	spooled := &spooledFile{file: file}
+	checksum := sha256.New()
+	head := make(sniffer, 0, sniffLength)
+
+	size, err := io.Copy(io.MultiWriter(file, checksum, &head), io.LimitReader(r, s.limits.MaxSize+1))
+	if err != nil {
+		spooled.remove()
+
+		if _, ok := errors.AsType[*http.MaxBytesError](err); ok {
+			return nil, s.tooLarge()
+		}
+
+		return nil, domain.ErrNoteAttachmentInvalid.Wrap(err).WithDetails(map[string]string{
+			"reason": "the upload could not be read",
+		})
+	}
+
+	if size > s.limits.MaxSize {
+		spooled.remove()
+
+		return nil, s.tooLarge()
+	}
+
+	spooled.size = size
+	spooled.checksum = hex.EncodeToString(checksum.Sum(nil))
+	spooled.contentType = http.DetectContentType(head)
+
+	if !s.limits.allows(spooled.contentType) {
+		spooled.remove()
+
+		return nil, domain.ErrNoteAttachmentTypeNotAllowed.New().WithDetails(map[string]any{
+			"content_type": spooled.contentType,
+			"allowed":      s.limits.ContentTypes,
+		})
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		spooled.remove()
+
+		return nil, domain.ErrNoteAttachmentSaveFailed.Wrap(err)
+	}
+
+	return spooled, nil
+}
+
+func (s *Service) tooLarge() error {
+	return domain.ErrNoteAttachmentTooLarge.New().WithDetails(map[string]int64{"max": s.limits.MaxSize})
+}
+
+// sniffer keeps the first bytes written to it.
+type sniffer []byte
+
+func (s *sniffer) Write(p []byte) (int, error) {
+	if rest := cap(*s) - len(*s); rest > 0 {
+		*s = append(*s, p[:min(rest, len(p))]...)
+	}
+
+	return len(p), nil
+}
+
+// verifiedReader fails instead of yielding the last byte of a content that
+// does not match its checksum, so a client bound by Content-Length never
+// receives a complete corrupted file.
+type verifiedReader struct {
+	r         io.Reader
+	closer    io.Closer
+	hash      hash.Hash
+	checksum  string
+	remaining int64
+	verified  bool
+}
+
+func (v *verifiedReader) Read(p []byte) (int, error) {
+	if v.remaining > 1 && int64(len(p)) >= v.remaining {
+		p = p[:v.remaining-1]
+	}
+
+	n, err := v.r.Read(p)
+	v.hash.Write(p[:n])
+	v.remaining -= int64(n)
+
+	if v.remaining == 0 && !v.verified {
+		v.verified = true
+
+		if actual := hex.EncodeToString(v.hash.Sum(nil)); actual != v.checksum {
+			return 0, domain.ErrNoteAttachmentReadFailed.New().WithDetails(map[string]string{
+				"reason":   "checksum mismatch",
+				"expected": v.checksum,
+				"actual":   actual,
+			})
+		}
+	}
+
+	if err != nil && !errors.Is(err, io.EOF) {
+		return n, domain.ErrNoteAttachmentReadFailed.Wrap(err)
+	}
+
+	return n, err
+}
+
+func (v *verifiedReader) Close() error {
+	return v.closer.Close()
+}
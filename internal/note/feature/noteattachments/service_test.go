@@ -0,0 +1,371 @@
+package noteattachments_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/feature/noteattachments"
+	"HATCH_APP/internal/note/mocks"
+	"HATCH_APP/pkg/blob"
+	"HATCH_APP/pkg/blob/local"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/core/apperr"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	now          = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	noteID       = core.MustParseID("01JWN3V0G0000000000000000A")
+	attachmentID = core.MustParseID("01JWN3V0G0000000000000000B")
+	// png is the signature of a PNG file followed by some bytes.
+	png = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{1}, 64)...)
+)
+
+type serviceSuite struct {
+	repo           *mocks.NoteRepository
+	attachmentRepo *mocks.AttachmentRepository
+	blobs          blob.Store
+	service        *noteattachments.Service
+}
+
+func setupServiceSuite(t *testing.T) *serviceSuite {
+	return setupServiceSuiteWithBatchSize(t, 2)
+}
+
+func setupServiceSuiteWithBatchSize(t *testing.T, batchSize int) *serviceSuite {
+	repo := mocks.NewNoteRepository(t)
+	attachmentRepo := mocks.NewAttachmentRepository(t)
+
+	blobs, err := local.New(t.TempDir())
+	require.NoError(t, err)
+
+	service := noteattachments.NewService(
+		repo,
+		attachmentRepo,
+		blobs,
+		core.FixedIDs(attachmentID),
+		core.FixedClock(now),
+		noteattachments.Limits{
+			ContentTypes: []string{"image/*", "text/plain"},
+			MaxSize:      100,
+		},
+		batchSize,
+	)
+
+	return &serviceSuite{
+		repo:           repo,
+		attachmentRepo: attachmentRepo,
+		blobs:          blobs,
+		service:        service,
+	}
+}
+
+func (s *serviceSuite) noteExists(t *testing.T) {
+	n, err := domain.NewNote(noteID, now, "title", "content")
+	require.NoError(t, err)
+
+	s.repo.On("FindByID", mock.Anything, noteID).
+		Return(n, nil).
+		Once()
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func readBlob(t *testing.T, blobs blob.Store, key string) ([]byte, error) {
+	r, _, err := blobs.Get(t.Context(), key)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
+
+func TestServiceUploadAttachment(t *testing.T) {
+	tests := []struct {
+		arrange func(t *testing.T, s *serviceSuite)
+		assert  func(t *testing.T, s *serviceSuite, a *domain.Attachment, err error)
+		upload  noteattachments.Upload
+		name    string
+	}{
+		{
+			name: "should store the content and its metadata",
+			upload: noteattachments.Upload{
+				Content:  bytes.NewReader(png),
+				Filename: "photos/cat.png",
+				Checksum: strings.ToUpper(checksum(png)),
+			},
+			arrange: func(t *testing.T, s *serviceSuite) {
+				s.noteExists(t)
+
+				s.attachmentRepo.On("CreateAttachment", mock.Anything, mock.MatchedBy(func(a *domain.Attachment) bool {
+					return a.ID == attachmentID && a.NoteID == noteID && a.CreatedAt.Equal(now)
+				})).
+					Return(nil).
+					Once()
+			},
+			assert: func(t *testing.T, s *serviceSuite, a *domain.Attachment, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, "cat.png", a.Filename)
+				assert.Equal(t, "image/png", a.ContentType)
+				assert.Equal(t, checksum(png), a.Checksum)
+				assert.Equal(t, int64(len(png)), a.Size)
+
+				stored, err := readBlob(t, s.blobs, a.StorageKey)
+				require.NoError(t, err)
+				assert.Equal(t, png, stored)
+			},
+		},
+		{
+			name:   "should return not found when the note does not exist",
+			upload: noteattachments.Upload{Content: bytes.NewReader(png), Filename: "cat.png"},
+			arrange: func(t *testing.T, s *serviceSuite) {
+				s.repo.On("FindByID", mock.Anything, noteID).
+					Return((*domain.Note)(nil), nil).
+					Once()
+			},
+			assert: func(t *testing.T, _ *serviceSuite, _ *domain.Attachment, err error) {
+				require.Error(t, err)
+				assert.True(t, domain.ErrNoteNotFound.Is(err))
+			},
+		},
+		{
+			name:   "should reject content over the maximum size",
+			upload: noteattachments.Upload{Content: strings.NewReader(strings.Repeat("a", 101)), Filename: "big.txt"},
+			arrange: func(t *testing.T, s *serviceSuite) {
+				s.noteExists(t)
+			},
+			assert: func(t *testing.T, _ *serviceSuite, _ *domain.Attachment, err error) {
+				require.Error(t, err)
+				assert.True(t, domain.ErrNoteAttachmentTooLarge.Is(err))
+			},
+		},
+		{
+			name: "should judge the type from the content rather than the filename",
+			upload: noteattachments.Upload{
+				Content:  strings.NewReader("%PDF-1.7 hidden"),
+				Filename: "cat.png",
+			},
+			arrange: func(t *testing.T, s *serviceSuite) {
+				s.noteExists(t)
+			},
+			assert: func(t *testing.T, _ *serviceSuite, _ *domain.Attachment, err error) {
+				require.Error(t, err)
+				assert.True(t, domain.ErrNoteAttachmentTypeNotAllowed.Is(err))
+			},
+		},
+		{
+			name: "should reject a checksum mismatch",
+			upload: noteattachments.Upload{
+				Content:  bytes.NewReader(png),
+				Filename: "cat.png",
+				Checksum: checksum([]byte("other")),
+			},
+			arrange: func(t *testing.T, s *serviceSuite) {
+				s.noteExists(t)
+			},
+			assert: func(t *testing.T, _ *serviceSuite, _ *domain.Attachment, err error) {
+				require.Error(t, err)
+				assert.True(t, domain.ErrNoteAttachmentChecksumMismatch.Is(err))
+			},
+		},
+		{
+			name:   "should reject invalid filenames",
+			upload: noteattachments.Upload{Content: bytes.NewReader(png), Filename: ".."},
+			arrange: func(t *testing.T, s *serviceSuite) {
+				s.noteExists(t)
+			},
+			assert: func(t *testing.T, _ *serviceSuite, _ *domain.Attachment, err error) {
+				require.Error(t, err)
+				assert.True(t, domain.ErrNoteAttachmentFilenameInvalid.Is(err))
+			},
+		},
+		{
+			name:   "should remove the blob when the metadata cannot be saved",
+			upload: noteattachments.Upload{Content: bytes.NewReader(png), Filename: "cat.png"},
+			arrange: func(t *testing.T, s *serviceSuite) {
+				s.noteExists(t)
+
+				s.attachmentRepo.On("CreateAttachment", mock.Anything, mock.Anything).
+					Return(errors.New("db error")).
+					Once()
+			},
+			assert: func(t *testing.T, s *serviceSuite, _ *domain.Attachment, err error) {
+				require.Error(t, err)
+				assert.True(t, domain.ErrNoteAttachmentSaveFailed.Is(err))
+
+				a, _ := domain.NewAttachment(attachmentID, noteID, now, "cat.png", "", "", 0)
+				_, err = readBlob(t, s.blobs, a.StorageKey)
+				assert.ErrorIs(t, err, blob.ErrNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			s := setupServiceSuite(t)
+
+			if tc.arrange != nil {
+				tc.arrange(t, s)
+			}
+
+			a, err := s.service.UploadAttachment(t.Context(), noteID, tc.upload)
+
+			tc.assert(t, s, a, err)
+		})
+	}
+}
+
+func storedAttachment(t *testing.T, s *serviceSuite, content []byte) *domain.Attachment {
+	a, err := domain.NewAttachment(attachmentID, noteID, now, "hello.txt", "text/plain; charset=utf-8", checksum(content), int64(len(content)))
+	require.NoError(t, err)
+
+	require.NoError(t, s.blobs.Put(t.Context(), a.StorageKey, bytes.NewReader(content), a.Size, a.ContentType))
+
+	s.attachmentRepo.On("FindAttachment", mock.Anything, noteID, attachmentID).
+		Return(a, nil).
+		Once()
+
+	return a
+}
+
+func TestServiceOpenAttachment(t *testing.T) {
+	t.Run("should stream the stored content", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		storedAttachment(t, s, []byte("hello"))
+
+		a, content, err := s.service.OpenAttachment(t.Context(), noteID, attachmentID)
+		require.NoError(t, err)
+		defer content.Close()
+
+		body, err := io.ReadAll(content)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(body))
+		assert.Equal(t, "hello.txt", a.Filename)
+	})
+
+	t.Run("should withhold the last byte of corrupted content", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		a := storedAttachment(t, s, []byte("hello"))
+		require.NoError(t, s.blobs.Put(t.Context(), a.StorageKey, strings.NewReader("jello"), 5, ""))
+
+		_, content, err := s.service.OpenAttachment(t.Context(), noteID, attachmentID)
+		require.NoError(t, err)
+		defer content.Close()
+
+		body, err := io.ReadAll(content)
+		require.Error(t, err)
+		assert.True(t, domain.ErrNoteAttachmentReadFailed.Is(err))
+		assert.Equal(t, "jell", string(body))
+	})
+
+	t.Run("should fail on truncated content", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		a := storedAttachment(t, s, []byte("hello"))
+		require.NoError(t, s.blobs.Put(t.Context(), a.StorageKey, strings.NewReader("hell"), 4, ""))
+
+		_, content, err := s.service.OpenAttachment(t.Context(), noteID, attachmentID)
+		require.NoError(t, err)
+		defer content.Close()
+
+		_, err = io.ReadAll(content)
+		require.Error(t, err)
+		assert.True(t, domain.ErrNoteAttachmentReadFailed.Is(err))
+	})
+
+	t.Run("should return not found for unknown attachments", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		s.attachmentRepo.On("FindAttachment", mock.Anything, noteID, attachmentID).
+			Return((*domain.Attachment)(nil), nil).
+			Once()
+
+		_, _, err := s.service.OpenAttachment(t.Context(), noteID, attachmentID)
+
+		require.Error(t, err)
+		assert.True(t, apperr.IsNotFound(err))
+		assert.True(t, domain.ErrNoteAttachmentNotFound.Is(err))
+	})
+}
+
+func TestServiceDeleteAttachment(t *testing.T) {
+	s := setupServiceSuite(t)
+	a := storedAttachment(t, s, []byte("hello"))
+
+	s.attachmentRepo.On("DeleteAttachment", mock.Anything, attachmentID).
+		Return(nil).
+		Once()
+
+	s.attachmentRepo.On("ForgetDeletedBlobs", mock.Anything, []string{a.StorageKey}).
+		Return(nil).
+		Once()
+
+	require.NoError(t, s.service.DeleteAttachment(t.Context(), noteID, attachmentID))
+
+	_, err := readBlob(t, s.blobs, a.StorageKey)
+	assert.ErrorIs(t, err, blob.ErrNotFound)
+}
+
+func TestServiceSweepDeletedBlobs(t *testing.T) {
+	s := setupServiceSuite(t)
+
+	keys := []string{"notes/a/attachments/1", "notes/a/attachments/2", "notes/b/attachments/3"}
+	for _, key := range keys {
+		require.NoError(t, s.blobs.Put(t.Context(), key, strings.NewReader("x"), 1, ""))
+	}
+
+	s.attachmentRepo.On("ListDeletedBlobs", mock.Anything, 2).
+		Return(keys[:2], nil).
+		Once()
+
+	s.attachmentRepo.On("ForgetDeletedBlobs", mock.Anything, keys[:2]).
+		Return(nil).
+		Once()
+
+	s.attachmentRepo.On("ListDeletedBlobs", mock.Anything, 2).
+		Return(keys[2:], nil).
+		Once()
+
+	s.attachmentRepo.On("ForgetDeletedBlobs", mock.Anything, keys[2:]).
+		Return(nil).
+		Once()
+
+	swept, err := s.service.SweepDeletedBlobs(t.Context())
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, swept)
+
+	for _, key := range keys {
+		_, err := readBlob(t, s.blobs, key)
+		assert.ErrorIs(t, err, blob.ErrNotFound)
+	}
+}
+
+func TestServiceSweepDeletedBlobsStopsOnAnEmptyBatch(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		s := setupServiceSuiteWithBatchSize(t, size)
+
+		s.attachmentRepo.On("ListDeletedBlobs", mock.Anything, size).
+			Return([]string{}, nil).
+			Once()
+
+		swept, err := s.service.SweepDeletedBlobs(t.Context())
+
+		require.NoError(t, err)
+		assert.Zero(t, swept)
+	}
+}
@@ -0,0 +1,23 @@
+package noterevisions
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/core"
+)
+
+type Feature struct {
+	service *Service
+}
+
+func New(
+	noteRepo domain.NoteRepository,
+	revisionRepo domain.RevisionRepository,
+	txManager domain.TransactionManager,
+	ids core.IDGenerator,
+	clock core.Clock,
+	retention int,
+) *Feature {
+	return &Feature{
+		service: NewService(noteRepo, revisionRepo, txManager, ids, clock, retention),
+	}
+}
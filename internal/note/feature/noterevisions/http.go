@@ -0,0 +1,172 @@
+package noterevisions
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/transport/httpx"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Summary leaves the content out of listed revisions.
+type Summary struct {
+	CreatedAt time.Time `json:"created_at"`
+	Title     string    `json:"title"`
+	Version   int       `json:"version"`
+}
+
+type ListResponse struct {
+	Message string    `json:"message"`
+	Data    []Summary `json:"data"`
+}
+
+type RevisionResponse struct {
+	Message string               `json:"message"`
+	Data    *domain.NoteRevision `json:"data"`
+}
+
+type DiffResponse struct {
+	Message string `json:"message"`
+	Data    *Diff  `json:"data"`
+}
+
+type RestoreResponse struct {
+	Message string       `json:"message"`
+	Data    *domain.Note `json:"data"`
+}
+
+func (f *Feature) ListRevisionsEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "ListRevisions")
+
+	id, err := httpx.PathID(w, r, "id")
+	if err != nil {
+		log.WarnContext(ctx, "invalid id", "error", err)
+		return
+	}
+
+	log = log.With("note_id", id)
+
+	revisions, err := f.service.ListRevisions(ctx, id)
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	summaries := make([]Summary, 0, len(revisions))
+	for _, revision := range revisions {
+		summaries = append(summaries, Summary{
+			Version:   revision.Version,
+			Title:     revision.Title,
+			CreatedAt: revision.CreatedAt,
+		})
+	}
+
+	httpx.WriteOKResponse(w, ListResponse{
+		Message: fmt.Sprintf("%d revisions listed", len(summaries)),
+		Data:    summaries,
+	})
+}
+
+func (f *Feature) GetRevisionEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "GetRevision")
+
+	id, err := httpx.PathID(w, r, "id")
+	if err != nil {
+		log.WarnContext(ctx, "invalid id", "error", err)
+		return
+	}
+
+	log = log.With("note_id", id)
+
+	version, err := domain.ParseRevisionVersion(r.PathValue("version"))
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	revision, err := f.service.GetRevision(ctx, id, version)
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	httpx.WriteOKResponse(w, RevisionResponse{
+		Message: fmt.Sprintf("revision %d found", version),
+		Data:    revision,
+	})
+}
+
+func (f *Feature) DiffRevisionsEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "DiffRevisions")
+
+	id, err := httpx.PathID(w, r, "id")
+	if err != nil {
+		log.WarnContext(ctx, "invalid id", "error", err)
+		return
+	}
+
+	log = log.With("note_id", id)
+
+	query := r.URL.Query()
+
+	from, err := domain.ParseRevisionVersion(query.Get("from"))
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	to, err := domain.ParseRevisionVersion(query.Get("to"))
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	d, err := f.service.DiffRevisions(ctx, id, from, to)
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	httpx.WriteOKResponse(w, DiffResponse{
+		Message: fmt.Sprintf("revision %d diffed against %d", to, from),
+		Data:    d,
+	})
+}
+
+func (f *Feature) RestoreRevisionEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "RestoreRevision")
+
+	id, err := httpx.PathID(w, r, "id")
+	if err != nil {
+		log.WarnContext(ctx, "invalid id", "error", err)
+		return
+	}
+
+	log = log.With("note_id", id)
+
+	version, err := domain.ParseRevisionVersion(r.PathValue("version"))
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	note, err := f.service.RestoreRevision(ctx, id, version)
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	httpx.WriteOKResponse(w, RestoreResponse{
+		Message: fmt.Sprintf("revision %d restored as version %d", version, note.Version),
+		Data:    note,
+	})
+}
@@ -0,0 +1,321 @@
+package noterevisions_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/feature/noterevisions"
+	"HATCH_APP/internal/note/infra/store/postgres"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/diff"
+	"HATCH_APP/pkg/transport/httpx"
+	"HATCH_APP/test/container"
+	"HATCH_APP/test/httptest"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type httpSuite struct {
+	repo         *postgres.NoteRepository
+	revisionRepo *postgres.RevisionRepository
+	feat         *noterevisions.Feature
+}
+
+func setupHTTPSuite(t *testing.T) *httpSuite {
+	db, dbTeardown := container.SetupPostgres(t)
+
+	t.Cleanup(func() {
+		dbTeardown()
+	})
+
+	repo, err := postgres.NewNoteRepository(db)
+	require.NoError(t, err)
+
+	revisionRepo, err := postgres.NewRevisionRepository(db)
+	require.NoError(t, err)
+
+	txManager := postgres.NewTransactionManager(db)
+
+	return &httpSuite{
+		repo:         repo,
+		revisionRepo: revisionRepo,
+		feat:         noterevisions.New(repo, revisionRepo, txManager, core.NewULIDGenerator(core.SystemClock()), core.SystemClock(), 0),
+	}
+}
+
+// createNote stores a note with one revision per content.
+func (s *httpSuite) createNote(t *testing.T, contents ...string) *domain.Note {
+	n, err := domain.NewNote(core.NewID(), time.Now(), "Test Note", contents[0])
+	require.NoError(t, err)
+	require.NoError(t, s.repo.Create(t.Context(), n))
+	require.NoError(t, domain.RecordRevision(t.Context(), s.revisionRepo, n, 0))
+
+	for _, content := range contents[1:] {
+		_, err := n.Edit(n.Title, content, time.Now())
+		require.NoError(t, err)
+		require.NoError(t, s.repo.Save(t.Context(), n))
+		require.NoError(t, domain.RecordRevision(t.Context(), s.revisionRepo, n, 0))
+	}
+
+	return n
+}
+
+func revisionRequest(method string, id core.ID, version int, suffix string) *http.Request {
+	req := httptest.NewRequest(method, "/api/v1/notes/"+id.String()+"/revisions/"+strconv.Itoa(version)+suffix)
+	req = httptest.WithParam(req, "id", id.String())
+
+	return httptest.WithParam(req, "version", strconv.Itoa(version))
+}
+
+func TestListRevisionsEndpoint(t *testing.T) {
+	s := setupHTTPSuite(t)
+	httptest.Init()
+
+	tests := []struct {
+		tc   httptest.Case
+		name string
+	}{
+		{
+			name: "should list revisions newest first",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					n := s.createNote(t, "one", "two", "three")
+
+					req := httptest.NewRequest(http.MethodGet, "/api/v1/notes/"+n.ID.String()+"/revisions")
+
+					return httptest.WithParam(req, "id", n.ID.String())
+				},
+				ExpectStatus: http.StatusOK,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[noterevisions.ListResponse](body)
+
+					require.NoError(t, err)
+					require.Len(t, resp.Data, 3)
+					assert.Equal(t, 3, resp.Data[0].Version)
+					assert.Equal(t, 1, resp.Data[2].Version)
+				},
+			},
+		},
+		{
+			name: "should return 404 when note not found",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					id := core.NewID().String()
+					req := httptest.NewRequest(http.MethodGet, "/api/v1/notes/"+id+"/revisions")
+
+					return httptest.WithParam(req, "id", id)
+				},
+				ExpectStatus: http.StatusNotFound,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrNoteNotFound.ID, resp.Code)
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			httptest.Run(t, s.feat.ListRevisionsEndpoint, tc.tc)
+		})
+	}
+}
+
+func TestGetRevisionEndpoint(t *testing.T) {
+	s := setupHTTPSuite(t)
+	httptest.Init()
+
+	tests := []struct {
+		tc   httptest.Case
+		name string
+	}{
+		{
+			name: "should get a revision",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					n := s.createNote(t, "one", "two")
+
+					return revisionRequest(http.MethodGet, n.ID, 1, "")
+				},
+				ExpectStatus: http.StatusOK,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[noterevisions.RevisionResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, 1, resp.Data.Version)
+					assert.Equal(t, "one", resp.Data.Content)
+				},
+			},
+		},
+		{
+			name: "should return 400 when version is invalid",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					n := s.createNote(t, "one")
+
+					return revisionRequest(http.MethodGet, n.ID, 0, "")
+				},
+				ExpectStatus: http.StatusBadRequest,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrNoteRevisionVersionInvalid.ID, resp.Code)
+				},
+			},
+		},
+		{
+			name: "should return 404 when revision not found",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					n := s.createNote(t, "one")
+
+					return revisionRequest(http.MethodGet, n.ID, 5, "")
+				},
+				ExpectStatus: http.StatusNotFound,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrNoteRevisionNotFound.ID, resp.Code)
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			httptest.Run(t, s.feat.GetRevisionEndpoint, tc.tc)
+		})
+	}
+}
+
+func TestDiffRevisionsEndpoint(t *testing.T) {
+	s := setupHTTPSuite(t)
+	httptest.Init()
+
+	diffRequest := func(id core.ID, query string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/notes/"+id.String()+"/revisions/diff?"+query)
+
+		return httptest.WithParam(req, "id", id.String())
+	}
+
+	tests := []struct {
+		tc   httptest.Case
+		name string
+	}{
+		{
+			name: "should diff two revisions line by line",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					n := s.createNote(t, "a\nb\nc", "a\nB\nc")
+
+					return diffRequest(n.ID, "from=1&to=2")
+				},
+				ExpectStatus: http.StatusOK,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[noterevisions.DiffResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, []diff.Line{{Op: diff.Equal, Text: "Test Note"}}, resp.Data.Title)
+					assert.Equal(t, []diff.Line{
+						{Op: diff.Equal, Text: "a"},
+						{Op: diff.Delete, Text: "b"},
+						{Op: diff.Insert, Text: "B"},
+						{Op: diff.Equal, Text: "c"},
+					}, resp.Data.Content)
+				},
+			},
+		},
+		{
+			name: "should return 400 when a version is missing",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					n := s.createNote(t, "one")
+
+					return diffRequest(n.ID, "from=1")
+				},
+				ExpectStatus: http.StatusBadRequest,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrNoteRevisionVersionInvalid.ID, resp.Code)
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			httptest.Run(t, s.feat.DiffRevisionsEndpoint, tc.tc)
+		})
+	}
+}
+
+func TestRestoreRevisionEndpoint(t *testing.T) {
+	s := setupHTTPSuite(t)
+	httptest.Init()
+
+	tests := []struct {
+		tc   httptest.Case
+		name string
+	}{
+		{
+			name: "should restore a revision as a new version",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					n := s.createNote(t, "one", "two")
+
+					return revisionRequest(http.MethodPost, n.ID, 1, "/restore")
+				},
+				ExpectStatus: http.StatusOK,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[noterevisions.RestoreResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, 3, resp.Data.Version)
+					assert.Equal(t, "one", resp.Data.Content)
+
+					found, err := s.repo.FindByID(t.Context(), resp.Data.ID)
+					require.NoError(t, err)
+					assert.Equal(t, "one", found.Content)
+
+					revisions, err := s.revisionRepo.ListRevisions(t.Context(), resp.Data.ID)
+					require.NoError(t, err)
+					assert.Len(t, revisions, 3)
+				},
+			},
+		},
+		{
+			name: "should return 404 when note not found",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					return revisionRequest(http.MethodPost, core.NewID(), 1, "/restore")
+				},
+				ExpectStatus: http.StatusNotFound,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrNoteNotFound.ID, resp.Code)
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			httptest.Run(t, s.feat.RestoreRevisionEndpoint, tc.tc)
+		})
+	}
+}
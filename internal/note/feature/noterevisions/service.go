@@ -0,0 +1,167 @@
+package noterevisions
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/diff"
+	"context"
+)
+
+type Service struct {
+	noteRepo     domain.NoteRepository
+	revisionRepo domain.RevisionRepository
+	txManager    domain.TransactionManager
+	ids          core.IDGenerator
+	clock        core.Clock
+	retention    int
+}
+
+func NewService(
+	noteRepo domain.NoteRepository,
+	revisionRepo domain.RevisionRepository,
+	txManager domain.TransactionManager,
+	ids core.IDGenerator,
+	clock core.Clock,
+	retention int,
+) *Service {
+	return &Service{
+		noteRepo:     noteRepo,
+		revisionRepo: revisionRepo,
+		txManager:    txManager,
+		ids:          ids,
+		clock:        clock,
+		retention:    retention,
+	}
+}
+
+// Diff is the line diff of the title and the content between two versions.
+type Diff struct {
+	Title   []diff.Line `json:"title"`
+	Content []diff.Line `json:"content"`
+	From    int         `json:"from"`
+	To      int         `json:"to"`
+}
+
+// ListRevisions returns the retained revisions of the note, newest first.
+func (s *Service) ListRevisions(ctx context.Context, id core.ID) ([]domain.NoteRevision, error) {
+	if _, err := s.find(ctx, s.noteRepo, id); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.revisionRepo.ListRevisions(ctx, id)
+	if err != nil {
+		return nil, domain.ErrNoteRevisionFindFailed.Propagate(err)
+	}
+
+	return revisions, nil
+}
+
+func (s *Service) GetRevision(ctx context.Context, id core.ID, version int) (*domain.NoteRevision, error) {
+	if _, err := s.find(ctx, s.noteRepo, id); err != nil {
+		return nil, err
+	}
+
+	return s.findRevision(ctx, s.revisionRepo, id, version)
+}
+
+func (s *Service) DiffRevisions(ctx context.Context, id core.ID, from, to int) (*Diff, error) {
+	if _, err := s.find(ctx, s.noteRepo, id); err != nil {
+		return nil, err
+	}
+
+	fromRevision, err := s.findRevision(ctx, s.revisionRepo, id, from)
+	if err != nil {
+		return nil, err
+	}
+
+	toRevision, err := s.findRevision(ctx, s.revisionRepo, id, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Diff{
+		From:    from,
+		To:      to,
+		Title:   diff.Lines(fromRevision.Title, toRevision.Title),
+		Content: diff.Lines(fromRevision.Content, toRevision.Content),
+	}, nil
+}
+
+// RestoreRevision edits the note back to the revision, recording it as a new
+// version so the history after it is kept.
+func (s *Service) RestoreRevision(ctx context.Context, id core.ID, version int) (*domain.Note, error) {
+	var note *domain.Note
+
+	err := s.txManager.Transact(func(input domain.TransactionManagerInput) error {
+		found, err := s.find(ctx, input.NoteRepository, id)
+		if err != nil {
+			return err
+		}
+
+		revision, err := s.findRevision(ctx, input.RevisionRepository, id, version)
+		if err != nil {
+			return err
+		}
+
+		now := s.clock.Now()
+
+		changed, err := found.Edit(revision.Title, revision.Content, now)
+		if err != nil {
+			return err
+		}
+
+		note = found
+
+		if !changed {
+			return nil
+		}
+
+		if err := input.NoteRepository.Save(ctx, found); err != nil {
+			return domain.ErrNoteSaveFailed.Propagate(err)
+		}
+
+		if err := input.Events.Publish(ctx, domain.NewEvent(s.ids.NewID(), domain.EventNoteUpdated, found, now)); err != nil {
+			return domain.ErrNoteEventPublishFailed.Wrap(err)
+		}
+
+		return domain.RecordRevision(ctx, input.RevisionRepository, found, s.retention)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return note, nil
+}
+
+func (s *Service) find(ctx context.Context, repo domain.NoteRepository, id core.ID) (*domain.Note, error) {
+	note, err := repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, domain.ErrNoteFindFailed.Propagate(err)
+	}
+
+	if note == nil {
+		return nil, domain.ErrNoteNotFound.New()
+	}
+
+	return note, nil
+}
+
+func (s *Service) findRevision(
+	ctx context.Context,
+	repo domain.RevisionRepository,
+	id core.ID,
+	version int,
+) (*domain.NoteRevision, error) {
+	revision, err := repo.FindRevision(ctx, id, version)
+	if err != nil {
+		return nil, domain.ErrNoteRevisionFindFailed.Propagate(err)
+	}
+
+	if revision == nil {
+		return nil, domain.ErrNoteRevisionNotFound.New().WithDetails(map[string]int{
+			"version": version,
+		})
+	}
+
+	return revision, nil
+}
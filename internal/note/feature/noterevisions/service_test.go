@@ -0,0 +1,248 @@
+package noterevisions_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/feature/noterevisions"
+	"HATCH_APP/internal/note/mocks"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/diff"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type serviceSuite struct {
+	repo         *mocks.NoteRepository
+	revisionRepo *mocks.RevisionRepository
+	events       *mocks.EventPublisher
+	service      *noterevisions.Service
+}
+
+var (
+	created = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	now     = created.Add(time.Hour)
+	eventID = core.NewID()
+)
+
+func setupServiceSuite(t *testing.T) *serviceSuite {
+	repo := mocks.NewNoteRepository(t)
+	revisionRepo := mocks.NewRevisionRepository(t)
+	events := mocks.NewEventPublisher(t)
+	txManager := mocks.NewTransactionManager(t)
+
+	txManager.On("Transact", mock.Anything).
+		Return(func(fn func(domain.TransactionManagerInput) error) error {
+			return fn(domain.TransactionManagerInput{
+				NoteRepository:     repo,
+				RevisionRepository: revisionRepo,
+				Events:             events,
+			})
+		}).
+		Maybe()
+
+	return &serviceSuite{
+		repo:         repo,
+		revisionRepo: revisionRepo,
+		events:       events,
+		service:      noterevisions.NewService(repo, revisionRepo, txManager, core.FixedIDs(eventID), core.FixedClock(now), 0),
+	}
+}
+
+// editedNote returns a note at version 2 along with both of its revisions.
+func editedNote(t *testing.T) (*domain.Note, domain.NoteRevision, domain.NoteRevision) {
+	n, err := domain.NewNote(core.NewID(), created, "Title", "first\nsecond")
+	require.NoError(t, err)
+
+	first := n.Revision()
+
+	_, err = n.Edit("New title", "first\nchanged", created.Add(time.Minute))
+	require.NoError(t, err)
+
+	return n, first, n.Revision()
+}
+
+func TestServiceListRevisions(t *testing.T) {
+	t.Run("should list the revisions of the note", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		n, first, second := editedNote(t)
+
+		s.repo.On("FindByID", t.Context(), n.ID).
+			Return(n, nil).
+			Once()
+
+		s.revisionRepo.On("ListRevisions", t.Context(), n.ID).
+			Return([]domain.NoteRevision{second, first}, nil).
+			Once()
+
+		revisions, err := s.service.ListRevisions(t.Context(), n.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, []domain.NoteRevision{second, first}, revisions)
+	})
+
+	t.Run("should return not found when note does not exist", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		id := core.NewID()
+
+		s.repo.On("FindByID", t.Context(), id).
+			Return((*domain.Note)(nil), nil).
+			Once()
+
+		_, err := s.service.ListRevisions(t.Context(), id)
+
+		require.Error(t, err)
+		assert.True(t, domain.ErrNoteNotFound.Is(err))
+	})
+
+	t.Run("should return error when ListRevisions fails", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		n, _, _ := editedNote(t)
+
+		s.repo.On("FindByID", t.Context(), n.ID).
+			Return(n, nil).
+			Once()
+
+		s.revisionRepo.On("ListRevisions", t.Context(), n.ID).
+			Return(nil, errors.New("db error")).
+			Once()
+
+		_, err := s.service.ListRevisions(t.Context(), n.ID)
+
+		require.Error(t, err)
+		assert.True(t, domain.ErrNoteRevisionFindFailed.Is(err))
+	})
+}
+
+func TestServiceGetRevision(t *testing.T) {
+	t.Run("should return not found for pruned versions", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		n, _, _ := editedNote(t)
+
+		s.repo.On("FindByID", t.Context(), n.ID).
+			Return(n, nil).
+			Once()
+
+		s.revisionRepo.On("FindRevision", t.Context(), n.ID, 1).
+			Return((*domain.NoteRevision)(nil), nil).
+			Once()
+
+		_, err := s.service.GetRevision(t.Context(), n.ID, 1)
+
+		require.Error(t, err)
+		assert.True(t, domain.ErrNoteRevisionNotFound.Is(err))
+	})
+}
+
+func TestServiceDiffRevisions(t *testing.T) {
+	s := setupServiceSuite(t)
+	n, first, second := editedNote(t)
+
+	s.repo.On("FindByID", t.Context(), n.ID).
+		Return(n, nil).
+		Once()
+
+	s.revisionRepo.On("FindRevision", t.Context(), n.ID, 1).
+		Return(&first, nil).
+		Once()
+
+	s.revisionRepo.On("FindRevision", t.Context(), n.ID, 2).
+		Return(&second, nil).
+		Once()
+
+	d, err := s.service.DiffRevisions(t.Context(), n.ID, 1, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, d.From)
+	assert.Equal(t, 2, d.To)
+	assert.Equal(t, []diff.Line{
+		{Op: diff.Delete, Text: "Title"},
+		{Op: diff.Insert, Text: "New title"},
+	}, d.Title)
+	assert.Equal(t, []diff.Line{
+		{Op: diff.Equal, Text: "first"},
+		{Op: diff.Delete, Text: "second"},
+		{Op: diff.Insert, Text: "changed"},
+	}, d.Content)
+}
+
+func TestServiceRestoreRevision(t *testing.T) {
+	t.Run("should restore the revision as a new version", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		n, first, _ := editedNote(t)
+
+		s.repo.On("FindByID", t.Context(), n.ID).
+			Return(n, nil).
+			Once()
+
+		s.revisionRepo.On("FindRevision", t.Context(), n.ID, 1).
+			Return(&first, nil).
+			Once()
+
+		s.repo.On("Save", t.Context(), n).
+			Return(nil).
+			Once()
+
+		s.events.On("Publish", t.Context(), mock.MatchedBy(func(event domain.Event) bool {
+			return event.ID == eventID && event.Type == domain.EventNoteUpdated && event.Note == n
+		})).
+			Return(nil).
+			Once()
+
+		s.revisionRepo.On("CreateRevision", t.Context(), domain.NoteRevision{
+			NoteID:    n.ID,
+			Version:   3,
+			Title:     first.Title,
+			Content:   first.Content,
+			CreatedAt: now,
+		}).
+			Return(nil).
+			Once()
+
+		note, err := s.service.RestoreRevision(t.Context(), n.ID, 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, note.Version)
+		assert.Equal(t, first.Content, note.Content)
+	})
+
+	t.Run("should not write when restoring the current version", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		n, _, second := editedNote(t)
+
+		s.repo.On("FindByID", t.Context(), n.ID).
+			Return(n, nil).
+			Once()
+
+		s.revisionRepo.On("FindRevision", t.Context(), n.ID, 2).
+			Return(&second, nil).
+			Once()
+
+		note, err := s.service.RestoreRevision(t.Context(), n.ID, 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, note.Version)
+	})
+
+	t.Run("should return not found for unknown versions", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		n, _, _ := editedNote(t)
+
+		s.repo.On("FindByID", t.Context(), n.ID).
+			Return(n, nil).
+			Once()
+
+		s.revisionRepo.On("FindRevision", t.Context(), n.ID, 9).
+			Return((*domain.NoteRevision)(nil), nil).
+			Once()
+
+		note, err := s.service.RestoreRevision(t.Context(), n.ID, 9)
+
+		assert.Nil(t, note)
+		require.Error(t, err)
+		assert.True(t, domain.ErrNoteRevisionNotFound.Is(err))
+	})
+}
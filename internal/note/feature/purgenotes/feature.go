@@ -0,0 +1,23 @@
+package purgenotes
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/core"
+	"time"
+)
+
+type Feature struct {
+	service *Service
+}
+
+func New(
+	txManager domain.TransactionManager,
+	ids core.IDGenerator,
+	clock core.Clock,
+	retention time.Duration,
+	batchSize int,
+) *Feature {
+	return &Feature{
+		service: NewService(txManager, ids, clock, retention, batchSize),
+	}
+}
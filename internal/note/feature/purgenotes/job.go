@@ -0,0 +1,14 @@
+package purgenotes
+
+import (
+	"HATCH_APP/pkg/o11y"
+	"context"
+)
+
+func (f *Feature) PurgeTrashedNotesJob(ctx context.Context) error {
+	purged, err := f.service.PurgeTrashedNotes(ctx)
+
+	o11y.LoggerFromContext(ctx).InfoContext(ctx, "trashed notes purged", "purged", purged)
+
+	return err
+}
@@ -0,0 +1,87 @@
+package purgenotes
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/core"
+	"context"
+	"time"
+)
+
+type Service struct {
+	txManager domain.TransactionManager
+	ids       core.IDGenerator
+	clock     core.Clock
+	retention time.Duration
+	batchSize int
+}
+
+func NewService(
+	txManager domain.TransactionManager,
+	ids core.IDGenerator,
+	clock core.Clock,
+	retention time.Duration,
+	batchSize int,
+) *Service {
+	return &Service{
+		txManager: txManager,
+		ids:       ids,
+		clock:     clock,
+		retention: retention,
+		batchSize: batchSize,
+	}
+}
+
+func (s *Service) PurgeTrashedNotes(ctx context.Context) (int, error) {
+	before := s.clock.Now().Add(-s.retention)
+
+	total := 0
+
+	for {
+		purged, err := s.purgeBatch(ctx, before)
+		if err != nil {
+			return total, domain.ErrNotePurgeFailed.Propagate(err)
+		}
+
+		total += purged
+
+		// An empty batch ends the purge whatever the batch size.
+		if purged == 0 || purged < s.batchSize {
+			return total, nil
+		}
+
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+	}
+}
+
+// purgeBatch deletes a batch of notes and publishes their events in the same
+// transaction, so every replica drops them from its cache.
+func (s *Service) purgeBatch(ctx context.Context, before time.Time) (int, error) {
+	purged := 0
+
+	err := s.txManager.Transact(func(input domain.TransactionManagerInput) error {
+		notes, err := input.NoteRepository.PurgeTrashed(ctx, before, s.batchSize)
+		if err != nil {
+			return err
+		}
+
+		now := s.clock.Now()
+
+		for _, note := range notes {
+			if err := note.TransitionTo(domain.NoteStatusPurged, now); err != nil {
+				return err
+			}
+
+			if err := input.Events.Publish(ctx, domain.NewEvent(s.ids.NewID(), domain.EventNotePurged, note, now)); err != nil {
+				return domain.ErrNoteEventPublishFailed.Wrap(err)
+			}
+		}
+
+		purged = len(notes)
+
+		return nil
+	})
+
+	return purged, err
+}
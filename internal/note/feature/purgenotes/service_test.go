@@ -0,0 +1,183 @@
+package purgenotes_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/feature/purgenotes"
+	"HATCH_APP/internal/note/mocks"
+	"HATCH_APP/pkg/core"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	retention = 24 * time.Hour
+	batchSize = 2
+)
+
+var (
+	now     = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	eventID = core.MustParseID("01JWN3V0G0000000000000000E")
+)
+
+type serviceSuite struct {
+	repo    *mocks.NoteRepository
+	events  *mocks.EventPublisher
+	service *purgenotes.Service
+}
+
+func setupSuite(t *testing.T, size int) *serviceSuite {
+	repo := mocks.NewNoteRepository(t)
+	events := mocks.NewEventPublisher(t)
+	txManager := mocks.NewTransactionManager(t)
+
+	txManager.On("Transact", mock.Anything).
+		Return(func(fn func(domain.TransactionManagerInput) error) error {
+			return fn(domain.TransactionManagerInput{
+				NoteRepository: repo,
+				Events:         events,
+			})
+		}).
+		Maybe()
+
+	service := purgenotes.NewService(txManager, core.FixedIDs(eventID), core.FixedClock(now), retention, size)
+
+	return &serviceSuite{
+		repo:    repo,
+		events:  events,
+		service: service,
+	}
+}
+
+// trashedNotes returns n notes as the repository hands them once purged.
+func trashedNotes(t *testing.T, n int) []*domain.Note {
+	notes := make([]*domain.Note, 0, n)
+
+	for range n {
+		note, err := domain.NewNote(core.NewID(), now, "title", "content")
+		require.NoError(t, err)
+		require.NoError(t, note.Trash(now))
+
+		notes = append(notes, note)
+	}
+
+	return notes
+}
+
+func (s *serviceSuite) onPurged(times int) {
+	s.events.On("Publish", mock.Anything, mock.MatchedBy(func(e domain.Event) bool {
+		return e.Type == domain.EventNotePurged && e.Note.Status == domain.NoteStatusPurged
+	})).
+		Return(nil).
+		Times(times)
+}
+
+func TestServicePurgeTrashedNotes(t *testing.T) {
+	beforeRetention := now.Add(-retention)
+
+	tests := []struct {
+		arrange func(t *testing.T, s *serviceSuite)
+		assert  func(t *testing.T, purged int, err error)
+		name    string
+	}{
+		{
+			name: "should purge in batches until a partial batch",
+			arrange: func(t *testing.T, s *serviceSuite) {
+				for range 2 {
+					s.repo.On("PurgeTrashed", t.Context(), beforeRetention, batchSize).
+						Return(trashedNotes(t, batchSize), nil).
+						Once()
+				}
+
+				s.repo.On("PurgeTrashed", t.Context(), beforeRetention, batchSize).
+					Return(trashedNotes(t, 1), nil).
+					Once()
+
+				s.onPurged(5)
+			},
+			assert: func(t *testing.T, purged int, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, 5, purged)
+			},
+		},
+		{
+			name: "should stop when there is nothing to purge",
+			arrange: func(t *testing.T, s *serviceSuite) {
+				s.repo.On("PurgeTrashed", t.Context(), beforeRetention, batchSize).
+					Return([]*domain.Note{}, nil).
+					Once()
+			},
+			assert: func(t *testing.T, purged int, err error) {
+				require.NoError(t, err)
+				assert.Zero(t, purged)
+			},
+		},
+		{
+			name: "should return purged count so far when a batch fails",
+			arrange: func(t *testing.T, s *serviceSuite) {
+				s.repo.On("PurgeTrashed", t.Context(), beforeRetention, batchSize).
+					Return(trashedNotes(t, batchSize), nil).
+					Once()
+
+				s.repo.On("PurgeTrashed", t.Context(), beforeRetention, batchSize).
+					Return(nil, errors.New("db error")).
+					Once()
+
+				s.onPurged(batchSize)
+			},
+			assert: func(t *testing.T, purged int, err error) {
+				require.Error(t, err)
+				assert.True(t, domain.ErrNotePurgeFailed.Is(err))
+				assert.Equal(t, batchSize, purged)
+			},
+		},
+		{
+			name: "should keep the batch when its events cannot be published",
+			arrange: func(t *testing.T, s *serviceSuite) {
+				s.repo.On("PurgeTrashed", t.Context(), beforeRetention, batchSize).
+					Return(trashedNotes(t, 1), nil).
+					Once()
+
+				s.events.On("Publish", mock.Anything, mock.Anything).
+					Return(errors.New("db error")).
+					Once()
+			},
+			assert: func(t *testing.T, purged int, err error) {
+				require.Error(t, err)
+				assert.True(t, domain.ErrNotePurgeFailed.Is(err))
+				assert.Zero(t, purged)
+			},
+		},
+	}
+
+	t.Run("should stop on an empty batch when the batch size is not positive", func(t *testing.T) {
+		for _, size := range []int{0, -1} {
+			s := setupSuite(t, size)
+			s.repo.On("PurgeTrashed", t.Context(), beforeRetention, size).
+				Return([]*domain.Note{}, nil).
+				Once()
+
+			purged, err := s.service.PurgeTrashedNotes(t.Context())
+
+			require.NoError(t, err)
+			assert.Zero(t, purged)
+		}
+	})
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			s := setupSuite(t, batchSize)
+
+			tc.arrange(t, s)
+
+			purged, err := s.service.PurgeTrashedNotes(t.Context())
+
+			tc.assert(t, purged, err)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package restorenote
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/core"
+)
+
+type Feature struct {
+	service *Service
+}
+
+func New(txManager domain.TransactionManager, ids core.IDGenerator, clock core.Clock) *Feature {
+	return &Feature{
+		service: NewService(txManager, ids, clock),
+	}
+}
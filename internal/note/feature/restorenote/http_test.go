@@ -0,0 +1,104 @@
+package restorenote_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/feature/restorenote"
+	"HATCH_APP/internal/note/infra/store/postgres"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/transport/httpx"
+	"HATCH_APP/test/container"
+	"HATCH_APP/test/httptest"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type httpSuite struct {
+	repo *postgres.NoteRepository
+	feat *restorenote.Feature
+}
+
+func setupHTTPSuite(t *testing.T) *httpSuite {
+	db, dbTeardown := container.SetupPostgres(t)
+
+	t.Cleanup(func() {
+		dbTeardown()
+	})
+
+	repo, err := postgres.NewNoteRepository(db)
+	require.NoError(t, err)
+
+	return &httpSuite{
+		repo: repo,
+		feat: restorenote.New(postgres.NewTransactionManager(db), core.NewULIDGenerator(core.SystemClock()), core.SystemClock()),
+	}
+}
+
+func TestRestoreNoteEndpoint(t *testing.T) {
+	s := setupHTTPSuite(t)
+	httptest.Init()
+
+	tests := []struct {
+		name string
+		tc   httptest.Case
+	}{
+		{
+			name: "should restore note from trash successfully",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					note, err := domain.NewNote(core.NewID(), time.Now(), "Test Note", "Test Content")
+					require.NoError(t, err)
+					require.NoError(t, note.Trash(time.Now()))
+
+					err = s.repo.Create(t.Context(), note)
+					require.NoError(t, err)
+
+					return httptest.WithParam(
+						httptest.NewRequest(http.MethodPost, "/api/v1/notes/"+note.ID.String()+"/restore"),
+						"id",
+						note.ID.String(),
+					)
+				},
+				ExpectStatus: http.StatusNoContent,
+				CheckResponse: func(t *testing.T, body []byte) {
+					assert.Empty(t, body)
+				},
+			},
+		},
+		{
+			name: "should return 404 when note is not in trash",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					note, err := domain.NewNote(core.NewID(), time.Now(), "Test Note", "Test Content")
+					require.NoError(t, err)
+
+					err = s.repo.Create(t.Context(), note)
+					require.NoError(t, err)
+
+					return httptest.WithParam(
+						httptest.NewRequest(http.MethodPost, "/api/v1/notes/"+note.ID.String()+"/restore"),
+						"id",
+						note.ID.String(),
+					)
+				},
+				ExpectStatus: http.StatusNotFound,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrNoteNotInTrash.ID, resp.Code)
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			httptest.Run(t, s.feat.RestoreNoteEndpoint, tc.tc)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package restorenote
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/core"
+	"context"
+)
+
+type Service struct {
+	txManager domain.TransactionManager
+	ids       core.IDGenerator
+	clock     core.Clock
+}
+
+func NewService(txManager domain.TransactionManager, ids core.IDGenerator, clock core.Clock) *Service {
+	return &Service{
+		txManager: txManager,
+		ids:       ids,
+		clock:     clock,
+	}
+}
+
+func (s *Service) RestoreNote(ctx context.Context, id core.ID) error {
+	return s.txManager.Transact(func(input domain.TransactionManagerInput) error {
+		note, err := input.NoteRepository.FindTrashedByID(ctx, id)
+		if err != nil {
+			return domain.ErrNoteFindFailed.Propagate(err)
+		}
+
+		if note == nil {
+			return domain.ErrNoteNotInTrash.New()
+		}
+
+		now := s.clock.Now()
+
+		if err := note.Restore(now); err != nil {
+			return err
+		}
+
+		if err := input.NoteRepository.Save(ctx, note); err != nil {
+			return domain.ErrNoteSaveFailed.Propagate(err)
+		}
+
+		if err := input.Events.Publish(ctx, domain.NewEvent(s.ids.NewID(), domain.EventNoteRestored, note, now)); err != nil {
+			return domain.ErrNoteEventPublishFailed.Wrap(err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,17 @@
+package streamnotes
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/o11y"
+	"context"
+)
+
+func (f *Feature) OnNoteEvent(ctx context.Context, notice domain.EventNotice) {
+	if err := f.service.PublishEvent(ctx, notice); err != nil {
+		o11y.LoggerFromContext(ctx).ErrorContext(ctx, "note event not streamed",
+			"event_id", notice.ID,
+			"note_id", notice.NoteID,
+			"error", err,
+		)
+	}
+}
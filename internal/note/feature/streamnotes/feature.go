@@ -0,0 +1,16 @@
+package streamnotes
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/transport/httpx/sse"
+)
+
+type Feature struct {
+	service *Service
+}
+
+func New(repo domain.NoteRepository, broker *sse.Broker) *Feature {
+	return &Feature{
+		service: NewService(repo, broker),
+	}
+}
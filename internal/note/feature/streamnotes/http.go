@@ -0,0 +1,16 @@
+package streamnotes
+
+import (
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/transport/httpx"
+	"net/http"
+)
+
+func (f *Feature) StreamNotesEndpoint(w http.ResponseWriter, r *http.Request) {
+	log := o11y.LoggerFromContext(r.Context()).With("endpoint", "StreamNotes")
+
+	if err := f.service.StreamNotes(w, r); err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+}
@@ -0,0 +1,94 @@
+package streamnotes_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/feature/streamnotes"
+	"HATCH_APP/internal/note/mocks"
+	"HATCH_APP/internal/shared/auth"
+	"HATCH_APP/pkg/transport/httpx"
+	"HATCH_APP/pkg/transport/httpx/sse"
+	"HATCH_APP/test/httptest"
+	"bufio"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamNotesEndpoint(t *testing.T) {
+	httptest.Init()
+
+	t.Run("should stream note events", func(t *testing.T) {
+		broker := sse.NewBroker(sse.Config{Heartbeat: time.Hour})
+		t.Cleanup(broker.Close)
+
+		feat := streamnotes.New(mocks.NewNoteRepository(t), broker)
+
+		srv := stdhttptest.NewServer(auth.Owner(http.HandlerFunc(feat.StreamNotesEndpoint)))
+		t.Cleanup(srv.Close)
+
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL+"/api/v1/notes/stream", nil)
+		require.NoError(t, err)
+
+		req.Header.Set(auth.OwnerHeader, "acme")
+
+		res, err := srv.Client().Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = res.Body.Close() })
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+		reader := bufio.NewReader(res.Body)
+
+		readLines := func(n int) []string {
+			lines := make([]string, 0, n)
+
+			for range n {
+				line, err := reader.ReadString('\n')
+				require.NoError(t, err)
+
+				lines = append(lines, line)
+			}
+
+			return lines
+		}
+
+		broker.Publish(sse.Event{
+			ID:       "01J00000000000000000000000",
+			Type:     domain.EventNoteCreated,
+			Data:     []byte(`{"id":"01J00000000000000000000001"}`),
+			Audience: "acme",
+		})
+
+		assert.Equal(t, []string{
+			"id: 01J00000000000000000000000\n",
+			"event: note.created\n",
+			"data: {\"id\":\"01J00000000000000000000001\"}\n",
+			"\n",
+		}, readLines(4))
+	})
+
+	t.Run("should refuse streams once shutting down", func(t *testing.T) {
+		broker := sse.NewBroker(sse.Config{})
+		broker.Close()
+
+		feat := streamnotes.New(mocks.NewNoteRepository(t), broker)
+
+		httptest.Run(t, feat.StreamNotesEndpoint, httptest.Case{
+			ArrangeRequest: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/api/v1/notes/stream")
+			},
+			ExpectStatus: http.StatusServiceUnavailable,
+			CheckResponse: func(t *testing.T, body []byte) {
+				resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+				require.NoError(t, err)
+				assert.Equal(t, sse.ErrStreamClosed.ID, resp.Code)
+			},
+		})
+	})
+}
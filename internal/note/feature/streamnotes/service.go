@@ -0,0 +1,72 @@
+package streamnotes
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/shared/auth"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/transport/httpx/sse"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+type Service struct {
+	repo   domain.NoteRepository
+	broker *sse.Broker
+}
+
+func NewService(repo domain.NoteRepository, broker *sse.Broker) *Service {
+	return &Service{
+		repo:   repo,
+		broker: broker,
+	}
+}
+
+// StreamNotes serves the events of the notes of the request owner to the
+// client until it goes away.
+func (s *Service) StreamNotes(w http.ResponseWriter, r *http.Request) error {
+	return s.broker.Stream(w, r, auth.OwnerFromContext(r.Context()))
+}
+
+// removedNote is the data of the events of notes that cannot be loaded
+// anymore, trashed or purged.
+type removedNote struct {
+	ID core.ID `json:"id"`
+}
+
+// PublishEvent streams the event announced by notice, carrying the note as it
+// is once loaded. Events of notes deleted since are skipped. Trashed and
+// purged notes are not loaded, their events only carry the note id.
+func (s *Service) PublishEvent(ctx context.Context, notice domain.EventNotice) error {
+	switch notice.Type {
+	case domain.EventNoteTrashed, domain.EventNotePurged:
+		return s.publish(notice, notice.OwnerID, removedNote{ID: notice.NoteID})
+	}
+
+	note, err := s.repo.FindByID(ctx, notice.NoteID)
+	if err != nil {
+		return domain.ErrNoteFindFailed.Propagate(err)
+	}
+
+	if note == nil {
+		return nil
+	}
+
+	return s.publish(notice, note.OwnerID, note)
+}
+
+func (s *Service) publish(notice domain.EventNotice, audience string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	s.broker.Publish(sse.Event{
+		ID:       notice.ID.String(),
+		Type:     notice.Type,
+		Data:     data,
+		Audience: audience,
+	})
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package streamnotes_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/feature/streamnotes"
+	"HATCH_APP/internal/note/mocks"
+	"HATCH_APP/internal/shared/auth"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/transport/httpx/sse"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type serviceSuite struct {
+	repo    *mocks.NoteRepository
+	service *streamnotes.Service
+}
+
+func setupServiceSuite(t *testing.T) *serviceSuite {
+	repo := mocks.NewNoteRepository(t)
+	broker := sse.NewBroker(sse.Config{Heartbeat: time.Hour})
+	t.Cleanup(broker.Close)
+
+	return &serviceSuite{
+		repo:    repo,
+		service: streamnotes.NewService(repo, broker),
+	}
+}
+
+// subscribe opens a stream of owner served by s and returns a function
+// reading its messages.
+func (s *serviceSuite) subscribe(t *testing.T, owner string) func() string {
+	t.Helper()
+
+	srv := stdhttptest.NewServer(auth.Owner(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = s.service.StreamNotes(w, r)
+	})))
+	t.Cleanup(srv.Close)
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	req.Header.Set(auth.OwnerHeader, owner)
+
+	res, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = res.Body.Close() })
+
+	reader := bufio.NewReader(res.Body)
+
+	return func() string {
+		var lines []string
+
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+
+			if line == "\n" {
+				return strings.Join(lines, "\n")
+			}
+
+			lines = append(lines, strings.TrimSuffix(line, "\n"))
+		}
+	}
+}
+
+func TestServicePublishEvent(t *testing.T) {
+	t.Run("should stream the note of the event to its owner only", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		next := s.subscribe(t, "acme")
+		other := s.subscribe(t, "globex")
+
+		n, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+		require.NoError(t, err)
+
+		n.OwnerID = "acme"
+
+		notice := domain.NewEvent(core.NewID(), domain.EventNoteUpdated, n, time.Now()).Notice()
+
+		s.repo.On("FindByID", t.Context(), n.ID).
+			Return(n, nil).
+			Once()
+
+		require.NoError(t, s.service.PublishEvent(t.Context(), notice))
+
+		data, err := json.Marshal(n)
+		require.NoError(t, err)
+
+		assert.Equal(t, "id: "+notice.ID.String()+"\nevent: note.updated\ndata: "+string(data), next())
+
+		// globex would read the event of acme first had it been streamed to it.
+		o, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+		require.NoError(t, err)
+
+		o.OwnerID = "globex"
+
+		otherNotice := domain.NewEvent(core.NewID(), domain.EventNoteUpdated, o, time.Now()).Notice()
+
+		s.repo.On("FindByID", t.Context(), o.ID).
+			Return(o, nil).
+			Once()
+
+		require.NoError(t, s.service.PublishEvent(t.Context(), otherNotice))
+
+		assert.Contains(t, other(), "id: "+otherNotice.ID.String()+"\n")
+	})
+
+	t.Run("should skip notes deleted since", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		noteID := core.NewID()
+
+		s.repo.On("FindByID", t.Context(), noteID).
+			Return((*domain.Note)(nil), nil).
+			Once()
+
+		err := s.service.PublishEvent(t.Context(), domain.EventNotice{
+			ID:     core.NewID(),
+			NoteID: noteID,
+			Type:   domain.EventNoteUpdated,
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should stream trashed and purged notes without loading them", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		next := s.subscribe(t, "acme")
+
+		n, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+		require.NoError(t, err)
+
+		n.OwnerID = "acme"
+		require.NoError(t, n.Trash(time.Now()))
+
+		for _, eventType := range []string{domain.EventNoteTrashed, domain.EventNotePurged} {
+			notice := domain.NewEvent(core.NewID(), eventType, n, time.Now()).Notice()
+
+			require.NoError(t, s.service.PublishEvent(t.Context(), notice))
+
+			assert.Equal(t, "id: "+notice.ID.String()+"\nevent: "+eventType+"\ndata: {\"id\":\""+n.ID.String()+"\"}", next())
+		}
+	})
+
+	t.Run("should return error when FindByID fails", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		noteID := core.NewID()
+
+		s.repo.On("FindByID", t.Context(), noteID).
+			Return((*domain.Note)(nil), errors.New("db error")).
+			Once()
+
+		err := s.service.PublishEvent(t.Context(), domain.EventNotice{
+			ID:     core.NewID(),
+			NoteID: noteID,
+			Type:   domain.EventNoteUpdated,
+		})
+
+		require.Error(t, err)
+		assert.True(t, domain.ErrNoteFindFailed.Is(err))
+	})
+}
@@ -0,0 +1,73 @@
+package tagnote
+
+import (
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/transport/httpx"
+	"fmt"
+	"net/http"
+)
+
+type Request struct {
+	Tags []string `json:"tags" validate:"required,min=1,max=20,dive,notblank,max=50"`
+}
+
+type Response struct {
+	Message string       `json:"message"`
+	Data    ResponseData `json:"data"`
+}
+
+type ResponseData struct {
+	Tags []string `json:"tags"`
+}
+
+func (f *Feature) AddTagsEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "AddTags")
+
+	id, err := httpx.PathID(w, r, "id")
+	if err != nil {
+		log.WarnContext(ctx, "invalid id", "error", err)
+		return
+	}
+
+	log = log.With("note_id", id)
+
+	req, err := httpx.ParseRequest[Request](w, r)
+	if err != nil {
+		log.WarnContext(ctx, "invalid payload", "error", err)
+		return
+	}
+
+	tags, err := f.service.AddTags(ctx, id, req.Tags)
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	httpx.WriteOKResponse(w, Response{
+		Message: fmt.Sprintf("note has %d tags", len(tags)),
+		Data:    ResponseData{Tags: tags},
+	})
+}
+
+func (f *Feature) RemoveTagEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "RemoveTag")
+
+	id, err := httpx.PathID(w, r, "id")
+	if err != nil {
+		log.WarnContext(ctx, "invalid id", "error", err)
+		return
+	}
+
+	log = log.With("note_id", id)
+
+	if err = f.service.RemoveTags(ctx, id, []string{r.PathValue("tag")}); err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	httpx.WriteEmptyResponse(w)
+}
@@ -0,0 +1,164 @@
+package tagnote_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/feature/tagnote"
+	"HATCH_APP/internal/note/infra/store/postgres"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/transport/httpx"
+	"HATCH_APP/test/container"
+	"HATCH_APP/test/httptest"
+	"bytes"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type httpSuite struct {
+	repo *postgres.NoteRepository
+	feat *tagnote.Feature
+}
+
+func setupHTTPSuite(t *testing.T) *httpSuite {
+	db, dbTeardown := container.SetupPostgres(t)
+
+	t.Cleanup(func() {
+		dbTeardown()
+	})
+
+	repo, err := postgres.NewNoteRepository(db)
+	require.NoError(t, err)
+
+	clock := core.SystemClock()
+
+	return &httpSuite{
+		repo: repo,
+		feat: tagnote.New(postgres.NewTransactionManager(db), core.NewULIDGenerator(clock), clock),
+	}
+}
+
+func (s *httpSuite) createNote(t *testing.T, tags ...string) *domain.Note {
+	n, err := domain.NewNote(core.NewID(), time.Now(), "Test Note", "Test Content")
+	require.NoError(t, err)
+	require.NoError(t, s.repo.Create(t.Context(), n))
+
+	added := make([]domain.Tag, 0, len(tags))
+	for _, name := range tags {
+		added = append(added, domain.Tag{ID: core.NewID(), Name: name, CreatedAt: time.Now()})
+	}
+
+	require.NoError(t, s.repo.AddTags(t.Context(), n.ID, added))
+
+	return n
+}
+
+func TestAddTagsEndpoint(t *testing.T) {
+	s := setupHTTPSuite(t)
+	httptest.Init()
+
+	addTags := func(id, body string) *http.Request {
+		req := stdhttptest.NewRequest(http.MethodPost, "/api/v1/notes/"+id+"/tags", bytes.NewReader([]byte(body)))
+
+		return httptest.WithParam(req, "id", id)
+	}
+
+	tests := []struct {
+		tc   httptest.Case
+		name string
+	}{
+		{
+			name: "should tag note and reuse existing tags",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					s.createNote(t, "work")
+					n := s.createNote(t, "home")
+
+					return addTags(n.ID.String(), `{"tags": ["Work", "Side Project", "work"]}`)
+				},
+				Headers:      map[string]string{"Content-Type": "application/json"},
+				ExpectStatus: http.StatusOK,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[tagnote.Response](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, []string{"home", "side-project", "work"}, resp.Data.Tags)
+
+					tags, err := s.repo.ListTags(t.Context(), "")
+					require.NoError(t, err)
+					assert.Contains(t, tags, domain.TagCount{Name: "work", Count: 2})
+				},
+			},
+		},
+		{
+			name: "should return 400 when a tag is invalid",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					n := s.createNote(t)
+
+					return addTags(n.ID.String(), `{"tags": ["c++"]}`)
+				},
+				Headers:      map[string]string{"Content-Type": "application/json"},
+				ExpectStatus: http.StatusBadRequest,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrNoteTagInvalid.ID, resp.Code)
+				},
+			},
+		},
+		{
+			name: "should return 404 when note not found",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					return addTags(core.NewID().String(), `{"tags": ["work"]}`)
+				},
+				Headers:      map[string]string{"Content-Type": "application/json"},
+				ExpectStatus: http.StatusNotFound,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrNoteNotFound.ID, resp.Code)
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			httptest.Run(t, s.feat.AddTagsEndpoint, tc.tc)
+		})
+	}
+}
+
+func TestRemoveTagEndpoint(t *testing.T) {
+	s := setupHTTPSuite(t)
+	httptest.Init()
+
+	var note *domain.Note
+
+	httptest.Run(t, s.feat.RemoveTagEndpoint, httptest.Case{
+		ArrangeRequest: func() *http.Request {
+			note = s.createNote(t, "work", "home")
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/notes/"+note.ID.String()+"/tags/Work")
+			req = httptest.WithParam(req, "id", note.ID.String())
+
+			return httptest.WithParam(req, "tag", "Work")
+		},
+		ExpectStatus: http.StatusNoContent,
+		CheckResponse: func(t *testing.T, body []byte) {
+			assert.Empty(t, body)
+
+			n, err := s.repo.FindByID(t.Context(), note.ID)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"home"}, n.Tags)
+		},
+	})
+}
@@ -0,0 +1,110 @@
+package tagnote
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/core"
+	"context"
+)
+
+type Service struct {
+	txManager domain.TransactionManager
+	ids       core.IDGenerator
+	clock     core.Clock
+}
+
+func NewService(txManager domain.TransactionManager, ids core.IDGenerator, clock core.Clock) *Service {
+	return &Service{
+		txManager: txManager,
+		ids:       ids,
+		clock:     clock,
+	}
+}
+
+// AddTags tags the note and returns all of its tags.
+func (s *Service) AddTags(ctx context.Context, id core.ID, names []string) ([]string, error) {
+	var tags []string
+
+	err := s.txManager.Transact(func(input domain.TransactionManagerInput) error {
+		note, err := find(ctx, input.NoteRepository, id)
+		if err != nil {
+			return err
+		}
+
+		added, err := note.AddTags(names...)
+		if err != nil {
+			return err
+		}
+
+		tags = note.Tags
+
+		if len(added) == 0 {
+			return nil
+		}
+
+		now := s.clock.Now()
+		newTags := make([]domain.Tag, 0, len(added))
+
+		for _, name := range added {
+			newTags = append(newTags, domain.Tag{ID: s.ids.NewID(), Name: name, CreatedAt: now})
+		}
+
+		if err := input.NoteRepository.AddTags(ctx, note.ID, newTags); err != nil {
+			return domain.ErrNoteTagsSaveFailed.Propagate(err)
+		}
+
+		if err := input.Events.Publish(ctx, domain.NewEvent(s.ids.NewID(), domain.EventNoteTagged, note, now)); err != nil {
+			return domain.ErrNoteEventPublishFailed.Wrap(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// RemoveTags untags the note, tags it does not have are ignored.
+func (s *Service) RemoveTags(ctx context.Context, id core.ID, names []string) error {
+	return s.txManager.Transact(func(input domain.TransactionManagerInput) error {
+		note, err := find(ctx, input.NoteRepository, id)
+		if err != nil {
+			return err
+		}
+
+		removed, err := note.RemoveTags(names...)
+		if err != nil {
+			return err
+		}
+
+		if len(removed) == 0 {
+			return nil
+		}
+
+		if err := input.NoteRepository.RemoveTags(ctx, note.ID, removed); err != nil {
+			return domain.ErrNoteTagsSaveFailed.Propagate(err)
+		}
+
+		event := domain.NewEvent(s.ids.NewID(), domain.EventNoteUntagged, note, s.clock.Now())
+
+		if err := input.Events.Publish(ctx, event); err != nil {
+			return domain.ErrNoteEventPublishFailed.Wrap(err)
+		}
+
+		return nil
+	})
+}
+
+func find(ctx context.Context, noteRepo domain.NoteRepository, id core.ID) (*domain.Note, error) {
+	note, err := noteRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, domain.ErrNoteFindFailed.Propagate(err)
+	}
+
+	if note == nil {
+		return nil, domain.ErrNoteNotFound.New()
+	}
+
+	return note, nil
+}
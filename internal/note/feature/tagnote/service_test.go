@@ -0,0 +1,205 @@
+package tagnote_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/feature/tagnote"
+	"HATCH_APP/internal/note/mocks"
+	"HATCH_APP/pkg/core"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	now   = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tagID = core.MustParseID("01JWN3V0G0000000000000000T")
+)
+
+type serviceSuite struct {
+	repo    *mocks.NoteRepository
+	events  *mocks.EventPublisher
+	service *tagnote.Service
+}
+
+func setupSuite(t *testing.T) *serviceSuite {
+	repo := mocks.NewNoteRepository(t)
+	events := mocks.NewEventPublisher(t)
+	txManager := mocks.NewTransactionManager(t)
+
+	txManager.On("Transact", mock.Anything).
+		Return(func(fn func(domain.TransactionManagerInput) error) error {
+			return fn(domain.TransactionManagerInput{
+				NoteRepository: repo,
+				Events:         events,
+			})
+		}).
+		Maybe()
+
+	return &serviceSuite{
+		repo:    repo,
+		events:  events,
+		service: tagnote.NewService(txManager, core.FixedIDs(tagID), core.FixedClock(now)),
+	}
+}
+
+func (s *serviceSuite) onPublish(t *testing.T, eventType string, tags []string) {
+	s.events.On("Publish", t.Context(), mock.MatchedBy(func(e domain.Event) bool {
+		return e.Type == eventType &&
+			e.CreatedAt.Equal(now) &&
+			assert.ObjectsAreEqual(tags, e.Note.Tags)
+	})).
+		Return(nil).
+		Once()
+}
+
+func newNote(t *testing.T, tags ...string) *domain.Note {
+	n, err := domain.NewNote(core.NewID(), now, "title", "content")
+	require.NoError(t, err)
+
+	_, err = n.AddTags(tags...)
+	require.NoError(t, err)
+
+	return n
+}
+
+func TestServiceAddTags(t *testing.T) {
+	tests := []struct {
+		arrange func(t *testing.T, s *serviceSuite) core.ID
+		assert  func(t *testing.T, tags []string, err error)
+		name    string
+		names   []string
+	}{
+		{
+			name:  "should add the missing tags",
+			names: []string{"Work", "ideas"},
+			arrange: func(t *testing.T, s *serviceSuite) core.ID {
+				n := newNote(t, "work")
+
+				s.repo.On("FindByID", t.Context(), n.ID).
+					Return(n, nil).
+					Once()
+
+				s.repo.On("AddTags", t.Context(), n.ID, []domain.Tag{
+					{ID: tagID, Name: "ideas", CreatedAt: now},
+				}).
+					Return(nil).
+					Once()
+
+				s.onPublish(t, domain.EventNoteTagged, []string{"ideas", "work"})
+
+				return n.ID
+			},
+			assert: func(t *testing.T, tags []string, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, []string{"ideas", "work"}, tags)
+			},
+		},
+		{
+			name:  "should neither save nor publish when the note has the tags",
+			names: []string{"Work"},
+			arrange: func(t *testing.T, s *serviceSuite) core.ID {
+				n := newNote(t, "work")
+
+				s.repo.On("FindByID", t.Context(), n.ID).
+					Return(n, nil).
+					Once()
+
+				return n.ID
+			},
+			assert: func(t *testing.T, tags []string, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, []string{"work"}, tags)
+			},
+		},
+		{
+			name:  "should return not found when the note does not exist",
+			names: []string{"work"},
+			arrange: func(t *testing.T, s *serviceSuite) core.ID {
+				id := core.NewID()
+
+				s.repo.On("FindByID", t.Context(), id).
+					Return((*domain.Note)(nil), nil).
+					Once()
+
+				return id
+			},
+			assert: func(t *testing.T, tags []string, err error) {
+				require.Error(t, err)
+				assert.True(t, domain.ErrNoteNotFound.Is(err))
+			},
+		},
+		{
+			name:  "should reject invalid tags before saving",
+			names: []string{"not valid!"},
+			arrange: func(t *testing.T, s *serviceSuite) core.ID {
+				n := newNote(t)
+
+				s.repo.On("FindByID", t.Context(), n.ID).
+					Return(n, nil).
+					Once()
+
+				return n.ID
+			},
+			assert: func(t *testing.T, tags []string, err error) {
+				require.Error(t, err)
+				assert.True(t, domain.ErrNoteTagInvalid.Is(err))
+			},
+		},
+		{
+			name:  "should return error when AddTags fails",
+			names: []string{"work"},
+			arrange: func(t *testing.T, s *serviceSuite) core.ID {
+				n := newNote(t)
+
+				s.repo.On("FindByID", t.Context(), n.ID).
+					Return(n, nil).
+					Once()
+
+				s.repo.On("AddTags", t.Context(), n.ID, mock.Anything).
+					Return(errors.New("db down")).
+					Once()
+
+				return n.ID
+			},
+			assert: func(t *testing.T, tags []string, err error) {
+				require.Error(t, err)
+				assert.True(t, domain.ErrNoteTagsSaveFailed.Is(err))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			s := setupSuite(t)
+
+			id := tc.arrange(t, s)
+
+			tags, err := s.service.AddTags(t.Context(), id, tc.names)
+
+			tc.assert(t, tags, err)
+		})
+	}
+}
+
+func TestServiceRemoveTags(t *testing.T) {
+	s := setupSuite(t)
+
+	n := newNote(t, "work", "home")
+
+	s.repo.On("FindByID", t.Context(), n.ID).
+		Return(n, nil).
+		Once()
+
+	s.repo.On("RemoveTags", t.Context(), n.ID, []string{"work"}).
+		Return(nil).
+		Once()
+
+	s.onPublish(t, domain.EventNoteUntagged, []string{"home"})
+
+	require.NoError(t, s.service.RemoveTags(t.Context(), n.ID, []string{"Work", "missing"}))
+}
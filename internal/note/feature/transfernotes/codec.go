@@ -0,0 +1,477 @@
+package transfernotes
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	frontMatterDelimiter = "---"
+	// byteOrderMark starts the files of some editors and spreadsheets.
+	byteOrderMark = "\ufeff"
+	// maxEntrySize bounds a decompressed markdown file, a note at its
+	// content limit and its front matter fit well within it.
+	maxEntrySize = 1 << 20
+)
+
+// csvColumns is the header of CSV exports, imports map columns by name and
+// only need title.
+var csvColumns = []string{"id", "title", "content", "status", "format", "version", "tags", "created_at", "updated_at"}
+
+// Record is a note as read from an import, every field but title is
+// optional.
+type Record struct {
+	CreatedAt *time.Time `json:"created_at"`
+	ID        string     `json:"id"`
+	Title     string     `json:"title"`
+	Content   string     `json:"content"`
+	Status    string     `json:"status"`
+	Format    string     `json:"format"`
+	Tags      []string   `json:"tags"`
+}
+
+// Row is a record and where it was read, Err is set instead of the record
+// when it could not be read.
+type Row struct {
+	Err    error
+	File   string
+	Record Record
+	// Index is the line of the record, or the position of the file in a zip.
+	Index int
+}
+
+type encoder interface {
+	Encode(note *domain.Note) error
+	// Close writes what the format needs after the last note.
+	Close() error
+}
+
+func newEncoder(format Format, w io.Writer) encoder {
+	switch format {
+	case FormatCSV:
+		return newCSVEncoder(w)
+	case FormatZip:
+		return &zipEncoder{w: zip.NewWriter(w)}
+	default:
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+
+		return &jsonlEncoder{enc: enc}
+	}
+}
+
+type jsonlEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *jsonlEncoder) Encode(note *domain.Note) error {
+	return e.enc.Encode(note)
+}
+
+func (e *jsonlEncoder) Close() error {
+	return nil
+}
+
+type csvEncoder struct {
+	w   *csv.Writer
+	err error
+}
+
+func newCSVEncoder(w io.Writer) *csvEncoder {
+	e := &csvEncoder{w: csv.NewWriter(w)}
+	e.err = e.w.Write(csvColumns)
+
+	return e
+}
+
+func (e *csvEncoder) Encode(note *domain.Note) error {
+	if e.err != nil {
+		return e.err
+	}
+
+	var updatedAt string
+	if note.UpdatedAt != nil {
+		updatedAt = note.UpdatedAt.Format(time.RFC3339Nano)
+	}
+
+	return e.w.Write([]string{
+		note.ID.String(),
+		note.Title,
+		note.Content,
+		string(note.Status),
+		string(note.Format),
+		fmt.Sprint(note.Version),
+		strings.Join(note.Tags, ","),
+		note.CreatedAt.Format(time.RFC3339Nano),
+		updatedAt,
+	})
+}
+
+func (e *csvEncoder) Close() error {
+	if e.err != nil {
+		return e.err
+	}
+
+	e.w.Flush()
+
+	return e.w.Error()
+}
+
+type zipEncoder struct {
+	w *zip.Writer
+}
+
+func (e *zipEncoder) Encode(note *domain.Note) error {
+	modified := note.CreatedAt
+	if note.UpdatedAt != nil {
+		modified = *note.UpdatedAt
+	}
+
+	file, err := e.w.CreateHeader(&zip.FileHeader{
+		Name:     note.ID.String() + ".md",
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(markdownFile(note))
+
+	return err
+}
+
+func (e *zipEncoder) Close() error {
+	return e.w.Close()
+}
+
+// markdownFile puts the fields of the note in YAML front matter before its
+// content. Strings are written JSON encoded, which YAML reads as well.
+func markdownFile(note *domain.Note) []byte {
+	var buf bytes.Buffer
+
+	field := func(key string, value any) {
+		raw, _ := json.Marshal(value)
+		fmt.Fprintf(&buf, "%s: %s\n", key, raw)
+	}
+
+	buf.WriteString(frontMatterDelimiter + "\n")
+	fmt.Fprintf(&buf, "id: %s\n", note.ID)
+	field("title", note.Title)
+	fmt.Fprintf(&buf, "status: %s\n", note.Status)
+	fmt.Fprintf(&buf, "format: %s\n", note.Format)
+	fmt.Fprintf(&buf, "version: %d\n", note.Version)
+	field("tags", note.Tags)
+	fmt.Fprintf(&buf, "created_at: %s\n", note.CreatedAt.Format(time.RFC3339Nano))
+
+	if note.UpdatedAt != nil {
+		fmt.Fprintf(&buf, "updated_at: %s\n", note.UpdatedAt.Format(time.RFC3339Nano))
+	}
+
+	buf.WriteString(frontMatterDelimiter + "\n")
+	buf.WriteString(note.Content)
+
+	return buf.Bytes()
+}
+
+// decode calls fn with every row of r, stopping at the first error fn
+// returns. Rows that cannot be read are passed with Err set, a file that
+// cannot be read at all is an error. maxUnzipped bounds the bytes the files of
+// a zip decompress to.
+func decode(format Format, r io.Reader, maxUnzipped int64, fn func(row Row) error) error {
+	switch format {
+	case FormatCSV:
+		return decodeCSV(r, fn)
+	case FormatZip:
+		return decodeZip(r, maxUnzipped, fn)
+	default:
+		return decodeJSONL(r, fn)
+	}
+}
+
+func decodeJSONL(r io.Reader, fn func(row Row) error) error {
+	reader := bufio.NewReader(r)
+
+	for line := 1; ; line++ {
+		raw, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return unreadable(err)
+		}
+
+		if len(bytes.TrimSpace(raw)) > 0 {
+			row := Row{Index: line}
+
+			if jsonErr := json.Unmarshal(raw, &row.Record); jsonErr != nil {
+				row.Err = rowInvalid(jsonErr, "the line is not a JSON note")
+			}
+
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+	}
+}
+
+func decodeCSV(r io.Reader, fn func(row Row) error) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	if err != nil {
+		return invalid(err, "the header could not be read")
+	}
+
+	columns := make(map[string]int, len(header))
+
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, byteOrderMark)
+		}
+
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	if _, ok := columns["title"]; !ok {
+		return invalid(nil, "the header has no title column")
+	}
+
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if parseErr, ok := errors.AsType[*csv.ParseError](err); ok {
+			if err := fn(Row{Index: parseErr.StartLine, Err: rowInvalid(err, parseErr.Err.Error())}); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err != nil {
+			return unreadable(err)
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		if err := fn(csvRow(line, columns, fields)); err != nil {
+			return err
+		}
+	}
+}
+
+func csvRow(line int, columns map[string]int, fields []string) Row {
+	field := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(fields) {
+			return ""
+		}
+
+		return fields[i]
+	}
+
+	row := Row{
+		Index: line,
+		Record: Record{
+			ID:      field("id"),
+			Title:   field("title"),
+			Content: field("content"),
+			Status:  field("status"),
+			Format:  field("format"),
+		},
+	}
+
+	if tags := field("tags"); tags != "" {
+		row.Record.Tags = strings.Split(tags, ",")
+	}
+
+	if createdAt := field("created_at"); createdAt != "" {
+		t, err := time.Parse(time.RFC3339Nano, createdAt)
+		if err != nil {
+			row.Err = rowInvalid(err, "created_at is not an RFC 3339 time")
+		}
+
+		row.Record.CreatedAt = &t
+	}
+
+	return row
+}
+
+// decodeZip reads the whole archive, its directory is at the end. Files
+// other than markdown are skipped. The bytes actually decompressed are
+// counted, the sizes the headers declare can lie.
+func decodeZip(r io.Reader, maxUnzipped int64, fn func(row Row) error) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return unreadable(err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return invalid(err, "the file is not a zip archive")
+	}
+
+	var unzipped int64
+
+	for i, file := range archive.File {
+		if file.FileInfo().IsDir() || !strings.EqualFold(path.Ext(file.Name), ".md") {
+			continue
+		}
+
+		row := Row{Index: i + 1, File: file.Name}
+
+		raw, err := readZipEntry(file)
+		if unzipped += int64(len(raw)); unzipped > maxUnzipped {
+			return domain.ErrNoteImportUnzippedTooLarge.New().WithDetails(map[string]int64{"max": maxUnzipped})
+		}
+
+		if err == nil {
+			row.Record, err = parseMarkdownFile(string(raw))
+		}
+
+		row.Err = err
+
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readZipEntry returns what was decompressed of file, even when it fails.
+func readZipEntry(file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, rowInvalid(err, "the file could not be opened")
+	}
+	defer rc.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(rc, maxEntrySize+1))
+	if err != nil {
+		return raw, rowInvalid(err, "the file could not be decompressed")
+	}
+
+	if len(raw) > maxEntrySize {
+		return raw, rowInvalid(nil, fmt.Sprintf("the file is over %d bytes", maxEntrySize))
+	}
+
+	return raw, nil
+}
+
+// parseMarkdownFile reads the front matter markdownFile writes, one key per
+// line with JSON or bare values.
+func parseMarkdownFile(raw string) (Record, error) {
+	rest, ok := strings.CutPrefix(strings.TrimPrefix(raw, byteOrderMark), frontMatterDelimiter+"\n")
+	if !ok {
+		return Record{}, rowInvalid(nil, "the file does not start with front matter")
+	}
+
+	var record Record
+
+	for {
+		line, after, found := strings.Cut(rest, "\n")
+		if !found {
+			return Record{}, rowInvalid(nil, "the front matter is not closed")
+		}
+
+		rest = after
+		line = strings.TrimSuffix(line, "\r")
+
+		if line == frontMatterDelimiter {
+			break
+		}
+
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			return Record{}, rowInvalid(nil, fmt.Sprintf("the front matter line %q is not a key and value", line))
+		}
+
+		if err := record.set(strings.TrimSpace(key), strings.TrimSpace(value)); err != nil {
+			return Record{}, err
+		}
+	}
+
+	record.Content = rest
+
+	return record, nil
+}
+
+func (r *Record) set(key, value string) error {
+	str := func(dst *string) error {
+		if !strings.HasPrefix(value, `"`) {
+			*dst = value
+			return nil
+		}
+
+		if err := json.Unmarshal([]byte(value), dst); err != nil {
+			return rowInvalid(err, key+" is not a valid string")
+		}
+
+		return nil
+	}
+
+	switch key {
+	case "id":
+		return str(&r.ID)
+	case "title":
+		return str(&r.Title)
+	case "status":
+		return str(&r.Status)
+	case "format":
+		return str(&r.Format)
+	case "tags":
+		if err := json.Unmarshal([]byte(value), &r.Tags); err != nil {
+			return rowInvalid(err, "tags is not a list of strings")
+		}
+	case "created_at":
+		var raw string
+		if err := str(&raw); err != nil {
+			return err
+		}
+
+		t, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return rowInvalid(err, "created_at is not an RFC 3339 time")
+		}
+
+		r.CreatedAt = &t
+	}
+
+	return nil
+}
+
+func invalid(err error, reason string) error {
+	return domain.ErrNoteImportInvalid.Wrap(err).WithDetails(map[string]string{"reason": reason})
+}
+
+func unreadable(err error) error {
+	return invalid(err, "the file could not be read")
+}
+
+func rowInvalid(err error, reason string) error {
+	return domain.ErrNoteImportRowInvalid.Wrap(err).WithDetails(map[string]string{"reason": reason})
+}
@@ -0,0 +1,34 @@
+package transfernotes
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/core"
+)
+
+// Limits bound what a single import may take, the rows are all held until
+// they are created.
+type Limits struct {
+	// MaxSize bounds the imported file, in bytes.
+	MaxSize int64
+	// MaxUnzippedSize bounds the decompressed files of a zip, in bytes.
+	MaxUnzippedSize int64
+	MaxRows         int
+}
+
+type Feature struct {
+	service *Service
+	maxSize int64
+}
+
+func New(
+	noteRepo domain.NoteRepository,
+	txManager domain.TransactionManager,
+	ids core.IDGenerator,
+	clock core.Clock,
+	limits Limits,
+) *Feature {
+	return &Feature{
+		service: NewService(noteRepo, txManager, ids, clock, limits),
+		maxSize: limits.MaxSize,
+	}
+}
@@ -0,0 +1,50 @@
+package transfernotes
+
+import "HATCH_APP/internal/note/domain"
+
+// Format is the file format notes are exported and imported in.
+type Format string
+
+const (
+	// FormatJSONL writes a note as JSON per line.
+	FormatJSONL Format = "jsonl"
+	// FormatCSV writes a note per record after a header naming the columns.
+	FormatCSV Format = "csv"
+	// FormatZip archives a markdown file per note, its fields in front matter.
+	FormatZip Format = "zip"
+)
+
+// Enum lists the formats for the API schema.
+func (Format) Enum() []any {
+	return []any{FormatJSONL, FormatCSV, FormatZip}
+}
+
+// ParseFormat defaults an empty format to jsonl.
+func ParseFormat(raw string) (Format, error) {
+	switch format := Format(raw); format {
+	case "":
+		return FormatJSONL, nil
+	case FormatJSONL, FormatCSV, FormatZip:
+		return format, nil
+	default:
+		return "", domain.ErrNoteTransferFormatInvalid.New().WithDetails(map[string]any{
+			"format":  raw,
+			"allowed": Format("").Enum(),
+		})
+	}
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatZip:
+		return "application/zip"
+	default:
+		return "application/x-ndjson"
+	}
+}
+
+func (f Format) Filename() string {
+	return "notes." + string(f)
+}
@@ -0,0 +1,188 @@
+package transfernotes
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/transport/httpx"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// FileField is the multipart form field holding the imported file.
+	FileField = "file"
+	// multipartOverhead bounds the boundaries, part headers and other fields
+	// around the file.
+	multipartOverhead = 64 << 10
+	// transferTimeout replaces the server timeouts, which are sized for JSON
+	// bodies, on exports and imports.
+	transferTimeout = 5 * time.Minute
+)
+
+type ExportParams struct {
+	Format Format `query:"format" validate:"omitempty,oneof=jsonl csv zip"`
+}
+
+type ImportParams struct {
+	Format Format `query:"format"  validate:"omitempty,oneof=jsonl csv zip"`
+	DryRun bool   `query:"dry_run"`
+}
+
+type ImportResponse struct {
+	Message string        `json:"message"`
+	Data    *ImportResult `json:"data"`
+}
+
+func (f *Feature) ExportNotesEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "ExportNotes")
+
+	format, err := ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(transferTimeout))
+
+	header := w.Header()
+	header.Set("Content-Type", format.ContentType())
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": format.Filename(),
+	}))
+	header.Set("X-Content-Type-Options", "nosniff")
+
+	out := &sentWriter{w: w}
+
+	if err := f.service.ExportNotes(ctx, format, out); err != nil {
+		if !out.sent {
+			header.Del("Content-Disposition")
+			httpx.WriteError(log, w, err)
+
+			return
+		}
+
+		log.ErrorContext(ctx, "note export aborted", "error", err)
+
+		// The status is sent, dropping the connection is the only way left
+		// to tell the client.
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// sentWriter tells whether the response was started.
+type sentWriter struct {
+	w    io.Writer
+	sent bool
+}
+
+func (s *sentWriter) Write(p []byte) (int, error) {
+	s.sent = true
+
+	return s.w.Write(p)
+}
+
+func (f *Feature) ImportNotesEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "ImportNotes")
+
+	query := r.URL.Query()
+
+	format, err := ParseFormat(query.Get("format"))
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	var dryRun bool
+
+	if raw := query.Get("dry_run"); raw != "" {
+		if dryRun, err = strconv.ParseBool(raw); err != nil {
+			httpx.WriteError(log, w, domain.ErrNoteImportInvalid.Wrap(err).WithDetails(map[string]string{
+				"reason": "dry_run is not a boolean",
+			}))
+
+			return
+		}
+	}
+
+	log = log.With("format", format, "dry_run", dryRun)
+
+	_ = http.NewResponseController(w).SetReadDeadline(time.Now().Add(transferTimeout))
+
+	r.Body = http.MaxBytesReader(w, r.Body, f.maxSize+multipartOverhead)
+
+	part, err := f.filePart(r)
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+	defer part.Close()
+
+	result, err := f.service.ImportNotes(ctx, format, part, dryRun)
+	if _, ok := errors.AsType[*http.MaxBytesError](err); ok {
+		err = f.tooLarge()
+	}
+
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	message := fmt.Sprintf("%d notes imported, %d rows rejected", result.Imported, len(result.Errors))
+	if dryRun {
+		message = fmt.Sprintf("%d notes can be imported, %d rows would be rejected", result.Imported, len(result.Errors))
+	}
+
+	httpx.WriteOKResponse(w, ImportResponse{
+		Message: message,
+		Data:    result,
+	})
+}
+
+// filePart skips the form fields preceding the file.
+func (f *Feature) filePart(r *http.Request) (*multipart.Part, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, domain.ErrNoteImportInvalid.Wrap(err).WithDetails(map[string]string{
+			"reason": "the body is not multipart/form-data",
+		})
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, domain.ErrNoteImportInvalid.New().WithDetails(map[string]string{
+				"reason": fmt.Sprintf("the %s field is missing", FileField),
+			})
+		}
+
+		if _, ok := errors.AsType[*http.MaxBytesError](err); ok {
+			return nil, f.tooLarge()
+		}
+
+		if err != nil {
+			return nil, domain.ErrNoteImportInvalid.Wrap(err).WithDetails(map[string]string{
+				"reason": "the multipart body is malformed",
+			})
+		}
+
+		if part.FormName() == FileField {
+			return part, nil
+		}
+
+		_ = part.Close()
+	}
+}
+
+func (f *Feature) tooLarge() error {
+	return domain.ErrNoteImportTooLarge.New().WithDetails(map[string]int64{"max": f.maxSize})
+}
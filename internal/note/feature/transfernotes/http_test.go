@@ -0,0 +1,200 @@
+package transfernotes_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/feature/transfernotes"
+	"HATCH_APP/internal/note/infra/store/postgres"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/transport/httpx"
+	"HATCH_APP/test/container"
+	"HATCH_APP/test/httptest"
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type httpSuite struct {
+	repo *postgres.NoteRepository
+	feat *transfernotes.Feature
+}
+
+func setupHTTPSuite(t *testing.T) *httpSuite {
+	db, dbTeardown := container.SetupPostgres(t)
+
+	t.Cleanup(func() {
+		dbTeardown()
+	})
+
+	repo, err := postgres.NewNoteRepository(db)
+	require.NoError(t, err)
+
+	clock := core.SystemClock()
+
+	return &httpSuite{
+		repo: repo,
+		feat: transfernotes.New(repo, postgres.NewTransactionManager(db), core.NewULIDGenerator(clock), clock, transfernotes.Limits{
+			MaxSize:         1 << 10,
+			MaxUnzippedSize: 1 << 20,
+			MaxRows:         100,
+		}),
+	}
+}
+
+func (s *httpSuite) createNote(t *testing.T, title string) *domain.Note {
+	n, err := domain.NewNote(core.NewID(), time.Now(), title, "Test Content")
+	require.NoError(t, err)
+	require.NoError(t, s.repo.Create(t.Context(), n))
+
+	return n
+}
+
+func importRequest(query, field, content string) *http.Request {
+	var body bytes.Buffer
+
+	form := multipart.NewWriter(&body)
+	file, _ := form.CreateFormFile(field, "notes")
+	_, _ = file.Write([]byte(content))
+	_ = form.Close()
+
+	req := stdhttptest.NewRequest(http.MethodPost, "/api/v1/notes/import"+query, &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	return req
+}
+
+func TestExportNotesEndpoint(t *testing.T) {
+	s := setupHTTPSuite(t)
+	httptest.Init()
+
+	first := s.createNote(t, "First")
+	second := s.createNote(t, "Second")
+
+	t.Run("should stream the notes as an attachment", func(t *testing.T) {
+		rec := stdhttptest.NewRecorder()
+		s.feat.ExportNotesEndpoint(rec, httptest.NewRequest(http.MethodGet, "/api/v1/notes/export?format=jsonl"))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename=notes.jsonl`, rec.Header().Get("Content-Disposition"))
+
+		lines := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n")
+		require.Len(t, lines, 2)
+		assert.Contains(t, lines[0], first.ID.String())
+		assert.Contains(t, lines[1], second.ID.String())
+	})
+
+	t.Run("should return 400 when the format is unknown", func(t *testing.T) {
+		httptest.Run(t, s.feat.ExportNotesEndpoint, httptest.Case{
+			ArrangeRequest: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/api/v1/notes/export?format=xml")
+			},
+			ExpectStatus: http.StatusBadRequest,
+			CheckResponse: func(t *testing.T, body []byte) {
+				resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+				require.NoError(t, err)
+				assert.Equal(t, domain.ErrNoteTransferFormatInvalid.ID, resp.Code)
+			},
+		})
+	})
+}
+
+func TestImportNotesEndpoint(t *testing.T) {
+	s := setupHTTPSuite(t)
+	httptest.Init()
+
+	csv := "title,content,tags\nImported,Body,\"a,b\"\n,No title,\n"
+
+	tests := []struct {
+		tc   httptest.Case
+		name string
+	}{
+		{
+			name: "should only report in a dry run",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					return importRequest("?format=csv&dry_run=true", transfernotes.FileField, csv)
+				},
+				ExpectStatus: http.StatusOK,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[transfernotes.ImportResponse](body)
+
+					require.NoError(t, err)
+					assert.True(t, resp.Data.DryRun)
+					assert.Equal(t, 1, resp.Data.Imported)
+					require.Len(t, resp.Data.Errors, 1)
+					assert.Equal(t, 3, resp.Data.Errors[0].Row)
+
+					notes, err := s.repo.List(t.Context(), domain.NoteFilter{})
+					require.NoError(t, err)
+					assert.Empty(t, notes)
+				},
+			},
+		},
+		{
+			name: "should import the valid rows",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					return importRequest("?format=csv", transfernotes.FileField, csv)
+				},
+				ExpectStatus: http.StatusOK,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[transfernotes.ImportResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, 1, resp.Data.Imported)
+					assert.Len(t, resp.Data.Errors, 1)
+
+					notes, err := s.repo.List(t.Context(), domain.NoteFilter{})
+					require.NoError(t, err)
+					require.Len(t, notes, 1)
+					assert.Equal(t, "Imported", notes[0].Title)
+					assert.Equal(t, []string{"a", "b"}, notes[0].Tags)
+				},
+			},
+		},
+		{
+			name: "should return 400 when the file is missing",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					return importRequest("", "other", "{}")
+				},
+				ExpectStatus: http.StatusBadRequest,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrNoteImportInvalid.ID, resp.Code)
+				},
+			},
+		},
+		{
+			name: "should return 413 when the file is too large",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					return importRequest("", transfernotes.FileField, strings.Repeat("x", 128<<10))
+				},
+				ExpectStatus: http.StatusRequestEntityTooLarge,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrNoteImportTooLarge.ID, resp.Code)
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httptest.Run(t, s.feat.ImportNotesEndpoint, tt.tc)
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package transfernotes
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/shared/auth"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/core/apperr"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"time"
+)
+
+// RowError reports a row left out of an import.
+type RowError struct {
+	Details any `json:"details,omitempty"`
+	// File names the markdown file of the row in a zip.
+	File    string `json:"file,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Row     int    `json:"row"`
+}
+
+type ImportResult struct {
+	Errors []RowError `json:"errors"`
+	// Imported counts the notes created, or that would have been in a dry
+	// run.
+	Imported int  `json:"imported"`
+	DryRun   bool `json:"dry_run"`
+}
+
+type Service struct {
+	noteRepo  domain.NoteRepository
+	txManager domain.TransactionManager
+	ids       core.IDGenerator
+	clock     core.Clock
+	limits    Limits
+}
+
+func NewService(
+	noteRepo domain.NoteRepository,
+	txManager domain.TransactionManager,
+	ids core.IDGenerator,
+	clock core.Clock,
+	limits Limits,
+) *Service {
+	return &Service{
+		noteRepo:  noteRepo,
+		txManager: txManager,
+		ids:       ids,
+		clock:     clock,
+		limits:    limits,
+	}
+}
+
+// ExportNotes writes the notes of the owner out of the trash to w as they are
+// read.
+func (s *Service) ExportNotes(ctx context.Context, format Format, w io.Writer) error {
+	enc := newEncoder(format, w)
+
+	filter := domain.NoteFilter{Owner: new(auth.OwnerFromContext(ctx))}
+
+	for note, err := range s.noteRepo.Iterate(ctx, filter) {
+		if err != nil {
+			return domain.ErrNoteExportFailed.Propagate(err)
+		}
+
+		if err := enc.Encode(note); err != nil {
+			return domain.ErrNoteExportFailed.Wrap(err)
+		}
+	}
+
+	if err := enc.Close(); err != nil {
+		return domain.ErrNoteExportFailed.Wrap(err)
+	}
+
+	return nil
+}
+
+// ImportNotes creates a note per valid row of r, with its id and creation
+// time when given, and reports the others. The notes are created in a single
+// transaction, once the whole file is read, so a failure imports none. Files
+// over the row or decompressed size limits are refused whole.
+func (s *Service) ImportNotes(ctx context.Context, format Format, r io.Reader, dryRun bool) (*ImportResult, error) {
+	result := &ImportResult{Errors: []RowError{}, DryRun: dryRun}
+
+	now := s.clock.Now()
+	// given are the rows of the ids the file gives, they must not be taken.
+	given := make(map[core.ID]Row)
+
+	var (
+		notes []*domain.Note
+		rows  int
+	)
+
+	err := decode(format, r, s.limits.MaxUnzippedSize, func(row Row) error {
+		if rows++; rows > s.limits.MaxRows {
+			return domain.ErrNoteImportTooManyRows.New().WithDetails(map[string]int{"max": s.limits.MaxRows})
+		}
+
+		note, err := s.prepare(ctx, row, given, now)
+
+		appErr, ok := errors.AsType[*apperr.Error](err)
+		if ok && appErr.Type != apperr.TypeInternal {
+			result.Errors = append(result.Errors, rowError(row, appErr))
+
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		notes = append(notes, note)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(notes) == 0 {
+		return result, nil
+	}
+
+	err = s.txManager.Transact(func(input domain.TransactionManagerInput) error {
+		notes, err = s.dropTaken(ctx, input.NoteRepository, notes, given, result)
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			return nil
+		}
+
+		for _, note := range notes {
+			if err := s.create(ctx, input, note, now); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Imported = len(notes)
+
+	return result, nil
+}
+
+// prepare builds the note of row under the rules of the API, ids given by an
+// earlier row are refused.
+func (s *Service) prepare(ctx context.Context, row Row, given map[core.ID]Row, now time.Time) (*domain.Note, error) {
+	if row.Err != nil {
+		return nil, row.Err
+	}
+
+	record := row.Record
+
+	var id core.ID
+
+	if record.ID == "" {
+		id = s.ids.NewID()
+	} else {
+		parsed, err := core.ParseID(record.ID)
+		if err != nil {
+			return nil, rowInvalid(err, "id is not a ULID")
+		}
+
+		if _, ok := given[parsed]; ok {
+			return nil, noteExists(parsed)
+		}
+
+		id = parsed
+	}
+
+	createdAt := now
+	if record.CreatedAt != nil {
+		createdAt = record.CreatedAt.UTC()
+	}
+
+	note, err := domain.NewNote(id, createdAt, record.Title, record.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	note.OwnerID = auth.OwnerFromContext(ctx)
+
+	if note.Format, err = domain.ParseNoteFormat(record.Format); err != nil {
+		return nil, err
+	}
+
+	if _, err := note.AddTags(record.Tags...); err != nil {
+		return nil, err
+	}
+
+	switch status := domain.NoteStatus(record.Status); status {
+	case "", domain.NoteStatusActive:
+	case domain.NoteStatusArchived:
+		if err := note.Archive(now); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, rowInvalid(nil, fmt.Sprintf("status %q cannot be imported, only active and archived can", status))
+	}
+
+	if record.ID != "" {
+		given[note.ID] = row
+	}
+
+	return note, nil
+}
+
+// dropTaken leaves out the notes whose given id the owner already has, in a
+// single query inside the import transaction, and reports their rows. Ids
+// taken by other owners are replaced rather than reported, so an import does
+// not tell which ids other owners have.
+func (s *Service) dropTaken(
+	ctx context.Context,
+	repo domain.NoteRepository,
+	notes []*domain.Note,
+	given map[core.ID]Row,
+	result *ImportResult,
+) ([]*domain.Note, error) {
+	if len(given) == 0 {
+		return notes, nil
+	}
+
+	owners, err := repo.FindIDOwners(ctx, slices.Collect(maps.Keys(given)))
+	if err != nil {
+		return nil, domain.ErrNoteFindFailed.Wrap(err)
+	}
+
+	if len(owners) == 0 {
+		return notes, nil
+	}
+
+	notes = slices.DeleteFunc(notes, func(note *domain.Note) bool {
+		owner, taken := owners[note.ID]
+		if !taken {
+			return false
+		}
+
+		if owner != note.OwnerID {
+			note.ID = s.ids.NewID()
+
+			return false
+		}
+
+		result.Errors = append(result.Errors, rowError(given[note.ID], noteExists(note.ID)))
+
+		return true
+	})
+
+	slices.SortStableFunc(result.Errors, func(a, b RowError) int {
+		return cmp.Compare(a.Row, b.Row)
+	})
+
+	return notes, nil
+}
+
+func noteExists(id core.ID) *apperr.Error {
+	return domain.ErrNoteAlreadyExists.New().WithDetails(map[string]core.ID{"id": id})
+}
+
+func rowError(row Row, err *apperr.Error) RowError {
+	return RowError{
+		Row:     row.Index,
+		File:    row.File,
+		Code:    err.Code,
+		Message: err.Message,
+		Details: err.Details,
+	}
+}
+
+func (s *Service) create(ctx context.Context, input domain.TransactionManagerInput, note *domain.Note, now time.Time) error {
+	if err := input.NoteRepository.Create(ctx, note); err != nil {
+		return domain.ErrNoteCreateFailed.Propagate(err)
+	}
+
+	tags := make([]domain.Tag, 0, len(note.Tags))
+
+	for _, name := range note.Tags {
+		tags = append(tags, domain.Tag{ID: s.ids.NewID(), Name: name, CreatedAt: now})
+	}
+
+	if err := input.NoteRepository.AddTags(ctx, note.ID, tags); err != nil {
+		return domain.ErrNoteTagsSaveFailed.Propagate(err)
+	}
+
+	// The first version never exceeds any retention.
+	if err := domain.RecordRevision(ctx, input.RevisionRepository, note, 0); err != nil {
+		return err
+	}
+
+	event := domain.NewEvent(s.ids.NewID(), domain.EventNoteCreated, note, now)
+	if err := input.Events.Publish(ctx, event); err != nil {
+		return domain.ErrNoteEventPublishFailed.Wrap(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,420 @@
+package transfernotes_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/feature/transfernotes"
+	"HATCH_APP/internal/note/mocks"
+	"HATCH_APP/internal/shared/auth"
+	"HATCH_APP/pkg/core"
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type serviceSuite struct {
+	repo         *mocks.NoteRepository
+	revisionRepo *mocks.RevisionRepository
+	events       *mocks.EventPublisher
+	service      *transfernotes.Service
+}
+
+var (
+	now       = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	noteID    = core.MustParseID("01JWN3V0G0000000000000000A")
+	otherID   = core.MustParseID("01JWN3V0G0000000000000000B")
+	newID     = core.MustParseID("01JWN3V0G0000000000000000C")
+	createdAt = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	limits    = transfernotes.Limits{MaxUnzippedSize: 4 << 10, MaxRows: 10}
+)
+
+func setupServiceSuite(t *testing.T) *serviceSuite {
+	repo := mocks.NewNoteRepository(t)
+	revisionRepo := mocks.NewRevisionRepository(t)
+	events := mocks.NewEventPublisher(t)
+	txManager := mocks.NewTransactionManager(t)
+
+	txManager.On("Transact", mock.Anything).
+		Return(func(fn func(domain.TransactionManagerInput) error) error {
+			return fn(domain.TransactionManagerInput{
+				NoteRepository:     repo,
+				RevisionRepository: revisionRepo,
+				Events:             events,
+			})
+		}).
+		Maybe()
+
+	return &serviceSuite{
+		repo:         repo,
+		revisionRepo: revisionRepo,
+		events:       events,
+		service:      transfernotes.NewService(repo, txManager, core.FixedIDs(newID), core.FixedClock(now), limits),
+	}
+}
+
+// exportedNotes are the notes the repository hands to export.
+func exportedNotes() []*domain.Note {
+	updatedAt := createdAt.Add(time.Hour)
+
+	return []*domain.Note{
+		{
+			ID:        noteID,
+			Title:     `Say "hi"`,
+			Content:   "line one\nline two, with a comma\n",
+			Status:    domain.NoteStatusActive,
+			Format:    domain.NoteFormatMarkdown,
+			Version:   2,
+			Tags:      []string{"home", "work"},
+			CreatedAt: createdAt,
+			UpdatedAt: &updatedAt,
+		},
+		{
+			ID:        otherID,
+			Title:     "Archived",
+			Content:   "",
+			Status:    domain.NoteStatusArchived,
+			Format:    domain.NoteFormatPlain,
+			Version:   1,
+			Tags:      []string{},
+			CreatedAt: createdAt,
+			UpdatedAt: &updatedAt,
+		},
+	}
+}
+
+// notesSeq yields notes, then err when set.
+func notesSeq(notes []*domain.Note, err error) iter.Seq2[*domain.Note, error] {
+	return func(yield func(*domain.Note, error) bool) {
+		for _, note := range notes {
+			if !yield(note, nil) {
+				return
+			}
+		}
+
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+func (s *serviceSuite) onExport(notes []*domain.Note) {
+	s.repo.On("Iterate", mock.Anything, domain.NoteFilter{Owner: new("")}).
+		Return(notesSeq(notes, nil)).
+		Once()
+}
+
+func (s *serviceSuite) onUnusedIDs() {
+	s.repo.On("FindIDOwners", mock.Anything, mock.Anything).Return(map[core.ID]string{}, nil).Once()
+}
+
+func TestServiceExportNotes(t *testing.T) {
+	t.Run("should write a note per line in jsonl", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		s.onExport(exportedNotes())
+
+		var out bytes.Buffer
+		require.NoError(t, s.service.ExportNotes(t.Context(), transfernotes.FormatJSONL, &out))
+
+		lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+		require.Len(t, lines, 2)
+		assert.Contains(t, lines[0], `"id":"`+noteID.String()+`"`)
+		assert.Contains(t, lines[0], `"tags":["home","work"]`)
+		assert.Contains(t, lines[1], `"status":"archived"`)
+	})
+
+	t.Run("should write a header and a record per note in csv", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		s.onExport(exportedNotes())
+
+		var out bytes.Buffer
+		require.NoError(t, s.service.ExportNotes(t.Context(), transfernotes.FormatCSV, &out))
+
+		assert.True(t, strings.HasPrefix(out.String(), "id,title,content,status,format,version,tags,created_at,updated_at\n"))
+		assert.Contains(t, out.String(), `"Say ""hi"""`)
+		assert.Contains(t, out.String(), `"home,work"`)
+	})
+
+	t.Run("should write a markdown file per note in zip", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		s.onExport(exportedNotes())
+
+		var out bytes.Buffer
+		require.NoError(t, s.service.ExportNotes(t.Context(), transfernotes.FormatZip, &out))
+
+		archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+		require.NoError(t, err)
+		require.Len(t, archive.File, 2)
+		assert.Equal(t, noteID.String()+".md", archive.File[0].Name)
+
+		f, err := archive.File[0].Open()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = f.Close() })
+
+		var content bytes.Buffer
+		_, err = content.ReadFrom(f)
+		require.NoError(t, err)
+
+		assert.Equal(t, "---\n"+
+			"id: "+noteID.String()+"\n"+
+			"title: \"Say \\\"hi\\\"\"\n"+
+			"status: active\n"+
+			"format: markdown\n"+
+			"version: 2\n"+
+			"tags: [\"home\",\"work\"]\n"+
+			"created_at: 2024-01-02T03:04:05Z\n"+
+			"updated_at: 2024-01-02T04:04:05Z\n"+
+			"---\n"+
+			"line one\nline two, with a comma\n", content.String())
+	})
+
+	t.Run("should only write the notes of the owner", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		s.repo.On("Iterate", mock.Anything, domain.NoteFilter{Owner: new("acme")}).
+			Return(notesSeq(exportedNotes()[:1], nil)).
+			Once()
+
+		var out bytes.Buffer
+		require.NoError(t, s.service.ExportNotes(auth.WithOwner(t.Context(), "acme"), transfernotes.FormatJSONL, &out))
+
+		assert.Contains(t, out.String(), noteID.String())
+	})
+
+	t.Run("should fail when the notes cannot be read", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		s.repo.On("Iterate", mock.Anything, mock.Anything).
+			Return(notesSeq(exportedNotes()[:1], errors.New("connection reset"))).
+			Once()
+
+		err := s.service.ExportNotes(t.Context(), transfernotes.FormatJSONL, &bytes.Buffer{})
+
+		require.Error(t, err)
+		assert.True(t, domain.ErrNoteExportFailed.Is(err))
+	})
+}
+
+func TestServiceImportNotes(t *testing.T) {
+	for _, format := range []transfernotes.Format{transfernotes.FormatJSONL, transfernotes.FormatCSV, transfernotes.FormatZip} {
+		t.Run("should read back its own "+string(format)+" export", func(t *testing.T) {
+			s := setupServiceSuite(t)
+			s.onExport(exportedNotes())
+			s.onUnusedIDs()
+
+			var out bytes.Buffer
+			require.NoError(t, s.service.ExportNotes(t.Context(), format, &out))
+
+			s.repo.On("Create", mock.Anything, mock.MatchedBy(func(n *domain.Note) bool {
+				return n.ID == noteID &&
+					n.Title == `Say "hi"` &&
+					n.Content == "line one\nline two, with a comma\n" &&
+					n.Format == domain.NoteFormatMarkdown &&
+					n.Status == domain.NoteStatusActive &&
+					n.Version == 1 &&
+					n.CreatedAt.Equal(createdAt) &&
+					assert.ObjectsAreEqual([]string{"home", "work"}, n.Tags)
+			})).
+				Return(nil).
+				Once()
+			s.repo.On("Create", mock.Anything, mock.MatchedBy(func(n *domain.Note) bool {
+				return n.ID == otherID && n.Status == domain.NoteStatusArchived
+			})).
+				Return(nil).
+				Once()
+			s.repo.On("AddTags", mock.Anything, noteID, mock.MatchedBy(func(tags []domain.Tag) bool {
+				return len(tags) == 2 && tags[0].Name == "home" && tags[1].Name == "work"
+			})).
+				Return(nil).
+				Once()
+			s.repo.On("AddTags", mock.Anything, otherID, []domain.Tag{}).
+				Return(nil).
+				Once()
+			s.revisionRepo.On("CreateRevision", mock.Anything, mock.Anything).
+				Return(nil).
+				Twice()
+			s.events.On("Publish", mock.Anything, mock.MatchedBy(func(e domain.Event) bool {
+				return e.Type == domain.EventNoteCreated
+			})).
+				Return(nil).
+				Twice()
+
+			result, err := s.service.ImportNotes(t.Context(), format, &out, false)
+
+			require.NoError(t, err)
+			assert.Empty(t, result.Errors)
+			assert.Equal(t, 2, result.Imported)
+			assert.False(t, result.DryRun)
+		})
+	}
+
+	t.Run("should report the rows left out", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		s.repo.On("FindIDOwners", mock.Anything, mock.MatchedBy(func(ids []core.ID) bool {
+			return assert.ElementsMatch(t, []core.ID{noteID, otherID}, ids)
+		})).
+			Return(map[core.ID]string{otherID: ""}, nil).
+			Once()
+
+		file := strings.Join([]string{
+			`{"id":"` + noteID.String() + `","title":"kept"}`,
+			`not json`,
+			``,
+			`{"title":""}`,
+			`{"id":"` + noteID.String() + `","title":"twice"}`,
+			`{"id":"` + otherID.String() + `","title":"taken"}`,
+			`{"id":"nope","title":"bad id"}`,
+			`{"title":"trashed","status":"trashed"}`,
+			`{"title":"html","format":"html"}`,
+			`{"title":"bad tag","tags":["a/b"]}`,
+		}, "\n")
+
+		result, err := s.service.ImportNotes(t.Context(), transfernotes.FormatJSONL, strings.NewReader(file), true)
+
+		require.NoError(t, err)
+		assert.True(t, result.DryRun)
+		assert.Equal(t, 1, result.Imported)
+
+		rows := make(map[int]string, len(result.Errors))
+		order := make([]int, 0, len(result.Errors))
+
+		for _, e := range result.Errors {
+			rows[e.Row] = e.Code
+			order = append(order, e.Row)
+		}
+
+		assert.IsIncreasing(t, order)
+
+		assert.Equal(t, map[int]string{
+			2:  domain.ErrNoteImportRowInvalid.ID,
+			4:  domain.ErrNoteTitleBlank.ID,
+			5:  domain.ErrNoteAlreadyExists.ID,
+			6:  domain.ErrNoteAlreadyExists.ID,
+			7:  domain.ErrNoteImportRowInvalid.ID,
+			8:  domain.ErrNoteImportRowInvalid.ID,
+			9:  domain.ErrNoteFormatInvalid.ID,
+			10: domain.ErrNoteTagInvalid.ID,
+		}, rows)
+	})
+
+	t.Run("should refuse csv without a title column", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		_, err := s.service.ImportNotes(t.Context(), transfernotes.FormatCSV, strings.NewReader("id,content\n1,2\n"), false)
+
+		require.Error(t, err)
+		assert.True(t, domain.ErrNoteImportInvalid.Is(err))
+	})
+
+	t.Run("should refuse zip files that are not archives", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		_, err := s.service.ImportNotes(t.Context(), transfernotes.FormatZip, strings.NewReader("not a zip"), false)
+
+		require.Error(t, err)
+		assert.True(t, domain.ErrNoteImportInvalid.Is(err))
+	})
+
+	t.Run("should refuse files over the row limit", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		file := strings.Repeat("{\"title\":\"note\"}\n", limits.MaxRows+1)
+
+		_, err := s.service.ImportNotes(t.Context(), transfernotes.FormatJSONL, strings.NewReader(file), false)
+
+		require.Error(t, err)
+		assert.True(t, domain.ErrNoteImportTooManyRows.Is(err))
+	})
+
+	t.Run("should refuse archives over the decompressed size limit", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		var archive bytes.Buffer
+
+		w := zip.NewWriter(&archive)
+
+		for i := range 3 {
+			file, err := w.Create(fmt.Sprintf("note-%d.md", i))
+			require.NoError(t, err)
+
+			_, err = file.Write([]byte("---\ntitle: note\n---\n" + strings.Repeat("a", 2<<10)))
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, w.Close())
+		require.Less(t, int64(archive.Len()), limits.MaxUnzippedSize)
+
+		_, err := s.service.ImportNotes(t.Context(), transfernotes.FormatZip, &archive, false)
+
+		require.Error(t, err)
+		assert.True(t, domain.ErrNoteImportUnzippedTooLarge.Is(err))
+	})
+
+	t.Run("should replace ids taken by other owners", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		s.repo.On("FindIDOwners", mock.Anything, []core.ID{otherID}).
+			Return(map[core.ID]string{otherID: "globex"}, nil).
+			Once()
+		s.repo.On("Create", mock.Anything, mock.MatchedBy(func(n *domain.Note) bool {
+			return n.ID == newID && n.OwnerID == "acme"
+		})).
+			Return(nil).
+			Once()
+		s.repo.On("AddTags", mock.Anything, newID, []domain.Tag{}).
+			Return(nil).
+			Once()
+		s.revisionRepo.On("CreateRevision", mock.Anything, mock.Anything).
+			Return(nil).
+			Once()
+		s.events.On("Publish", mock.Anything, mock.Anything).
+			Return(nil).
+			Once()
+
+		file := `{"id":"` + otherID.String() + `","title":"theirs"}`
+
+		result, err := s.service.ImportNotes(auth.WithOwner(t.Context(), "acme"), transfernotes.FormatJSONL, strings.NewReader(file), false)
+
+		require.NoError(t, err)
+		assert.Empty(t, result.Errors)
+		assert.Equal(t, 1, result.Imported)
+	})
+
+	t.Run("should import nothing when the ids cannot be checked", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		s.repo.On("FindIDOwners", mock.Anything, []core.ID{noteID}).
+			Return(nil, errors.New("connection reset")).
+			Once()
+
+		file := `{"id":"` + noteID.String() + `","title":"one"}`
+
+		_, err := s.service.ImportNotes(t.Context(), transfernotes.FormatJSONL, strings.NewReader(file), false)
+
+		require.Error(t, err)
+		assert.True(t, domain.ErrNoteFindFailed.Is(err))
+	})
+
+	t.Run("should import nothing when a note cannot be created", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		s.repo.On("Create", mock.Anything, mock.Anything).
+			Return(errors.New("connection reset")).
+			Once()
+
+		file := "{\"title\":\"one\"}\n{\"title\":\"two\"}\n"
+
+		_, err := s.service.ImportNotes(t.Context(), transfernotes.FormatJSONL, strings.NewReader(file), false)
+
+		require.Error(t, err)
+		assert.True(t, domain.ErrNoteCreateFailed.Is(err))
+	})
+}
@@ -0,0 +1,28 @@
+package trashnote
+
+import (
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/transport/httpx"
+	"net/http"
+)
+
+func (f *Feature) TrashNoteEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "TrashNote")
+
+	id, err := httpx.PathID(w, r, "id")
+	if err != nil {
+		log.WarnContext(ctx, "invalid id", "error", err)
+		return
+	}
+
+	log = log.With("note_id", id)
+
+	if err = f.service.TrashNote(ctx, id); err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	httpx.WriteEmptyResponse(w)
+}
@@ -0,0 +1,110 @@
+package trashnote_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/feature/trashnote"
+	"HATCH_APP/internal/note/infra/store/postgres"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/transport/httpx"
+	"HATCH_APP/test/container"
+	"HATCH_APP/test/httptest"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type httpSuite struct {
+	repo *postgres.NoteRepository
+	feat *trashnote.Feature
+}
+
+func setupHTTPSuite(t *testing.T) *httpSuite {
+	db, dbTeardown := container.SetupPostgres(t)
+
+	t.Cleanup(func() {
+		dbTeardown()
+	})
+
+	repo, err := postgres.NewNoteRepository(db)
+	require.NoError(t, err)
+
+	return &httpSuite{
+		repo: repo,
+		feat: trashnote.New(postgres.NewTransactionManager(db), core.NewULIDGenerator(core.SystemClock()), core.SystemClock()),
+	}
+}
+
+func TestTrashNoteEndpoint(t *testing.T) {
+	s := setupHTTPSuite(t)
+	httptest.Init()
+
+	tests := []struct {
+		name string
+		tc   httptest.Case
+	}{
+		{
+			name: "should move note to trash successfully",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					note, err := domain.NewNote(core.NewID(), time.Now(), "Test Note", "Test Content")
+					require.NoError(t, err)
+
+					err = s.repo.Create(t.Context(), note)
+					require.NoError(t, err)
+
+					return httptest.WithParam(
+						httptest.NewRequest(http.MethodDelete, "/api/v1/notes/"+note.ID.String()),
+						"id",
+						note.ID.String(),
+					)
+				},
+				ExpectStatus: http.StatusNoContent,
+				CheckResponse: func(t *testing.T, body []byte) {
+					assert.Empty(t, body)
+				},
+			},
+		},
+		{
+			name: "should return 404 when note not found",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					id := core.NewID().String()
+
+					return httptest.WithParam(httptest.NewRequest(http.MethodDelete, "/api/v1/notes/"+id), "id", id)
+				},
+				ExpectStatus: http.StatusNotFound,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrNoteNotFound.ID, resp.Code)
+				},
+			},
+		},
+		{
+			name: "should return 400 when id is malformed",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					return httptest.WithParam(httptest.NewRequest(http.MethodDelete, "/api/v1/notes/"+"not-an-id"), "id", "not-an-id")
+				},
+				ExpectStatus: http.StatusBadRequest,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, core.ErrInvalidID.ID, resp.Code)
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			httptest.Run(t, s.feat.TrashNoteEndpoint, tc.tc)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package trashnote_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/feature/trashnote"
+	"HATCH_APP/internal/note/mocks"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/core/apperr"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type serviceSuite struct {
+	repo    *mocks.NoteRepository
+	events  *mocks.EventPublisher
+	service *trashnote.Service
+}
+
+var eventID = core.MustParseID("01JWN3V0G0000000000000000E")
+
+func setupSuite(t *testing.T) *serviceSuite {
+	repo := mocks.NewNoteRepository(t)
+	events := mocks.NewEventPublisher(t)
+	txManager := mocks.NewTransactionManager(t)
+
+	txManager.On("Transact", mock.Anything).
+		Return(func(fn func(domain.TransactionManagerInput) error) error {
+			return fn(domain.TransactionManagerInput{
+				NoteRepository: repo,
+				Events:         events,
+			})
+		}).
+		Maybe()
+
+	service := trashnote.NewService(txManager, core.FixedIDs(eventID), core.SystemClock())
+
+	return &serviceSuite{
+		repo:    repo,
+		events:  events,
+		service: service,
+	}
+}
+
+func TestServiceTrashNote(t *testing.T) {
+	tests := []struct {
+		arrange   func(t *testing.T, s *serviceSuite) core.ID
+		assertErr func(t *testing.T, err error)
+		name      string
+	}{
+		{
+			name: "should trash successfully",
+			arrange: func(t *testing.T, s *serviceSuite) core.ID {
+				n, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+				require.NoError(t, err)
+
+				s.repo.On("FindByID", t.Context(), n.ID).
+					Return(n, nil).
+					Once()
+
+				s.repo.On("Save", t.Context(), mock.MatchedBy(func(note *domain.Note) bool {
+					return note.ID == n.ID &&
+						note.IsTrashed() &&
+						note.DeletedAt != nil
+				})).
+					Return(nil).
+					Once()
+
+				s.events.On("Publish", t.Context(), mock.MatchedBy(func(e domain.Event) bool {
+					return e.ID == eventID && e.Type == domain.EventNoteTrashed && e.Note.ID == n.ID
+				})).
+					Return(nil).
+					Once()
+
+				return n.ID
+			},
+			assertErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "should return not found when note does not exist",
+			arrange: func(t *testing.T, s *serviceSuite) core.ID {
+				id := core.NewID()
+
+				s.repo.On("FindByID", t.Context(), id).
+					Return((*domain.Note)(nil), nil).
+					Once()
+
+				return id
+			},
+			assertErr: func(t *testing.T, err error) {
+				require.Error(t, err)
+				assert.True(t, domain.ErrNoteNotFound.Is(err))
+			},
+		},
+		{
+			name: "should return error when FindByID fails",
+			arrange: func(t *testing.T, s *serviceSuite) core.ID {
+				id := core.NewID()
+
+				s.repo.On("FindByID", t.Context(), id).
+					Return((*domain.Note)(nil), errors.New("repo down")).
+					Once()
+
+				return id
+			},
+			assertErr: func(t *testing.T, err error) {
+				require.Error(t, err)
+				assert.True(t, apperr.IsInternal(err))
+			},
+		},
+		{
+			name: "should return error when Save fails",
+			arrange: func(t *testing.T, s *serviceSuite) core.ID {
+				n, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+				require.NoError(t, err)
+
+				s.repo.On("FindByID", t.Context(), n.ID).
+					Return(n, nil).
+					Once()
+
+				s.repo.On("Save", t.Context(), mock.Anything).
+					Return(errors.New("save error")).
+					Once()
+
+				return n.ID
+			},
+			assertErr: func(t *testing.T, err error) {
+				require.Error(t, err)
+				assert.True(t, domain.ErrNoteSaveFailed.Is(err))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			s := setupSuite(t)
+
+			noteID := tc.arrange(t, s)
+
+			err := s.service.TrashNote(t.Context(), noteID)
+
+			tc.assertErr(t, err)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package updatenote
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/transport/httpx"
+	"fmt"
+	"net/http"
+)
+
+// Request mirrors domain.MaxTitleLength and domain.MaxContentLength, the
+// domain still has the final say.
+type Request struct {
+	Title   string            `json:"title"            validate:"required,notblank,max=200"`
+	Content string            `json:"content"          validate:"required,max=100000"`
+	Format  domain.NoteFormat `json:"format,omitempty" validate:"omitempty,oneof=plain markdown"`
+}
+
+type Response struct {
+	Message string       `json:"message"`
+	Data    *domain.Note `json:"data"`
+}
+
+func (f *Feature) UpdateNoteEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "UpdateNote")
+
+	id, err := httpx.PathID(w, r, "id")
+	if err != nil {
+		log.WarnContext(ctx, "invalid id", "error", err)
+		return
+	}
+
+	log = log.With("note_id", id)
+
+	req, err := httpx.ParseRequest[Request](w, r)
+	if err != nil {
+		log.WarnContext(ctx, "invalid payload", "error", err)
+		return
+	}
+
+	note, err := f.service.UpdateNote(ctx, id, req.Title, req.Content, string(req.Format))
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	httpx.WriteOKResponse(w, Response{
+		Message: fmt.Sprintf("note at version %d", note.Version),
+		Data:    note,
+	})
+}
@@ -0,0 +1,173 @@
+package updatenote_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/feature/updatenote"
+	"HATCH_APP/internal/note/infra/store/postgres"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/transport/httpx"
+	"HATCH_APP/test/container"
+	"HATCH_APP/test/httptest"
+	"bytes"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type httpSuite struct {
+	repo         *postgres.NoteRepository
+	revisionRepo *postgres.RevisionRepository
+	txManager    *postgres.TransactionManager
+	feat         *updatenote.Feature
+}
+
+func setupHTTPSuite(t *testing.T) *httpSuite {
+	db, dbTeardown := container.SetupPostgres(t)
+
+	t.Cleanup(func() {
+		dbTeardown()
+	})
+
+	repo, err := postgres.NewNoteRepository(db)
+	require.NoError(t, err)
+
+	revisionRepo, err := postgres.NewRevisionRepository(db)
+	require.NoError(t, err)
+
+	txManager := postgres.NewTransactionManager(db)
+
+	return &httpSuite{
+		repo:         repo,
+		revisionRepo: revisionRepo,
+		txManager:    txManager,
+		feat:         updatenote.New(txManager, core.NewULIDGenerator(core.SystemClock()), core.SystemClock(), 2),
+	}
+}
+
+func (s *httpSuite) createNote(t *testing.T) *domain.Note {
+	n, err := domain.NewNote(core.NewID(), time.Now(), "Test Note", "Test Content")
+	require.NoError(t, err)
+
+	require.NoError(t, s.txManager.Transact(func(input domain.TransactionManagerInput) error {
+		if err := input.NoteRepository.Create(t.Context(), n); err != nil {
+			return err
+		}
+
+		return domain.RecordRevision(t.Context(), input.RevisionRepository, n, 0)
+	}))
+
+	return n
+}
+
+func TestUpdateNoteEndpoint(t *testing.T) {
+	s := setupHTTPSuite(t)
+	httptest.Init()
+
+	update := func(id, body string) *http.Request {
+		req := stdhttptest.NewRequest(http.MethodPut, "/api/v1/notes/"+id, bytes.NewReader([]byte(body)))
+
+		return httptest.WithParam(req, "id", id)
+	}
+
+	tests := []struct {
+		tc   httptest.Case
+		name string
+	}{
+		{
+			name: "should update note and record a revision",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					n := s.createNote(t)
+
+					return update(n.ID.String(), `{"title": "Edited", "content": "Edited Content"}`)
+				},
+				Headers:      map[string]string{"Content-Type": "application/json"},
+				ExpectStatus: http.StatusOK,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[updatenote.Response](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, 2, resp.Data.Version)
+					assert.Equal(t, "Edited", resp.Data.Title)
+
+					revisions, err := s.revisionRepo.ListRevisions(t.Context(), resp.Data.ID)
+					require.NoError(t, err)
+					require.Len(t, revisions, 2)
+					assert.Equal(t, "Edited Content", revisions[0].Content)
+				},
+			},
+		},
+		{
+			name: "should keep only the newest revisions",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					n := s.createNote(t)
+
+					_, err := n.Edit("Test Note", "Second", time.Now())
+					require.NoError(t, err)
+					require.NoError(t, s.repo.Save(t.Context(), n))
+					require.NoError(t, domain.RecordRevision(t.Context(), s.revisionRepo, n, 0))
+
+					return update(n.ID.String(), `{"title": "Test Note", "content": "Third"}`)
+				},
+				Headers:      map[string]string{"Content-Type": "application/json"},
+				ExpectStatus: http.StatusOK,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[updatenote.Response](body)
+					require.NoError(t, err)
+
+					revisions, err := s.revisionRepo.ListRevisions(t.Context(), resp.Data.ID)
+					require.NoError(t, err)
+					require.Len(t, revisions, 2)
+					assert.Equal(t, 3, revisions[0].Version)
+					assert.Equal(t, 2, revisions[1].Version)
+				},
+			},
+		},
+		{
+			name: "should return 400 when title is blank",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					n := s.createNote(t)
+
+					return update(n.ID.String(), `{"title": "   ", "content": "Content"}`)
+				},
+				Headers:      map[string]string{"Content-Type": "application/json"},
+				ExpectStatus: http.StatusBadRequest,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Contains(t, resp.Message, "invalid payload")
+				},
+			},
+		},
+		{
+			name: "should return 404 when note not found",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					return update(core.NewID().String(), `{"title": "Title", "content": "Content"}`)
+				},
+				Headers:      map[string]string{"Content-Type": "application/json"},
+				ExpectStatus: http.StatusNotFound,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrNoteNotFound.ID, resp.Code)
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			httptest.Run(t, s.feat.UpdateNoteEndpoint, tc.tc)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package updatenote
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/core"
+	"context"
+)
+
+type Service struct {
+	txManager domain.TransactionManager
+	ids       core.IDGenerator
+	clock     core.Clock
+	retention int
+}
+
+// NewService keeps the newest retention revisions of each note, all of them
+// when retention <= 0.
+func NewService(
+	txManager domain.TransactionManager,
+	ids core.IDGenerator,
+	clock core.Clock,
+	retention int,
+) *Service {
+	return &Service{
+		txManager: txManager,
+		ids:       ids,
+		clock:     clock,
+		retention: retention,
+	}
+}
+
+// UpdateNote edits the note and records the new version as a revision, an
+// empty format keeps the current one. Concurrent edits of one note race on
+// the same version, the loser gets a conflict.
+func (s *Service) UpdateNote(ctx context.Context, id core.ID, title, content, format string) (*domain.Note, error) {
+	var note *domain.Note
+
+	err := s.txManager.Transact(func(input domain.TransactionManagerInput) error {
+		found, err := input.NoteRepository.FindByID(ctx, id)
+		if err != nil {
+			return domain.ErrNoteFindFailed.Propagate(err)
+		}
+
+		if found == nil {
+			return domain.ErrNoteNotFound.New()
+		}
+
+		now := s.clock.Now()
+
+		edited, err := found.Edit(title, content, now)
+		if err != nil {
+			return err
+		}
+
+		reformatted := false
+
+		if format != "" {
+			noteFormat, err := domain.ParseNoteFormat(format)
+			if err != nil {
+				return err
+			}
+
+			reformatted = found.ChangeFormat(noteFormat, now)
+		}
+
+		note = found
+
+		if !edited && !reformatted {
+			return nil
+		}
+
+		if err := input.NoteRepository.Save(ctx, found); err != nil {
+			return domain.ErrNoteSaveFailed.Propagate(err)
+		}
+
+		if err := input.Events.Publish(ctx, domain.NewEvent(s.ids.NewID(), domain.EventNoteUpdated, found, now)); err != nil {
+			return domain.ErrNoteEventPublishFailed.Wrap(err)
+		}
+
+		if !edited {
+			return nil
+		}
+
+		return domain.RecordRevision(ctx, input.RevisionRepository, found, s.retention)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return note, nil
+}
@@ -0,0 +1,337 @@
+package updatenote_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/feature/updatenote"
+	"HATCH_APP/internal/note/mocks"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/core/apperr"
+	"HATCH_APP/pkg/store/postgres"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type serviceSuite struct {
+	repo         *mocks.NoteRepository
+	revisionRepo *mocks.RevisionRepository
+	events       *mocks.EventPublisher
+	service      *updatenote.Service
+}
+
+var (
+	created = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	now     = created.Add(time.Hour)
+	eventID = core.NewID()
+)
+
+func setupServiceSuite(t *testing.T) *serviceSuite {
+	repo := mocks.NewNoteRepository(t)
+	revisionRepo := mocks.NewRevisionRepository(t)
+	events := mocks.NewEventPublisher(t)
+	txManager := mocks.NewTransactionManager(t)
+
+	txManager.On("Transact", mock.Anything).
+		Return(func(fn func(domain.TransactionManagerInput) error) error {
+			return fn(domain.TransactionManagerInput{
+				NoteRepository:     repo,
+				RevisionRepository: revisionRepo,
+				Events:             events,
+			})
+		}).
+		Maybe()
+
+	return &serviceSuite{
+		repo:         repo,
+		revisionRepo: revisionRepo,
+		events:       events,
+		service:      updatenote.NewService(txManager, core.FixedIDs(eventID), core.FixedClock(now), 2),
+	}
+}
+
+func TestServiceUpdateNote(t *testing.T) {
+	tests := []struct {
+		arrange func(t *testing.T, s *serviceSuite) *domain.Note
+		assert  func(t *testing.T, note *domain.Note, err error)
+		name    string
+		title   string
+		content string
+		format  string
+	}{
+		{
+			name:    "should save the edit and record it",
+			title:   "New title",
+			content: "new content",
+			arrange: func(t *testing.T, s *serviceSuite) *domain.Note {
+				n, err := domain.NewNote(core.NewID(), created, "title", "content")
+				require.NoError(t, err)
+
+				s.repo.On("FindByID", t.Context(), n.ID).
+					Return(n, nil).
+					Once()
+
+				s.repo.On("Save", t.Context(), mock.MatchedBy(func(note *domain.Note) bool {
+					return note.Version == 2 && note.Title == "New title"
+				})).
+					Return(nil).
+					Once()
+
+				s.events.On("Publish", t.Context(), mock.MatchedBy(func(event domain.Event) bool {
+					return event.ID == eventID && event.Type == domain.EventNoteUpdated
+				})).
+					Return(nil).
+					Once()
+
+				s.revisionRepo.On("CreateRevision", t.Context(), domain.NoteRevision{
+					NoteID:    n.ID,
+					Version:   2,
+					Title:     "New title",
+					Content:   "new content",
+					CreatedAt: now,
+				}).
+					Return(nil).
+					Once()
+
+				return n
+			},
+			assert: func(t *testing.T, note *domain.Note, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, 2, note.Version)
+				assert.Equal(t, "new content", note.Content)
+			},
+		},
+		{
+			name:    "should prune revisions beyond retention",
+			title:   "title",
+			content: "third",
+			arrange: func(t *testing.T, s *serviceSuite) *domain.Note {
+				n, err := domain.NewNote(core.NewID(), created, "title", "content")
+				require.NoError(t, err)
+
+				_, err = n.Edit("title", "second", created)
+				require.NoError(t, err)
+
+				s.repo.On("FindByID", t.Context(), n.ID).
+					Return(n, nil).
+					Once()
+
+				s.repo.On("Save", t.Context(), n).
+					Return(nil).
+					Once()
+
+				s.events.On("Publish", t.Context(), mock.MatchedBy(func(event domain.Event) bool {
+					return event.ID == eventID && event.Type == domain.EventNoteUpdated
+				})).
+					Return(nil).
+					Once()
+
+				s.revisionRepo.On("CreateRevision", t.Context(), mock.Anything).
+					Return(nil).
+					Once()
+
+				s.revisionRepo.On("PruneRevisions", t.Context(), n.ID, 2).
+					Return(nil).
+					Once()
+
+				return n
+			},
+			assert: func(t *testing.T, note *domain.Note, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, 3, note.Version)
+			},
+		},
+		{
+			name:    "should not write unchanged notes",
+			title:   "title",
+			content: "content",
+			arrange: func(t *testing.T, s *serviceSuite) *domain.Note {
+				n, err := domain.NewNote(core.NewID(), created, "title", "content")
+				require.NoError(t, err)
+
+				s.repo.On("FindByID", t.Context(), n.ID).
+					Return(n, nil).
+					Once()
+
+				return n
+			},
+			assert: func(t *testing.T, note *domain.Note, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, 1, note.Version)
+			},
+		},
+		{
+			name:    "should save a format change without a revision",
+			title:   "title",
+			content: "content",
+			format:  "markdown",
+			arrange: func(t *testing.T, s *serviceSuite) *domain.Note {
+				n, err := domain.NewNote(core.NewID(), created, "title", "content")
+				require.NoError(t, err)
+
+				s.repo.On("FindByID", t.Context(), n.ID).
+					Return(n, nil).
+					Once()
+
+				s.repo.On("Save", t.Context(), n).
+					Return(nil).
+					Once()
+
+				s.events.On("Publish", t.Context(), mock.MatchedBy(func(event domain.Event) bool {
+					return event.ID == eventID && event.Type == domain.EventNoteUpdated
+				})).
+					Return(nil).
+					Once()
+
+				return n
+			},
+			assert: func(t *testing.T, note *domain.Note, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, domain.NoteFormatMarkdown, note.Format)
+				assert.Equal(t, 1, note.Version)
+			},
+		},
+		{
+			name:    "should return not found when note does not exist",
+			title:   "title",
+			content: "content",
+			arrange: func(t *testing.T, s *serviceSuite) *domain.Note {
+				n, err := domain.NewNote(core.NewID(), created, "title", "content")
+				require.NoError(t, err)
+
+				s.repo.On("FindByID", t.Context(), n.ID).
+					Return((*domain.Note)(nil), nil).
+					Once()
+
+				return n
+			},
+			assert: func(t *testing.T, note *domain.Note, err error) {
+				assert.Nil(t, note)
+				require.Error(t, err)
+				assert.True(t, domain.ErrNoteNotFound.Is(err))
+			},
+		},
+		{
+			name:    "should reject invalid edits",
+			title:   "   ",
+			content: "content",
+			arrange: func(t *testing.T, s *serviceSuite) *domain.Note {
+				n, err := domain.NewNote(core.NewID(), created, "title", "content")
+				require.NoError(t, err)
+
+				s.repo.On("FindByID", t.Context(), n.ID).
+					Return(n, nil).
+					Once()
+
+				return n
+			},
+			assert: func(t *testing.T, note *domain.Note, err error) {
+				assert.Nil(t, note)
+				require.Error(t, err)
+				assert.True(t, domain.ErrNoteTitleBlank.Is(err))
+			},
+		},
+		{
+			name:    "should return error when Save fails",
+			title:   "New title",
+			content: "content",
+			arrange: func(t *testing.T, s *serviceSuite) *domain.Note {
+				n, err := domain.NewNote(core.NewID(), created, "title", "content")
+				require.NoError(t, err)
+
+				s.repo.On("FindByID", t.Context(), n.ID).
+					Return(n, nil).
+					Once()
+
+				s.repo.On("Save", t.Context(), n).
+					Return(errors.New("save error")).
+					Once()
+
+				return n
+			},
+			assert: func(t *testing.T, note *domain.Note, err error) {
+				assert.Nil(t, note)
+				require.Error(t, err)
+				assert.True(t, domain.ErrNoteSaveFailed.Is(err))
+			},
+		},
+		{
+			name:    "should return error when Publish fails",
+			title:   "New title",
+			content: "content",
+			arrange: func(t *testing.T, s *serviceSuite) *domain.Note {
+				n, err := domain.NewNote(core.NewID(), created, "title", "content")
+				require.NoError(t, err)
+
+				s.repo.On("FindByID", t.Context(), n.ID).
+					Return(n, nil).
+					Once()
+
+				s.repo.On("Save", t.Context(), n).
+					Return(nil).
+					Once()
+
+				s.events.On("Publish", t.Context(), mock.Anything).
+					Return(errors.New("publish error")).
+					Once()
+
+				return n
+			},
+			assert: func(t *testing.T, note *domain.Note, err error) {
+				assert.Nil(t, note)
+				require.Error(t, err)
+				assert.True(t, domain.ErrNoteEventPublishFailed.Is(err))
+			},
+		},
+		{
+			name:    "should surface concurrent edits as conflicts",
+			title:   "New title",
+			content: "content",
+			arrange: func(t *testing.T, s *serviceSuite) *domain.Note {
+				n, err := domain.NewNote(core.NewID(), created, "title", "content")
+				require.NoError(t, err)
+
+				s.repo.On("FindByID", t.Context(), n.ID).
+					Return(n, nil).
+					Once()
+
+				s.repo.On("Save", t.Context(), n).
+					Return(nil).
+					Once()
+
+				s.events.On("Publish", t.Context(), mock.MatchedBy(func(event domain.Event) bool {
+					return event.ID == eventID && event.Type == domain.EventNoteUpdated
+				})).
+					Return(nil).
+					Once()
+
+				s.revisionRepo.On("CreateRevision", t.Context(), mock.Anything).
+					Return(postgres.ErrUniqueViolation.New()).
+					Once()
+
+				return n
+			},
+			assert: func(t *testing.T, note *domain.Note, err error) {
+				assert.Nil(t, note)
+				require.Error(t, err)
+				assert.True(t, apperr.IsConflict(err))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			s := setupServiceSuite(t)
+
+			n := tc.arrange(t, s)
+
+			note, err := s.service.UpdateNote(t.Context(), n.ID, tc.title, tc.content, tc.format)
+
+			tc.assert(t, note, err)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package note
+
+import (
+	"HATCH_APP/internal/note/feature/archivenote"
+	"HATCH_APP/internal/note/feature/createnote"
+	"HATCH_APP/internal/note/feature/listnotes"
+	"HATCH_APP/internal/note/pb"
+	"context"
+)
+
+// gRPCHandler adapts the features to pb.NoteServiceServer.
+type gRPCHandler struct {
+	pb.UnimplementedNoteServiceServer
+
+	createNoteF  *createnote.Feature
+	listNotesF   *listnotes.Feature
+	archiveNoteF *archivenote.Feature
+}
+
+func (h *gRPCHandler) CreateNote(ctx context.Context, req *pb.CreateNoteRequest) (*pb.CreateNoteResponse, error) {
+	return h.createNoteF.CreateNoteRPC(ctx, req)
+}
+
+func (h *gRPCHandler) ListNotes(ctx context.Context, req *pb.ListNotesRequest) (*pb.ListNotesResponse, error) {
+	return h.listNotesF.ListNotesRPC(ctx, req)
+}
+
+func (h *gRPCHandler) ArchiveNote(ctx context.Context, req *pb.ArchiveNoteRequest) (*pb.ArchiveNoteResponse, error) {
+	return h.archiveNoteF.ArchiveNoteRPC(ctx, req)
+}
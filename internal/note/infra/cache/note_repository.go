@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/cache"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/store/postgres"
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	noteKeyPrefix = "note:"
+	// generationKey holds the current generation of the note keys, bumping it
+	// drops every cached note at once.
+	generationKey = "note-generation"
+)
+
+// errNoteMissing keeps missing notes out of the cache, a note created or
+// restored by another replica would otherwise stay missing for the TTL.
+var errNoteMissing = errors.New("note missing")
+
+// NoteRepository caches FindByID lookups of the wrapped repository and
+// invalidates them on writes. Other methods go straight to the wrapped one.
+type NoteRepository struct {
+	domain.NoteRepository
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+func NewNoteRepository(repo domain.NoteRepository, c cache.Cache, ttl time.Duration) *NoteRepository {
+	return &NoteRepository{
+		NoteRepository: repo,
+		cache:          c,
+		ttl:            ttl,
+	}
+}
+
+// FindByID loads from the primary database, a lagging read replica would keep
+// a stale note cached for the whole TTL. Missing notes are not cached.
+func (r *NoteRepository) FindByID(ctx context.Context, id core.ID) (*domain.Note, error) {
+	note, err := cache.GetOrLoadJSON(ctx, r.cache, noteKey(ctx, r.cache, id), r.ttl, func(ctx context.Context) (*domain.Note, error) {
+		note, err := r.NoteRepository.FindByID(postgres.WithPrimary(ctx), id)
+		if err == nil && note == nil {
+			return nil, errNoteMissing
+		}
+
+		return note, err
+	})
+	if errors.Is(err, errNoteMissing) {
+		return nil, nil
+	}
+
+	return note, err
+}
+
+func (r *NoteRepository) Create(ctx context.Context, note *domain.Note) error {
+	if err := r.NoteRepository.Create(ctx, note); err != nil {
+		return err
+	}
+
+	r.Invalidate(ctx, note.ID)
+
+	return nil
+}
+
+func (r *NoteRepository) Save(ctx context.Context, note *domain.Note) error {
+	if err := r.NoteRepository.Save(ctx, note); err != nil {
+		return err
+	}
+
+	r.Invalidate(ctx, note.ID)
+
+	return nil
+}
+
+func (r *NoteRepository) AddTags(ctx context.Context, noteID core.ID, tags []domain.Tag) error {
+	if err := r.NoteRepository.AddTags(ctx, noteID, tags); err != nil {
+		return err
+	}
+
+	r.Invalidate(ctx, noteID)
+
+	return nil
+}
+
+func (r *NoteRepository) RemoveTags(ctx context.Context, noteID core.ID, names []string) error {
+	if err := r.NoteRepository.RemoveTags(ctx, noteID, names); err != nil {
+		return err
+	}
+
+	r.Invalidate(ctx, noteID)
+
+	return nil
+}
+
+// Invalidate drops the cached note, for writes made by other replicas.
+func (r *NoteRepository) Invalidate(ctx context.Context, id core.ID) {
+	invalidate(ctx, r.cache, id)
+}
+
+// Resync drops every cached note, for when the invalidations of other
+// replicas may have been missed.
+func (r *NoteRepository) Resync(ctx context.Context) {
+	err := r.cache.Set(ctx, generationKey, []byte(core.NewID().String()), 0)
+	if err != nil {
+		o11y.LoggerFromContext(ctx).ErrorContext(ctx, "cache: failed to resync notes", "error", err)
+	}
+}
+
+func invalidate(ctx context.Context, c cache.Cache, id core.ID) {
+	if err := c.Delete(ctx, noteKey(ctx, c, id)); err != nil {
+		o11y.LoggerFromContext(ctx).WarnContext(ctx, "cache: failed to invalidate note",
+			"note_id", id, "error", err)
+	}
+}
+
+func noteKey(ctx context.Context, c cache.Cache, id core.ID) string {
+	generation, err := c.Get(ctx, generationKey)
+	if err != nil && !errors.Is(err, cache.ErrMiss) {
+		o11y.LoggerFromContext(ctx).WarnContext(ctx, "cache: failed to load note generation", "error", err)
+	}
+
+	if len(generation) == 0 {
+		return noteKeyPrefix + id.String()
+	}
+
+	return noteKeyPrefix + string(generation) + ":" + id.String()
+}
@@ -0,0 +1,172 @@
+package cache_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/infra/cache"
+	"HATCH_APP/internal/note/mocks"
+	"HATCH_APP/pkg/cache/memory"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/store/postgres"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type suite struct {
+	repo   *mocks.NoteRepository
+	cached *cache.NoteRepository
+}
+
+func setupSuite(t *testing.T) *suite {
+	o11y.InitLogger()
+
+	repo := mocks.NewNoteRepository(t)
+
+	return &suite{
+		repo:   repo,
+		cached: cache.NewNoteRepository(repo, memory.NewLRU(10), time.Minute),
+	}
+}
+
+func TestNoteRepositoryFindByID(t *testing.T) {
+	t.Run("should hit the repository once", func(t *testing.T) {
+		s := setupSuite(t)
+		n, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+		require.NoError(t, err)
+
+		// From the primary, replicas may lag behind.
+		s.repo.On("FindByID", mock.MatchedBy(func(ctx context.Context) bool {
+			return postgres.NeedsPrimary(ctx, false)
+		}), n.ID).
+			Return(n, nil).
+			Once()
+
+		for range 3 {
+			found, err := s.cached.FindByID(t.Context(), n.ID)
+
+			require.NoError(t, err)
+			assert.Equal(t, n.ID, found.ID)
+			assert.Equal(t, n.Title, found.Title)
+		}
+	})
+
+	t.Run("should not cache errors", func(t *testing.T) {
+		s := setupSuite(t)
+		id := core.NewID()
+
+		s.repo.On("FindByID", mock.Anything, id).
+			Return((*domain.Note)(nil), errors.New("db error")).
+			Twice()
+
+		for range 2 {
+			_, err := s.cached.FindByID(t.Context(), id)
+			require.Error(t, err)
+		}
+	})
+}
+
+func TestNoteRepositoryInvalidation(t *testing.T) {
+	t.Run("should reload after save", func(t *testing.T) {
+		s := setupSuite(t)
+		n, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+		require.NoError(t, err)
+
+		s.repo.On("FindByID", mock.Anything, n.ID).
+			Return(n, nil).
+			Twice()
+
+		s.repo.On("Save", mock.Anything, n).
+			Return(nil).
+			Once()
+
+		_, err = s.cached.FindByID(t.Context(), n.ID)
+		require.NoError(t, err)
+
+		require.NoError(t, s.cached.Save(t.Context(), n))
+
+		_, err = s.cached.FindByID(t.Context(), n.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("should not cache misses", func(t *testing.T) {
+		s := setupSuite(t)
+		n, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+		require.NoError(t, err)
+
+		s.repo.On("FindByID", mock.Anything, n.ID).
+			Return((*domain.Note)(nil), nil).
+			Once()
+
+		s.repo.On("FindByID", mock.Anything, n.ID).
+			Return(n, nil).
+			Once()
+
+		found, err := s.cached.FindByID(t.Context(), n.ID)
+		require.NoError(t, err)
+		assert.Nil(t, found)
+
+		// Created by another replica, no invalidation reached this one.
+		found, err = s.cached.FindByID(t.Context(), n.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, found)
+	})
+
+	t.Run("should reload after tags change", func(t *testing.T) {
+		s := setupSuite(t)
+		n, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+		require.NoError(t, err)
+
+		tags := []domain.Tag{{ID: core.NewID(), Name: "work", CreatedAt: time.Now()}}
+
+		s.repo.On("FindByID", mock.Anything, n.ID).
+			Return(n, nil).
+			Times(3)
+
+		s.repo.On("AddTags", mock.Anything, n.ID, tags).
+			Return(nil).
+			Once()
+
+		s.repo.On("RemoveTags", mock.Anything, n.ID, []string{"work"}).
+			Return(nil).
+			Once()
+
+		_, err = s.cached.FindByID(t.Context(), n.ID)
+		require.NoError(t, err)
+
+		require.NoError(t, s.cached.AddTags(t.Context(), n.ID, tags))
+
+		_, err = s.cached.FindByID(t.Context(), n.ID)
+		require.NoError(t, err)
+
+		require.NoError(t, s.cached.RemoveTags(t.Context(), n.ID, []string{"work"}))
+
+		_, err = s.cached.FindByID(t.Context(), n.ID)
+		require.NoError(t, err)
+	})
+	t.Run("should reload every note after a resync", func(t *testing.T) {
+		s := setupSuite(t)
+		n, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+		require.NoError(t, err)
+
+		s.repo.On("FindByID", mock.Anything, n.ID).
+			Return(n, nil).
+			Twice()
+
+		_, err = s.cached.FindByID(t.Context(), n.ID)
+		require.NoError(t, err)
+
+		s.cached.Resync(t.Context())
+
+		_, err = s.cached.FindByID(t.Context(), n.ID)
+		require.NoError(t, err)
+
+		_, err = s.cached.FindByID(t.Context(), n.ID)
+		require.NoError(t, err)
+	})
+}
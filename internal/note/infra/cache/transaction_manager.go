@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/cache"
+	"HATCH_APP/pkg/core"
+	"context"
+	"sync"
+	"time"
+)
+
+// TransactionManager invalidates the notes written in a transaction once it
+// commits, so readers never cache a version that is later rolled back.
+type TransactionManager struct {
+	domain.TransactionManager
+	cache cache.Cache
+}
+
+func NewTransactionManager(tm domain.TransactionManager, c cache.Cache) *TransactionManager {
+	return &TransactionManager{
+		TransactionManager: tm,
+		cache:              c,
+	}
+}
+
+func (t *TransactionManager) Transact(fn func(input domain.TransactionManagerInput) error) error {
+	written := &writeRecorder{}
+
+	err := t.TransactionManager.Transact(func(input domain.TransactionManagerInput) error {
+		written.NoteRepository = input.NoteRepository
+		input.NoteRepository = written
+
+		return fn(input)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, id := range written.ids {
+		invalidate(context.Background(), t.cache, id)
+	}
+
+	return nil
+}
+
+type writeRecorder struct {
+	domain.NoteRepository
+	ids []core.ID
+	mu  sync.Mutex
+}
+
+func (w *writeRecorder) record(id core.ID) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.ids = append(w.ids, id)
+}
+
+func (w *writeRecorder) Create(ctx context.Context, note *domain.Note) error {
+	w.record(note.ID)
+	return w.NoteRepository.Create(ctx, note)
+}
+
+func (w *writeRecorder) Save(ctx context.Context, note *domain.Note) error {
+	w.record(note.ID)
+	return w.NoteRepository.Save(ctx, note)
+}
+
+func (w *writeRecorder) AddTags(ctx context.Context, noteID core.ID, tags []domain.Tag) error {
+	w.record(noteID)
+	return w.NoteRepository.AddTags(ctx, noteID, tags)
+}
+
+func (w *writeRecorder) PurgeTrashed(ctx context.Context, before time.Time, limit int) ([]*domain.Note, error) {
+	notes, err := w.NoteRepository.PurgeTrashed(ctx, before, limit)
+
+	for _, note := range notes {
+		w.record(note.ID)
+	}
+
+	return notes, err
+}
+
+func (w *writeRecorder) RemoveTags(ctx context.Context, noteID core.ID, names []string) error {
+	w.record(noteID)
+	return w.NoteRepository.RemoveTags(ctx, noteID, names)
+}
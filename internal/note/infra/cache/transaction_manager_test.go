@@ -0,0 +1,85 @@
+package cache_test
+
+import (
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/infra/cache"
+	"HATCH_APP/internal/note/mocks"
+	"HATCH_APP/pkg/cache/memory"
+	"HATCH_APP/pkg/core"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionManagerInvalidation(t *testing.T) {
+	setup := func(t *testing.T) (*suite, *cache.TransactionManager) {
+		s := setupSuite(t)
+		c := memory.NewLRU(10)
+		s.cached = cache.NewNoteRepository(s.repo, c, time.Minute)
+
+		tm := mocks.NewTransactionManager(t)
+		tm.On("Transact", mock.Anything).
+			Return(func(fn func(domain.TransactionManagerInput) error) error {
+				return fn(domain.TransactionManagerInput{NoteRepository: s.repo})
+			}).
+			Once()
+
+		return s, cache.NewTransactionManager(tm, c)
+	}
+
+	t.Run("should reload notes saved in a committed transaction", func(t *testing.T) {
+		s, tm := setup(t)
+		n, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+		require.NoError(t, err)
+
+		s.repo.On("FindByID", mock.Anything, n.ID).
+			Return(n, nil).
+			Twice()
+
+		s.repo.On("Save", mock.Anything, n).
+			Return(nil).
+			Once()
+
+		_, err = s.cached.FindByID(t.Context(), n.ID)
+		require.NoError(t, err)
+
+		require.NoError(t, tm.Transact(func(input domain.TransactionManagerInput) error {
+			return input.NoteRepository.Save(t.Context(), n)
+		}))
+
+		_, err = s.cached.FindByID(t.Context(), n.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("should keep the cache when the transaction fails", func(t *testing.T) {
+		s, tm := setup(t)
+		n, err := domain.NewNote(core.NewID(), time.Now(), "title", "content")
+		require.NoError(t, err)
+
+		s.repo.On("FindByID", mock.Anything, n.ID).
+			Return(n, nil).
+			Once()
+
+		s.repo.On("Save", mock.Anything, n).
+			Return(nil).
+			Once()
+
+		_, err = s.cached.FindByID(t.Context(), n.ID)
+		require.NoError(t, err)
+
+		err = tm.Transact(func(input domain.TransactionManagerInput) error {
+			if err := input.NoteRepository.Save(t.Context(), n); err != nil {
+				return err
+			}
+
+			return errors.New("revision failed")
+		})
+		require.Error(t, err)
+
+		_, err = s.cached.FindByID(t.Context(), n.ID)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,187 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/store/postgres"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	createAttachment   = "create attachment"
+	listAttachments    = "list attachments"
+	findAttachment     = "find attachment"
+	deleteAttachment   = "delete attachment"
+	listDeletedBlobs   = "list deleted blobs"
+	forgetDeletedBlobs = "forget deleted blobs"
+)
+
+const attachmentColumns = `id, note_id, filename, content_type, size, checksum, storage_key, created_at`
+
+var attachmentQueries = map[string]string{
+	createAttachment: `INSERT INTO note_attachments (` + attachmentColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+	listAttachments: `SELECT ` + attachmentColumns + ` FROM note_attachments
+		WHERE note_id = $1
+		ORDER BY created_at, id`,
+	findAttachment: `SELECT ` + attachmentColumns + ` FROM note_attachments
+		WHERE note_id = $1 AND id = $2`,
+	deleteAttachment: `DELETE FROM note_attachments WHERE id = $1`,
+	listDeletedBlobs: `SELECT storage_key FROM attachment_deletions
+		ORDER BY created_at, storage_key
+		LIMIT $1`,
+	forgetDeletedBlobs: `DELETE FROM attachment_deletions WHERE storage_key = ANY($1)`,
+}
+
+type AttachmentRepository struct {
+	stmts map[string]*sqlx.Stmt
+}
+
+func NewAttachmentRepository(db postgres.Querier) (*AttachmentRepository, error) {
+	stmts := make(map[string]*sqlx.Stmt)
+
+	for queryName, statement := range attachmentQueries {
+		stmt, err := db.Preparex(statement)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to prepare query %s for note attachment: %w",
+				postgres.ErrQueryPreparation, queryName, err)
+		}
+
+		stmts[queryName] = stmt
+	}
+
+	return &AttachmentRepository{
+		stmts: stmts,
+	}, nil
+}
+
+func (r *AttachmentRepository) statement(queryName string) (*sqlx.Stmt, error) {
+	stmt, ok := r.stmts[queryName]
+
+	if !ok {
+		return nil, fmt.Errorf("%w: statement %s not prepared for note attachment",
+			postgres.ErrQueryPreparation, queryName)
+	}
+
+	return stmt, nil
+}
+
+func (r *AttachmentRepository) CreateAttachment(ctx context.Context, attachment *domain.Attachment) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.statement(createAttachment)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx,
+		attachment.ID,
+		attachment.NoteID,
+		attachment.Filename,
+		attachment.ContentType,
+		attachment.Size,
+		attachment.Checksum,
+		attachment.StorageKey,
+		attachment.CreatedAt,
+	)
+
+	return postgres.TranslateError(err)
+}
+
+func (r *AttachmentRepository) ListAttachments(ctx context.Context, noteID core.ID) ([]*domain.Attachment, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.statement(listAttachments)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments := []*domain.Attachment{}
+
+	if err := stmt.SelectContext(ctx, &attachments, noteID); err != nil {
+		return nil, postgres.TranslateError(err)
+	}
+
+	return attachments, nil
+}
+
+func (r *AttachmentRepository) FindAttachment(ctx context.Context, noteID, id core.ID) (*domain.Attachment, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.statement(findAttachment)
+	if err != nil {
+		return nil, err
+	}
+
+	var attachment domain.Attachment
+
+	if err := stmt.GetContext(ctx, &attachment, noteID, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, postgres.TranslateError(err)
+	}
+
+	return &attachment, nil
+}
+
+func (r *AttachmentRepository) DeleteAttachment(ctx context.Context, id core.ID) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.statement(deleteAttachment)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx, id)
+
+	return postgres.TranslateError(err)
+}
+
+func (r *AttachmentRepository) ListDeletedBlobs(ctx context.Context, limit int) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.statement(listDeletedBlobs)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{}
+
+	if err := stmt.SelectContext(ctx, &keys, limit); err != nil {
+		return nil, postgres.TranslateError(err)
+	}
+
+	return keys, nil
+}
+
+func (r *AttachmentRepository) ForgetDeletedBlobs(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.statement(forgetDeletedBlobs)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx, pq.Array(keys))
+
+	return postgres.TranslateError(err)
+}
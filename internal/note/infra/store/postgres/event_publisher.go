@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/shared/events"
+	"HATCH_APP/pkg/store/postgres"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// EventChannel notifies the listeners of every replica of the note events
+// once their transaction commits.
+const EventChannel postgres.Channel[domain.EventNotice] = "note_events"
+
+// EventPublisher hands note events to the other modules and the listeners
+// through db, the transaction of the change they describe.
+type EventPublisher struct {
+	db     sqlx.ExtContext
+	shared events.Publisher
+}
+
+// NewEventPublisher only notifies the listeners when shared is nil.
+func NewEventPublisher(db sqlx.ExtContext, shared events.Publisher) *EventPublisher {
+	return &EventPublisher{db: db, shared: shared}
+}
+
+func (p *EventPublisher) Publish(ctx context.Context, event domain.Event) error {
+	if p.shared != nil {
+		data, err := json.Marshal(event.Note)
+		if err != nil {
+			return err
+		}
+
+		if err := p.shared.Publish(ctx, p.db, events.Event{
+			ID:        event.ID,
+			Type:      event.Type,
+			OwnerID:   event.Note.OwnerID,
+			CreatedAt: event.CreatedAt,
+			Data:      data,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return postgres.Notify(ctx, p.db, EventChannel, event.Notice())
+}
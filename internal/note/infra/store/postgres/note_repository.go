@@ -3,36 +3,133 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"iter"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/o11y"
 	"HATCH_APP/pkg/store/postgres"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 const (
-	createNote   = "create note"
-	findNoteByID = "find note by id"
-	listNotes    = "list notes"
-	saveNote     = "save note"
+	createNote          = "create note"
+	findNoteByID        = "find note by id"
+	listNotes           = "list notes"
+	saveNote            = "save note"
+	findTrashedNoteByID = "find trashed note by id"
+	findNoteIDOwners    = "find note id owners"
+	listTrashedNotes    = "list trashed notes"
+	purgeTrashedNotes   = "purge trashed notes"
+	findNoteTags        = "find note tags"
+	addNoteTags         = "add note tags"
+	removeNoteTags      = "remove note tags"
+	listTags            = "list tags"
 )
 
+const noteColumns = `id, owner_id, title, content, status, format, version, created_at, updated_at, deleted_at`
+
+// noteFilterCondition matches the notes out of the trash against the filter
+// tags in $1, a note matches when it has any of them or, with $2 set, all of
+// them. No tags means no filter. $3 is the owner, NULL for any.
+const noteFilterCondition = `deleted_at IS NULL AND ($3::varchar IS NULL OR owner_id = $3) AND (
+		cardinality($1::varchar[]) = 0 OR (
+			SELECT count(*) FROM note_tags
+			JOIN tags ON tags.id = note_tags.tag_id
+			WHERE note_tags.note_id = notes.id AND tags.name = ANY($1)
+		) >= CASE WHEN $2 THEN cardinality($1::varchar[]) ELSE 1 END
+	)`
+
+// iterateBatchSize is the number of notes fetched from an iteration cursor
+// at once, bounding the memory an iteration takes.
+const iterateBatchSize = 100
+
+// Cursors are declared per iteration, so their statements are not prepared
+// with the others.
+const declareNoteCursor = `DECLARE %s NO SCROLL CURSOR FOR
+	SELECT ` + noteColumns + ` FROM notes
+	WHERE ` + noteFilterCondition + `
+	ORDER BY created_at, id`
+
+// cursorSeq names the cursors apart, iterations may be nested in a
+// transaction.
+var cursorSeq atomic.Uint64
+
+var purgeLockKey = postgres.AdvisoryLockKey("note:purge-trashed")
+
 var noteQueries = map[string]string{
 	createNote: `INSERT INTO notes
-		(id, title, content, archived, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)`,
-	findNoteByID: `SELECT * FROM notes WHERE id = $1`,
-	listNotes:    `SELECT * FROM notes`,
+		(id, owner_id, title, content, status, format, version, created_at, updated_at, deleted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+	findNoteByID: `SELECT ` + noteColumns + ` FROM notes WHERE id = $1 AND deleted_at IS NULL`,
+	listNotes:    `SELECT ` + noteColumns + ` FROM notes WHERE ` + noteFilterCondition,
 	saveNote: `UPDATE notes
-		SET archived = $1, updated_at = $2
-		WHERE id = $3`,
+		SET title = $1, content = $2, status = $3, format = $4, version = $5,
+			updated_at = $6, deleted_at = $7
+		WHERE id = $8`,
+	findTrashedNoteByID: `SELECT ` + noteColumns + ` FROM notes WHERE id = $1 AND deleted_at IS NOT NULL`,
+	findNoteIDOwners:    `SELECT id, owner_id FROM notes WHERE id = ANY($1)`,
+	listTrashedNotes: `SELECT ` + noteColumns + ` FROM notes
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC`,
+	// The advisory lock is held until the transaction ends, so only one
+	// replica deletes a batch at a time; the others see zero rows and stop.
+	purgeTrashedNotes: `WITH lock AS (SELECT pg_try_advisory_xact_lock($1) AS acquired)
+		DELETE FROM notes WHERE id IN (
+			SELECT notes.id FROM notes, lock
+			WHERE lock.acquired AND notes.deleted_at < $2
+			ORDER BY notes.deleted_at
+			LIMIT $3
+			FOR UPDATE OF notes SKIP LOCKED
+		)
+		RETURNING ` + noteColumns,
+	findNoteTags: `SELECT note_tags.note_id, tags.name FROM note_tags
+		JOIN tags ON tags.id = note_tags.tag_id
+		WHERE note_tags.note_id = ANY($1)
+		ORDER BY tags.name`,
+	// Tags belong to the owner of the note. DO UPDATE instead of DO NOTHING
+	// so existing tags are returned too, and a tag created concurrently is
+	// waited for rather than missed.
+	addNoteTags: `WITH upserted AS (
+			INSERT INTO tags (id, owner_id, name, created_at)
+			SELECT input.id, notes.owner_id, input.name, $4
+			FROM unnest($2::varchar[], $3::varchar[]) AS input (id, name)
+			JOIN notes ON notes.id = $1
+			ON CONFLICT (owner_id, name) DO UPDATE SET name = EXCLUDED.name
+			RETURNING id
+		)
+		INSERT INTO note_tags (note_id, tag_id, created_at)
+		SELECT $1, id, $4 FROM upserted
+		ON CONFLICT DO NOTHING`,
+	removeNoteTags: `DELETE FROM note_tags USING tags
+		WHERE tags.id = note_tags.tag_id AND note_tags.note_id = $1 AND tags.name = ANY($2)`,
+	listTags: `SELECT tags.name, count(*) AS count FROM tags
+		JOIN note_tags ON note_tags.tag_id = tags.id
+		JOIN notes ON notes.id = note_tags.note_id AND notes.deleted_at IS NULL
+		WHERE tags.owner_id = $1
+		GROUP BY tags.name
+		ORDER BY tags.name`,
 }
 
 type NoteRepository struct {
 	db    postgres.Querier
+	reads postgres.ReadRouter
 	stmts map[string]*sqlx.Stmt
+	// readStmts are the statements prepared on the databases reads were
+	// routed to, by readStatementKey.
+	readStmts sync.Map
+}
+
+type readStatementKey struct {
+	db        *sqlx.DB
+	queryName string
 }
 
 func NewNoteRepository(db postgres.Querier) (*NoteRepository, error) {
@@ -65,7 +162,55 @@ func (r *NoteRepository) statement(queryName string) (*sqlx.Stmt, error) {
 	return stmt, nil
 }
 
+// RouteReads sends the read-only queries to the database router picks, such
+// as a read replica. Repositories bound to a transaction must not route.
+func (r *NoteRepository) RouteReads(router postgres.ReadRouter) *NoteRepository {
+	r.reads = router
+
+	return r
+}
+
+// reader returns the statements of the database reads of ctx go to. The
+// statements are prepared there on first use, falling back to the primary
+// when that fails.
+func (r *NoteRepository) reader(ctx context.Context) func(queryName string) (*sqlx.Stmt, error) {
+	if r.reads == nil {
+		return r.statement
+	}
+
+	db := r.reads.Reader(ctx)
+	if db == nil || db == r.db {
+		return r.statement
+	}
+
+	return func(queryName string) (*sqlx.Stmt, error) {
+		key := readStatementKey{db: db, queryName: queryName}
+
+		if stmt, ok := r.readStmts.Load(key); ok {
+			return stmt.(*sqlx.Stmt), nil
+		}
+
+		stmt, err := db.PreparexContext(ctx, noteQueries[queryName])
+		if err != nil {
+			o11y.LoggerFromContext(ctx).WarnContext(ctx, "note: failed to prepare a read on a replica, reading from the primary",
+				"query", queryName, "error", err)
+
+			return r.statement(queryName)
+		}
+
+		if prev, loaded := r.readStmts.LoadOrStore(key, stmt); loaded {
+			_ = stmt.Close()
+
+			return prev.(*sqlx.Stmt), nil
+		}
+
+		return stmt, nil
+	}
+}
+
 func (r *NoteRepository) Create(ctx context.Context, note *domain.Note) error {
+	postgres.MarkWrite(ctx)
+
 	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
 	defer cancel()
 
@@ -76,21 +221,81 @@ func (r *NoteRepository) Create(ctx context.Context, note *domain.Note) error {
 
 	_, err = stmt.ExecContext(ctx,
 		note.ID,
+		note.OwnerID,
 		note.Title,
 		note.Content,
-		note.Archived,
+		note.Status,
+		note.Format,
+		note.Version,
 		note.CreatedAt,
 		note.UpdatedAt,
+		note.DeletedAt,
 	)
 
-	return err
+	return postgres.TranslateError(err)
+}
+
+func (r *NoteRepository) FindByID(ctx context.Context, id core.ID) (*domain.Note, error) {
+	return r.findOne(ctx, findNoteByID, id)
+}
+
+func (r *NoteRepository) FindTrashedByID(ctx context.Context, id core.ID) (*domain.Note, error) {
+	return r.findOne(ctx, findTrashedNoteByID, id)
+}
+
+func (r *NoteRepository) FindIDOwners(ctx context.Context, ids []core.ID) (map[core.ID]string, error) {
+	owners := make(map[core.ID]string)
+
+	if len(ids) == 0 {
+		return owners, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.statement(findNoteIDOwners)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make([]string, 0, len(ids))
+	for _, id := range ids {
+		raw = append(raw, id.String())
+	}
+
+	rows, err := stmt.QueryContext(ctx, pq.Array(raw))
+	if err != nil {
+		return nil, postgres.TranslateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id    core.ID
+			owner string
+		)
+
+		if err := rows.Scan(&id, &owner); err != nil {
+			return nil, postgres.TranslateError(err)
+		}
+
+		owners[id] = owner
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, postgres.TranslateError(err)
+	}
+
+	return owners, nil
 }
 
-func (r *NoteRepository) FindByID(ctx context.Context, id string) (*domain.Note, error) {
+func (r *NoteRepository) findOne(ctx context.Context, queryName string, id core.ID) (*domain.Note, error) {
 	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
 	defer cancel()
 
-	stmt, err := r.statement(findNoteByID)
+	statement := r.reader(ctx)
+
+	stmt, err := statement(queryName)
 	if err != nil {
 		return nil, err
 	}
@@ -99,56 +304,242 @@ func (r *NoteRepository) FindByID(ctx context.Context, id string) (*domain.Note,
 
 	if err := stmt.QueryRowContext(ctx, id).Scan(
 		&note.ID,
+		&note.OwnerID,
 		&note.Title,
 		&note.Content,
-		&note.Archived,
+		&note.Status,
+		&note.Format,
+		&note.Version,
 		&note.CreatedAt,
 		&note.UpdatedAt,
+		&note.DeletedAt,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 
+		return nil, postgres.TranslateError(err)
+	}
+
+	if err := r.loadTags(ctx, statement, []*domain.Note{&note}); err != nil {
 		return nil, err
 	}
 
 	return &note, nil
 }
 
-func (r *NoteRepository) List(ctx context.Context) ([]*domain.Note, error) {
+func (r *NoteRepository) List(ctx context.Context, filter domain.NoteFilter) ([]*domain.Note, error) {
+	return r.list(ctx, listNotes, pq.Array(filter.Tags), filter.Match == domain.TagMatchAll, filter.Owner)
+}
+
+func (r *NoteRepository) ListTrashed(ctx context.Context) ([]*domain.Note, error) {
+	return r.list(ctx, listTrashedNotes)
+}
+
+func (r *NoteRepository) list(ctx context.Context, queryName string, args ...any) ([]*domain.Note, error) {
 	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
 	defer cancel()
 
-	stmt, err := r.statement(listNotes)
+	statement := r.reader(ctx)
+
+	stmt, err := statement(queryName)
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := stmt.QueryxContext(ctx)
+	rows, err := stmt.QueryxContext(ctx, args...)
+	if err != nil {
+		return nil, postgres.TranslateError(err)
+	}
+
+	notes, err := scanNotes(rows)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := r.loadTags(ctx, statement, notes); err != nil {
+		return nil, err
+	}
+
+	return notes, nil
+}
+
+// Iterate reads the notes through a cursor, in a read only snapshot of its
+// own unless the repository is bound to a transaction already.
+func (r *NoteRepository) Iterate(ctx context.Context, filter domain.NoteFilter) iter.Seq2[*domain.Note, error] {
+	return func(yield func(*domain.Note, error) bool) {
+		if err := r.iterate(ctx, filter, yield); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// iterate returns nil once yield asked to stop, so it is not called again.
+func (r *NoteRepository) iterate(ctx context.Context, filter domain.NoteFilter, yield func(*domain.Note, error) bool) error {
+	switch db := r.db.(type) {
+	case *sqlx.Tx:
+		tagsStmt, err := r.statement(findNoteTags)
+		if err != nil {
+			return err
+		}
+
+		return iterate(ctx, db, tagsStmt, filter, yield)
+	case *sqlx.DB:
+		if r.reads != nil {
+			db = r.reads.Reader(ctx)
+		}
+
+		tx, err := db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+		if err != nil {
+			return postgres.TranslateError(err)
+		}
+
+		// Nothing was written, rolling back closes the cursor too.
+		defer func() { _ = tx.Rollback() }()
+
+		tagsStmt, err := tx.PreparexContext(ctx, noteQueries[findNoteTags])
+		if err != nil {
+			return postgres.TranslateError(err)
+		}
+
+		return iterate(ctx, tx, tagsStmt, filter, yield)
+	default:
+		return errors.New("note iteration needs a database or a transaction")
+	}
+}
+
+func iterate(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	tagsStmt *sqlx.Stmt,
+	filter domain.NoteFilter,
+	yield func(*domain.Note, error) bool,
+) error {
+	cursor := fmt.Sprintf("note_cursor_%d", cursorSeq.Add(1))
+
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(declareNoteCursor, cursor),
+		pq.Array(filter.Tags), filter.Match == domain.TagMatchAll, filter.Owner)
+	if err != nil {
+		return postgres.TranslateError(err)
+	}
+
+	defer func() {
+		_, _ = tx.ExecContext(context.WithoutCancel(ctx), "CLOSE "+cursor)
+	}()
+
+	fetch := fmt.Sprintf("FETCH %d FROM %s", iterateBatchSize, cursor)
+
+	for {
+		notes, err := fetchNotes(ctx, tx, fetch)
+		if err != nil {
+			return err
+		}
+
+		if len(notes) == 0 {
+			return nil
+		}
+
+		if err := loadTags(ctx, tagsStmt, notes); err != nil {
+			return err
+		}
+
+		for _, note := range notes {
+			if !yield(note, nil) {
+				return nil
+			}
+		}
+	}
+}
+
+func fetchNotes(ctx context.Context, tx *sqlx.Tx, fetch string) ([]*domain.Note, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	rows, err := tx.QueryxContext(ctx, fetch)
+	if err != nil {
+		return nil, postgres.TranslateError(err)
+	}
+
+	return scanNotes(rows)
+}
+
+// scanNotes reads and closes rows.
+func scanNotes(rows *sqlx.Rows) ([]*domain.Note, error) {
+	defer rows.Close()
+
 	var notes []*domain.Note
 
 	for rows.Next() {
 		var note domain.Note
 		if err := rows.StructScan(&note); err != nil {
-			return nil, err
+			return nil, postgres.TranslateError(err)
 		}
 
-		n := note
-		notes = append(notes, &n)
+		notes = append(notes, &note)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, postgres.TranslateError(err)
 	}
 
 	return notes, nil
 }
 
+// loadTags fills the tags of all notes with a single query.
+func (r *NoteRepository) loadTags(
+	ctx context.Context,
+	statement func(queryName string) (*sqlx.Stmt, error),
+	notes []*domain.Note,
+) error {
+	stmt, err := statement(findNoteTags)
+	if err != nil {
+		return err
+	}
+
+	return loadTags(ctx, stmt, notes)
+}
+
+func loadTags(ctx context.Context, stmt *sqlx.Stmt, notes []*domain.Note) error {
+	if len(notes) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(notes))
+	byID := make(map[core.ID]*domain.Note, len(notes))
+
+	for _, n := range notes {
+		n.Tags = []string{}
+		ids = append(ids, n.ID.String())
+		byID[n.ID] = n
+	}
+
+	rows, err := stmt.QueryContext(ctx, pq.Array(ids))
+	if err != nil {
+		return postgres.TranslateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			noteID core.ID
+			name   string
+		)
+
+		if err := rows.Scan(&noteID, &name); err != nil {
+			return postgres.TranslateError(err)
+		}
+
+		if n, ok := byID[noteID]; ok {
+			n.Tags = append(n.Tags, name)
+		}
+	}
+
+	return postgres.TranslateError(rows.Err())
+}
+
 func (r *NoteRepository) Save(ctx context.Context, note *domain.Note) error {
+	postgres.MarkWrite(ctx)
+
 	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
 	defer cancel()
 
@@ -158,10 +549,110 @@ func (r *NoteRepository) Save(ctx context.Context, note *domain.Note) error {
 	}
 
 	_, err = stmt.ExecContext(ctx,
-		note.Archived,
+		note.Title,
+		note.Content,
+		note.Status,
+		note.Format,
+		note.Version,
 		note.UpdatedAt,
+		note.DeletedAt,
 		note.ID,
 	)
 
-	return err
+	return postgres.TranslateError(err)
+}
+
+func (r *NoteRepository) PurgeTrashed(ctx context.Context, before time.Time, limit int) ([]*domain.Note, error) {
+	postgres.MarkWrite(ctx)
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*30)
+	defer cancel()
+
+	stmt, err := r.statement(purgeTrashedNotes)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.QueryxContext(ctx, purgeLockKey, before, limit)
+	if err != nil {
+		return nil, postgres.TranslateError(err)
+	}
+
+	notes, err := scanNotes(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// The tags were deleted along with the notes.
+	for _, note := range notes {
+		note.Tags = []string{}
+	}
+
+	return notes, nil
+}
+
+func (r *NoteRepository) AddTags(ctx context.Context, noteID core.ID, tags []domain.Tag) error {
+	postgres.MarkWrite(ctx)
+
+	if len(tags) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.statement(addNoteTags)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, 0, len(tags))
+	names := make([]string, 0, len(tags))
+
+	for _, t := range tags {
+		ids = append(ids, t.ID.String())
+		names = append(names, t.Name)
+	}
+
+	_, err = stmt.ExecContext(ctx, noteID, pq.Array(ids), pq.Array(names), tags[0].CreatedAt)
+
+	return postgres.TranslateError(err)
+}
+
+func (r *NoteRepository) RemoveTags(ctx context.Context, noteID core.ID, names []string) error {
+	postgres.MarkWrite(ctx)
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.statement(removeNoteTags)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx, noteID, pq.Array(names))
+
+	return postgres.TranslateError(err)
+}
+
+func (r *NoteRepository) ListTags(ctx context.Context, owner string) ([]domain.TagCount, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.reader(ctx)(listTags)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []domain.TagCount{}
+
+	if err := stmt.SelectContext(ctx, &tags, owner); err != nil {
+		return nil, postgres.TranslateError(err)
+	}
+
+	return tags, nil
 }
@@ -0,0 +1,200 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/note/infra/store/postgres"
+	"HATCH_APP/pkg/core"
+	pgStore "HATCH_APP/pkg/store/postgres"
+	"HATCH_APP/test/container"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoteRepositoryIterateIntegration(t *testing.T) {
+	db, teardown := container.SetupPostgres(t)
+	t.Cleanup(teardown)
+
+	repo, err := postgres.NewNoteRepository(db)
+	require.NoError(t, err)
+
+	// More notes than a batch, so iterations fetch several times.
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	ids := make([]core.ID, 0, 250)
+
+	for i := range 250 {
+		note, err := domain.NewNote(core.NewID(), start.Add(time.Duration(i)*time.Second), "title", "content")
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(t.Context(), note))
+
+		ids = append(ids, note.ID)
+	}
+
+	require.NoError(t, repo.AddTags(t.Context(), ids[0], []domain.Tag{{ID: core.NewID(), Name: "work", CreatedAt: start}}))
+
+	t.Run("should stream every note oldest first", func(t *testing.T) {
+		var got []core.ID
+
+		for note, err := range repo.Iterate(t.Context(), domain.NoteFilter{}) {
+			require.NoError(t, err)
+
+			got = append(got, note.ID)
+		}
+
+		assert.Equal(t, ids, got)
+	})
+
+	t.Run("should filter by tag", func(t *testing.T) {
+		var got []*domain.Note
+
+		for note, err := range repo.Iterate(t.Context(), domain.NoteFilter{Tags: []string{"work"}, Match: domain.TagMatchAny}) {
+			require.NoError(t, err)
+
+			got = append(got, note)
+		}
+
+		require.Len(t, got, 1)
+		assert.Equal(t, []string{"work"}, got[0].Tags)
+	})
+
+	t.Run("should release the cursor when the loop breaks", func(t *testing.T) {
+		require.NoError(t, pgStore.RunInTx(db, func(tx *sqlx.Tx) error {
+			txRepo, err := postgres.NewNoteRepository(tx)
+			require.NoError(t, err)
+
+			for range 3 {
+				for note, err := range txRepo.Iterate(t.Context(), domain.NoteFilter{}) {
+					require.NoError(t, err)
+					assert.Equal(t, ids[0], note.ID)
+
+					break
+				}
+			}
+
+			var cursors int
+			require.NoError(t, tx.GetContext(t.Context(), &cursors, `SELECT count(*) FROM pg_cursors`))
+			assert.Zero(t, cursors)
+
+			return nil
+		}))
+	})
+
+	t.Run("should end with an error once cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+
+		var (
+			seen    int
+			lastErr error
+		)
+
+		for _, err := range repo.Iterate(ctx, domain.NoteFilter{}) {
+			if err != nil {
+				lastErr = err
+				continue
+			}
+
+			seen++
+			cancel()
+		}
+
+		require.Error(t, lastErr)
+		assert.Less(t, seen, len(ids))
+	})
+}
+
+func TestNoteRepositoryTagsIntegration(t *testing.T) {
+	db, teardown := container.SetupPostgres(t)
+	t.Cleanup(teardown)
+
+	repo, err := postgres.NewNoteRepository(db)
+	require.NoError(t, err)
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, owner := range []string{"acme", "acme", "globex"} {
+		note, err := domain.NewNote(core.NewID(), now, "title", "content")
+		require.NoError(t, err)
+
+		note.OwnerID = owner
+		require.NoError(t, repo.Create(t.Context(), note))
+
+		require.NoError(t, repo.AddTags(t.Context(), note.ID, []domain.Tag{{ID: core.NewID(), Name: "work", CreatedAt: now}}))
+	}
+
+	t.Run("should keep tag names unique per owner", func(t *testing.T) {
+		var tags int
+		require.NoError(t, db.GetContext(t.Context(), &tags, `SELECT count(*) FROM tags WHERE name = 'work'`))
+		assert.Equal(t, 2, tags)
+	})
+
+	t.Run("should only list the tags of the owner", func(t *testing.T) {
+		tags, err := repo.ListTags(t.Context(), "acme")
+		require.NoError(t, err)
+		assert.Equal(t, []domain.TagCount{{Name: "work", Count: 2}}, tags)
+
+		tags, err = repo.ListTags(t.Context(), "initech")
+		require.NoError(t, err)
+		assert.Empty(t, tags)
+	})
+}
+
+func TestNoteRepositoryFindIDOwnersIntegration(t *testing.T) {
+	db, teardown := container.SetupPostgres(t)
+	t.Cleanup(teardown)
+
+	repo, err := postgres.NewNoteRepository(db)
+	require.NoError(t, err)
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	active, err := domain.NewNote(core.NewID(), now, "active", "content")
+	require.NoError(t, err)
+
+	active.OwnerID = "acme"
+	require.NoError(t, repo.Create(t.Context(), active))
+
+	trashed, err := domain.NewNote(core.NewID(), now, "trashed", "content")
+	require.NoError(t, err)
+	require.NoError(t, trashed.Trash(now))
+
+	trashed.OwnerID = "globex"
+	require.NoError(t, repo.Create(t.Context(), trashed))
+
+	t.Run("should find the owners of notes in and out of the trash", func(t *testing.T) {
+		owners, err := repo.FindIDOwners(t.Context(), []core.ID{active.ID, core.NewID(), trashed.ID})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[core.ID]string{active.ID: "acme", trashed.ID: "globex"}, owners)
+	})
+
+	t.Run("should find nothing without ids", func(t *testing.T) {
+		owners, err := repo.FindIDOwners(t.Context(), nil)
+
+		require.NoError(t, err)
+		assert.Empty(t, owners)
+	})
+
+	t.Run("should only iterate the notes of the owner", func(t *testing.T) {
+		other, err := domain.NewNote(core.NewID(), now, "other", "content")
+		require.NoError(t, err)
+
+		other.OwnerID = "initech"
+		require.NoError(t, repo.Create(t.Context(), other))
+
+		var ids []core.ID
+
+		for note, err := range repo.Iterate(t.Context(), domain.NoteFilter{Owner: new("acme")}) {
+			require.NoError(t, err)
+
+			ids = append(ids, note.ID)
+		}
+
+		assert.Equal(t, []core.ID{active.ID}, ids)
+	})
+}
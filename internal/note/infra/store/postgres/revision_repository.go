@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/store/postgres"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	createRevision = "create revision"
+	listRevisions  = "list revisions"
+	findRevision   = "find revision"
+	pruneRevisions = "prune revisions"
+)
+
+const revisionColumns = `note_id, version, title, content, created_at`
+
+var revisionQueries = map[string]string{
+	createRevision: `INSERT INTO note_revisions (` + revisionColumns + `)
+		VALUES ($1, $2, $3, $4, $5)`,
+	listRevisions: `SELECT ` + revisionColumns + ` FROM note_revisions
+		WHERE note_id = $1
+		ORDER BY version DESC`,
+	findRevision: `SELECT ` + revisionColumns + ` FROM note_revisions
+		WHERE note_id = $1 AND version = $2`,
+	pruneRevisions: `DELETE FROM note_revisions WHERE note_id = $1 AND version < $2`,
+}
+
+type RevisionRepository struct {
+	stmts map[string]*sqlx.Stmt
+}
+
+func NewRevisionRepository(db postgres.Querier) (*RevisionRepository, error) {
+	stmts := make(map[string]*sqlx.Stmt)
+
+	for queryName, statement := range revisionQueries {
+		stmt, err := db.Preparex(statement)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to prepare query %s for note revision: %w",
+				postgres.ErrQueryPreparation, queryName, err)
+		}
+
+		stmts[queryName] = stmt
+	}
+
+	return &RevisionRepository{
+		stmts: stmts,
+	}, nil
+}
+
+func (r *RevisionRepository) statement(queryName string) (*sqlx.Stmt, error) {
+	stmt, ok := r.stmts[queryName]
+
+	if !ok {
+		return nil, fmt.Errorf("%w: statement %s not prepared for note revision",
+			postgres.ErrQueryPreparation, queryName)
+	}
+
+	return stmt, nil
+}
+
+func (r *RevisionRepository) CreateRevision(ctx context.Context, revision domain.NoteRevision) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.statement(createRevision)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx,
+		revision.NoteID,
+		revision.Version,
+		revision.Title,
+		revision.Content,
+		revision.CreatedAt,
+	)
+
+	return postgres.TranslateError(err)
+}
+
+func (r *RevisionRepository) ListRevisions(ctx context.Context, noteID core.ID) ([]domain.NoteRevision, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.statement(listRevisions)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := []domain.NoteRevision{}
+
+	if err := stmt.SelectContext(ctx, &revisions, noteID); err != nil {
+		return nil, postgres.TranslateError(err)
+	}
+
+	return revisions, nil
+}
+
+func (r *RevisionRepository) FindRevision(ctx context.Context, noteID core.ID, version int) (*domain.NoteRevision, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.statement(findRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	var revision domain.NoteRevision
+
+	if err := stmt.GetContext(ctx, &revision, noteID, version); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, postgres.TranslateError(err)
+	}
+
+	return &revision, nil
+}
+
+func (r *RevisionRepository) PruneRevisions(ctx context.Context, noteID core.ID, version int) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.statement(pruneRevisions)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx, noteID, version)
+
+	return postgres.TranslateError(err)
+}
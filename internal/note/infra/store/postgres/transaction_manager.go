@@ -2,28 +2,45 @@ package postgres
 
 import (
 	"HATCH_APP/internal/note/domain"
+	"HATCH_APP/internal/shared/events"
 	"HATCH_APP/pkg/store/postgres"
 
 	"github.com/jmoiron/sqlx"
 )
 
 type TransactionManager struct {
-	db *sqlx.DB
+	db     *sqlx.DB
+	shared events.Publisher
 }
 
 func NewTransactionManager(db *sqlx.DB) *TransactionManager {
 	return &TransactionManager{db: db}
 }
 
+// PublishTo shares the note events with other modules through p, in the
+// transaction of the change.
+func (t *TransactionManager) PublishTo(p events.Publisher) *TransactionManager {
+	t.shared = p
+
+	return t
+}
+
 func (t *TransactionManager) Transact(fn func(input domain.TransactionManagerInput) error) error {
 	return postgres.RunInTx(t.db, func(tx *sqlx.Tx) error {
-		repo, err := NewNoteRepository(tx)
+		noteRepo, err := NewNoteRepository(tx)
+		if err != nil {
+			return err
+		}
+
+		revisionRepo, err := NewRevisionRepository(tx)
 		if err != nil {
 			return err
 		}
 
 		return fn(domain.TransactionManagerInput{
-			NoteRepository: repo,
+			NoteRepository:     noteRepo,
+			RevisionRepository: revisionRepo,
+			Events:             NewEventPublisher(tx, t.shared),
 		})
 	})
 }
@@ -0,0 +1,175 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	core "HATCH_APP/pkg/core"
+	context "context"
+
+	domain "HATCH_APP/internal/note/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AttachmentRepository is an autogenerated mock type for the AttachmentRepository type
+type AttachmentRepository struct {
+	mock.Mock
+}
+
+// CreateAttachment provides a mock function with given fields: ctx, attachment
+func (_m *AttachmentRepository) CreateAttachment(ctx context.Context, attachment *domain.Attachment) error {
+	ret := _m.Called(ctx, attachment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAttachment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Attachment) error); ok {
+		r0 = rf(ctx, attachment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteAttachment provides a mock function with given fields: ctx, id
+func (_m *AttachmentRepository) DeleteAttachment(ctx context.Context, id core.ID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAttachment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindAttachment provides a mock function with given fields: ctx, noteID, id
+func (_m *AttachmentRepository) FindAttachment(ctx context.Context, noteID core.ID, id core.ID) (*domain.Attachment, error) {
+	ret := _m.Called(ctx, noteID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAttachment")
+	}
+
+	var r0 *domain.Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID, core.ID) (*domain.Attachment, error)); ok {
+		return rf(ctx, noteID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID, core.ID) *domain.Attachment); ok {
+		r0 = rf(ctx, noteID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Attachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, core.ID, core.ID) error); ok {
+		r1 = rf(ctx, noteID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ForgetDeletedBlobs provides a mock function with given fields: ctx, keys
+func (_m *AttachmentRepository) ForgetDeletedBlobs(ctx context.Context, keys []string) error {
+	ret := _m.Called(ctx, keys)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ForgetDeletedBlobs")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) error); ok {
+		r0 = rf(ctx, keys)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListAttachments provides a mock function with given fields: ctx, noteID
+func (_m *AttachmentRepository) ListAttachments(ctx context.Context, noteID core.ID) ([]*domain.Attachment, error) {
+	ret := _m.Called(ctx, noteID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAttachments")
+	}
+
+	var r0 []*domain.Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID) ([]*domain.Attachment, error)); ok {
+		return rf(ctx, noteID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID) []*domain.Attachment); ok {
+		r0 = rf(ctx, noteID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Attachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, core.ID) error); ok {
+		r1 = rf(ctx, noteID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDeletedBlobs provides a mock function with given fields: ctx, limit
+func (_m *AttachmentRepository) ListDeletedBlobs(ctx context.Context, limit int) ([]string, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeletedBlobs")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]string, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []string); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAttachmentRepository creates a new instance of AttachmentRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAttachmentRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AttachmentRepository {
+	mock := &AttachmentRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,47 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "HATCH_APP/internal/note/domain"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EventPublisher is an autogenerated mock type for the EventPublisher type
+type EventPublisher struct {
+	mock.Mock
+}
+
+// Publish provides a mock function with given fields: ctx, event
+func (_m *EventPublisher) Publish(ctx context.Context, event domain.Event) error {
+	ret := _m.Called(ctx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Event) error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewEventPublisher creates a new instance of EventPublisher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEventPublisher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EventPublisher {
+	mock := &EventPublisher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
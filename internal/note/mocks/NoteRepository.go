@@ -3,10 +3,16 @@
 package mocks
 
 import (
-	domain "HATCH_APP/internal/note/domain"
+	core "HATCH_APP/pkg/core"
 	context "context"
 
+	domain "HATCH_APP/internal/note/domain"
+
+	iter "iter"
+
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // NoteRepository is an autogenerated mock type for the NoteRepository type
@@ -14,6 +20,24 @@ type NoteRepository struct {
 	mock.Mock
 }
 
+// AddTags provides a mock function with given fields: ctx, noteID, tags
+func (_m *NoteRepository) AddTags(ctx context.Context, noteID core.ID, tags []domain.Tag) error {
+	ret := _m.Called(ctx, noteID, tags)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddTags")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID, []domain.Tag) error); ok {
+		r0 = rf(ctx, noteID, tags)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Create provides a mock function with given fields: ctx, note
 func (_m *NoteRepository) Create(ctx context.Context, note *domain.Note) error {
 	ret := _m.Called(ctx, note)
@@ -33,7 +57,7 @@ func (_m *NoteRepository) Create(ctx context.Context, note *domain.Note) error {
 }
 
 // FindByID provides a mock function with given fields: ctx, id
-func (_m *NoteRepository) FindByID(ctx context.Context, id string) (*domain.Note, error) {
+func (_m *NoteRepository) FindByID(ctx context.Context, id core.ID) (*domain.Note, error) {
 	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
@@ -42,10 +66,10 @@ func (_m *NoteRepository) FindByID(ctx context.Context, id string) (*domain.Note
 
 	var r0 *domain.Note
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Note, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID) (*domain.Note, error)); ok {
 		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Note); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID) *domain.Note); ok {
 		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
@@ -53,7 +77,7 @@ func (_m *NoteRepository) FindByID(ctx context.Context, id string) (*domain.Note
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, core.ID) error); ok {
 		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
@@ -62,14 +86,154 @@ func (_m *NoteRepository) FindByID(ctx context.Context, id string) (*domain.Note
 	return r0, r1
 }
 
-// List provides a mock function with given fields: ctx
-func (_m *NoteRepository) List(ctx context.Context) ([]*domain.Note, error) {
-	ret := _m.Called(ctx)
+// FindIDOwners provides a mock function with given fields: ctx, ids
+func (_m *NoteRepository) FindIDOwners(ctx context.Context, ids []core.ID) (map[core.ID]string, error) {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindIDOwners")
+	}
+
+	var r0 map[core.ID]string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []core.ID) (map[core.ID]string, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []core.ID) map[core.ID]string); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[core.ID]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []core.ID) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindTrashedByID provides a mock function with given fields: ctx, id
+func (_m *NoteRepository) FindTrashedByID(ctx context.Context, id core.ID) (*domain.Note, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTrashedByID")
+	}
+
+	var r0 *domain.Note
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID) (*domain.Note, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID) *domain.Note); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Note)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, core.ID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Iterate provides a mock function with given fields: ctx, filter
+func (_m *NoteRepository) Iterate(ctx context.Context, filter domain.NoteFilter) iter.Seq2[*domain.Note, error] {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Iterate")
+	}
+
+	var r0 iter.Seq2[*domain.Note, error]
+	if rf, ok := ret.Get(0).(func(context.Context, domain.NoteFilter) iter.Seq2[*domain.Note, error]); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(iter.Seq2[*domain.Note, error])
+		}
+	}
+
+	return r0
+}
+
+// List provides a mock function with given fields: ctx, filter
+func (_m *NoteRepository) List(ctx context.Context, filter domain.NoteFilter) ([]*domain.Note, error) {
+	ret := _m.Called(ctx, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for List")
 	}
 
+	var r0 []*domain.Note
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.NoteFilter) ([]*domain.Note, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.NoteFilter) []*domain.Note); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Note)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.NoteFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTags provides a mock function with given fields: ctx, owner
+func (_m *NoteRepository) ListTags(ctx context.Context, owner string) ([]domain.TagCount, error) {
+	ret := _m.Called(ctx, owner)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTags")
+	}
+
+	var r0 []domain.TagCount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.TagCount, error)); ok {
+		return rf(ctx, owner)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.TagCount); ok {
+		r0 = rf(ctx, owner)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TagCount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, owner)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTrashed provides a mock function with given fields: ctx
+func (_m *NoteRepository) ListTrashed(ctx context.Context) ([]*domain.Note, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTrashed")
+	}
+
 	var r0 []*domain.Note
 	var r1 error
 	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.Note, error)); ok {
@@ -92,6 +256,54 @@ func (_m *NoteRepository) List(ctx context.Context) ([]*domain.Note, error) {
 	return r0, r1
 }
 
+// PurgeTrashed provides a mock function with given fields: ctx, before, limit
+func (_m *NoteRepository) PurgeTrashed(ctx context.Context, before time.Time, limit int) ([]*domain.Note, error) {
+	ret := _m.Called(ctx, before, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeTrashed")
+	}
+
+	var r0 []*domain.Note
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]*domain.Note, error)); ok {
+		return rf(ctx, before, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []*domain.Note); ok {
+		r0 = rf(ctx, before, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Note)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, before, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveTags provides a mock function with given fields: ctx, noteID, names
+func (_m *NoteRepository) RemoveTags(ctx context.Context, noteID core.ID, names []string) error {
+	ret := _m.Called(ctx, noteID, names)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveTags")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID, []string) error); ok {
+		r0 = rf(ctx, noteID, names)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Save provides a mock function with given fields: ctx, note
 func (_m *NoteRepository) Save(ctx context.Context, note *domain.Note) error {
 	ret := _m.Called(ctx, note)
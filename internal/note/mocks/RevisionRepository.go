@@ -0,0 +1,127 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	core "HATCH_APP/pkg/core"
+	context "context"
+
+	domain "HATCH_APP/internal/note/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RevisionRepository is an autogenerated mock type for the RevisionRepository type
+type RevisionRepository struct {
+	mock.Mock
+}
+
+// CreateRevision provides a mock function with given fields: ctx, revision
+func (_m *RevisionRepository) CreateRevision(ctx context.Context, revision domain.NoteRevision) error {
+	ret := _m.Called(ctx, revision)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRevision")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.NoteRevision) error); ok {
+		r0 = rf(ctx, revision)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindRevision provides a mock function with given fields: ctx, noteID, version
+func (_m *RevisionRepository) FindRevision(ctx context.Context, noteID core.ID, version int) (*domain.NoteRevision, error) {
+	ret := _m.Called(ctx, noteID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindRevision")
+	}
+
+	var r0 *domain.NoteRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID, int) (*domain.NoteRevision, error)); ok {
+		return rf(ctx, noteID, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID, int) *domain.NoteRevision); ok {
+		r0 = rf(ctx, noteID, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.NoteRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, core.ID, int) error); ok {
+		r1 = rf(ctx, noteID, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListRevisions provides a mock function with given fields: ctx, noteID
+func (_m *RevisionRepository) ListRevisions(ctx context.Context, noteID core.ID) ([]domain.NoteRevision, error) {
+	ret := _m.Called(ctx, noteID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRevisions")
+	}
+
+	var r0 []domain.NoteRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID) ([]domain.NoteRevision, error)); ok {
+		return rf(ctx, noteID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID) []domain.NoteRevision); ok {
+		r0 = rf(ctx, noteID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.NoteRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, core.ID) error); ok {
+		r1 = rf(ctx, noteID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PruneRevisions provides a mock function with given fields: ctx, noteID, version
+func (_m *RevisionRepository) PruneRevisions(ctx context.Context, noteID core.ID, version int) error {
+	ret := _m.Called(ctx, noteID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PruneRevisions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID, int) error); ok {
+		r0 = rf(ctx, noteID, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRevisionRepository creates a new instance of RevisionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRevisionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RevisionRepository {
+	mock := &RevisionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
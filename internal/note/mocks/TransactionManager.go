@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "HATCH_APP/internal/note/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TransactionManager is an autogenerated mock type for the TransactionManager type
+type TransactionManager struct {
+	mock.Mock
+}
+
+// Transact provides a mock function with given fields: fn
+func (_m *TransactionManager) Transact(fn func(domain.TransactionManagerInput) error) error {
+	ret := _m.Called(fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Transact")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(func(domain.TransactionManagerInput) error) error); ok {
+		r0 = rf(fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewTransactionManager creates a new instance of TransactionManager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTransactionManager(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TransactionManager {
+	mock := &TransactionManager{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
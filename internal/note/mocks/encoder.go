@@ -0,0 +1,64 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "HATCH_APP/internal/note/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// encoder is an autogenerated mock type for the encoder type
+type encoder struct {
+	mock.Mock
+}
+
+// Close provides a mock function with no fields
+func (_m *encoder) Close() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Close")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Encode provides a mock function with given fields: note
+func (_m *encoder) Encode(note *domain.Note) error {
+	ret := _m.Called(note)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Encode")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*domain.Note) error); ok {
+		r0 = rf(note)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// newEncoder creates a new instance of encoder. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newEncoder(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *encoder {
+	mock := &encoder{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
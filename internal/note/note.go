@@ -1,30 +1,507 @@
 package note
 
 import (
+	"HATCH_APP/internal/note/domain"
 	"HATCH_APP/internal/note/feature/archivenote"
 	"HATCH_APP/internal/note/feature/createnote"
+	"HATCH_APP/internal/note/feature/getnote"
 	"HATCH_APP/internal/note/feature/listnotes"
+	"HATCH_APP/internal/note/feature/listtags"
+	"HATCH_APP/internal/note/feature/listtrash"
+	"HATCH_APP/internal/note/feature/noteattachments"
+	"HATCH_APP/internal/note/feature/noterevisions"
+	"HATCH_APP/internal/note/feature/purgenotes"
+	"HATCH_APP/internal/note/feature/restorenote"
+	"HATCH_APP/internal/note/feature/streamnotes"
+	"HATCH_APP/internal/note/feature/tagnote"
+	"HATCH_APP/internal/note/feature/transfernotes"
+	"HATCH_APP/internal/note/feature/trashnote"
+	"HATCH_APP/internal/note/feature/updatenote"
+	noteCache "HATCH_APP/internal/note/infra/cache"
 	"HATCH_APP/internal/note/infra/store/postgres"
+	"HATCH_APP/internal/note/pb"
+	"HATCH_APP/internal/shared/events"
+	"HATCH_APP/pkg/blob"
+	"HATCH_APP/pkg/cache"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/markdown"
+	"HATCH_APP/pkg/scheduler"
+	pgStore "HATCH_APP/pkg/store/postgres"
+	"HATCH_APP/pkg/transport/httpx/openapi"
+	"HATCH_APP/pkg/transport/httpx/sse"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/jmoiron/sqlx"
+	"google.golang.org/grpc"
 )
 
-func Register(r chi.Router, db *sqlx.DB) error {
-	noteRepo, err := postgres.NewNoteRepository(db)
+// EventTypes lists the events published by the module, see domain.Event.
+var EventTypes = domain.EventTypes
+
+type External struct {
+	DB        *sqlx.DB
+	Scheduler *scheduler.Scheduler
+	Cache     cache.Cache
+	// Blob keeps the content of note attachments.
+	Blob blob.Store
+	// GRPC is optional, the NoteService is only exposed when it is set.
+	GRPC grpc.ServiceRegistrar
+	// OpenAPI documents the HTTP routes when set.
+	OpenAPI *openapi.Spec
+	// Listener receives the note events committed by every replica, they
+	// invalidate the cache and feed Stream, the streams of the process.
+	Listener *pgStore.Listener
+	Stream   *sse.Broker
+	// Events shares the note events with other modules, such as the
+	// webhooks, they are not shared when nil.
+	Events events.Publisher
+	// Reads routes the read-only note queries, to read replicas for
+	// instance, they all go to DB when nil.
+	Reads pgStore.ReadRouter
+	// Clock and IDs default to the system clock and a ULID generator on it.
+	Clock core.Clock
+	IDs   core.IDGenerator
+}
+
+// idParams types the {id} path param of the note routes.
+type idParams struct {
+	ID core.ID `path:"id"`
+}
+
+// tagParams types the path params of the tag removal route.
+type tagParams struct {
+	ID  core.ID `path:"id"`
+	Tag string  `path:"tag"`
+}
+
+// revisionParams types the path params of a single revision route.
+type revisionParams struct {
+	ID      core.ID `path:"id"`
+	Version int     `path:"version" validate:"min=1"`
+}
+
+// uploadParams types the params of the attachment upload route.
+type uploadParams struct {
+	ID       core.ID `path:"id"`
+	Checksum string  `header:"X-Checksum-Sha256" validate:"omitempty,len=64"`
+}
+
+// attachmentParams types the path params of a single attachment route.
+type attachmentParams struct {
+	ID           core.ID `path:"id"`
+	AttachmentID core.ID `path:"attachmentID"`
+}
+
+// diffParams types the params of the revision diff route, any two versions
+// can be diffed in either order.
+type diffParams struct {
+	ID   core.ID `path:"id"`
+	From int     `query:"from" validate:"required,min=1"`
+	To   int     `query:"to"   validate:"required,min=1"`
+}
+
+type Config struct {
+	PurgeSchedule  string
+	TrashRetention time.Duration
+	PurgeTimeout   time.Duration
+	CacheTTL       time.Duration
+	PurgeBatchSize int
+	// BlobSweepBatchSize is the number of attachment blobs deleted per batch
+	// by the sweep.
+	BlobSweepBatchSize int
+	// RevisionRetention is the number of revisions kept per note, 0 keeps
+	// them all.
+	RevisionRetention int
+	// RenderCacheTTL bounds how long rendered contents stay cached, they are
+	// keyed by content hash and never go stale.
+	RenderCacheTTL time.Duration
+	// AttachmentContentTypes lists the media types attachments may have once
+	// sniffed, type/* allows a whole type.
+	AttachmentContentTypes []string
+	AttachmentMaxSize      int64
+	// ImportMaxSize bounds the files imported, in bytes.
+	ImportMaxSize int64
+	// ImportMaxUnzippedSize bounds the decompressed files of an imported zip,
+	// in bytes.
+	ImportMaxUnzippedSize int64
+	ImportMaxRows         int
+}
+
+// Validate rejects the values the background jobs and imports cannot run
+// with.
+func (c Config) Validate() error {
+	if c.PurgeBatchSize <= 0 {
+		return fmt.Errorf("note: purge batch size must be positive, got %d", c.PurgeBatchSize)
+	}
+
+	if c.BlobSweepBatchSize <= 0 {
+		return fmt.Errorf("note: blob sweep batch size must be positive, got %d", c.BlobSweepBatchSize)
+	}
+
+	if c.ImportMaxRows <= 0 {
+		return fmt.Errorf("note: import max rows must be positive, got %d", c.ImportMaxRows)
+	}
+
+	if c.ImportMaxUnzippedSize <= 0 {
+		return fmt.Errorf("note: import max unzipped size must be positive, got %d", c.ImportMaxUnzippedSize)
+	}
+
+	return nil
+}
+
+func Register(r chi.Router, ext External, cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	pgNoteRepo, err := postgres.NewNoteRepository(ext.DB)
 	if err != nil {
 		return err
 	}
 
-	createNoteF := createnote.New(noteRepo)
-	archiveNoteF := archivenote.New(noteRepo)
+	if ext.Reads != nil {
+		pgNoteRepo.RouteReads(ext.Reads)
+	}
+
+	noteRepo := noteCache.NewNoteRepository(pgNoteRepo, ext.Cache, cfg.CacheTTL)
+	txManager := noteCache.NewTransactionManager(postgres.NewTransactionManager(ext.DB).PublishTo(ext.Events), ext.Cache)
+
+	revisionRepo, err := postgres.NewRevisionRepository(ext.DB)
+	if err != nil {
+		return err
+	}
+
+	attachmentRepo, err := postgres.NewAttachmentRepository(ext.DB)
+	if err != nil {
+		return err
+	}
+
+	purgeSchedule, err := scheduler.Parse(cfg.PurgeSchedule)
+	if err != nil {
+		return err
+	}
+
+	clock := ext.Clock
+	if clock == nil {
+		clock = core.SystemClock()
+	}
+
+	ids := ext.IDs
+	if ids == nil {
+		ids = core.NewULIDGenerator(clock)
+	}
+
+	createNoteF := createnote.New(txManager, ids, clock)
+	updateNoteF := updatenote.New(txManager, ids, clock, cfg.RevisionRetention)
+	noteRevisionsF := noterevisions.New(noteRepo, revisionRepo, txManager, ids, clock, cfg.RevisionRetention)
+	getNoteF := getnote.New(noteRepo, markdown.NewRenderer(), ext.Cache, cfg.RenderCacheTTL)
+	archiveNoteF := archivenote.New(txManager, ids, clock)
 	listNotesF := listnotes.New(noteRepo)
+	trashNoteF := trashnote.New(txManager, ids, clock)
+	restoreNoteF := restorenote.New(txManager, ids, clock)
+	listTrashF := listtrash.New(noteRepo)
+	streamNotesF := streamnotes.New(noteRepo, ext.Stream)
+	transferNotesF := transfernotes.New(noteRepo, txManager, ids, clock, transfernotes.Limits{
+		MaxSize:         cfg.ImportMaxSize,
+		MaxUnzippedSize: cfg.ImportMaxUnzippedSize,
+		MaxRows:         cfg.ImportMaxRows,
+	})
+	tagNoteF := tagnote.New(txManager, ids, clock)
+	listTagsF := listtags.New(noteRepo)
+	purgeNotesF := purgenotes.New(txManager, ids, clock, cfg.TrashRetention, cfg.PurgeBatchSize)
+	noteAttachmentsF := noteattachments.New(noteRepo, attachmentRepo, ext.Blob, ids, clock, noteattachments.Limits{
+		ContentTypes: cfg.AttachmentContentTypes,
+		MaxSize:      cfg.AttachmentMaxSize,
+	}, cfg.BlobSweepBatchSize)
+
+	// HTTP
+	tags := []string{"notes"}
 
-	r.Route("/v1/notes", func(r chi.Router) {
-		r.Post("/", createNoteF.CreateNoteEndpoint)
-		r.Get("/", listNotesF.ListNotesEndpoint)
-		r.Patch("/{id}", archiveNoteF.ArchiveNoteEndpoint)
+	openapi.Mount(r, ext.OpenAPI, "/v1/notes",
+		openapi.Route{
+			Method:      http.MethodPost,
+			Path:        "/",
+			Handler:     createNoteF.CreateNoteEndpoint,
+			OperationID: "createNote",
+			Summary:     "Create a note",
+			Tags:        tags,
+			Request:     createnote.Request{},
+			Responses:   map[int]any{http.StatusCreated: createnote.Response{}},
+			Errors:      []int{http.StatusBadRequest},
+		},
+		openapi.Route{
+			Method:      http.MethodGet,
+			Path:        "/",
+			Handler:     listNotesF.ListNotesEndpoint,
+			OperationID: "listNotes",
+			Params:      listnotes.Params{},
+			Summary:     "List notes",
+			Tags:        tags,
+			Responses:   map[int]any{http.StatusOK: listnotes.Response{}},
+			Errors:      []int{http.StatusBadRequest},
+		},
+		openapi.Route{
+			Method:      http.MethodGet,
+			Path:        "/tags",
+			Handler:     listTagsF.ListTagsEndpoint,
+			OperationID: "listTags",
+			Summary:     "List tags with their note counts",
+			Tags:        tags,
+			Responses:   map[int]any{http.StatusOK: listtags.Response{}},
+		},
+		openapi.Route{
+			Method:      http.MethodGet,
+			Path:        "/trash",
+			Handler:     listTrashF.ListTrashEndpoint,
+			OperationID: "listTrash",
+			Summary:     "List trashed notes",
+			Tags:        tags,
+			Responses:   map[int]any{http.StatusOK: listtrash.Response{}},
+		},
+		openapi.Route{
+			Method:      http.MethodGet,
+			Path:        "/export",
+			Handler:     transferNotesF.ExportNotesEndpoint,
+			OperationID: "exportNotes",
+			Params:      transfernotes.ExportParams{},
+			Summary:     "Export the notes out of the trash, oldest first",
+			Tags:        tags,
+			Responses:   map[int]any{http.StatusOK: openapi.Binary{}},
+			Errors:      []int{http.StatusBadRequest},
+		},
+		openapi.Route{
+			Method:      http.MethodPost,
+			Path:        "/import",
+			Handler:     transferNotesF.ImportNotesEndpoint,
+			OperationID: "importNotes",
+			Params:      transfernotes.ImportParams{},
+			Summary:     "Import notes, reporting the rows left out",
+			Tags:        tags,
+			Request:     openapi.File{Field: transfernotes.FileField},
+			Responses:   map[int]any{http.StatusOK: transfernotes.ImportResponse{}},
+			Errors:      []int{http.StatusBadRequest, http.StatusConflict, http.StatusRequestEntityTooLarge},
+		},
+		openapi.Route{
+			Method:      http.MethodGet,
+			Path:        "/stream",
+			Handler:     streamNotesF.StreamNotesEndpoint,
+			OperationID: "streamNotes",
+			Summary:     "Stream note changes, resuming after the Last-Event-ID header",
+			Tags:        tags,
+			Responses:   map[int]any{http.StatusOK: openapi.EventStream{}},
+			Errors:      []int{http.StatusServiceUnavailable},
+		},
+		openapi.Route{
+			Method:      http.MethodGet,
+			Path:        "/{id}",
+			Handler:     getNoteF.GetNoteEndpoint,
+			OperationID: "getNote",
+			Params:      getnote.Params{},
+			Summary:     "Get a note, optionally rendered as sanitized HTML",
+			Tags:        tags,
+			Responses:   map[int]any{http.StatusOK: getnote.Response{}},
+			Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		openapi.Route{
+			Method:      http.MethodPut,
+			Path:        "/{id}",
+			Handler:     updateNoteF.UpdateNoteEndpoint,
+			OperationID: "updateNote",
+			Params:      idParams{},
+			Summary:     "Edit the title and content of a note",
+			Tags:        tags,
+			Request:     updatenote.Request{},
+			Responses:   map[int]any{http.StatusOK: updatenote.Response{}},
+			Errors:      []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+		},
+		openapi.Route{
+			Method:      http.MethodPatch,
+			Path:        "/{id}",
+			Handler:     archiveNoteF.ArchiveNoteEndpoint,
+			OperationID: "archiveNote",
+			Params:      idParams{},
+			Summary:     "Archive a note",
+			Tags:        tags,
+			Responses:   map[int]any{http.StatusNoContent: nil},
+			Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		openapi.Route{
+			Method:      http.MethodDelete,
+			Path:        "/{id}",
+			Handler:     trashNoteF.TrashNoteEndpoint,
+			OperationID: "trashNote",
+			Params:      idParams{},
+			Summary:     "Move a note to the trash",
+			Tags:        tags,
+			Responses:   map[int]any{http.StatusNoContent: nil},
+			Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		openapi.Route{
+			Method:      http.MethodPost,
+			Path:        "/{id}/restore",
+			Handler:     restoreNoteF.RestoreNoteEndpoint,
+			OperationID: "restoreNote",
+			Params:      idParams{},
+			Summary:     "Restore a note from the trash",
+			Tags:        tags,
+			Responses:   map[int]any{http.StatusNoContent: nil},
+			Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		openapi.Route{
+			Method:      http.MethodPost,
+			Path:        "/{id}/attachments",
+			Handler:     noteAttachmentsF.UploadAttachmentEndpoint,
+			OperationID: "uploadNoteAttachment",
+			Params:      uploadParams{},
+			Summary:     "Attach a file to a note",
+			Tags:        tags,
+			Request:     openapi.File{Field: noteattachments.FileField},
+			Responses:   map[int]any{http.StatusCreated: noteattachments.Response{}},
+			Errors:      []int{http.StatusBadRequest, http.StatusNotFound, http.StatusRequestEntityTooLarge},
+		},
+		openapi.Route{
+			Method:      http.MethodGet,
+			Path:        "/{id}/attachments",
+			Handler:     noteAttachmentsF.ListAttachmentsEndpoint,
+			OperationID: "listNoteAttachments",
+			Params:      idParams{},
+			Summary:     "List the attachments of a note",
+			Tags:        tags,
+			Responses:   map[int]any{http.StatusOK: noteattachments.ListResponse{}},
+			Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		openapi.Route{
+			Method:      http.MethodGet,
+			Path:        "/{id}/attachments/{attachmentID}",
+			Handler:     noteAttachmentsF.DownloadAttachmentEndpoint,
+			OperationID: "downloadNoteAttachment",
+			Params:      attachmentParams{},
+			Summary:     "Download an attachment of a note",
+			Tags:        tags,
+			Responses:   map[int]any{http.StatusOK: openapi.Binary{}},
+			Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		openapi.Route{
+			Method:      http.MethodDelete,
+			Path:        "/{id}/attachments/{attachmentID}",
+			Handler:     noteAttachmentsF.DeleteAttachmentEndpoint,
+			OperationID: "deleteNoteAttachment",
+			Params:      attachmentParams{},
+			Summary:     "Delete an attachment of a note",
+			Tags:        tags,
+			Responses:   map[int]any{http.StatusNoContent: nil},
+			Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		openapi.Route{
+			Method:      http.MethodGet,
+			Path:        "/{id}/revisions",
+			Handler:     noteRevisionsF.ListRevisionsEndpoint,
+			OperationID: "listNoteRevisions",
+			Params:      idParams{},
+			Summary:     "List the revisions of a note, newest first",
+			Tags:        tags,
+			Responses:   map[int]any{http.StatusOK: noterevisions.ListResponse{}},
+			Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		openapi.Route{
+			Method:      http.MethodGet,
+			Path:        "/{id}/revisions/diff",
+			Handler:     noteRevisionsF.DiffRevisionsEndpoint,
+			OperationID: "diffNoteRevisions",
+			Params:      diffParams{},
+			Summary:     "Diff two revisions of a note line by line",
+			Tags:        tags,
+			Responses:   map[int]any{http.StatusOK: noterevisions.DiffResponse{}},
+			Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		openapi.Route{
+			Method:      http.MethodGet,
+			Path:        "/{id}/revisions/{version}",
+			Handler:     noteRevisionsF.GetRevisionEndpoint,
+			OperationID: "getNoteRevision",
+			Params:      revisionParams{},
+			Summary:     "Get a revision of a note",
+			Tags:        tags,
+			Responses:   map[int]any{http.StatusOK: noterevisions.RevisionResponse{}},
+			Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		openapi.Route{
+			Method:      http.MethodPost,
+			Path:        "/{id}/revisions/{version}/restore",
+			Handler:     noteRevisionsF.RestoreRevisionEndpoint,
+			OperationID: "restoreNoteRevision",
+			Params:      revisionParams{},
+			Summary:     "Restore a revision of a note as its new version",
+			Tags:        tags,
+			Responses:   map[int]any{http.StatusOK: noterevisions.RestoreResponse{}},
+			Errors:      []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+		},
+		openapi.Route{
+			Method:      http.MethodPost,
+			Path:        "/{id}/tags",
+			Handler:     tagNoteF.AddTagsEndpoint,
+			OperationID: "addNoteTags",
+			Params:      idParams{},
+			Summary:     "Tag a note",
+			Tags:        tags,
+			Request:     tagnote.Request{},
+			Responses:   map[int]any{http.StatusOK: tagnote.Response{}},
+			Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		openapi.Route{
+			Method:      http.MethodDelete,
+			Path:        "/{id}/tags/{tag}",
+			Handler:     tagNoteF.RemoveTagEndpoint,
+			OperationID: "removeNoteTag",
+			Params:      tagParams{},
+			Summary:     "Remove a tag from a note",
+			Tags:        tags,
+			Responses:   map[int]any{http.StatusNoContent: nil},
+			Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+	)
+
+	// Notifications, the cache is invalidated before the note is loaded for
+	// the streams.
+	pgStore.Subscribe(ext.Listener, postgres.EventChannel, func(ctx context.Context, notice domain.EventNotice) {
+		noteRepo.Invalidate(ctx, notice.NoteID)
 	})
+	pgStore.Subscribe(ext.Listener, postgres.EventChannel, streamNotesF.OnNoteEvent)
+	ext.Listener.OnMissed(noteRepo.Resync)
+
+	// gRPC
+	if ext.GRPC != nil {
+		pb.RegisterNoteServiceServer(ext.GRPC, &gRPCHandler{
+			createNoteF:  createNoteF,
+			listNotesF:   listNotesF,
+			archiveNoteF: archiveNoteF,
+		})
+	}
+
+	// Jobs
+	if err := ext.Scheduler.Register(scheduler.Job{
+		Name:     "note.purge-trashed",
+		Schedule: purgeSchedule,
+		Timeout:  cfg.PurgeTimeout,
+		Jitter:   time.Minute,
+		Run:      purgeNotesF.PurgeTrashedNotesJob,
+	}); err != nil {
+		return err
+	}
+
+	if err := ext.Scheduler.Register(scheduler.Job{
+		Name:     "note.sweep-attachment-blobs",
+		Schedule: purgeSchedule,
+		Timeout:  cfg.PurgeTimeout,
+		Jitter:   time.Minute,
+		Run:      noteAttachmentsF.SweepDeletedBlobsJob,
+	}); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -0,0 +1,273 @@
+package note_test
+
+import (
+	"HATCH_APP/internal/note"
+	"HATCH_APP/pkg/blob/local"
+	"HATCH_APP/pkg/cache/memory"
+	"HATCH_APP/pkg/scheduler"
+	pgStore "HATCH_APP/pkg/store/postgres"
+	"HATCH_APP/pkg/transport/httpx"
+	"HATCH_APP/pkg/transport/httpx/openapi"
+	"HATCH_APP/pkg/transport/httpx/sse"
+	"HATCH_APP/test/container"
+	"HATCH_APP/test/httptest"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRouter(t *testing.T) (*openapi.Spec, chi.Router) {
+	t.Helper()
+
+	httptest.Init()
+
+	db, url, teardown := container.SetupPostgresWithURL(t)
+	t.Cleanup(teardown)
+
+	blobs, err := local.New(t.TempDir())
+	require.NoError(t, err)
+
+	spec := openapi.New(openapi.Info{Title: "test", Version: "1"}, "/api", httpx.ErrorResponse{})
+
+	r := chi.NewRouter()
+
+	r.Route("/api", func(r chi.Router) {
+		r.Use(httptest.Contract(t, spec))
+
+		err = note.Register(r, note.External{
+			DB:        db,
+			Scheduler: scheduler.New(scheduler.Local()),
+			Cache:     memory.NewLRU(10),
+			Blob:      blobs,
+			OpenAPI:   spec,
+			Listener:  pgStore.NewListener(url, pgStore.ListenerConfig{}),
+			Stream:    sse.NewBroker(sse.Config{}),
+		}, note.Config{
+			PurgeSchedule:         "@every 1h",
+			PurgeBatchSize:        10,
+			BlobSweepBatchSize:    10,
+			ImportMaxSize:         1 << 20,
+			ImportMaxUnzippedSize: 1 << 20,
+			ImportMaxRows:         10,
+		})
+	})
+	require.NoError(t, err)
+
+	return spec, r
+}
+
+func TestConfigValidate(t *testing.T) {
+	valid := note.Config{PurgeBatchSize: 1, BlobSweepBatchSize: 1, ImportMaxUnzippedSize: 1, ImportMaxRows: 1}
+
+	t.Run("should accept positive batch sizes and import limits", func(t *testing.T) {
+		require.NoError(t, valid.Validate())
+	})
+
+	t.Run("should reject a purge batch size that is not positive", func(t *testing.T) {
+		for _, size := range []int{0, -1} {
+			cfg := valid
+			cfg.PurgeBatchSize = size
+
+			assert.Error(t, cfg.Validate())
+		}
+	})
+
+	t.Run("should reject a blob sweep batch size that is not positive", func(t *testing.T) {
+		for _, size := range []int{0, -1} {
+			cfg := valid
+			cfg.BlobSweepBatchSize = size
+
+			assert.Error(t, cfg.Validate())
+		}
+	})
+
+	t.Run("should reject import limits that are not positive", func(t *testing.T) {
+		for _, limit := range []int{0, -1} {
+			cfg := valid
+			cfg.ImportMaxRows = limit
+
+			assert.Error(t, cfg.Validate())
+
+			cfg = valid
+			cfg.ImportMaxUnzippedSize = int64(limit)
+
+			assert.Error(t, cfg.Validate())
+		}
+	})
+}
+
+func TestOpenAPIIntegration(t *testing.T) {
+	spec, r := setupRouter(t)
+
+	t.Run("should declare every registered route", func(t *testing.T) {
+		missing, err := spec.Missing(r)
+		require.NoError(t, err)
+
+		assert.Empty(t, missing, "every registered route must be declared in the spec")
+	})
+
+	t.Run("should answer within the contract", func(t *testing.T) {
+		do := func(method, target, body string) *stdhttptest.ResponseRecorder {
+			rec := stdhttptest.NewRecorder()
+			r.ServeHTTP(rec, stdhttptest.NewRequest(method, target, strings.NewReader(body)))
+
+			return rec
+		}
+
+		rec := do(http.MethodPost, "/api/v1/notes", `{"title":"title","content":"content"}`)
+		require.Equal(t, http.StatusCreated, rec.Code)
+
+		var created struct {
+			Data struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+
+		assert.Equal(t, http.StatusOK, do(http.MethodGet, "/api/v1/notes", "").Code)
+		assert.Equal(t, http.StatusNoContent, do(http.MethodPatch, "/api/v1/notes/"+created.Data.ID, "").Code)
+		assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/api/v1/notes/"+created.Data.ID, "").Code)
+		assert.Equal(t, http.StatusOK, do(http.MethodGet, "/api/v1/notes/trash", "").Code)
+		assert.Equal(t, http.StatusNoContent, do(http.MethodPost, "/api/v1/notes/"+created.Data.ID+"/restore", "").Code)
+
+		var upload bytes.Buffer
+		form := multipart.NewWriter(&upload)
+		file, err := form.CreateFormFile("file", "hello.txt")
+		require.NoError(t, err)
+		_, _ = file.Write([]byte("hello"))
+		require.NoError(t, form.Close())
+
+		req := stdhttptest.NewRequest(http.MethodPost, "/api/v1/notes/"+created.Data.ID+"/attachments", &upload)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		rec = stdhttptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusCreated, rec.Code)
+
+		var attached struct {
+			Data struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &attached))
+
+		rec = do(http.MethodGet, "/api/v1/notes/"+created.Data.ID+"/attachments/"+attached.Data.ID, "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "hello", rec.Body.String())
+
+		rec = do(http.MethodGet, "/api/v1/notes/export?format=csv", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), created.Data.ID)
+
+		upload.Reset()
+		form = multipart.NewWriter(&upload)
+		file, err = form.CreateFormFile("file", "notes.jsonl")
+		require.NoError(t, err)
+		_, _ = file.Write([]byte(`{"title":"imported"}`))
+		require.NoError(t, form.Close())
+
+		req = stdhttptest.NewRequest(http.MethodPost, "/api/v1/notes/import?dry_run=true", &upload)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		rec = stdhttptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/api/v1/notes/"+created.Data.ID+"/attachments", "hello").Code)
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/api/v1/notes", `{"title":""}`).Code)
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodPatch, "/api/v1/notes/not-an-id", "").Code)
+	})
+}
+
+func TestNoteStreamIntegration(t *testing.T) {
+	httptest.Init()
+
+	db, url, teardown := container.SetupPostgresWithURL(t)
+	t.Cleanup(teardown)
+
+	// replica registers the module as another API process would, sharing
+	// only the database.
+	replica := func() chi.Router {
+		blobs, err := local.New(t.TempDir())
+		require.NoError(t, err)
+
+		listener := pgStore.NewListener(url, pgStore.ListenerConfig{})
+
+		r := chi.NewRouter()
+
+		r.Route("/api", func(r chi.Router) {
+			err = note.Register(r, note.External{
+				DB:        db,
+				Scheduler: scheduler.New(scheduler.Local()),
+				Cache:     memory.NewLRU(10),
+				Blob:      blobs,
+				Listener:  listener,
+				Stream:    sse.NewBroker(sse.Config{}),
+			}, note.Config{
+				PurgeSchedule:         "@every 1h",
+				PurgeBatchSize:        10,
+				BlobSweepBatchSize:    10,
+				ImportMaxUnzippedSize: 1 << 20,
+				ImportMaxRows:         10,
+			})
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, listener.Start(t.Context()))
+		t.Cleanup(func() {
+			require.NoError(t, listener.Close(context.Background()))
+		})
+
+		return r
+	}
+
+	writer, reader := replica(), replica()
+
+	srv := stdhttptest.NewServer(reader)
+	t.Cleanup(srv.Close)
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL+"/api/v1/notes/stream", nil)
+	require.NoError(t, err)
+
+	res, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = res.Body.Close() })
+
+	rec := stdhttptest.NewRecorder()
+	writer.ServeHTTP(rec, stdhttptest.NewRequest(http.MethodPost, "/api/v1/notes", strings.NewReader(`{"title":"title","content":"content"}`)))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var created struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+
+	stream := bufio.NewReader(res.Body)
+
+	var lines []string
+
+	for {
+		line, err := stream.ReadString('\n')
+		require.NoError(t, err)
+
+		if line == "\n" {
+			break
+		}
+
+		lines = append(lines, line)
+	}
+
+	require.Len(t, lines, 3)
+	assert.Equal(t, "event: note.created\n", lines[1])
+	assert.Contains(t, lines[2], `"id":"`+created.Data.ID+`"`)
+}
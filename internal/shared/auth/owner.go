@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// OwnerHeader carries the owner a request acts for. Requests are
+// authenticated by the gateway in front of the API, which sets it and must
+// drop it from what clients send.
+const OwnerHeader = "X-Owner-ID"
+
+type ownerCtxKey struct{}
+
+// WithOwner scopes ctx to owner. The empty owner holds everything created
+// without one, e.g. before notes had owners.
+func WithOwner(ctx context.Context, owner string) context.Context {
+	return context.WithValue(ctx, ownerCtxKey{}, owner)
+}
+
+func OwnerFromContext(ctx context.Context) string {
+	owner, _ := ctx.Value(ownerCtxKey{}).(string)
+	return owner
+}
+
+// Owner is the HTTP middleware reading OwnerHeader.
+func Owner(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := WithOwner(r.Context(), strings.TrimSpace(r.Header.Get(OwnerHeader)))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// OwnerFromMetadata is the gRPC counterpart of Owner, reading OwnerHeader
+// from the incoming metadata.
+func OwnerFromMetadata(ctx context.Context) context.Context {
+	var owner string
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(OwnerHeader); len(values) > 0 {
+			owner = strings.TrimSpace(values[0])
+		}
+	}
+
+	return WithOwner(ctx, owner)
+}
@@ -0,0 +1,39 @@
+package auth_test
+
+import (
+	"HATCH_APP/internal/shared/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestOwner(t *testing.T) {
+	t.Run("should read the owner header", func(t *testing.T) {
+		var got string
+
+		h := auth.Owner(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			got = auth.OwnerFromContext(r.Context())
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(auth.OwnerHeader, " acme ")
+
+		h.ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.Equal(t, "acme", got)
+	})
+
+	t.Run("should read the owner metadata", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(t.Context(), metadata.Pairs(auth.OwnerHeader, "acme"))
+
+		assert.Equal(t, "acme", auth.OwnerFromContext(auth.OwnerFromMetadata(ctx)))
+	})
+
+	t.Run("should default to the empty owner", func(t *testing.T) {
+		assert.Empty(t, auth.OwnerFromContext(t.Context()))
+		assert.Empty(t, auth.OwnerFromContext(auth.OwnerFromMetadata(t.Context())))
+	})
+}
@@ -0,0 +1,27 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"HATCH_APP/pkg/core"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Event is a change a module shares with the others, Data being its JSON
+// payload. OwnerID is the owner of the changed data, only they may see it.
+type Event struct {
+	CreatedAt time.Time
+	ID        core.ID
+	Type      string
+	OwnerID   string
+	Data      json.RawMessage
+}
+
+// Publisher hands events to other modules. db is the transaction of the
+// change the event describes, so nothing is acted on before it commits.
+type Publisher interface {
+	Publish(ctx context.Context, db sqlx.ExtContext, event Event) error
+}
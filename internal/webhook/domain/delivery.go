@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"HATCH_APP/pkg/core"
+)
+
+const (
+	// MaxResponseBodyLength bounds the part of the endpoint answer kept in
+	// the delivery log, in bytes.
+	MaxResponseBodyLength = 1024
+	DefaultDeliveryLimit  = 50
+	MaxDeliveryLimit      = 100
+)
+
+// Delivery logs one attempt to deliver an event to an endpoint.
+type Delivery struct {
+	CreatedAt    time.Time       `json:"created_at"      db:"created_at"`
+	StatusCode   *int            `json:"status_code"     db:"status_code"`
+	ID           core.ID         `json:"id"              db:"id"`
+	EndpointID   core.ID         `json:"endpoint_id"     db:"endpoint_id"`
+	EventID      core.ID         `json:"event_id"        db:"event_id"`
+	EventType    string          `json:"event_type"      db:"event_type"`
+	Payload      json.RawMessage `json:"payload"         db:"payload"`
+	ResponseBody string          `json:"response_body"   db:"response_body"`
+	Error        string          `json:"error,omitempty" db:"error"`
+	Attempt      int             `json:"attempt"         db:"attempt"`
+	DurationMS   int64           `json:"duration_ms"     db:"duration_ms"`
+	Succeeded    bool            `json:"succeeded"       db:"succeeded"`
+}
+
+type DeliveryRepository interface {
+	CreateDelivery(ctx context.Context, delivery *Delivery) error
+	// ListDeliveries returns the latest deliveries to an endpoint, newest
+	// first.
+	ListDeliveries(ctx context.Context, endpointID core.ID, limit int) ([]*Delivery, error)
+	FindDelivery(ctx context.Context, endpointID, id core.ID) (*Delivery, error)
+	// PruneDeliveries drops the deliveries of an endpoint beyond the newest
+	// keep ones.
+	PruneDeliveries(ctx context.Context, endpointID core.ID, keep int) error
+}
+
+// DeliveryQueue delivers events in the background, retrying with backoff.
+type DeliveryQueue interface {
+	// EnqueueDelivery reports false when the event is already waiting to be
+	// delivered to the endpoint.
+	EnqueueDelivery(ctx context.Context, endpointID core.ID, event Event) (bool, error)
+}
+
+// NewDelivery logs an attempt, statusCode is 0 when no answer was received.
+func NewDelivery(
+	id core.ID,
+	endpointID core.ID,
+	event Event,
+	payload []byte,
+	attempt, statusCode int,
+	responseBody []byte,
+	deliveryErr error,
+	duration time.Duration,
+	now time.Time,
+) *Delivery {
+	d := &Delivery{
+		ID:           id,
+		EndpointID:   endpointID,
+		EventID:      event.ID,
+		EventType:    event.Type,
+		Payload:      payload,
+		Attempt:      attempt,
+		ResponseBody: truncate(responseBody),
+		DurationMS:   duration.Milliseconds(),
+		Succeeded:    deliveryErr == nil && statusCode >= 200 && statusCode < 300,
+		CreatedAt:    now,
+	}
+
+	if statusCode != 0 {
+		d.StatusCode = new(statusCode)
+	}
+
+	if deliveryErr != nil {
+		d.Error = deliveryErr.Error()
+	}
+
+	return d
+}
+
+// Event decodes the delivered payload, to deliver it again.
+func (d *Delivery) Event() (Event, error) {
+	var event Event
+
+	err := json.Unmarshal(d.Payload, &event)
+
+	return event, err
+}
+
+func truncate(body []byte) string {
+	if len(body) > MaxResponseBodyLength {
+		body = body[:MaxResponseBodyLength]
+	}
+
+	return strings.ToValidUTF8(string(body), "")
+}
+
+// ParseDeliveryLimit parses the number of deliveries to list, an empty raw
+// value is DefaultDeliveryLimit.
+func ParseDeliveryLimit(raw string) (int, error) {
+	if raw == "" {
+		return DefaultDeliveryLimit, nil
+	}
+
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 1 || limit > MaxDeliveryLimit {
+		return 0, ErrWebhookDeliveryLimitInvalid.New().WithDetails(map[string]int{"max": MaxDeliveryLimit})
+	}
+
+	return limit, nil
+}
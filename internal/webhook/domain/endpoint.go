@@ -0,0 +1,277 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"HATCH_APP/pkg/core"
+)
+
+const (
+	MaxURLLength         = 2048
+	MaxDescriptionLength = 200
+	secretPrefix         = "whsec_"
+)
+
+type EndpointStatus string
+
+const (
+	EndpointStatusEnabled  EndpointStatus = "enabled"
+	EndpointStatusDisabled EndpointStatus = "disabled"
+)
+
+// Enum lists the statuses for the API schema.
+func (EndpointStatus) Enum() []any {
+	return []any{EndpointStatusEnabled, EndpointStatusDisabled}
+}
+
+// Endpoint receives the events of the types it subscribes to, about the data
+// of its owner only.
+type Endpoint struct {
+	UpdatedAt   *time.Time     `json:"updated_at"  db:"updated_at"`
+	DisabledAt  *time.Time     `json:"disabled_at" db:"disabled_at"`
+	CreatedAt   time.Time      `json:"created_at"  db:"created_at"`
+	ID          core.ID        `json:"id"          db:"id"`
+	OwnerID     string         `json:"owner_id"    db:"owner_id"`
+	URL         string         `json:"url"         db:"url"`
+	Description string         `json:"description" db:"description"`
+	Secret      string         `json:"-"           db:"secret"`
+	Status      EndpointStatus `json:"status"      db:"status"`
+	EventTypes  []string       `json:"event_types" db:"-"`
+	// FailureCount is the number of failed attempts in a row, the endpoint
+	// is disabled once it reaches the configured threshold.
+	FailureCount int `json:"failure_count" db:"failure_count"`
+}
+
+type EndpointRepository interface {
+	CreateEndpoint(ctx context.Context, endpoint *Endpoint) error
+	FindEndpoint(ctx context.Context, id core.ID) (*Endpoint, error)
+	ListEndpoints(ctx context.Context, owner string) ([]*Endpoint, error)
+	SaveEndpoint(ctx context.Context, endpoint *Endpoint) error
+	DeleteEndpoint(ctx context.Context, id core.ID) error
+	// ListSubscribed returns the enabled endpoints of owner subscribed to
+	// eventType.
+	ListSubscribed(ctx context.Context, eventType, owner string) ([]*Endpoint, error)
+	// RecordSuccess resets the failures in a row of the endpoint.
+	RecordSuccess(ctx context.Context, id core.ID) error
+	// RecordFailure counts a failure in a row and disables the endpoint when
+	// it reaches disableAfter, reporting whether it did.
+	RecordFailure(ctx context.Context, id core.ID, disableAfter int, now time.Time) (bool, error)
+}
+
+// NewEndpoint subscribes rawURL to eventTypes, which must all be part of
+// known.
+func NewEndpoint(
+	id core.ID,
+	now time.Time,
+	rawURL, description, secret string,
+	eventTypes, known []string,
+) (*Endpoint, error) {
+	e := &Endpoint{
+		ID:        id,
+		Secret:    secret,
+		Status:    EndpointStatusEnabled,
+		CreatedAt: now,
+	}
+
+	if err := e.set(rawURL, description, eventTypes, known); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// Update replaces the subscription under the same rules as NewEndpoint.
+func (e *Endpoint) Update(rawURL, description string, eventTypes, known []string, now time.Time) error {
+	if err := e.set(rawURL, description, eventTypes, known); err != nil {
+		return err
+	}
+
+	e.UpdatedAt = new(now)
+
+	return nil
+}
+
+func (e *Endpoint) set(rawURL, description string, eventTypes, known []string) error {
+	endpointURL, err := ParseEndpointURL(rawURL)
+	if err != nil {
+		return err
+	}
+
+	description = strings.TrimSpace(description)
+	if utf8.RuneCountInString(description) > MaxDescriptionLength {
+		return ErrWebhookEndpointDescriptionTooLong.New().WithDetails(map[string]int{"max": MaxDescriptionLength})
+	}
+
+	types, err := ParseEventTypes(eventTypes, known)
+	if err != nil {
+		return err
+	}
+
+	e.URL = endpointURL
+	e.Description = description
+	e.EventTypes = types
+
+	return nil
+}
+
+// Enable clears the failures that disabled the endpoint.
+func (e *Endpoint) Enable(now time.Time) bool {
+	if e.Status == EndpointStatusEnabled {
+		return false
+	}
+
+	e.Status = EndpointStatusEnabled
+	e.FailureCount = 0
+	e.DisabledAt = nil
+	e.UpdatedAt = new(now)
+
+	return true
+}
+
+func (e *Endpoint) Disable(now time.Time) bool {
+	if e.Status == EndpointStatusDisabled {
+		return false
+	}
+
+	e.Status = EndpointStatusDisabled
+	e.DisabledAt = new(now)
+	e.UpdatedAt = new(now)
+
+	return true
+}
+
+func (e *Endpoint) IsEnabled() bool {
+	return e.Status == EndpointStatusEnabled
+}
+
+func (e *Endpoint) Subscribes(eventType string) bool {
+	return slices.Contains(e.EventTypes, eventType)
+}
+
+// ParseEndpointURL refuses hosts that are internal addresses. Names are only
+// resolved when delivering, where the dialer checks the addresses again.
+func ParseEndpointURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(raw) > MaxURLLength {
+		return "", ErrWebhookEndpointURLInvalid.New().WithDetails(map[string]int{"max": MaxURLLength})
+	}
+
+	if !isPublicHost(u.Hostname()) {
+		return "", ErrWebhookEndpointURLForbidden.New().WithDetails(map[string]string{"host": u.Hostname()})
+	}
+
+	return u.String(), nil
+}
+
+// internalPrefixes are the ranges IsGlobalUnicast and IsPrivate let through
+// that still reach internal networks: shared and benchmarking ranges, cloud
+// metadata services, and the IPv6 forms of IPv4 addresses.
+var internalPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("::/96"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("2002::/16"),
+}
+
+var (
+	nat64Prefix = netip.MustParsePrefix("64:ff9b::/96")
+	sixToFour   = netip.MustParsePrefix("2002::/16")
+)
+
+// IsPublicAddr reports whether deliveries may reach addr, loopback, private,
+// link-local, multicast, unspecified and internalPrefixes addresses are
+// internal, and so are NAT64 and 6to4 addresses embedding one.
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+
+	if embedded, ok := embeddedIPv4(addr); ok && !IsPublicAddr(embedded) {
+		return false
+	}
+
+	for _, prefix := range internalPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// embeddedIPv4 returns the IPv4 address a NAT64 address ends with, or a 6to4
+// address holds after its prefix.
+func embeddedIPv4(addr netip.Addr) (netip.Addr, bool) {
+	raw := addr.As16()
+
+	switch {
+	case nat64Prefix.Contains(addr):
+		return netip.AddrFrom4([4]byte(raw[12:16])), true
+	case sixToFour.Contains(addr):
+		return netip.AddrFrom4([4]byte(raw[2:6])), true
+	default:
+		return netip.Addr{}, false
+	}
+}
+
+func isPublicHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return IsPublicAddr(addr)
+	}
+
+	return true
+}
+
+// ParseEventTypes returns the sorted distinct types, all of them part of
+// known.
+func ParseEventTypes(eventTypes, known []string) ([]string, error) {
+	types := slices.Clone(eventTypes)
+	slices.Sort(types)
+	types = slices.Compact(types)
+
+	if len(types) == 0 {
+		return nil, ErrWebhookEventTypeInvalid.New().WithDetails(map[string]any{"allowed": known})
+	}
+
+	for _, t := range types {
+		if !slices.Contains(known, t) {
+			return nil, ErrWebhookEventTypeInvalid.New().WithDetails(map[string]any{
+				"event_type": t,
+				"allowed":    known,
+			})
+		}
+	}
+
+	return types, nil
+}
+
+// NewSecret issues a random signing secret, shown once to the subscriber.
+func NewSecret() string {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+
+	return secretPrefix + base64.StdEncoding.EncodeToString(key)
+}
@@ -0,0 +1,114 @@
+package domain_test
+
+import (
+	"HATCH_APP/internal/webhook/domain"
+	"HATCH_APP/pkg/core"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var known = []string{"note.archived", "note.created"}
+
+func TestNewEndpoint(t *testing.T) {
+	id := core.MustParseID("01JWN3V0G0000000000000000A")
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should subscribe to distinct sorted event types", func(t *testing.T) {
+		e, err := domain.NewEndpoint(id, now, " https://example.com/hooks ", " notes ", "whsec_a",
+			[]string{"note.created", "note.archived", "note.created"}, known)
+
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/hooks", e.URL)
+		assert.Equal(t, "notes", e.Description)
+		assert.Equal(t, []string{"note.archived", "note.created"}, e.EventTypes)
+		assert.True(t, e.IsEnabled())
+		assert.True(t, e.Subscribes("note.created"))
+	})
+
+	t.Run("should reject invalid urls", func(t *testing.T) {
+		for _, raw := range []string{"", "example.com", "ftp://example.com", "https://", "https://example.com/" + strings.Repeat("a", 2048)} {
+			_, err := domain.NewEndpoint(id, now, raw, "", "whsec_a", known, known)
+
+			require.Error(t, err, raw)
+			assert.True(t, domain.ErrWebhookEndpointURLInvalid.Is(err), raw)
+		}
+	})
+
+	t.Run("should reject internal hosts", func(t *testing.T) {
+		for _, raw := range []string{
+			"http://localhost:8080/hooks",
+			"http://api.localhost",
+			"http://127.0.0.1",
+			"http://10.0.0.1",
+			"http://172.16.0.1",
+			"http://192.168.1.1",
+			"http://169.254.169.254/latest/meta-data",
+			"http://0.0.0.0",
+			"http://[::1]:8080",
+			"http://[fd00::1]",
+			"http://[fe80::1]",
+			"http://[::ffff:127.0.0.1]",
+			"http://0.1.2.3",
+			"http://100.100.100.200/latest/meta-data",
+			"http://192.0.0.170",
+			"http://198.18.0.1",
+			"http://240.0.0.1",
+			"http://[::127.0.0.1]",
+			"http://[64:ff9b::7f00:1]",
+			"http://[64:ff9b::808:808]",
+			"http://[64:ff9b:1::a00:1]",
+			"http://[2002:7f00:1::1]",
+			"http://[2002:808:808::1]",
+		} {
+			_, err := domain.NewEndpoint(id, now, raw, "", "whsec_a", known, known)
+
+			require.Error(t, err, raw)
+			assert.True(t, domain.ErrWebhookEndpointURLForbidden.Is(err), raw)
+		}
+	})
+
+	t.Run("should accept public addresses", func(t *testing.T) {
+		for _, raw := range []string{"http://93.184.215.14/hooks", "https://[2606:4700::1111]"} {
+			_, err := domain.NewEndpoint(id, now, raw, "", "whsec_a", known, known)
+
+			require.NoError(t, err, raw)
+		}
+	})
+
+	t.Run("should reject unknown or missing event types", func(t *testing.T) {
+		for _, types := range [][]string{nil, {"note.deleted"}} {
+			_, err := domain.NewEndpoint(id, now, "https://example.com", "", "whsec_a", types, known)
+
+			require.Error(t, err)
+			assert.True(t, domain.ErrWebhookEventTypeInvalid.Is(err))
+		}
+	})
+
+	t.Run("should reject long descriptions", func(t *testing.T) {
+		_, err := domain.NewEndpoint(id, now, "https://example.com", strings.Repeat("é", 201), "whsec_a", known, known)
+
+		assert.True(t, domain.ErrWebhookEndpointDescriptionTooLong.Is(err))
+	})
+}
+
+func TestEndpointEnable(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	e, err := domain.NewEndpoint(core.NewID(), now, "https://example.com", "", "whsec_a", known, known)
+	require.NoError(t, err)
+
+	assert.False(t, e.Enable(now))
+	assert.True(t, e.Disable(now))
+	assert.False(t, e.Disable(now))
+	assert.Equal(t, now, *e.DisabledAt)
+
+	e.FailureCount = 25
+
+	assert.True(t, e.Enable(now))
+	assert.Zero(t, e.FailureCount)
+	assert.Nil(t, e.DisabledAt)
+}
@@ -0,0 +1,59 @@
+package domain
+
+import "HATCH_APP/pkg/core/apperr"
+
+var Codes = apperr.NewRegistry("WEBHOOK")
+
+var (
+	ErrWebhookEndpointNotFound = Codes.Register("WEBHOOK_ENDPOINT_NOT_FOUND", apperr.TypeNotFound,
+		"webhook endpoint not found",
+		"The requested webhook endpoint does not exist.")
+	ErrWebhookEndpointURLInvalid = Codes.Register("WEBHOOK_ENDPOINT_URL_INVALID", apperr.TypeValidation,
+		"invalid webhook endpoint url",
+		"Endpoint URLs are absolute http or https URLs of at most details.max characters.")
+	ErrWebhookEndpointURLForbidden = Codes.Register("WEBHOOK_ENDPOINT_URL_FORBIDDEN", apperr.TypeValidation,
+		"webhook endpoint url is not public",
+		"Deliveries only go to public addresses, loopback, private, link-local and unspecified ones are refused.")
+	ErrWebhookEndpointDescriptionTooLong = Codes.Register("WEBHOOK_ENDPOINT_DESCRIPTION_TOO_LONG", apperr.TypeValidation,
+		"webhook endpoint description is too long",
+		"The description exceeds the maximum number of characters, given in details.max.")
+	ErrWebhookEventTypeInvalid = Codes.Register("WEBHOOK_EVENT_TYPE_INVALID", apperr.TypeValidation,
+		"invalid webhook event type",
+		"Endpoints subscribe to at least one of the event types given in details.allowed.")
+	ErrWebhookEndpointDisabled = Codes.Register("WEBHOOK_ENDPOINT_DISABLED", apperr.TypeInvalidOperation,
+		"webhook endpoint is disabled",
+		"Nothing is delivered to a disabled endpoint, enable it again first.")
+	ErrWebhookEndpointSaveFailed = Codes.Register("WEBHOOK_ENDPOINT_SAVE_FAILED", apperr.TypeInternal,
+		"failed to save webhook endpoint",
+		"The webhook endpoint could not be persisted.")
+	ErrWebhookEndpointFindFailed = Codes.Register("WEBHOOK_ENDPOINT_FIND_FAILED", apperr.TypeInternal,
+		"failed to find webhook endpoints",
+		"Webhook endpoints could not be loaded from the datasource.")
+	ErrWebhookEndpointDeleteFailed = Codes.Register("WEBHOOK_ENDPOINT_DELETE_FAILED", apperr.TypeInternal,
+		"failed to delete webhook endpoint",
+		"The webhook endpoint could not be removed from the datasource.")
+	ErrWebhookDeliveryNotFound = Codes.Register("WEBHOOK_DELIVERY_NOT_FOUND", apperr.TypeNotFound,
+		"webhook delivery not found",
+		"The endpoint has no delivery with the requested id, it may have been pruned by retention.")
+	ErrWebhookDeliveryLimitInvalid = Codes.Register("WEBHOOK_DELIVERY_LIMIT_INVALID", apperr.TypeValidation,
+		"invalid webhook delivery limit",
+		"The limit is a number of deliveries between 1 and details.max.")
+	ErrWebhookDeliveryFindFailed = Codes.Register("WEBHOOK_DELIVERY_FIND_FAILED", apperr.TypeInternal,
+		"failed to find webhook deliveries",
+		"Deliveries of the endpoint could not be loaded from the datasource.")
+	ErrWebhookDeliveryPending = Codes.Register("WEBHOOK_DELIVERY_PENDING", apperr.TypeConflict,
+		"webhook delivery already pending",
+		"The event is still being delivered to the endpoint, possibly waiting for a retry.")
+	ErrWebhookDeliveryEnqueueFailed = Codes.Register("WEBHOOK_DELIVERY_ENQUEUE_FAILED", apperr.TypeInternal,
+		"failed to schedule webhook delivery",
+		"The delivery could not be queued.")
+	ErrWebhookDeliveryFailed = Codes.Register("WEBHOOK_DELIVERY_FAILED", apperr.TypeInternal,
+		"webhook delivery failed",
+		"The endpoint did not answer with a 2xx status, the delivery is retried with backoff.")
+	ErrWebhookSignatureInvalid = Codes.Register("WEBHOOK_SIGNATURE_INVALID", apperr.TypeUnauthorized,
+		"invalid webhook signature",
+		"None of the signatures in Webhook-Signature matches the payload signed with the endpoint secret.")
+	ErrWebhookSignatureExpired = Codes.Register("WEBHOOK_SIGNATURE_EXPIRED", apperr.TypeUnauthorized,
+		"webhook timestamp out of tolerance",
+		"Webhook-Timestamp is too far from the current time, the payload may be replayed.")
+)
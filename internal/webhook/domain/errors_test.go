@@ -0,0 +1,11 @@
+package domain_test
+
+import (
+	"HATCH_APP/internal/webhook/domain"
+	"HATCH_APP/test/apperrtest"
+	"testing"
+)
+
+func TestCodes(t *testing.T) {
+	apperrtest.AssertRegistries(t, domain.Codes)
+}
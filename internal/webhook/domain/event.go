@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"HATCH_APP/pkg/core"
+)
+
+// Event is published by other modules and delivered as is, as the JSON body,
+// to the endpoints of its owner subscribed to its type.
+type Event struct {
+	CreatedAt time.Time       `json:"created_at"`
+	ID        core.ID         `json:"id"`
+	Type      string          `json:"type"`
+	OwnerID   string          `json:"owner_id"`
+	Data      json.RawMessage `json:"data"`
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Signature headers follow the Standard Webhooks specification, so receivers
+// can rely on existing libraries to verify them.
+const (
+	HeaderID        = "Webhook-Id"
+	HeaderTimestamp = "Webhook-Timestamp"
+	HeaderSignature = "Webhook-Signature"
+	signatureScheme = "v1,"
+)
+
+// Sign computes the HMAC-SHA256 of "id.timestamp.body" keyed with the
+// decoded secret. The timestamp is signed along with the body so a captured
+// payload can't be replayed once it falls out of the receiver tolerance.
+func Sign(secret, id string, timestamp time.Time, body []byte) (string, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(secret, secretPrefix))
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id + "." + strconv.FormatInt(timestamp.Unix(), 10) + "."))
+	mac.Write(body)
+
+	return signatureScheme + base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// SignatureHeaders returns the headers of a delivery of body, id must stay
+// the same across retries so receivers can deduplicate.
+func SignatureHeaders(secret, id string, timestamp time.Time, body []byte) (http.Header, error) {
+	signature, err := Sign(secret, id, timestamp, body)
+	if err != nil {
+		return nil, err
+	}
+
+	header := http.Header{}
+	header.Set(HeaderID, id)
+	header.Set(HeaderTimestamp, strconv.FormatInt(timestamp.Unix(), 10))
+	header.Set(HeaderSignature, signature)
+
+	return header, nil
+}
+
+// Verify is what receivers run on a delivery: the timestamp must be within
+// tolerance of now and one of the space separated signatures must match.
+func Verify(secret string, header http.Header, body []byte, tolerance time.Duration, now time.Time) error {
+	unix, err := strconv.ParseInt(header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return ErrWebhookSignatureInvalid.New()
+	}
+
+	timestamp := time.Unix(unix, 0)
+	if timestamp.Before(now.Add(-tolerance)) || timestamp.After(now.Add(tolerance)) {
+		return ErrWebhookSignatureExpired.New()
+	}
+
+	expected, err := Sign(secret, header.Get(HeaderID), timestamp, body)
+	if err != nil {
+		return ErrWebhookSignatureInvalid.Wrap(err)
+	}
+
+	for signature := range strings.FieldsSeq(header.Get(HeaderSignature)) {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return nil
+		}
+	}
+
+	return ErrWebhookSignatureInvalid.New()
+}
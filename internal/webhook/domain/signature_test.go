@@ -0,0 +1,73 @@
+package domain_test
+
+import (
+	"HATCH_APP/internal/webhook/domain"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign(t *testing.T) {
+	// Test vector of the Standard Webhooks specification.
+	secret := "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+	timestamp := time.Unix(1614265330, 0)
+	body := []byte(`{"test": 2432232314}`)
+
+	signature, err := domain.Sign(secret, "msg_p5jXN8AQM9LWM0D4loKWxJek", timestamp, body)
+
+	require.NoError(t, err)
+	assert.Equal(t, "v1,g0hM9SsE+OTPJTGt/tmIKtSyZlE3uFJELVlNIOLJ1OE=", signature)
+}
+
+func TestVerify(t *testing.T) {
+	secret := domain.NewSecret()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"id":"evt"}`)
+
+	signed := func(t *testing.T, at time.Time) http.Header {
+		header, err := domain.SignatureHeaders(secret, "evt", at, body)
+		require.NoError(t, err)
+
+		return header
+	}
+
+	t.Run("should accept a fresh signed payload", func(t *testing.T) {
+		header := signed(t, now.Add(-time.Minute))
+
+		require.NoError(t, domain.Verify(secret, header, body, 5*time.Minute, now))
+	})
+
+	t.Run("should accept any matching signature of a rotated secret", func(t *testing.T) {
+		header := signed(t, now)
+		header.Set(domain.HeaderSignature, "v1,b2xk "+header.Get(domain.HeaderSignature))
+
+		require.NoError(t, domain.Verify(secret, header, body, 5*time.Minute, now))
+	})
+
+	t.Run("should reject replayed payloads", func(t *testing.T) {
+		header := signed(t, now.Add(-10*time.Minute))
+
+		err := domain.Verify(secret, header, body, 5*time.Minute, now)
+
+		assert.True(t, domain.ErrWebhookSignatureExpired.Is(err))
+	})
+
+	t.Run("should reject tampered payloads", func(t *testing.T) {
+		header := signed(t, now)
+
+		err := domain.Verify(secret, header, []byte(`{"id":"other"}`), 5*time.Minute, now)
+		assert.True(t, domain.ErrWebhookSignatureInvalid.Is(err))
+
+		err = domain.Verify(domain.NewSecret(), header, body, 5*time.Minute, now)
+		assert.True(t, domain.ErrWebhookSignatureInvalid.Is(err))
+	})
+
+	t.Run("should prefix generated secrets", func(t *testing.T) {
+		assert.True(t, strings.HasPrefix(secret, "whsec_"))
+		assert.NotEqual(t, secret, domain.NewSecret())
+	})
+}
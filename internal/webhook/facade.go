@@ -0,0 +1,29 @@
+package webhook
+
+import (
+	"HATCH_APP/internal/shared/events"
+	"HATCH_APP/internal/webhook/domain"
+	"HATCH_APP/internal/webhook/infra/queue"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Publisher schedules the delivery of the events of other modules to the
+// subscribed endpoints. Their types must be one of Config.EventTypes for
+// endpoints to subscribe to them.
+type Publisher struct{}
+
+var _ events.Publisher = Publisher{}
+
+// Publish only delivers event once db, the transaction of the change it
+// describes, commits.
+func (Publisher) Publish(ctx context.Context, db sqlx.ExtContext, event events.Event) error {
+	return queue.Dispatch(ctx, db, domain.Event{
+		ID:        event.ID,
+		Type:      event.Type,
+		OwnerID:   event.OwnerID,
+		CreatedAt: event.CreatedAt,
+		Data:      event.Data,
+	})
+}
@@ -0,0 +1,41 @@
+package deliveries
+
+import (
+	"HATCH_APP/internal/webhook/domain"
+	"HATCH_APP/pkg/transport/httpx"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// NewClient returns the client deliveries are sent with. It checks every
+// address it connects to, whatever the endpoint host resolved to, and does
+// not follow redirects, so endpoints cannot reach the internal network.
+func NewClient(timeout time.Duration) *httpx.Client {
+	dialer := &net.Dialer{
+		Timeout: 30 * time.Second,
+		Control: dialControl,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// A proxy would be the address checked instead of the endpoint.
+	transport.Proxy = nil
+
+	return httpx.NewClient(timeout, httpx.WithTransport(transport), httpx.WithoutRedirects())
+}
+
+func dialControl(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+
+	if !domain.IsPublicAddr(addrPort.Addr()) {
+		return domain.ErrWebhookEndpointURLForbidden.New().WithDetails(map[string]string{"address": address})
+	}
+
+	return nil
+}
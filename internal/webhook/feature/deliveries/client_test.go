@@ -0,0 +1,55 @@
+package deliveries_test
+
+import (
+	"HATCH_APP/internal/webhook/domain"
+	"HATCH_APP/internal/webhook/feature/deliveries"
+	"HATCH_APP/pkg/transport/httpx"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClient(t *testing.T) {
+	t.Run("should refuse to connect to internal addresses", func(t *testing.T) {
+		called := false
+
+		srv := stdhttptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			called = true
+		}))
+		t.Cleanup(srv.Close)
+
+		_, err := deliveries.NewClient(time.Second).Do(t.Context(), httpx.Request{
+			Method: httpx.HTTPMethodPost,
+			URL:    srv.URL,
+		})
+
+		require.Error(t, err)
+		assert.True(t, domain.ErrWebhookEndpointURLForbidden.Is(err))
+		assert.False(t, called)
+	})
+	t.Run("should refuse to connect to internal ranges", func(t *testing.T) {
+		for _, host := range []string{
+			"0.1.2.3",
+			"100.100.100.200",
+			"192.0.0.170",
+			"198.18.0.1",
+			"240.0.0.1",
+			"[::127.0.0.1]",
+			"[64:ff9b::7f00:1]",
+			"[64:ff9b:1::a00:1]",
+			"[2002:7f00:1::1]",
+		} {
+			_, err := deliveries.NewClient(time.Second).Do(t.Context(), httpx.Request{
+				Method: httpx.HTTPMethodPost,
+				URL:    "http://" + host + "/hooks",
+			})
+
+			require.Error(t, err, host)
+			assert.True(t, domain.ErrWebhookEndpointURLForbidden.Is(err), host)
+		}
+	})
+}
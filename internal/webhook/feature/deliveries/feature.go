@@ -0,0 +1,25 @@
+package deliveries
+
+import (
+	"HATCH_APP/internal/webhook/domain"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/transport/httpx"
+)
+
+type Feature struct {
+	service *Service
+}
+
+func New(
+	endpointRepo domain.EndpointRepository,
+	deliveryRepo domain.DeliveryRepository,
+	queue domain.DeliveryQueue,
+	client *httpx.Client,
+	ids core.IDGenerator,
+	clock core.Clock,
+	policy Policy,
+) *Feature {
+	return &Feature{
+		service: NewService(endpointRepo, deliveryRepo, queue, client, ids, clock, policy),
+	}
+}
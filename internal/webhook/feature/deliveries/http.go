@@ -0,0 +1,84 @@
+package deliveries
+
+import (
+	"HATCH_APP/internal/webhook/domain"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/transport/httpx"
+	"fmt"
+	"net/http"
+)
+
+type Params struct {
+	ID    core.ID `path:"id"`
+	Limit int     `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type ListResponse struct {
+	Message string             `json:"message"`
+	Data    []*domain.Delivery `json:"data"`
+}
+
+type RedeliverResponse struct {
+	Message string `json:"message"`
+}
+
+func (f *Feature) ListDeliveriesEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "ListWebhookDeliveries")
+
+	id, err := httpx.PathID(w, r, "id")
+	if err != nil {
+		log.WarnContext(ctx, "invalid id", "error", err)
+		return
+	}
+
+	log = log.With("webhook_endpoint_id", id)
+
+	limit, err := domain.ParseDeliveryLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	deliveries, err := f.service.ListDeliveries(ctx, id, limit)
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	httpx.WriteOKResponse(w, ListResponse{
+		Message: fmt.Sprintf("%d webhook deliveries listed", len(deliveries)),
+		Data:    deliveries,
+	})
+}
+
+func (f *Feature) RedeliverEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "RedeliverWebhook")
+
+	id, err := httpx.PathID(w, r, "id")
+	if err != nil {
+		log.WarnContext(ctx, "invalid id", "error", err)
+		return
+	}
+
+	deliveryID, err := httpx.PathID(w, r, "deliveryID")
+	if err != nil {
+		log.WarnContext(ctx, "invalid delivery id", "error", err)
+		return
+	}
+
+	log = log.With("webhook_endpoint_id", id, "webhook_delivery_id", deliveryID)
+
+	if err := f.service.Redeliver(ctx, id, deliveryID); err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	httpx.WriteResponse(w, http.StatusAccepted, RedeliverResponse{
+		Message: "webhook redelivery scheduled",
+	})
+}
@@ -0,0 +1,201 @@
+package deliveries_test
+
+import (
+	"HATCH_APP/internal/webhook/domain"
+	"HATCH_APP/internal/webhook/feature/deliveries"
+	"HATCH_APP/internal/webhook/infra/queue"
+	"HATCH_APP/internal/webhook/infra/store/postgres"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/transport/httpx"
+	"HATCH_APP/test/container"
+	"HATCH_APP/test/httptest"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type httpSuite struct {
+	endpointRepo *postgres.EndpointRepository
+	deliveryRepo *postgres.DeliveryRepository
+	feat         *deliveries.Feature
+}
+
+func setupHTTPSuite(t *testing.T) *httpSuite {
+	db, dbTeardown := container.SetupPostgres(t)
+
+	t.Cleanup(func() {
+		dbTeardown()
+	})
+
+	endpointRepo, err := postgres.NewEndpointRepository(db)
+	require.NoError(t, err)
+
+	deliveryRepo, err := postgres.NewDeliveryRepository(db)
+	require.NoError(t, err)
+
+	clock := core.SystemClock()
+
+	return &httpSuite{
+		endpointRepo: endpointRepo,
+		deliveryRepo: deliveryRepo,
+		feat: deliveries.New(endpointRepo, deliveryRepo, queue.NewDeliveryQueue(db, 0), httpx.NewClient(time.Second),
+			core.NewULIDGenerator(clock), clock, policy),
+	}
+}
+
+// logDeliveries stores an endpoint with n failed deliveries of the event.
+func (s *httpSuite) logDeliveries(t *testing.T, n int) (*domain.Endpoint, *domain.Delivery) {
+	e := newEndpoint(t, "https://example.com")
+	e.ID = core.NewID()
+	require.NoError(t, s.endpointRepo.CreateEndpoint(t.Context(), e))
+
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	var d *domain.Delivery
+
+	for attempt := range n {
+		d = domain.NewDelivery(core.NewID(), e.ID, event, payload, attempt+1, http.StatusBadGateway, nil, nil,
+			time.Millisecond, time.Now().Add(time.Duration(attempt)*time.Second))
+		require.NoError(t, s.deliveryRepo.CreateDelivery(t.Context(), d))
+	}
+
+	return e, d
+}
+
+func TestListDeliveriesEndpoint(t *testing.T) {
+	s := setupHTTPSuite(t)
+	httptest.Init()
+
+	tests := []struct {
+		tc   httptest.Case
+		name string
+	}{
+		{
+			name: "should list the latest deliveries first",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					e, _ := s.logDeliveries(t, 3)
+					req := httptest.NewRequest(http.MethodGet, "/api/v1/webhooks/"+e.ID.String()+"/deliveries?limit=2")
+
+					return httptest.WithParam(req, "id", e.ID.String())
+				},
+				ExpectStatus: http.StatusOK,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[deliveries.ListResponse](body)
+
+					require.NoError(t, err)
+					require.Len(t, resp.Data, 2)
+					assert.Equal(t, 3, resp.Data[0].Attempt)
+					assert.Equal(t, http.StatusBadGateway, *resp.Data[0].StatusCode)
+					assert.JSONEq(t, string(event.Data), string(mustEvent(t, resp.Data[0]).Data))
+				},
+			},
+		},
+		{
+			name: "should return 400 when limit is invalid",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					id := core.NewID().String()
+					req := httptest.NewRequest(http.MethodGet, "/api/v1/webhooks/"+id+"/deliveries?limit="+
+						strconv.Itoa(domain.MaxDeliveryLimit+1))
+
+					return httptest.WithParam(req, "id", id)
+				},
+				ExpectStatus: http.StatusBadRequest,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrWebhookDeliveryLimitInvalid.ID, resp.Code)
+				},
+			},
+		},
+		{
+			name: "should return 404 when endpoint not found",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					id := core.NewID().String()
+					req := httptest.NewRequest(http.MethodGet, "/api/v1/webhooks/"+id+"/deliveries")
+
+					return httptest.WithParam(req, "id", id)
+				},
+				ExpectStatus: http.StatusNotFound,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrWebhookEndpointNotFound.ID, resp.Code)
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			httptest.Run(t, s.feat.ListDeliveriesEndpoint, tc.tc)
+		})
+	}
+}
+
+func TestRedeliverEndpoint(t *testing.T) {
+	s := setupHTTPSuite(t)
+	httptest.Init()
+
+	e, d := s.logDeliveries(t, 1)
+
+	redeliver := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost,
+			"/api/v1/webhooks/"+e.ID.String()+"/deliveries/"+d.ID.String()+"/redeliver")
+		req = httptest.WithParam(req, "id", e.ID.String())
+
+		return httptest.WithParam(req, "deliveryID", d.ID.String())
+	}
+
+	t.Run("should schedule the redelivery", func(t *testing.T) {
+		httptest.Run(t, s.feat.RedeliverEndpoint, httptest.Case{
+			ArrangeRequest: redeliver,
+			ExpectStatus:   http.StatusAccepted,
+		})
+	})
+
+	t.Run("should return 409 while the redelivery is pending", func(t *testing.T) {
+		httptest.Run(t, s.feat.RedeliverEndpoint, httptest.Case{
+			ArrangeRequest: redeliver,
+			ExpectStatus:   http.StatusConflict,
+			CheckResponse: func(t *testing.T, body []byte) {
+				resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+				require.NoError(t, err)
+				assert.Equal(t, domain.ErrWebhookDeliveryPending.ID, resp.Code)
+			},
+		})
+	})
+}
+
+func TestPruneDeliveriesIntegration(t *testing.T) {
+	s := setupHTTPSuite(t)
+
+	e, _ := s.logDeliveries(t, 4)
+
+	require.NoError(t, s.deliveryRepo.PruneDeliveries(t.Context(), e.ID, 2))
+
+	kept, err := s.deliveryRepo.ListDeliveries(t.Context(), e.ID, domain.MaxDeliveryLimit)
+	require.NoError(t, err)
+	require.Len(t, kept, 2)
+	assert.Equal(t, 4, kept[0].Attempt)
+	assert.Equal(t, 3, kept[1].Attempt)
+}
+
+func mustEvent(t *testing.T, d *domain.Delivery) domain.Event {
+	e, err := d.Event()
+	require.NoError(t, err)
+
+	return e
+}
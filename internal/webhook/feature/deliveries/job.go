@@ -0,0 +1,24 @@
+package deliveries
+
+import (
+	"HATCH_APP/internal/webhook/domain"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/o11y"
+	"context"
+)
+
+func (f *Feature) DispatchEventJob(ctx context.Context, event domain.Event) error {
+	dispatched, err := f.service.DispatchEvent(ctx, event)
+
+	o11y.LoggerFromContext(ctx).InfoContext(ctx, "webhook event dispatched",
+		"event_id", event.ID,
+		"event_type", event.Type,
+		"deliveries", dispatched,
+	)
+
+	return err
+}
+
+func (f *Feature) DeliverJob(ctx context.Context, endpointID core.ID, event domain.Event, attempt int) error {
+	return f.service.Deliver(ctx, endpointID, event, attempt)
+}
@@ -0,0 +1,251 @@
+package deliveries
+
+import (
+	"HATCH_APP/internal/shared/auth"
+	"HATCH_APP/internal/webhook/domain"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/transport/httpx"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Policy bounds what is kept and tolerated per endpoint, 0 disables either
+// limit.
+type Policy struct {
+	// DisableAfter is the number of failed attempts in a row after which the
+	// endpoint is disabled and its pending deliveries dropped.
+	DisableAfter int
+	// Retention is the number of delivery logs kept per endpoint.
+	Retention int
+}
+
+type Service struct {
+	endpointRepo domain.EndpointRepository
+	deliveryRepo domain.DeliveryRepository
+	queue        domain.DeliveryQueue
+	client       *httpx.Client
+	ids          core.IDGenerator
+	clock        core.Clock
+	policy       Policy
+}
+
+func NewService(
+	endpointRepo domain.EndpointRepository,
+	deliveryRepo domain.DeliveryRepository,
+	queue domain.DeliveryQueue,
+	client *httpx.Client,
+	ids core.IDGenerator,
+	clock core.Clock,
+	policy Policy,
+) *Service {
+	return &Service{
+		endpointRepo: endpointRepo,
+		deliveryRepo: deliveryRepo,
+		queue:        queue,
+		client:       client,
+		ids:          ids,
+		clock:        clock,
+		policy:       policy,
+	}
+}
+
+// DispatchEvent schedules the delivery of event to every enabled endpoint of
+// its owner subscribed to its type. Dispatching it again only schedules the
+// deliveries that are not pending already.
+func (s *Service) DispatchEvent(ctx context.Context, event domain.Event) (int, error) {
+	endpoints, err := s.endpointRepo.ListSubscribed(ctx, event.Type, event.OwnerID)
+	if err != nil {
+		return 0, domain.ErrWebhookEndpointFindFailed.Propagate(err)
+	}
+
+	dispatched := 0
+
+	for _, endpoint := range endpoints {
+		enqueued, err := s.queue.EnqueueDelivery(ctx, endpoint.ID, event)
+		if err != nil {
+			return dispatched, domain.ErrWebhookDeliveryEnqueueFailed.Wrap(err)
+		}
+
+		if enqueued {
+			dispatched++
+		}
+	}
+
+	return dispatched, nil
+}
+
+// Deliver posts the signed event to the endpoint and logs the attempt. A
+// failed attempt returns ErrWebhookDeliveryFailed to be retried, unless it
+// disabled the endpoint.
+func (s *Service) Deliver(ctx context.Context, endpointID core.ID, event domain.Event, attempt int) error {
+	log := o11y.LoggerFromContext(ctx).With("webhook_endpoint_id", endpointID, "event_id", event.ID)
+
+	endpoint, err := s.endpointRepo.FindEndpoint(ctx, endpointID)
+	if err != nil {
+		return domain.ErrWebhookEndpointFindFailed.Propagate(err)
+	}
+
+	// Deleted or disabled since the event was dispatched.
+	if endpoint == nil || !endpoint.IsEnabled() {
+		log.InfoContext(ctx, "webhook delivery skipped, endpoint gone or disabled")
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return domain.ErrWebhookDeliveryFailed.Wrap(err)
+	}
+
+	now := s.clock.Now()
+
+	header, err := domain.SignatureHeaders(endpoint.Secret, event.ID.String(), now, payload)
+	if err != nil {
+		return domain.ErrWebhookDeliveryFailed.Wrap(err)
+	}
+
+	header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	statusCode, body, sendErr := s.send(ctx, endpoint.URL, header, payload)
+
+	delivery := domain.NewDelivery(
+		s.ids.NewID(),
+		endpoint.ID,
+		event,
+		payload,
+		attempt,
+		statusCode,
+		body,
+		sendErr,
+		time.Since(start),
+		now,
+	)
+
+	// The log is best effort, failing to write it must not deliver the
+	// event twice.
+	if err := s.deliveryRepo.CreateDelivery(ctx, delivery); err != nil {
+		log.WarnContext(ctx, "failed to log webhook delivery", "error", err)
+	} else if s.policy.Retention > 0 {
+		if err := s.deliveryRepo.PruneDeliveries(ctx, endpoint.ID, s.policy.Retention); err != nil {
+			log.WarnContext(ctx, "failed to prune webhook deliveries", "error", err)
+		}
+	}
+
+	if delivery.Succeeded {
+		if err := s.endpointRepo.RecordSuccess(ctx, endpoint.ID); err != nil {
+			log.WarnContext(ctx, "failed to reset webhook endpoint failures", "error", err)
+		}
+
+		return nil
+	}
+
+	disabled, err := s.endpointRepo.RecordFailure(ctx, endpoint.ID, s.policy.DisableAfter, now)
+	if err != nil {
+		return domain.ErrWebhookEndpointSaveFailed.Propagate(err)
+	}
+
+	if disabled {
+		log.WarnContext(ctx, "webhook endpoint disabled after failed deliveries", "failures", s.policy.DisableAfter)
+		return nil
+	}
+
+	details := map[string]any{"attempt": attempt, "status_code": statusCode}
+
+	if sendErr != nil {
+		return domain.ErrWebhookDeliveryFailed.Wrap(sendErr).WithDetails(details)
+	}
+
+	return domain.ErrWebhookDeliveryFailed.New().WithDetails(details)
+}
+
+// send returns the status code and the head of the body of the answer, the
+// status code is 0 when none was received.
+func (s *Service) send(ctx context.Context, url string, header http.Header, payload []byte) (int, []byte, error) {
+	res, err := s.client.Do(ctx, httpx.Request{
+		Method:  httpx.HTTPMethodPost,
+		URL:     url,
+		Headers: header,
+		Body:    payload,
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, domain.MaxResponseBodyLength))
+
+	return res.StatusCode, body, err
+}
+
+func (s *Service) ListDeliveries(ctx context.Context, endpointID core.ID, limit int) ([]*domain.Delivery, error) {
+	if _, err := s.findEndpoint(ctx, endpointID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.deliveryRepo.ListDeliveries(ctx, endpointID, limit)
+	if err != nil {
+		return nil, domain.ErrWebhookDeliveryFindFailed.Propagate(err)
+	}
+
+	return deliveries, nil
+}
+
+// Redeliver schedules the event of a logged delivery again, with the same
+// webhook id so receivers can tell it apart from a new event.
+func (s *Service) Redeliver(ctx context.Context, endpointID, deliveryID core.ID) error {
+	endpoint, err := s.findEndpoint(ctx, endpointID)
+	if err != nil {
+		return err
+	}
+
+	if !endpoint.IsEnabled() {
+		return domain.ErrWebhookEndpointDisabled.New()
+	}
+
+	delivery, err := s.deliveryRepo.FindDelivery(ctx, endpointID, deliveryID)
+	if err != nil {
+		return domain.ErrWebhookDeliveryFindFailed.Propagate(err)
+	}
+
+	if delivery == nil {
+		return domain.ErrWebhookDeliveryNotFound.New()
+	}
+
+	event, err := delivery.Event()
+	if err != nil {
+		return domain.ErrWebhookDeliveryFindFailed.Wrap(err)
+	}
+
+	enqueued, err := s.queue.EnqueueDelivery(ctx, endpointID, event)
+	if err != nil {
+		return domain.ErrWebhookDeliveryEnqueueFailed.Wrap(err)
+	}
+
+	if !enqueued {
+		return domain.ErrWebhookDeliveryPending.New()
+	}
+
+	return nil
+}
+
+// findEndpoint only finds the endpoints of the owner in ctx, those of others
+// are not found rather than forbidden.
+func (s *Service) findEndpoint(ctx context.Context, id core.ID) (*domain.Endpoint, error) {
+	endpoint, err := s.endpointRepo.FindEndpoint(ctx, id)
+	if err != nil {
+		return nil, domain.ErrWebhookEndpointFindFailed.Propagate(err)
+	}
+
+	if endpoint == nil || endpoint.OwnerID != auth.OwnerFromContext(ctx) {
+		return nil, domain.ErrWebhookEndpointNotFound.New()
+	}
+
+	return endpoint, nil
+}
@@ -0,0 +1,333 @@
+package deliveries_test
+
+import (
+	"HATCH_APP/internal/shared/auth"
+	"HATCH_APP/internal/webhook/domain"
+	"HATCH_APP/internal/webhook/feature/deliveries"
+	"HATCH_APP/internal/webhook/mocks"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/transport/httpx"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type serviceSuite struct {
+	endpointRepo *mocks.EndpointRepository
+	deliveryRepo *mocks.DeliveryRepository
+	queue        *mocks.DeliveryQueue
+	service      *deliveries.Service
+}
+
+var (
+	now        = time.Now().UTC().Truncate(time.Second)
+	endpointID = core.MustParseID("01JWN3V0G0000000000000000A")
+	deliveryID = core.MustParseID("01JWN3V0G0000000000000000D")
+	event      = domain.Event{
+		ID:        core.MustParseID("01JWN3V0G0000000000000000E"),
+		Type:      "note.created",
+		OwnerID:   "acme",
+		CreatedAt: now,
+		Data:      json.RawMessage(`{"id":"01JWN3V0G0000000000000000N"}`),
+	}
+	policy = deliveries.Policy{DisableAfter: 3, Retention: 10}
+)
+
+func setupServiceSuite(t *testing.T) *serviceSuite {
+	o11y.InitLogger()
+
+	endpointRepo := mocks.NewEndpointRepository(t)
+	deliveryRepo := mocks.NewDeliveryRepository(t)
+	queue := mocks.NewDeliveryQueue(t)
+
+	return &serviceSuite{
+		endpointRepo: endpointRepo,
+		deliveryRepo: deliveryRepo,
+		queue:        queue,
+		service: deliveries.NewService(endpointRepo, deliveryRepo, queue, httpx.NewClient(time.Second),
+			core.FixedIDs(deliveryID), core.FixedClock(now), policy),
+	}
+}
+
+// newEndpoint points an endpoint at url after registering it, receivers
+// listen on loopback which endpoints may not register.
+func newEndpoint(t *testing.T, url string) *domain.Endpoint {
+	e, err := domain.NewEndpoint(endpointID, now, "https://example.com", "", domain.NewSecret(),
+		[]string{event.Type}, []string{event.Type})
+	require.NoError(t, err)
+
+	e.URL = url
+
+	return e
+}
+
+// receiver answers status and checks the signature of what it receives.
+func receiver(t *testing.T, status int, secret *string) *stdhttptest.Server {
+	srv := stdhttptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, domain.Verify(*secret, r.Header, body, 5*time.Minute, time.Now()))
+		assert.Equal(t, event.ID.String(), r.Header.Get(domain.HeaderID))
+
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(strings.Repeat("x", 2*domain.MaxResponseBodyLength)))
+	}))
+
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestServiceDispatchEvent(t *testing.T) {
+	t.Run("should enqueue one delivery per subscribed endpoint", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		other := newEndpoint(t, "https://example.org")
+		other.ID = core.NewID()
+
+		s.endpointRepo.On("ListSubscribed", t.Context(), event.Type, event.OwnerID).
+			Return([]*domain.Endpoint{newEndpoint(t, "https://example.com"), other}, nil).
+			Once()
+
+		s.queue.On("EnqueueDelivery", t.Context(), endpointID, event).
+			Return(true, nil).
+			Once()
+
+		s.queue.On("EnqueueDelivery", t.Context(), other.ID, event).
+			Return(false, nil).
+			Once()
+
+		dispatched, err := s.service.DispatchEvent(t.Context(), event)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, dispatched)
+	})
+
+	t.Run("should fail when the delivery cannot be enqueued", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		s.endpointRepo.On("ListSubscribed", t.Context(), event.Type, event.OwnerID).
+			Return([]*domain.Endpoint{newEndpoint(t, "https://example.com")}, nil).
+			Once()
+
+		s.queue.On("EnqueueDelivery", t.Context(), endpointID, event).
+			Return(false, errors.New("db down")).
+			Once()
+
+		_, err := s.service.DispatchEvent(t.Context(), event)
+
+		assert.True(t, domain.ErrWebhookDeliveryEnqueueFailed.Is(err))
+	})
+}
+
+func TestServiceDeliver(t *testing.T) {
+	t.Run("should post the signed event and reset failures", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		var secret string
+		srv := receiver(t, http.StatusOK, &secret)
+		endpoint := newEndpoint(t, srv.URL)
+		secret = endpoint.Secret
+
+		s.endpointRepo.On("FindEndpoint", t.Context(), endpointID).
+			Return(endpoint, nil).
+			Once()
+
+		s.deliveryRepo.On("CreateDelivery", t.Context(), mock.MatchedBy(func(d *domain.Delivery) bool {
+			return d.ID == deliveryID &&
+				d.Succeeded &&
+				*d.StatusCode == http.StatusOK &&
+				d.Attempt == 2 &&
+				len(d.ResponseBody) == domain.MaxResponseBodyLength
+		})).
+			Return(nil).
+			Once()
+
+		s.deliveryRepo.On("PruneDeliveries", t.Context(), endpointID, policy.Retention).
+			Return(nil).
+			Once()
+
+		s.endpointRepo.On("RecordSuccess", t.Context(), endpointID).
+			Return(nil).
+			Once()
+
+		require.NoError(t, s.service.Deliver(t.Context(), endpointID, event, 2))
+	})
+
+	t.Run("should fail to be retried when the endpoint answers an error", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		var secret string
+		srv := receiver(t, http.StatusInternalServerError, &secret)
+		endpoint := newEndpoint(t, srv.URL)
+		secret = endpoint.Secret
+
+		s.endpointRepo.On("FindEndpoint", t.Context(), endpointID).
+			Return(endpoint, nil).
+			Once()
+
+		s.deliveryRepo.On("CreateDelivery", t.Context(), mock.MatchedBy(func(d *domain.Delivery) bool {
+			return !d.Succeeded && *d.StatusCode == http.StatusInternalServerError
+		})).
+			Return(nil).
+			Once()
+
+		s.deliveryRepo.On("PruneDeliveries", t.Context(), endpointID, policy.Retention).
+			Return(nil).
+			Once()
+
+		s.endpointRepo.On("RecordFailure", t.Context(), endpointID, policy.DisableAfter, now).
+			Return(false, nil).
+			Once()
+
+		err := s.service.Deliver(t.Context(), endpointID, event, 1)
+
+		require.Error(t, err)
+		assert.True(t, domain.ErrWebhookDeliveryFailed.Is(err))
+	})
+
+	t.Run("should stop retrying once the endpoint is disabled", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		srv := stdhttptest.NewServer(http.NotFoundHandler())
+		srv.Close()
+
+		s.endpointRepo.On("FindEndpoint", t.Context(), endpointID).
+			Return(newEndpoint(t, srv.URL), nil).
+			Once()
+
+		s.deliveryRepo.On("CreateDelivery", t.Context(), mock.MatchedBy(func(d *domain.Delivery) bool {
+			return !d.Succeeded && d.StatusCode == nil && d.Error != ""
+		})).
+			Return(errors.New("db down")).
+			Once()
+
+		s.endpointRepo.On("RecordFailure", t.Context(), endpointID, policy.DisableAfter, now).
+			Return(true, nil).
+			Once()
+
+		require.NoError(t, s.service.Deliver(t.Context(), endpointID, event, 3))
+	})
+
+	t.Run("should skip disabled or deleted endpoints", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		disabled := newEndpoint(t, "https://example.com")
+		disabled.Disable(now)
+
+		s.endpointRepo.On("FindEndpoint", t.Context(), endpointID).
+			Return(disabled, nil).
+			Once()
+
+		s.endpointRepo.On("FindEndpoint", t.Context(), endpointID).
+			Return((*domain.Endpoint)(nil), nil).
+			Once()
+
+		require.NoError(t, s.service.Deliver(t.Context(), endpointID, event, 1))
+		require.NoError(t, s.service.Deliver(t.Context(), endpointID, event, 1))
+	})
+}
+
+func TestServiceRedeliver(t *testing.T) {
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	delivery := &domain.Delivery{ID: deliveryID, EndpointID: endpointID, Payload: payload}
+
+	tests := []struct {
+		arrange func(t *testing.T, s *serviceSuite)
+		assert  func(t *testing.T, err error)
+		name    string
+	}{
+		{
+			name: "should enqueue the logged event again",
+			arrange: func(t *testing.T, s *serviceSuite) {
+				s.deliveryRepo.On("FindDelivery", t.Context(), endpointID, deliveryID).
+					Return(delivery, nil).
+					Once()
+
+				s.queue.On("EnqueueDelivery", t.Context(), endpointID, mock.MatchedBy(func(e domain.Event) bool {
+					return e.ID == event.ID && e.Type == event.Type && e.CreatedAt.Equal(event.CreatedAt)
+				})).
+					Return(true, nil).
+					Once()
+			},
+			assert: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "should conflict while the event is pending",
+			arrange: func(t *testing.T, s *serviceSuite) {
+				s.deliveryRepo.On("FindDelivery", t.Context(), endpointID, deliveryID).
+					Return(delivery, nil).
+					Once()
+
+				s.queue.On("EnqueueDelivery", t.Context(), endpointID, mock.Anything).
+					Return(false, nil).
+					Once()
+			},
+			assert: func(t *testing.T, err error) {
+				assert.True(t, domain.ErrWebhookDeliveryPending.Is(err))
+			},
+		},
+		{
+			name: "should return not found for unknown deliveries",
+			arrange: func(t *testing.T, s *serviceSuite) {
+				s.deliveryRepo.On("FindDelivery", t.Context(), endpointID, deliveryID).
+					Return((*domain.Delivery)(nil), nil).
+					Once()
+			},
+			assert: func(t *testing.T, err error) {
+				assert.True(t, domain.ErrWebhookDeliveryNotFound.Is(err))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			s := setupServiceSuite(t)
+
+			s.endpointRepo.On("FindEndpoint", t.Context(), endpointID).
+				Return(newEndpoint(t, "https://example.com"), nil).
+				Once()
+
+			tc.arrange(t, s)
+
+			tc.assert(t, s.service.Redeliver(t.Context(), endpointID, deliveryID))
+		})
+	}
+
+	t.Run("should not find the endpoints of other owners", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		ctx := auth.WithOwner(t.Context(), "globex")
+
+		s.endpointRepo.On("FindEndpoint", ctx, endpointID).
+			Return(newEndpoint(t, "https://example.com"), nil).
+			Once()
+
+		err := s.service.Redeliver(ctx, endpointID, deliveryID)
+
+		assert.True(t, domain.ErrWebhookEndpointNotFound.Is(err))
+	})
+
+	t.Run("should refuse disabled endpoints", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		disabled := newEndpoint(t, "https://example.com")
+		disabled.Disable(now)
+
+		s.endpointRepo.On("FindEndpoint", t.Context(), endpointID).
+			Return(disabled, nil).
+			Once()
+
+		err := s.service.Redeliver(t.Context(), endpointID, deliveryID)
+
+		assert.True(t, domain.ErrWebhookEndpointDisabled.Is(err))
+	})
+}
@@ -0,0 +1,16 @@
+package subscriptions
+
+import (
+	"HATCH_APP/internal/webhook/domain"
+	"HATCH_APP/pkg/core"
+)
+
+type Feature struct {
+	service *Service
+}
+
+func New(endpointRepo domain.EndpointRepository, ids core.IDGenerator, clock core.Clock, eventTypes []string) *Feature {
+	return &Feature{
+		service: NewService(endpointRepo, ids, clock, eventTypes),
+	}
+}
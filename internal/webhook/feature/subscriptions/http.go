@@ -0,0 +1,166 @@
+package subscriptions
+
+import (
+	"HATCH_APP/internal/webhook/domain"
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/transport/httpx"
+	"fmt"
+	"net/http"
+)
+
+// Request mirrors domain.MaxURLLength and domain.MaxDescriptionLength, the
+// domain still has the final say on the URL and the event types.
+type Request struct {
+	URL         string   `json:"url"                   validate:"required,url,max=2048"`
+	Description string   `json:"description,omitempty" validate:"max=200"`
+	EventTypes  []string `json:"event_types"           validate:"required,min=1,dive,required"`
+}
+
+// UpdateRequest replaces the subscription, an empty status keeps the current
+// one.
+type UpdateRequest struct {
+	Request
+	Status domain.EndpointStatus `json:"status,omitempty" validate:"omitempty,oneof=enabled disabled"`
+}
+
+type Response struct {
+	Message string           `json:"message"`
+	Data    *domain.Endpoint `json:"data"`
+}
+
+type CreateResponse struct {
+	Message string      `json:"message"`
+	Data    CreatedData `json:"data"`
+}
+
+// CreatedData is the only place the secret is shown, receivers need it to
+// verify the signature of deliveries.
+type CreatedData struct {
+	*domain.Endpoint
+	Secret string `json:"secret"`
+}
+
+type ListResponse struct {
+	Message string             `json:"message"`
+	Data    []*domain.Endpoint `json:"data"`
+}
+
+func (f *Feature) CreateEndpointEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "CreateWebhookEndpoint")
+
+	req, err := httpx.ParseRequest[Request](w, r)
+	if err != nil {
+		log.WarnContext(ctx, "invalid payload", "error", err)
+		return
+	}
+
+	endpoint, err := f.service.CreateEndpoint(ctx, req.URL, req.Description, req.EventTypes)
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	httpx.WriteCreatedResponse(w, CreateResponse{
+		Message: "webhook endpoint created",
+		Data: CreatedData{
+			Endpoint: endpoint,
+			Secret:   endpoint.Secret,
+		},
+	})
+}
+
+func (f *Feature) ListEndpointsEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "ListWebhookEndpoints")
+
+	endpoints, err := f.service.ListEndpoints(ctx)
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	httpx.WriteOKResponse(w, ListResponse{
+		Message: fmt.Sprintf("%d webhook endpoints listed", len(endpoints)),
+		Data:    endpoints,
+	})
+}
+
+func (f *Feature) GetEndpointEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "GetWebhookEndpoint")
+
+	id, err := httpx.PathID(w, r, "id")
+	if err != nil {
+		log.WarnContext(ctx, "invalid id", "error", err)
+		return
+	}
+
+	log = log.With("webhook_endpoint_id", id)
+
+	endpoint, err := f.service.GetEndpoint(ctx, id)
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	httpx.WriteOKResponse(w, Response{
+		Message: "webhook endpoint found",
+		Data:    endpoint,
+	})
+}
+
+func (f *Feature) UpdateEndpointEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "UpdateWebhookEndpoint")
+
+	id, err := httpx.PathID(w, r, "id")
+	if err != nil {
+		log.WarnContext(ctx, "invalid id", "error", err)
+		return
+	}
+
+	log = log.With("webhook_endpoint_id", id)
+
+	req, err := httpx.ParseRequest[UpdateRequest](w, r)
+	if err != nil {
+		log.WarnContext(ctx, "invalid payload", "error", err)
+		return
+	}
+
+	endpoint, err := f.service.UpdateEndpoint(ctx, id, req.URL, req.Description, req.EventTypes, req.Status)
+	if err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	httpx.WriteOKResponse(w, Response{
+		Message: "webhook endpoint updated",
+		Data:    endpoint,
+	})
+}
+
+func (f *Feature) DeleteEndpointEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	log := o11y.LoggerFromContext(ctx).With("endpoint", "DeleteWebhookEndpoint")
+
+	id, err := httpx.PathID(w, r, "id")
+	if err != nil {
+		log.WarnContext(ctx, "invalid id", "error", err)
+		return
+	}
+
+	log = log.With("webhook_endpoint_id", id)
+
+	if err := f.service.DeleteEndpoint(ctx, id); err != nil {
+		httpx.WriteError(log, w, err)
+		return
+	}
+
+	httpx.WriteEmptyResponse(w)
+}
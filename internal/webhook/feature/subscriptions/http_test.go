@@ -0,0 +1,229 @@
+package subscriptions_test
+
+import (
+	"HATCH_APP/internal/webhook/domain"
+	"HATCH_APP/internal/webhook/feature/subscriptions"
+	"HATCH_APP/internal/webhook/infra/store/postgres"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/transport/httpx"
+	"HATCH_APP/test/container"
+	"HATCH_APP/test/httptest"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type httpSuite struct {
+	repo *postgres.EndpointRepository
+	feat *subscriptions.Feature
+}
+
+func setupHTTPSuite(t *testing.T) *httpSuite {
+	db, dbTeardown := container.SetupPostgres(t)
+
+	t.Cleanup(func() {
+		dbTeardown()
+	})
+
+	repo, err := postgres.NewEndpointRepository(db)
+	require.NoError(t, err)
+
+	return &httpSuite{
+		repo: repo,
+		feat: subscriptions.New(repo, core.NewULIDGenerator(core.SystemClock()), core.SystemClock(), eventTypes),
+	}
+}
+
+func (s *httpSuite) createEndpoint(t *testing.T) *domain.Endpoint {
+	e, err := domain.NewEndpoint(core.NewID(), time.Now(), "https://example.com", "", domain.NewSecret(),
+		eventTypes, eventTypes)
+	require.NoError(t, err)
+	require.NoError(t, s.repo.CreateEndpoint(t.Context(), e))
+
+	return e
+}
+
+func jsonRequest(method, target string, v any) *http.Request {
+	body, _ := json.Marshal(v)
+
+	return stdhttptest.NewRequest(method, target, bytes.NewReader(body))
+}
+
+func TestCreateEndpointEndpoint(t *testing.T) {
+	s := setupHTTPSuite(t)
+	httptest.Init()
+
+	tests := []struct {
+		tc   httptest.Case
+		name string
+	}{
+		{
+			name: "should create the endpoint and return its secret once",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					return jsonRequest(http.MethodPost, "/api/v1/webhooks", subscriptions.Request{
+						URL:        "https://example.com/hooks",
+						EventTypes: []string{"note.created"},
+					})
+				},
+				Headers:      map[string]string{"Content-Type": "application/json"},
+				ExpectStatus: http.StatusCreated,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[subscriptions.CreateResponse](body)
+
+					require.NoError(t, err)
+					assert.NotEmpty(t, resp.Data.Secret)
+					assert.Equal(t, domain.EndpointStatusEnabled, resp.Data.Status)
+
+					stored, err := s.repo.FindEndpoint(t.Context(), resp.Data.ID)
+					require.NoError(t, err)
+					assert.Equal(t, resp.Data.Secret, stored.Secret)
+					assert.Equal(t, []string{"note.created"}, stored.EventTypes)
+				},
+			},
+		},
+		{
+			name: "should return 400 when the event type is unknown",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					return jsonRequest(http.MethodPost, "/api/v1/webhooks", subscriptions.Request{
+						URL:        "https://example.com/hooks",
+						EventTypes: []string{"note.deleted"},
+					})
+				},
+				Headers:      map[string]string{"Content-Type": "application/json"},
+				ExpectStatus: http.StatusBadRequest,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrWebhookEventTypeInvalid.ID, resp.Code)
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			httptest.Run(t, s.feat.CreateEndpointEndpoint, tc.tc)
+		})
+	}
+}
+
+func TestGetEndpointEndpoint(t *testing.T) {
+	s := setupHTTPSuite(t)
+	httptest.Init()
+
+	tests := []struct {
+		tc   httptest.Case
+		name string
+	}{
+		{
+			name: "should get the endpoint without its secret",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					e := s.createEndpoint(t)
+					req := httptest.NewRequest(http.MethodGet, "/api/v1/webhooks/"+e.ID.String())
+
+					return httptest.WithParam(req, "id", e.ID.String())
+				},
+				ExpectStatus: http.StatusOK,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[subscriptions.Response](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, eventTypes, resp.Data.EventTypes)
+					assert.NotContains(t, string(body), "whsec_")
+				},
+			},
+		},
+		{
+			name: "should return 404 when endpoint not found",
+			tc: httptest.Case{
+				ArrangeRequest: func() *http.Request {
+					id := core.NewID().String()
+					req := httptest.NewRequest(http.MethodGet, "/api/v1/webhooks/"+id)
+
+					return httptest.WithParam(req, "id", id)
+				},
+				ExpectStatus: http.StatusNotFound,
+				CheckResponse: func(t *testing.T, body []byte) {
+					resp, err := httptest.ParseResponse[httpx.ErrorResponse](body)
+
+					require.NoError(t, err)
+					assert.Equal(t, domain.ErrWebhookEndpointNotFound.ID, resp.Code)
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			httptest.Run(t, s.feat.GetEndpointEndpoint, tc.tc)
+		})
+	}
+}
+
+func TestUpdateEndpointEndpoint(t *testing.T) {
+	s := setupHTTPSuite(t)
+	httptest.Init()
+
+	t.Run("should enable a disabled endpoint again", func(t *testing.T) {
+		e := s.createEndpoint(t)
+
+		disabled, err := s.repo.RecordFailure(t.Context(), e.ID, 1, time.Now())
+		require.NoError(t, err)
+		require.True(t, disabled)
+
+		httptest.Run(t, s.feat.UpdateEndpointEndpoint, httptest.Case{
+			ArrangeRequest: func() *http.Request {
+				req := jsonRequest(http.MethodPut, "/api/v1/webhooks/"+e.ID.String(), subscriptions.UpdateRequest{
+					Request: subscriptions.Request{URL: "https://example.org", EventTypes: eventTypes},
+					Status:  domain.EndpointStatusEnabled,
+				})
+
+				return httptest.WithParam(req, "id", e.ID.String())
+			},
+			Headers:      map[string]string{"Content-Type": "application/json"},
+			ExpectStatus: http.StatusOK,
+			CheckResponse: func(t *testing.T, body []byte) {
+				resp, err := httptest.ParseResponse[subscriptions.Response](body)
+
+				require.NoError(t, err)
+				assert.Equal(t, domain.EndpointStatusEnabled, resp.Data.Status)
+				assert.Zero(t, resp.Data.FailureCount)
+				assert.Equal(t, "https://example.org", resp.Data.URL)
+			},
+		})
+	})
+}
+
+func TestDeleteEndpointEndpoint(t *testing.T) {
+	s := setupHTTPSuite(t)
+	httptest.Init()
+
+	t.Run("should delete the endpoint", func(t *testing.T) {
+		e := s.createEndpoint(t)
+
+		httptest.Run(t, s.feat.DeleteEndpointEndpoint, httptest.Case{
+			ArrangeRequest: func() *http.Request {
+				req := httptest.NewRequest(http.MethodDelete, "/api/v1/webhooks/"+e.ID.String())
+
+				return httptest.WithParam(req, "id", e.ID.String())
+			},
+			ExpectStatus: http.StatusNoContent,
+		})
+
+		found, err := s.repo.FindEndpoint(t.Context(), e.ID)
+		require.NoError(t, err)
+		assert.Nil(t, found)
+	})
+}
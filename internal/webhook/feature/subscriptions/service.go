@@ -0,0 +1,132 @@
+package subscriptions
+
+import (
+	"HATCH_APP/internal/shared/auth"
+	"HATCH_APP/internal/webhook/domain"
+	"HATCH_APP/pkg/core"
+	"context"
+)
+
+type Service struct {
+	endpointRepo domain.EndpointRepository
+	ids          core.IDGenerator
+	clock        core.Clock
+	eventTypes   []string
+}
+
+// NewService only lets endpoints subscribe to eventTypes, the events
+// published by the other modules.
+func NewService(
+	endpointRepo domain.EndpointRepository,
+	ids core.IDGenerator,
+	clock core.Clock,
+	eventTypes []string,
+) *Service {
+	return &Service{
+		endpointRepo: endpointRepo,
+		ids:          ids,
+		clock:        clock,
+		eventTypes:   eventTypes,
+	}
+}
+
+// CreateEndpoint issues the signing secret of the endpoint, it is never
+// returned again.
+func (s *Service) CreateEndpoint(
+	ctx context.Context,
+	url, description string,
+	eventTypes []string,
+) (*domain.Endpoint, error) {
+	endpoint, err := domain.NewEndpoint(
+		s.ids.NewID(),
+		s.clock.Now(),
+		url,
+		description,
+		domain.NewSecret(),
+		eventTypes,
+		s.eventTypes,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint.OwnerID = auth.OwnerFromContext(ctx)
+
+	if err := s.endpointRepo.CreateEndpoint(ctx, endpoint); err != nil {
+		return nil, domain.ErrWebhookEndpointSaveFailed.Propagate(err)
+	}
+
+	return endpoint, nil
+}
+
+func (s *Service) ListEndpoints(ctx context.Context) ([]*domain.Endpoint, error) {
+	endpoints, err := s.endpointRepo.ListEndpoints(ctx, auth.OwnerFromContext(ctx))
+	if err != nil {
+		return nil, domain.ErrWebhookEndpointFindFailed.Propagate(err)
+	}
+
+	return endpoints, nil
+}
+
+// GetEndpoint only finds the endpoints of the owner in ctx, those of others
+// are not found rather than forbidden.
+func (s *Service) GetEndpoint(ctx context.Context, id core.ID) (*domain.Endpoint, error) {
+	endpoint, err := s.endpointRepo.FindEndpoint(ctx, id)
+	if err != nil {
+		return nil, domain.ErrWebhookEndpointFindFailed.Propagate(err)
+	}
+
+	if endpoint == nil || endpoint.OwnerID != auth.OwnerFromContext(ctx) {
+		return nil, domain.ErrWebhookEndpointNotFound.New()
+	}
+
+	return endpoint, nil
+}
+
+// UpdateEndpoint replaces the subscription, an empty status keeps the
+// current one. Enabling a disabled endpoint forgets its failures.
+func (s *Service) UpdateEndpoint(
+	ctx context.Context,
+	id core.ID,
+	url, description string,
+	eventTypes []string,
+	status domain.EndpointStatus,
+) (*domain.Endpoint, error) {
+	endpoint, err := s.GetEndpoint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+
+	if err := endpoint.Update(url, description, eventTypes, s.eventTypes, now); err != nil {
+		return nil, err
+	}
+
+	switch status {
+	case domain.EndpointStatusEnabled:
+		endpoint.Enable(now)
+	case domain.EndpointStatusDisabled:
+		endpoint.Disable(now)
+	}
+
+	if err := s.endpointRepo.SaveEndpoint(ctx, endpoint); err != nil {
+		return nil, domain.ErrWebhookEndpointSaveFailed.Propagate(err)
+	}
+
+	return endpoint, nil
+}
+
+// DeleteEndpoint drops the endpoint along with its delivery logs, pending
+// deliveries to it are skipped.
+func (s *Service) DeleteEndpoint(ctx context.Context, id core.ID) error {
+	if _, err := s.GetEndpoint(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.endpointRepo.DeleteEndpoint(ctx, id); err != nil {
+		return domain.ErrWebhookEndpointDeleteFailed.Propagate(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+package subscriptions_test
+
+import (
+	"HATCH_APP/internal/shared/auth"
+	"HATCH_APP/internal/webhook/domain"
+	"HATCH_APP/internal/webhook/feature/subscriptions"
+	"HATCH_APP/internal/webhook/mocks"
+	"HATCH_APP/pkg/core"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type serviceSuite struct {
+	repo    *mocks.EndpointRepository
+	service *subscriptions.Service
+}
+
+var (
+	now        = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	endpointID = core.MustParseID("01JWN3V0G0000000000000000A")
+	eventTypes = []string{"note.archived", "note.created"}
+)
+
+func setupServiceSuite(t *testing.T) *serviceSuite {
+	repo := mocks.NewEndpointRepository(t)
+
+	return &serviceSuite{
+		repo:    repo,
+		service: subscriptions.NewService(repo, core.FixedIDs(endpointID), core.FixedClock(now), eventTypes),
+	}
+}
+
+func disabledEndpoint(t *testing.T) *domain.Endpoint {
+	e, err := domain.NewEndpoint(endpointID, now, "https://example.com", "", "whsec_a", eventTypes, eventTypes)
+	require.NoError(t, err)
+
+	e.Disable(now)
+	e.FailureCount = 20
+
+	return e
+}
+
+func TestServiceCreateEndpoint(t *testing.T) {
+	t.Run("should create the endpoint with a fresh secret", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		s.repo.On("CreateEndpoint", mock.Anything, mock.MatchedBy(func(e *domain.Endpoint) bool {
+			return e.ID == endpointID &&
+				e.URL == "https://example.com/hooks" &&
+				strings.HasPrefix(e.Secret, "whsec_") &&
+				e.OwnerID == "acme" &&
+				e.IsEnabled()
+		})).
+			Return(nil).
+			Once()
+
+		ctx := auth.WithOwner(t.Context(), "acme")
+
+		e, err := s.service.CreateEndpoint(ctx, "https://example.com/hooks", "", []string{"note.created"})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"note.created"}, e.EventTypes)
+		assert.NotEmpty(t, e.Secret)
+	})
+
+	t.Run("should reject unknown event types", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		_, err := s.service.CreateEndpoint(t.Context(), "https://example.com", "", []string{"note.deleted"})
+
+		require.Error(t, err)
+		assert.True(t, domain.ErrWebhookEventTypeInvalid.Is(err))
+	})
+
+	t.Run("should fail when the endpoint cannot be saved", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		s.repo.On("CreateEndpoint", t.Context(), mock.Anything).
+			Return(errors.New("db down")).
+			Once()
+
+		_, err := s.service.CreateEndpoint(t.Context(), "https://example.com", "", eventTypes)
+
+		require.Error(t, err)
+		assert.True(t, domain.ErrWebhookEndpointSaveFailed.Is(err))
+	})
+}
+
+func TestServiceUpdateEndpoint(t *testing.T) {
+	t.Run("should forget failures when enabled again", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		s.repo.On("FindEndpoint", t.Context(), endpointID).
+			Return(disabledEndpoint(t), nil).
+			Once()
+
+		s.repo.On("SaveEndpoint", t.Context(), mock.MatchedBy(func(e *domain.Endpoint) bool {
+			return e.IsEnabled() && e.FailureCount == 0 && e.URL == "https://example.org"
+		})).
+			Return(nil).
+			Once()
+
+		e, err := s.service.UpdateEndpoint(t.Context(), endpointID, "https://example.org", "", eventTypes,
+			domain.EndpointStatusEnabled)
+
+		require.NoError(t, err)
+		assert.Nil(t, e.DisabledAt)
+	})
+
+	t.Run("should keep the status when none is given", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		s.repo.On("FindEndpoint", t.Context(), endpointID).
+			Return(disabledEndpoint(t), nil).
+			Once()
+
+		s.repo.On("SaveEndpoint", t.Context(), mock.MatchedBy(func(e *domain.Endpoint) bool {
+			return !e.IsEnabled() && e.FailureCount == 20
+		})).
+			Return(nil).
+			Once()
+
+		_, err := s.service.UpdateEndpoint(t.Context(), endpointID, "https://example.com", "", eventTypes, "")
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should return not found for unknown endpoints", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		s.repo.On("FindEndpoint", t.Context(), endpointID).
+			Return((*domain.Endpoint)(nil), nil).
+			Once()
+
+		_, err := s.service.UpdateEndpoint(t.Context(), endpointID, "https://example.com", "", eventTypes, "")
+
+		require.Error(t, err)
+		assert.True(t, domain.ErrWebhookEndpointNotFound.Is(err))
+	})
+}
+
+func TestServiceDeleteEndpoint(t *testing.T) {
+	t.Run("should delete existing endpoints", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		s.repo.On("FindEndpoint", t.Context(), endpointID).
+			Return(disabledEndpoint(t), nil).
+			Once()
+
+		s.repo.On("DeleteEndpoint", t.Context(), endpointID).
+			Return(nil).
+			Once()
+
+		require.NoError(t, s.service.DeleteEndpoint(t.Context(), endpointID))
+	})
+
+	t.Run("should return not found for unknown endpoints", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		s.repo.On("FindEndpoint", t.Context(), endpointID).
+			Return((*domain.Endpoint)(nil), nil).
+			Once()
+
+		err := s.service.DeleteEndpoint(t.Context(), endpointID)
+
+		assert.True(t, domain.ErrWebhookEndpointNotFound.Is(err))
+	})
+
+	t.Run("should not find the endpoints of other owners", func(t *testing.T) {
+		s := setupServiceSuite(t)
+		ctx := auth.WithOwner(t.Context(), "globex")
+
+		s.repo.On("FindEndpoint", ctx, endpointID).
+			Return(disabledEndpoint(t), nil).
+			Once()
+
+		err := s.service.DeleteEndpoint(ctx, endpointID)
+
+		assert.True(t, domain.ErrWebhookEndpointNotFound.Is(err))
+	})
+}
+
+func TestServiceListEndpoints(t *testing.T) {
+	t.Run("should only list the endpoints of the owner", func(t *testing.T) {
+		s := setupServiceSuite(t)
+
+		s.repo.On("ListEndpoints", mock.Anything, "acme").
+			Return([]*domain.Endpoint{}, nil).
+			Once()
+
+		endpoints, err := s.service.ListEndpoints(auth.WithOwner(t.Context(), "acme"))
+
+		require.NoError(t, err)
+		assert.Empty(t, endpoints)
+	})
+}
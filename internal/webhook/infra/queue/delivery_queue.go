@@ -0,0 +1,72 @@
+package queue
+
+import (
+	"context"
+	"errors"
+
+	"HATCH_APP/internal/webhook/domain"
+	"HATCH_APP/pkg/core"
+	pgQueue "HATCH_APP/pkg/queue/postgres"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Delivery is the payload of a delivery job.
+type Delivery struct {
+	Event      domain.Event `json:"event"`
+	EndpointID core.ID      `json:"endpoint_id"`
+}
+
+var (
+	// DispatchKind fans an event out to the subscribed endpoints, once it is
+	// committed along with the change it describes.
+	DispatchKind = pgQueue.Kind[domain.Event]("webhook.dispatch")
+	DeliverKind  = pgQueue.Kind[Delivery]("webhook.deliver")
+)
+
+// Dispatch enqueues the event on db, a transaction of the publisher drops
+// the event along with its change when it rolls back.
+func Dispatch(ctx context.Context, db sqlx.ExtContext, event domain.Event) error {
+	_, err := pgQueue.Enqueue(ctx, db, DispatchKind, event, pgQueue.WithUniqueKey(event.ID.String()))
+	if errors.Is(err, pgQueue.ErrDuplicatedJob) {
+		return nil
+	}
+
+	return err
+}
+
+type DeliveryQueue struct {
+	db          sqlx.ExtContext
+	maxAttempts int
+}
+
+func NewDeliveryQueue(db sqlx.ExtContext, maxAttempts int) *DeliveryQueue {
+	return &DeliveryQueue{
+		db:          db,
+		maxAttempts: maxAttempts,
+	}
+}
+
+func (q *DeliveryQueue) EnqueueDelivery(ctx context.Context, endpointID core.ID, event domain.Event) (bool, error) {
+	opts := []pgQueue.EnqueueOption{
+		pgQueue.WithUniqueKey(endpointID.String() + ":" + event.ID.String()),
+	}
+
+	if q.maxAttempts > 0 {
+		opts = append(opts, pgQueue.WithMaxAttempts(q.maxAttempts))
+	}
+
+	_, err := pgQueue.Enqueue(ctx, q.db, DeliverKind, Delivery{
+		EndpointID: endpointID,
+		Event:      event,
+	}, opts...)
+	if errors.Is(err, pgQueue.ErrDuplicatedJob) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
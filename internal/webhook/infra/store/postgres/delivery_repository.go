@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"HATCH_APP/internal/webhook/domain"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/store/postgres"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	createDelivery  = "create delivery"
+	listDeliveries  = "list deliveries"
+	findDelivery    = "find delivery"
+	pruneDeliveries = "prune deliveries"
+)
+
+const deliveryColumns = `id, endpoint_id, event_id, event_type, payload, attempt, status_code,
+	response_body, error, succeeded, duration_ms, created_at`
+
+var deliveryQueries = map[string]string{
+	createDelivery: `INSERT INTO webhook_deliveries (` + deliveryColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+	listDeliveries: `SELECT ` + deliveryColumns + ` FROM webhook_deliveries
+		WHERE endpoint_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2`,
+	findDelivery: `SELECT ` + deliveryColumns + ` FROM webhook_deliveries
+		WHERE endpoint_id = $1 AND id = $2`,
+	pruneDeliveries: `DELETE FROM webhook_deliveries
+		WHERE endpoint_id = $1 AND id NOT IN (
+			SELECT id FROM webhook_deliveries
+			WHERE endpoint_id = $1
+			ORDER BY created_at DESC, id DESC
+			LIMIT $2
+		)`,
+}
+
+type DeliveryRepository struct {
+	stmts map[string]*sqlx.Stmt
+}
+
+func NewDeliveryRepository(db postgres.Querier) (*DeliveryRepository, error) {
+	stmts := make(map[string]*sqlx.Stmt)
+
+	for queryName, statement := range deliveryQueries {
+		stmt, err := db.Preparex(statement)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to prepare query %s for webhook delivery: %w",
+				postgres.ErrQueryPreparation, queryName, err)
+		}
+
+		stmts[queryName] = stmt
+	}
+
+	return &DeliveryRepository{
+		stmts: stmts,
+	}, nil
+}
+
+func (r *DeliveryRepository) statement(queryName string) (*sqlx.Stmt, error) {
+	stmt, ok := r.stmts[queryName]
+
+	if !ok {
+		return nil, fmt.Errorf("%w: statement %s not prepared for webhook delivery",
+			postgres.ErrQueryPreparation, queryName)
+	}
+
+	return stmt, nil
+}
+
+func (r *DeliveryRepository) CreateDelivery(ctx context.Context, delivery *domain.Delivery) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.statement(createDelivery)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx,
+		delivery.ID,
+		delivery.EndpointID,
+		delivery.EventID,
+		delivery.EventType,
+		[]byte(delivery.Payload),
+		delivery.Attempt,
+		delivery.StatusCode,
+		delivery.ResponseBody,
+		delivery.Error,
+		delivery.Succeeded,
+		delivery.DurationMS,
+		delivery.CreatedAt,
+	)
+
+	return postgres.TranslateError(err)
+}
+
+func (r *DeliveryRepository) ListDeliveries(
+	ctx context.Context,
+	endpointID core.ID,
+	limit int,
+) ([]*domain.Delivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.statement(listDeliveries)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := []*domain.Delivery{}
+
+	if err := stmt.SelectContext(ctx, &deliveries, endpointID, limit); err != nil {
+		return nil, postgres.TranslateError(err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *DeliveryRepository) FindDelivery(ctx context.Context, endpointID, id core.ID) (*domain.Delivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.statement(findDelivery)
+	if err != nil {
+		return nil, err
+	}
+
+	var delivery domain.Delivery
+
+	if err := stmt.GetContext(ctx, &delivery, endpointID, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, postgres.TranslateError(err)
+	}
+
+	return &delivery, nil
+}
+
+func (r *DeliveryRepository) PruneDeliveries(ctx context.Context, endpointID core.ID, keep int) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.statement(pruneDeliveries)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx, endpointID, keep)
+
+	return postgres.TranslateError(err)
+}
@@ -0,0 +1,260 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"HATCH_APP/internal/webhook/domain"
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/store/postgres"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	createEndpoint = "create endpoint"
+	findEndpoint   = "find endpoint"
+	listEndpoints  = "list endpoints"
+	saveEndpoint   = "save endpoint"
+	deleteEndpoint = "delete endpoint"
+	listSubscribed = "list subscribed"
+	recordSuccess  = "record success"
+	recordFailure  = "record failure"
+)
+
+const endpointColumns = `id, owner_id, url, description, secret, event_types, status, failure_count,
+	disabled_at, created_at, updated_at`
+
+var endpointQueries = map[string]string{
+	createEndpoint: `INSERT INTO webhook_endpoints (` + endpointColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+	findEndpoint: `SELECT ` + endpointColumns + ` FROM webhook_endpoints WHERE id = $1`,
+	listEndpoints: `SELECT ` + endpointColumns + ` FROM webhook_endpoints
+		WHERE owner_id = $1
+		ORDER BY created_at, id`,
+	saveEndpoint: `UPDATE webhook_endpoints
+		SET url = $2, description = $3, event_types = $4, status = $5, failure_count = $6,
+			disabled_at = $7, updated_at = $8
+		WHERE id = $1`,
+	deleteEndpoint: `DELETE FROM webhook_endpoints WHERE id = $1`,
+	listSubscribed: `SELECT ` + endpointColumns + ` FROM webhook_endpoints
+		WHERE status = 'enabled' AND event_types @> ARRAY[$1]::VARCHAR[] AND owner_id = $2
+		ORDER BY created_at, id`,
+	recordSuccess: `UPDATE webhook_endpoints SET failure_count = 0
+		WHERE id = $1 AND failure_count > 0`,
+	// The count and the status change in one statement so concurrent
+	// deliveries to the same endpoint can't miss the threshold.
+	recordFailure: `UPDATE webhook_endpoints
+		SET failure_count = failure_count + 1,
+			status = CASE WHEN $2 > 0 AND failure_count + 1 >= $2 THEN 'disabled' ELSE status END,
+			disabled_at = CASE WHEN $2 > 0 AND failure_count + 1 >= $2 THEN $3 ELSE disabled_at END,
+			updated_at = CASE WHEN $2 > 0 AND failure_count + 1 >= $2 THEN $3 ELSE updated_at END
+		WHERE id = $1 AND status = 'enabled'
+		RETURNING status = 'disabled'`,
+}
+
+// endpointRow scans the event types array the domain keeps as a plain slice.
+type endpointRow struct {
+	domain.Endpoint
+	EventTypes pq.StringArray `db:"event_types"`
+}
+
+func (r endpointRow) endpoint() *domain.Endpoint {
+	e := r.Endpoint
+	e.EventTypes = r.EventTypes
+
+	return &e
+}
+
+type EndpointRepository struct {
+	stmts map[string]*sqlx.Stmt
+}
+
+func NewEndpointRepository(db postgres.Querier) (*EndpointRepository, error) {
+	stmts := make(map[string]*sqlx.Stmt)
+
+	for queryName, statement := range endpointQueries {
+		stmt, err := db.Preparex(statement)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to prepare query %s for webhook endpoint: %w",
+				postgres.ErrQueryPreparation, queryName, err)
+		}
+
+		stmts[queryName] = stmt
+	}
+
+	return &EndpointRepository{
+		stmts: stmts,
+	}, nil
+}
+
+func (r *EndpointRepository) statement(queryName string) (*sqlx.Stmt, error) {
+	stmt, ok := r.stmts[queryName]
+
+	if !ok {
+		return nil, fmt.Errorf("%w: statement %s not prepared for webhook endpoint",
+			postgres.ErrQueryPreparation, queryName)
+	}
+
+	return stmt, nil
+}
+
+func (r *EndpointRepository) CreateEndpoint(ctx context.Context, endpoint *domain.Endpoint) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.statement(createEndpoint)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx,
+		endpoint.ID,
+		endpoint.OwnerID,
+		endpoint.URL,
+		endpoint.Description,
+		endpoint.Secret,
+		pq.Array(endpoint.EventTypes),
+		endpoint.Status,
+		endpoint.FailureCount,
+		endpoint.DisabledAt,
+		endpoint.CreatedAt,
+		endpoint.UpdatedAt,
+	)
+
+	return postgres.TranslateError(err)
+}
+
+func (r *EndpointRepository) FindEndpoint(ctx context.Context, id core.ID) (*domain.Endpoint, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.statement(findEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var row endpointRow
+
+	if err := stmt.GetContext(ctx, &row, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, postgres.TranslateError(err)
+	}
+
+	return row.endpoint(), nil
+}
+
+func (r *EndpointRepository) ListEndpoints(ctx context.Context, owner string) ([]*domain.Endpoint, error) {
+	return r.list(ctx, listEndpoints, owner)
+}
+
+func (r *EndpointRepository) ListSubscribed(ctx context.Context, eventType, owner string) ([]*domain.Endpoint, error) {
+	return r.list(ctx, listSubscribed, eventType, owner)
+}
+
+func (r *EndpointRepository) list(ctx context.Context, queryName string, args ...any) ([]*domain.Endpoint, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.statement(queryName)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []endpointRow{}
+
+	if err := stmt.SelectContext(ctx, &rows, args...); err != nil {
+		return nil, postgres.TranslateError(err)
+	}
+
+	endpoints := make([]*domain.Endpoint, 0, len(rows))
+	for _, row := range rows {
+		endpoints = append(endpoints, row.endpoint())
+	}
+
+	return endpoints, nil
+}
+
+func (r *EndpointRepository) SaveEndpoint(ctx context.Context, endpoint *domain.Endpoint) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.statement(saveEndpoint)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx,
+		endpoint.ID,
+		endpoint.URL,
+		endpoint.Description,
+		pq.Array(endpoint.EventTypes),
+		endpoint.Status,
+		endpoint.FailureCount,
+		endpoint.DisabledAt,
+		endpoint.UpdatedAt,
+	)
+
+	return postgres.TranslateError(err)
+}
+
+func (r *EndpointRepository) DeleteEndpoint(ctx context.Context, id core.ID) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.statement(deleteEndpoint)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx, id)
+
+	return postgres.TranslateError(err)
+}
+
+func (r *EndpointRepository) RecordSuccess(ctx context.Context, id core.ID) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.statement(recordSuccess)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx, id)
+
+	return postgres.TranslateError(err)
+}
+
+func (r *EndpointRepository) RecordFailure(
+	ctx context.Context,
+	id core.ID,
+	disableAfter int,
+	now time.Time,
+) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	stmt, err := r.statement(recordFailure)
+	if err != nil {
+		return false, err
+	}
+
+	var disabled bool
+
+	if err := stmt.GetContext(ctx, &disabled, id, disableAfter, now); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+
+		return false, postgres.TranslateError(err)
+	}
+
+	return disabled, nil
+}
@@ -0,0 +1,59 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	core "HATCH_APP/pkg/core"
+	context "context"
+
+	domain "HATCH_APP/internal/webhook/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DeliveryQueue is an autogenerated mock type for the DeliveryQueue type
+type DeliveryQueue struct {
+	mock.Mock
+}
+
+// EnqueueDelivery provides a mock function with given fields: ctx, endpointID, event
+func (_m *DeliveryQueue) EnqueueDelivery(ctx context.Context, endpointID core.ID, event domain.Event) (bool, error) {
+	ret := _m.Called(ctx, endpointID, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnqueueDelivery")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID, domain.Event) (bool, error)); ok {
+		return rf(ctx, endpointID, event)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID, domain.Event) bool); ok {
+		r0 = rf(ctx, endpointID, event)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, core.ID, domain.Event) error); ok {
+		r1 = rf(ctx, endpointID, event)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDeliveryQueue creates a new instance of DeliveryQueue. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDeliveryQueue(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DeliveryQueue {
+	mock := &DeliveryQueue{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
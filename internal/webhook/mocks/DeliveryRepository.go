@@ -0,0 +1,127 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	core "HATCH_APP/pkg/core"
+	context "context"
+
+	domain "HATCH_APP/internal/webhook/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DeliveryRepository is an autogenerated mock type for the DeliveryRepository type
+type DeliveryRepository struct {
+	mock.Mock
+}
+
+// CreateDelivery provides a mock function with given fields: ctx, delivery
+func (_m *DeliveryRepository) CreateDelivery(ctx context.Context, delivery *domain.Delivery) error {
+	ret := _m.Called(ctx, delivery)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateDelivery")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Delivery) error); ok {
+		r0 = rf(ctx, delivery)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindDelivery provides a mock function with given fields: ctx, endpointID, id
+func (_m *DeliveryRepository) FindDelivery(ctx context.Context, endpointID core.ID, id core.ID) (*domain.Delivery, error) {
+	ret := _m.Called(ctx, endpointID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindDelivery")
+	}
+
+	var r0 *domain.Delivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID, core.ID) (*domain.Delivery, error)); ok {
+		return rf(ctx, endpointID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID, core.ID) *domain.Delivery); ok {
+		r0 = rf(ctx, endpointID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Delivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, core.ID, core.ID) error); ok {
+		r1 = rf(ctx, endpointID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDeliveries provides a mock function with given fields: ctx, endpointID, limit
+func (_m *DeliveryRepository) ListDeliveries(ctx context.Context, endpointID core.ID, limit int) ([]*domain.Delivery, error) {
+	ret := _m.Called(ctx, endpointID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeliveries")
+	}
+
+	var r0 []*domain.Delivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID, int) ([]*domain.Delivery, error)); ok {
+		return rf(ctx, endpointID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID, int) []*domain.Delivery); ok {
+		r0 = rf(ctx, endpointID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Delivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, core.ID, int) error); ok {
+		r1 = rf(ctx, endpointID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PruneDeliveries provides a mock function with given fields: ctx, endpointID, keep
+func (_m *DeliveryRepository) PruneDeliveries(ctx context.Context, endpointID core.ID, keep int) error {
+	ret := _m.Called(ctx, endpointID, keep)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PruneDeliveries")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID, int) error); ok {
+		r0 = rf(ctx, endpointID, keep)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewDeliveryRepository creates a new instance of DeliveryRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDeliveryRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DeliveryRepository {
+	mock := &DeliveryRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,223 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	core "HATCH_APP/pkg/core"
+	context "context"
+
+	domain "HATCH_APP/internal/webhook/domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// EndpointRepository is an autogenerated mock type for the EndpointRepository type
+type EndpointRepository struct {
+	mock.Mock
+}
+
+// CreateEndpoint provides a mock function with given fields: ctx, endpoint
+func (_m *EndpointRepository) CreateEndpoint(ctx context.Context, endpoint *domain.Endpoint) error {
+	ret := _m.Called(ctx, endpoint)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateEndpoint")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Endpoint) error); ok {
+		r0 = rf(ctx, endpoint)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteEndpoint provides a mock function with given fields: ctx, id
+func (_m *EndpointRepository) DeleteEndpoint(ctx context.Context, id core.ID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteEndpoint")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindEndpoint provides a mock function with given fields: ctx, id
+func (_m *EndpointRepository) FindEndpoint(ctx context.Context, id core.ID) (*domain.Endpoint, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindEndpoint")
+	}
+
+	var r0 *domain.Endpoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID) (*domain.Endpoint, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID) *domain.Endpoint); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Endpoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, core.ID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListEndpoints provides a mock function with given fields: ctx, owner
+func (_m *EndpointRepository) ListEndpoints(ctx context.Context, owner string) ([]*domain.Endpoint, error) {
+	ret := _m.Called(ctx, owner)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListEndpoints")
+	}
+
+	var r0 []*domain.Endpoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*domain.Endpoint, error)); ok {
+		return rf(ctx, owner)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*domain.Endpoint); ok {
+		r0 = rf(ctx, owner)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Endpoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, owner)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListSubscribed provides a mock function with given fields: ctx, eventType, owner
+func (_m *EndpointRepository) ListSubscribed(ctx context.Context, eventType string, owner string) ([]*domain.Endpoint, error) {
+	ret := _m.Called(ctx, eventType, owner)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSubscribed")
+	}
+
+	var r0 []*domain.Endpoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]*domain.Endpoint, error)); ok {
+		return rf(ctx, eventType, owner)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []*domain.Endpoint); ok {
+		r0 = rf(ctx, eventType, owner)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Endpoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, eventType, owner)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordFailure provides a mock function with given fields: ctx, id, disableAfter, now
+func (_m *EndpointRepository) RecordFailure(ctx context.Context, id core.ID, disableAfter int, now time.Time) (bool, error) {
+	ret := _m.Called(ctx, id, disableAfter, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordFailure")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID, int, time.Time) (bool, error)); ok {
+		return rf(ctx, id, disableAfter, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID, int, time.Time) bool); ok {
+		r0 = rf(ctx, id, disableAfter, now)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, core.ID, int, time.Time) error); ok {
+		r1 = rf(ctx, id, disableAfter, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordSuccess provides a mock function with given fields: ctx, id
+func (_m *EndpointRepository) RecordSuccess(ctx context.Context, id core.ID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordSuccess")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, core.ID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveEndpoint provides a mock function with given fields: ctx, endpoint
+func (_m *EndpointRepository) SaveEndpoint(ctx context.Context, endpoint *domain.Endpoint) error {
+	ret := _m.Called(ctx, endpoint)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveEndpoint")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Endpoint) error); ok {
+		r0 = rf(ctx, endpoint)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewEndpointRepository creates a new instance of EndpointRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEndpointRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EndpointRepository {
+	mock := &EndpointRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
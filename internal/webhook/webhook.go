@@ -0,0 +1,187 @@
+package webhook
+
+import (
+	"HATCH_APP/internal/webhook/domain"
+	"HATCH_APP/internal/webhook/feature/deliveries"
+	"HATCH_APP/internal/webhook/feature/subscriptions"
+	"HATCH_APP/internal/webhook/infra/queue"
+	"HATCH_APP/internal/webhook/infra/store/postgres"
+	"HATCH_APP/pkg/core"
+	pgQueue "HATCH_APP/pkg/queue/postgres"
+	"HATCH_APP/pkg/transport/httpx/openapi"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+type External struct {
+	DB *sqlx.DB
+	// Worker runs the dispatch and delivery jobs, it must be started after
+	// Register.
+	Worker *pgQueue.Worker
+	// OpenAPI documents the HTTP routes when set.
+	OpenAPI *openapi.Spec
+	// Clock and IDs default to the system clock and a ULID generator on it.
+	Clock core.Clock
+	IDs   core.IDGenerator
+}
+
+// idParams types the {id} path param of the endpoint routes.
+type idParams struct {
+	ID core.ID `path:"id"`
+}
+
+// deliveryParams types the path params of a single delivery route.
+type deliveryParams struct {
+	ID         core.ID `path:"id"`
+	DeliveryID core.ID `path:"deliveryID"`
+}
+
+type Config struct {
+	// EventTypes lists the events published by the other modules, endpoints
+	// may only subscribe to those.
+	EventTypes []string
+	// MaxAttempts bounds the attempts of a delivery, retried with the
+	// backoff of the worker.
+	MaxAttempts int
+	// Timeout bounds a single attempt, answer included.
+	Timeout time.Duration
+	// DisableAfter is the number of failed attempts in a row that disables
+	// an endpoint, 0 never disables.
+	DisableAfter int
+	// DeliveryRetention is the number of delivery logs kept per endpoint, 0
+	// keeps them all.
+	DeliveryRetention int
+}
+
+func Register(r chi.Router, ext External, cfg Config) error {
+	endpointRepo, err := postgres.NewEndpointRepository(ext.DB)
+	if err != nil {
+		return err
+	}
+
+	deliveryRepo, err := postgres.NewDeliveryRepository(ext.DB)
+	if err != nil {
+		return err
+	}
+
+	clock := ext.Clock
+	if clock == nil {
+		clock = core.SystemClock()
+	}
+
+	ids := ext.IDs
+	if ids == nil {
+		ids = core.NewULIDGenerator(clock)
+	}
+
+	subscriptionsF := subscriptions.New(endpointRepo, ids, clock, cfg.EventTypes)
+	deliveriesF := deliveries.New(
+		endpointRepo,
+		deliveryRepo,
+		queue.NewDeliveryQueue(ext.DB, cfg.MaxAttempts),
+		deliveries.NewClient(cfg.Timeout),
+		ids,
+		clock,
+		deliveries.Policy{
+			DisableAfter: cfg.DisableAfter,
+			Retention:    cfg.DeliveryRetention,
+		},
+	)
+
+	// HTTP
+	tags := []string{"webhooks"}
+
+	openapi.Mount(r, ext.OpenAPI, "/v1/webhooks",
+		openapi.Route{
+			Method:      http.MethodPost,
+			Path:        "/",
+			Handler:     subscriptionsF.CreateEndpointEndpoint,
+			OperationID: "createWebhookEndpoint",
+			Summary:     "Subscribe an endpoint to events, the signing secret is only returned here",
+			Tags:        tags,
+			Request:     subscriptions.Request{},
+			Responses:   map[int]any{http.StatusCreated: subscriptions.CreateResponse{}},
+			Errors:      []int{http.StatusBadRequest},
+		},
+		openapi.Route{
+			Method:      http.MethodGet,
+			Path:        "/",
+			Handler:     subscriptionsF.ListEndpointsEndpoint,
+			OperationID: "listWebhookEndpoints",
+			Summary:     "List webhook endpoints",
+			Tags:        tags,
+			Responses:   map[int]any{http.StatusOK: subscriptions.ListResponse{}},
+		},
+		openapi.Route{
+			Method:      http.MethodGet,
+			Path:        "/{id}",
+			Handler:     subscriptionsF.GetEndpointEndpoint,
+			OperationID: "getWebhookEndpoint",
+			Params:      idParams{},
+			Summary:     "Get a webhook endpoint",
+			Tags:        tags,
+			Responses:   map[int]any{http.StatusOK: subscriptions.Response{}},
+			Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		openapi.Route{
+			Method:      http.MethodPut,
+			Path:        "/{id}",
+			Handler:     subscriptionsF.UpdateEndpointEndpoint,
+			OperationID: "updateWebhookEndpoint",
+			Params:      idParams{},
+			Summary:     "Edit a webhook endpoint, enabling it again forgets its failures",
+			Tags:        tags,
+			Request:     subscriptions.UpdateRequest{},
+			Responses:   map[int]any{http.StatusOK: subscriptions.Response{}},
+			Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		openapi.Route{
+			Method:      http.MethodDelete,
+			Path:        "/{id}",
+			Handler:     subscriptionsF.DeleteEndpointEndpoint,
+			OperationID: "deleteWebhookEndpoint",
+			Params:      idParams{},
+			Summary:     "Delete a webhook endpoint and its delivery logs",
+			Tags:        tags,
+			Responses:   map[int]any{http.StatusNoContent: nil},
+			Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		openapi.Route{
+			Method:      http.MethodGet,
+			Path:        "/{id}/deliveries",
+			Handler:     deliveriesF.ListDeliveriesEndpoint,
+			OperationID: "listWebhookDeliveries",
+			Params:      deliveries.Params{},
+			Summary:     "List the latest deliveries to a webhook endpoint, newest first",
+			Tags:        tags,
+			Responses:   map[int]any{http.StatusOK: deliveries.ListResponse{}},
+			Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		openapi.Route{
+			Method:      http.MethodPost,
+			Path:        "/{id}/deliveries/{deliveryID}/redeliver",
+			Handler:     deliveriesF.RedeliverEndpoint,
+			OperationID: "redeliverWebhook",
+			Params:      deliveryParams{},
+			Summary:     "Deliver the event of a logged delivery again",
+			Tags:        tags,
+			Responses:   map[int]any{http.StatusAccepted: deliveries.RedeliverResponse{}},
+			Errors:      []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+		},
+	)
+
+	// Jobs
+	pgQueue.Handle(ext.Worker, queue.DispatchKind, func(ctx context.Context, job *pgQueue.Job[domain.Event]) error {
+		return deliveriesF.DispatchEventJob(ctx, job.Payload)
+	})
+
+	pgQueue.Handle(ext.Worker, queue.DeliverKind, func(ctx context.Context, job *pgQueue.Job[queue.Delivery]) error {
+		return deliveriesF.DeliverJob(ctx, job.Payload.EndpointID, job.Payload.Event, job.Attempt)
+	})
+
+	return nil
+}
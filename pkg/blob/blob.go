@@ -0,0 +1,102 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var (
+	ErrNotFound     = errors.New("blob not found")
+	ErrInvalidKey   = errors.New("invalid blob key")
+	ErrSizeMismatch = errors.New("blob size mismatch")
+)
+
+type Object struct {
+	Key  string
+	Size int64
+}
+
+// Store keeps opaque objects under slash separated keys.
+type Store interface {
+	// Put stores exactly size bytes of r under key, replacing any previous
+	// object. A reader yielding another size fails with ErrSizeMismatch and
+	// leaves no object behind.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get returns ErrNotFound when the key is absent, the caller closes the
+	// reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, Object, error)
+	// Delete ignores absent keys.
+	Delete(ctx context.Context, key string) error
+}
+
+// ValidateKey rejects keys that could escape the store root: empty or dot
+// segments, leading slashes and backslashes.
+func ValidateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, `\`) {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+
+	for segment := range strings.SplitSeq(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+		}
+	}
+
+	return nil
+}
+
+// ExactReader reads r while checking it yields exactly size bytes, failing
+// with ErrSizeMismatch otherwise. The last chunk is withheld from a longer
+// reader, so a consumer streaming it to a Content-Length bound request never
+// completes the body.
+func ExactReader(r io.Reader, size int64) io.Reader {
+	return &exactReader{r: r, remaining: size}
+}
+
+type exactReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (e *exactReader) Read(p []byte) (int, error) {
+	if e.remaining <= 0 {
+		return 0, e.checkEOF()
+	}
+
+	if int64(len(p)) > e.remaining {
+		p = p[:e.remaining]
+	}
+
+	n, err := e.r.Read(p)
+	e.remaining -= int64(n)
+
+	if err == io.EOF && e.remaining > 0 {
+		return 0, ErrSizeMismatch
+	}
+
+	if err == nil && e.remaining == 0 {
+		if err := e.checkEOF(); err != io.EOF {
+			return 0, err
+		}
+	}
+
+	return n, err
+}
+
+func (e *exactReader) checkEOF() error {
+	var extra [1]byte
+
+	for {
+		n, err := e.r.Read(extra[:])
+		if n > 0 {
+			return ErrSizeMismatch
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package local
+
+import (
+	"HATCH_APP/pkg/blob"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Store keeps objects as files under a root directory. Writes go to a
+// temporary file renamed into place, so readers never see partial objects.
+type Store struct {
+	root string
+}
+
+func New(root string) (*Store, error) {
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("blob: create root %s: %w", root, err)
+	}
+
+	return &Store{root: root}, nil
+}
+
+func (s *Store) path(key string) (string, error) {
+	if err := blob.ValidateKey(key); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+func (s *Store) Put(ctx context.Context, key string, r io.Reader, size int64, _ string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := io.Copy(tmp, blob.ExactReader(&contextReader{ctx: ctx, r: r}, size)); err != nil {
+		return fmt.Errorf("blob: put %s: %w", key, err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *Store) Get(_ context.Context, key string) (io.ReadCloser, blob.Object, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, blob.Object{}, err
+	}
+
+	f, err := os.Open(path) // #nosec G304 -- key validated against traversal
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, blob.Object{}, fmt.Errorf("%w: %s", blob.ErrNotFound, key)
+		}
+
+		return nil, blob.Object{}, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, blob.Object{}, err
+	}
+
+	return f, blob.Object{Key: key, Size: info.Size()}, nil
+}
+
+func (s *Store) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// contextReader stops a copy once ctx is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return c.r.Read(p)
+}
@@ -0,0 +1,38 @@
+package local_test
+
+import (
+	"HATCH_APP/pkg/blob"
+	"HATCH_APP/pkg/blob/local"
+	"HATCH_APP/test/blobtest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocal(t *testing.T) {
+	blobtest.Run(t, func(t *testing.T) blob.Store {
+		s, err := local.New(t.TempDir())
+		require.NoError(t, err)
+
+		return s
+	})
+}
+
+func TestLocalLeavesNoTemporaryFiles(t *testing.T) {
+	root := t.TempDir()
+
+	s, err := local.New(root)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Put(t.Context(), "notes/ok", strings.NewReader("ok"), 2, ""))
+	require.Error(t, s.Put(t.Context(), "notes/bad", strings.NewReader("bad"), 5, ""))
+
+	entries, err := os.ReadDir(filepath.Join(root, "notes"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "ok", entries[0].Name())
+}
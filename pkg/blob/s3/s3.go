@@ -0,0 +1,179 @@
+package s3
+
+import (
+	"HATCH_APP/pkg/blob"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	// Endpoint is the base URL of the S3-compatible service, objects are
+	// addressed path-style as Endpoint/Bucket/key.
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+// Store talks to an S3-compatible service over plain HTTP with SigV4 signed
+// requests, only the calls blob.Store needs are implemented.
+type Store struct {
+	client   *http.Client
+	endpoint *url.URL
+	signer   *signer
+	bucket   string
+}
+
+func New(cfg Config, client *http.Client) (*Store, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("blob: invalid s3 endpoint %q", cfg.Endpoint)
+	}
+
+	if cfg.Bucket == "" {
+		return nil, errors.New("blob: s3 bucket is required")
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Store{
+		client:   client,
+		endpoint: endpoint,
+		bucket:   cfg.Bucket,
+		signer: &signer{
+			region:    cfg.Region,
+			accessKey: cfg.AccessKey,
+			secretKey: cfg.SecretKey,
+			now:       time.Now,
+		},
+	}, nil
+}
+
+func (s *Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if err := blob.ValidateKey(key); err != nil {
+		return err
+	}
+
+	body := io.NopCloser(blob.ExactReader(r, size))
+
+	if size == 0 {
+		// A zero ContentLength with a body would be sent chunked.
+		if _, err := io.Copy(io.Discard, body); err != nil {
+			return err
+		}
+
+		body = http.NoBody
+	}
+
+	req, err := s.request(ctx, http.MethodPut, key, body)
+	if err != nil {
+		return err
+	}
+
+	req.ContentLength = size
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	res, err := s.do(req)
+	if err != nil {
+		return err
+	}
+
+	return res.Body.Close()
+}
+
+func (s *Store) Get(ctx context.Context, key string) (io.ReadCloser, blob.Object, error) {
+	if err := blob.ValidateKey(key); err != nil {
+		return nil, blob.Object{}, err
+	}
+
+	req, err := s.request(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, blob.Object{}, err
+	}
+
+	res, err := s.do(req)
+	if err != nil {
+		return nil, blob.Object{}, err
+	}
+
+	return res.Body, blob.Object{Key: key, Size: res.ContentLength}, nil
+}
+
+func (s *Store) Delete(ctx context.Context, key string) error {
+	if err := blob.ValidateKey(key); err != nil {
+		return err
+	}
+
+	req, err := s.request(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.do(req)
+	if errors.Is(err, blob.ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return res.Body.Close()
+}
+
+func (s *Store) request(ctx context.Context, method, key string, body io.ReadCloser) (*http.Request, error) {
+	u := *s.endpoint
+	base := strings.TrimSuffix(u.Path, "/")
+	u.Path = base + "/" + s.bucket + "/" + key
+	u.RawPath = base + "/" + escapePath(s.bucket) + "/" + escapePath(key)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		req.Body = body
+	}
+
+	return req, nil
+}
+
+// do signs and sends req, turning 404 into blob.ErrNotFound and any other
+// non 2xx status into an error carrying the start of the body.
+func (s *Store) do(req *http.Request) (*http.Response, error) {
+	s.signer.sign(req, unsignedPayload)
+
+	res, err := s.client.Do(req) // #nosec G704 -- endpoint comes from config
+	if err != nil {
+		return nil, fmt.Errorf("blob: s3 %s %s: %w", req.Method, req.URL.Path, err)
+	}
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return res, nil
+	}
+
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", blob.ErrNotFound, req.URL.Path)
+	}
+
+	detail, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+
+	return nil, fmt.Errorf("blob: s3 %s %s: %s: %s", req.Method, req.URL.Path, res.Status, detail)
+}
@@ -0,0 +1,70 @@
+package s3_test
+
+import (
+	"HATCH_APP/pkg/blob"
+	"HATCH_APP/pkg/blob/s3"
+	"HATCH_APP/test/blobtest"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStore(t *testing.T, handler http.Handler) *s3.Store {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	s, err := s3.New(s3.Config{
+		Endpoint:  srv.URL,
+		Region:    "us-east-1",
+		Bucket:    "hatch",
+		AccessKey: "access",
+		SecretKey: "secret",
+	}, srv.Client())
+	require.NoError(t, err)
+
+	return s
+}
+
+func TestS3(t *testing.T) {
+	blobtest.Run(t, func(t *testing.T) blob.Store {
+		backend := s3mem.New()
+		require.NoError(t, backend.CreateBucket("hatch"))
+
+		return newStore(t, gofakes3.New(backend).Server())
+	})
+}
+
+func TestS3SignsRequests(t *testing.T) {
+	var got *http.Request
+
+	s := newStore(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	require.NoError(t, s.Put(t.Context(), "notes/a b", strings.NewReader("x"), 1, "text/plain"))
+
+	require.NotNil(t, got)
+	assert.Equal(t, "/hatch/notes/a%20b", got.URL.EscapedPath())
+	assert.Equal(t, "UNSIGNED-PAYLOAD", got.Header.Get("X-Amz-Content-Sha256"))
+	assert.Contains(t, got.Header.Get("Authorization"),
+		"SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date,")
+}
+
+func TestS3ReportsServiceErrors(t *testing.T) {
+	s := newStore(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "<Error><Code>AccessDenied</Code></Error>", http.StatusForbidden)
+	}))
+
+	_, _, err := s.Get(t.Context(), "key")
+
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, blob.ErrNotFound)
+	assert.Contains(t, err.Error(), "AccessDenied")
+}
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrMiss   = errors.New("cache miss")
+	ErrDecode = errors.New("cache decode")
+)
+
+type Loader func(ctx context.Context) ([]byte, error)
+
+type Cache interface {
+	// Get returns ErrMiss when the key is absent or expired.
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	// GetOrLoad returns the cached value or calls load once per key, even when
+	// many callers miss at the same time, and caches its result.
+	GetOrLoad(ctx context.Context, key string, ttl time.Duration, load Loader) ([]byte, error)
+}
+
+func GetOrLoadJSON[T any](
+	ctx context.Context,
+	c Cache,
+	key string,
+	ttl time.Duration,
+	load func(ctx context.Context) (T, error),
+) (T, error) {
+	var v T
+
+	raw, err := c.GetOrLoad(ctx, key, ttl, func(ctx context.Context) ([]byte, error) {
+		loaded, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(loaded)
+	})
+	if err != nil {
+		return v, err
+	}
+
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return v, fmt.Errorf("%w: %s: %w", ErrDecode, key, err)
+	}
+
+	return v, nil
+}
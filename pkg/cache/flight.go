@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"HATCH_APP/pkg/o11y"
+	"context"
+	"errors"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// loadTimeout bounds a shared load, it outlives the caller that started it.
+const loadTimeout = 30 * time.Second
+
+// Flight implements GetOrLoad on top of Get and Set for Cache implementations.
+type Flight struct {
+	group singleflight.Group
+}
+
+type getSetter interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+func (f *Flight) GetOrLoad(
+	ctx context.Context,
+	c getSetter,
+	key string,
+	ttl time.Duration,
+	load Loader,
+) ([]byte, error) {
+	v, err := c.Get(ctx, key)
+	if err == nil {
+		return v, nil
+	}
+
+	if !errors.Is(err, ErrMiss) {
+		o11y.LoggerFromContext(ctx).WarnContext(ctx, "cache: get failed, loading from source",
+			"cache_key", key, "error", err)
+	}
+
+	// The load is shared by every caller missing key, cancelling the one that
+	// started it must not fail the others.
+	ch := f.group.DoChan(key, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loadTimeout)
+		defer cancel()
+
+		v, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := c.Set(ctx, key, v, ttl); err != nil {
+			o11y.LoggerFromContext(ctx).WarnContext(ctx, "cache: set failed", "cache_key", key, "error", err)
+		}
+
+		return v, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+
+		b, _ := res.Val.([]byte)
+
+		return b, nil
+	}
+}
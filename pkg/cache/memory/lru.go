@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"HATCH_APP/pkg/cache"
+	"container/list"
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+const DefaultCapacity = 10_000
+
+type item struct {
+	expiresAt time.Time
+	key       string
+	value     []byte
+}
+
+// LRU is an in-process cache that evicts the least recently used key once
+// capacity is reached.
+type LRU struct {
+	items    map[string]*list.Element
+	order    *list.List
+	flight   cache.Flight
+	capacity int
+	mu       sync.Mutex
+}
+
+func NewLRU(capacity int) *LRU {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+
+	return &LRU{
+		items:    make(map[string]*list.Element, capacity),
+		order:    list.New(),
+		capacity: capacity,
+	}
+}
+
+func (c *LRU) Get(_ context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, cache.ErrMiss
+	}
+
+	it, _ := el.Value.(*item)
+
+	if !it.expiresAt.IsZero() && time.Now().After(it.expiresAt) {
+		c.remove(el)
+		return nil, cache.ErrMiss
+	}
+
+	c.order.MoveToFront(el)
+
+	return slices.Clone(it.value), nil
+}
+
+func (c *LRU) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	if el, ok := c.items[key]; ok {
+		it, _ := el.Value.(*item)
+		it.value = slices.Clone(value)
+		it.expiresAt = expiresAt
+		c.order.MoveToFront(el)
+
+		return nil
+	}
+
+	c.items[key] = c.order.PushFront(&item{
+		key:       key,
+		value:     slices.Clone(value),
+		expiresAt: expiresAt,
+	})
+
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+
+	return nil
+}
+
+func (c *LRU) Delete(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if el, ok := c.items[key]; ok {
+			c.remove(el)
+		}
+	}
+
+	return nil
+}
+
+func (c *LRU) GetOrLoad(ctx context.Context, key string, ttl time.Duration, load cache.Loader) ([]byte, error) {
+	return c.flight.GetOrLoad(ctx, c, key, ttl, load)
+}
+
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *LRU) remove(el *list.Element) {
+	it, _ := el.Value.(*item)
+
+	c.order.Remove(el)
+	delete(c.items, it.key)
+}
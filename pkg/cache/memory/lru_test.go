@@ -0,0 +1,38 @@
+package memory_test
+
+import (
+	"HATCH_APP/pkg/cache"
+	"HATCH_APP/pkg/cache/memory"
+	"HATCH_APP/test/cachetest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRU(t *testing.T) {
+	cachetest.Run(t, func(*testing.T) cache.Cache {
+		return memory.NewLRU(100)
+	}, time.Sleep)
+}
+
+func TestLRUEviction(t *testing.T) {
+	c := memory.NewLRU(2)
+
+	require.NoError(t, c.Set(t.Context(), "a", []byte("1"), 0))
+	require.NoError(t, c.Set(t.Context(), "b", []byte("2"), 0))
+
+	_, err := c.Get(t.Context(), "a")
+	require.NoError(t, err)
+
+	require.NoError(t, c.Set(t.Context(), "c", []byte("3"), 0))
+
+	_, err = c.Get(t.Context(), "b")
+	require.ErrorIs(t, err, cache.ErrMiss)
+
+	_, err = c.Get(t.Context(), "a")
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, c.Len())
+}
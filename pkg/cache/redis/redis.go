@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"HATCH_APP/pkg/cache"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type Cache struct {
+	client redis.UniversalClient
+	flight cache.Flight
+	prefix string
+}
+
+// New namespaces every key with prefix so several services can share a Redis.
+func New(client redis.UniversalClient, prefix string) *Cache {
+	return &Cache{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+	v, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, cache.ErrMiss
+		}
+
+		return nil, err
+	}
+
+	return v, nil
+}
+
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, c.prefix+key, value, ttl).Err()
+}
+
+func (c *Cache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.prefix + key
+	}
+
+	return c.client.Del(ctx, prefixed...).Err()
+}
+
+func (c *Cache) GetOrLoad(ctx context.Context, key string, ttl time.Duration, load cache.Loader) ([]byte, error) {
+	return c.flight.GetOrLoad(ctx, c, key, ttl, load)
+}
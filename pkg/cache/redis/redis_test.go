@@ -0,0 +1,43 @@
+package redis_test
+
+import (
+	"HATCH_APP/pkg/cache"
+	cacheRedis "HATCH_APP/pkg/cache/redis"
+	"HATCH_APP/test/cachetest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedis(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	cachetest.Run(t, func(t *testing.T) cache.Cache {
+		mr.FlushAll()
+
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		t.Cleanup(func() {
+			_ = client.Close()
+		})
+
+		return cacheRedis.New(client, "hatch:")
+	}, mr.FastForward)
+}
+
+func TestRedisPrefix(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	c := cacheRedis.New(client, "hatch:")
+
+	require.NoError(t, c.Set(t.Context(), "key", []byte("value"), 0))
+
+	assert.True(t, mr.Exists("hatch:key"))
+}
@@ -0,0 +1,204 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"HATCH_APP/pkg/o11y"
+	pgStore "HATCH_APP/pkg/store/postgres"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var ErrClusterStarted = errors.New("cluster already started")
+
+type ClusterConfig struct {
+	ReplicaURLs []string
+	// HealthInterval is how often replicas are pinged, a replica failing a
+	// ping gets no reads until one succeeds again.
+	HealthInterval time.Duration
+	HealthTimeout  time.Duration
+	// ReadYourWrites sends the reads of a session to the primary once it
+	// wrote, so they never miss its writes on a lagging replica.
+	ReadYourWrites bool
+}
+
+// Cluster is a primary and its read replicas. Writes and transactions go to
+// Primary, read-only queries to a healthy replica through Reader, which falls
+// back to the primary when none is.
+type Cluster struct {
+	primary  *sqlx.DB
+	replicas []*replica
+	next     atomic.Uint64
+	stop     chan struct{}
+	cfg      ClusterConfig
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	started  bool
+}
+
+type replica struct {
+	db      *sqlx.DB
+	healthy atomic.Bool
+	// checked is only used by the checks, which never overlap.
+	checked bool
+}
+
+// ConnectCluster fails when the primary is unreachable. Replicas are not
+// required to be up, they are checked once before returning and then by
+// Start.
+func ConnectCluster(ctx context.Context, primaryURL string, cfg ClusterConfig) (*Cluster, error) {
+	primary, err := Connect(ctx, primaryURL)
+	if err != nil {
+		return nil, err
+	}
+
+	c := newCluster(primary, cfg)
+
+	for _, url := range cfg.ReplicaURLs {
+		db, err := sqlx.Open(driver, url)
+		if err != nil {
+			_ = c.Close()
+
+			return nil, err
+		}
+
+		c.addReplica(db)
+	}
+
+	c.check(ctx)
+
+	return c, nil
+}
+
+func newCluster(primary *sqlx.DB, cfg ClusterConfig) *Cluster {
+	if cfg.HealthInterval <= 0 {
+		cfg.HealthInterval = 5 * time.Second
+	}
+
+	if cfg.HealthTimeout <= 0 {
+		cfg.HealthTimeout = 2 * time.Second
+	}
+
+	return &Cluster{
+		primary: primary,
+		cfg:     cfg,
+		stop:    make(chan struct{}),
+	}
+}
+
+// addReplica must be called before the cluster is checked or started.
+func (c *Cluster) addReplica(db *sqlx.DB) {
+	c.replicas = append(c.replicas, &replica{db: db})
+}
+
+func (c *Cluster) Primary() *sqlx.DB {
+	return c.primary
+}
+
+// Reader takes turns between the healthy replicas.
+func (c *Cluster) Reader(ctx context.Context) *sqlx.DB {
+	if len(c.replicas) == 0 || pgStore.NeedsPrimary(ctx, c.cfg.ReadYourWrites) {
+		return c.primary
+	}
+
+	start := c.next.Add(1)
+
+	for i := range uint64(len(c.replicas)) {
+		r := c.replicas[(start+i)%uint64(len(c.replicas))]
+
+		if r.healthy.Load() {
+			return r.db
+		}
+	}
+
+	return c.primary
+}
+
+// Start checks the replicas every HealthInterval until Close.
+func (c *Cluster) Start(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.started {
+		return ErrClusterStarted
+	}
+
+	c.started = true
+
+	if len(c.replicas) == 0 {
+		return nil
+	}
+
+	c.wg.Go(func() {
+		ticker := time.NewTicker(c.cfg.HealthInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-c.stop:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.check(ctx)
+			}
+		}
+	})
+
+	return nil
+}
+
+func (c *Cluster) check(ctx context.Context) {
+	log := o11y.LoggerFromContext(ctx)
+
+	var wg sync.WaitGroup
+
+	for i, r := range c.replicas {
+		wg.Go(func() {
+			pingCtx, cancel := context.WithTimeout(ctx, c.cfg.HealthTimeout)
+			defer cancel()
+
+			err := r.db.PingContext(pingCtx)
+			healthy := err == nil
+
+			// Only changes are logged, and the first check.
+			changed := r.healthy.Swap(healthy) != healthy || !r.checked
+			r.checked = true
+
+			switch {
+			case !changed:
+			case healthy:
+				log.InfoContext(ctx, "postgres: replica is healthy", "replica", i)
+			default:
+				log.WarnContext(ctx, "postgres: replica is unhealthy, reading from the others", "replica", i, "error", err)
+			}
+		})
+	}
+
+	wg.Wait()
+}
+
+// Close stops the health checks and closes every database.
+func (c *Cluster) Close() error {
+	c.mu.Lock()
+	select {
+	case <-c.stop:
+	default:
+		close(c.stop)
+	}
+	c.mu.Unlock()
+
+	c.wg.Wait()
+
+	errs := []error{c.primary.Close()}
+
+	for _, r := range c.replicas {
+		errs = append(errs, r.db.Close())
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,194 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	sqldriver "database/sql/driver"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"HATCH_APP/pkg/o11y"
+	pgStore "HATCH_APP/pkg/store/postgres"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errDown = errors.New("database down")
+
+// fakeDB is a database whose pings succeed while it is up.
+type fakeDB struct {
+	down atomic.Bool
+}
+
+func (f *fakeDB) Connect(context.Context) (sqldriver.Conn, error) {
+	if f.down.Load() {
+		return nil, errDown
+	}
+
+	return &fakeConn{db: f}, nil
+}
+
+func (f *fakeDB) Driver() sqldriver.Driver {
+	return nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Ping(context.Context) error {
+	if c.db.down.Load() {
+		return sqldriver.ErrBadConn
+	}
+
+	return nil
+}
+
+func (c *fakeConn) Prepare(string) (sqldriver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (sqldriver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+func openFake(t *testing.T, down bool) (*sqlx.DB, *fakeDB) {
+	t.Helper()
+
+	fake := &fakeDB{}
+	fake.down.Store(down)
+
+	db := sqlx.NewDb(sql.OpenDB(fake), driver)
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db, fake
+}
+
+// setupCluster checks the replicas once, as ConnectCluster does. down lists
+// whether each replica starts down.
+func setupCluster(t *testing.T, cfg ClusterConfig, down ...bool) (*Cluster, []*sqlx.DB, []*fakeDB) {
+	t.Helper()
+
+	o11y.InitLogger()
+
+	primary, _ := openFake(t, false)
+	c := newCluster(primary, cfg)
+
+	dbs := make([]*sqlx.DB, 0, len(down))
+	fakes := make([]*fakeDB, 0, len(down))
+
+	for _, d := range down {
+		db, fake := openFake(t, d)
+		c.addReplica(db)
+
+		dbs = append(dbs, db)
+		fakes = append(fakes, fake)
+	}
+
+	c.check(t.Context())
+
+	return c, dbs, fakes
+}
+
+// readers returns the databases n reads are sent to.
+func readers(ctx context.Context, c *Cluster, n int) []*sqlx.DB {
+	dbs := make([]*sqlx.DB, 0, n)
+
+	for range n {
+		dbs = append(dbs, c.Reader(ctx))
+	}
+
+	return dbs
+}
+
+func TestClusterReader(t *testing.T) {
+	t.Run("should read from the primary without replicas", func(t *testing.T) {
+		c, _, _ := setupCluster(t, ClusterConfig{})
+
+		assert.Same(t, c.Primary(), c.Reader(t.Context()))
+	})
+
+	t.Run("should take turns between the healthy replicas", func(t *testing.T) {
+		c, replicas, _ := setupCluster(t, ClusterConfig{}, false, false)
+
+		got := readers(t.Context(), c, 4)
+
+		assert.NotSame(t, got[0], got[1])
+		assert.Same(t, got[0], got[2])
+		assert.Same(t, got[1], got[3])
+		assert.ElementsMatch(t, replicas, got[:2])
+	})
+
+	t.Run("should skip the unhealthy replicas", func(t *testing.T) {
+		c, replicas, _ := setupCluster(t, ClusterConfig{}, true, false)
+
+		for _, db := range readers(t.Context(), c, 4) {
+			assert.Same(t, replicas[1], db)
+		}
+	})
+
+	t.Run("should fall back to the primary when no replica is healthy", func(t *testing.T) {
+		c, _, _ := setupCluster(t, ClusterConfig{}, true, true)
+
+		for _, db := range readers(t.Context(), c, 4) {
+			assert.Same(t, c.Primary(), db)
+		}
+	})
+
+	t.Run("should read from the primary once the session wrote", func(t *testing.T) {
+		c, replicas, _ := setupCluster(t, ClusterConfig{ReadYourWrites: true}, false)
+		ctx := pgStore.WithSession(t.Context())
+
+		assert.Same(t, replicas[0], c.Reader(ctx))
+
+		pgStore.MarkWrite(ctx)
+
+		assert.Same(t, c.Primary(), c.Reader(ctx))
+	})
+}
+
+func TestClusterStart(t *testing.T) {
+	t.Run("should route around a replica while it is down and use it again once up", func(t *testing.T) {
+		c, replicas, fakes := setupCluster(t, ClusterConfig{HealthInterval: 10 * time.Millisecond}, false, false)
+
+		require.NoError(t, c.Start(t.Context()))
+		t.Cleanup(func() { _ = c.Close() })
+
+		require.ErrorIs(t, c.Start(t.Context()), ErrClusterStarted)
+
+		fakes[0].down.Store(true)
+
+		assert.Eventually(t, func() bool {
+			for _, db := range readers(t.Context(), c, 2) {
+				if db != replicas[1] {
+					return false
+				}
+			}
+
+			return true
+		}, 5*time.Second, 10*time.Millisecond)
+
+		fakes[0].down.Store(false)
+
+		assert.Eventually(t, func() bool {
+			return len(uniq(readers(t.Context(), c, 2))) == 2
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+}
+
+func uniq(dbs []*sqlx.DB) map[*sqlx.DB]struct{} {
+	set := make(map[*sqlx.DB]struct{}, len(dbs))
+	for _, db := range dbs {
+		set[db] = struct{}{}
+	}
+
+	return set
+}
@@ -7,12 +7,18 @@ import (
 type ErrorType string
 
 const (
-	TypeNotFound         = "NOT_FOUND"
-	TypeInternal         = "INTERNAL"
-	TypeValidation       = "VALIDATION"
-	TypeConflict         = "CONFLICT"
-	TypeInvalidOperation = "INVALID_OPERATION"
-	TypeUnauthorized     = "UNAUTHORIZED"
+	TypeNotFound           = "NOT_FOUND"
+	TypeInternal           = "INTERNAL"
+	TypeValidation         = "VALIDATION"
+	TypeConflict           = "CONFLICT"
+	TypeInvalidOperation   = "INVALID_OPERATION"
+	TypeUnauthorized       = "UNAUTHORIZED"
+	TypeForbidden          = "FORBIDDEN"
+	TypeRateLimited        = "RATE_LIMITED"
+	TypeUnavailable        = "UNAVAILABLE"
+	TypeTimeout            = "TIMEOUT"
+	TypePreconditionFailed = "PRECONDITION_FAILED"
+	TypePayloadTooLarge    = "PAYLOAD_TOO_LARGE"
 )
 
 type Error struct {
@@ -87,6 +93,54 @@ func IsUnauthorized(err error) bool {
 	return IsType(err, TypeUnauthorized)
 }
 
+func Forbidden(message string) *Error {
+	return New(TypeForbidden, message, nil)
+}
+
+func IsForbidden(err error) bool {
+	return IsType(err, TypeForbidden)
+}
+
+func RateLimited(message string) *Error {
+	return New(TypeRateLimited, message, nil)
+}
+
+func IsRateLimited(err error) bool {
+	return IsType(err, TypeRateLimited)
+}
+
+func Unavailable(message string, err error) *Error {
+	return New(TypeUnavailable, message, err)
+}
+
+func IsUnavailable(err error) bool {
+	return IsType(err, TypeUnavailable)
+}
+
+func Timeout(message string, err error) *Error {
+	return New(TypeTimeout, message, err)
+}
+
+func IsTimeout(err error) bool {
+	return IsType(err, TypeTimeout)
+}
+
+func PreconditionFailed(message string) *Error {
+	return New(TypePreconditionFailed, message, nil)
+}
+
+func IsPreconditionFailed(err error) bool {
+	return IsType(err, TypePreconditionFailed)
+}
+
+func PayloadTooLarge(message string) *Error {
+	return New(TypePayloadTooLarge, message, nil)
+}
+
+func IsPayloadTooLarge(err error) bool {
+	return IsType(err, TypePayloadTooLarge)
+}
+
 func (e *Error) WithDetails(details any) *Error {
 	e.Details = details
 	return e
@@ -101,3 +155,8 @@ func IsType(err error, t ErrorType) bool {
 	var e *Error
 	return errors.As(err, &e) && e.Type == t
 }
+
+func HasCode(err error, code string) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Code == code
+}
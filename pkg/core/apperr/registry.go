@@ -0,0 +1,107 @@
+package apperr
+
+import (
+	"errors"
+	"slices"
+	"sync"
+)
+
+type Code struct {
+	ID      string
+	Type    ErrorType
+	Message string
+	Doc     string
+}
+
+func (c Code) New() *Error {
+	return New(c.Type, c.Message, nil).WithCode(c.ID)
+}
+
+func (c Code) Wrap(err error) *Error {
+	return New(c.Type, c.Message, err).WithCode(c.ID)
+}
+
+// Propagate keeps client-facing app errors (e.g. a conflict translated by the
+// store) and wraps anything else into c.
+func (c Code) Propagate(err error) *Error {
+	if appErr, ok := errors.AsType[*Error](err); ok && appErr.Type != TypeInternal {
+		return appErr
+	}
+
+	return c.Wrap(err)
+}
+
+func (c Code) WithMessage(message string) *Error {
+	return New(c.Type, message, nil).WithCode(c.ID)
+}
+
+func (c Code) Is(err error) bool {
+	return HasCode(err, c.ID)
+}
+
+type Registry struct {
+	module string
+	codes  []Code
+	mu     sync.RWMutex
+}
+
+var (
+	registries   []*Registry
+	registriesMu sync.RWMutex
+)
+
+func NewRegistry(module string) *Registry {
+	r := &Registry{module: module}
+
+	registriesMu.Lock()
+	registries = append(registries, r)
+	registriesMu.Unlock()
+
+	return r
+}
+
+func Registries() []*Registry {
+	registriesMu.RLock()
+	defer registriesMu.RUnlock()
+
+	return slices.Clone(registries)
+}
+
+func (r *Registry) Register(id string, t ErrorType, message, doc string) Code {
+	c := Code{
+		ID:      id,
+		Type:    t,
+		Message: message,
+		Doc:     doc,
+	}
+
+	r.mu.Lock()
+	r.codes = append(r.codes, c)
+	r.mu.Unlock()
+
+	return c
+}
+
+func (r *Registry) Module() string {
+	return r.module
+}
+
+func (r *Registry) Codes() []Code {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return slices.Clone(r.codes)
+}
+
+func (r *Registry) Lookup(id string) (Code, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, c := range r.codes {
+		if c.ID == id {
+			return c, true
+		}
+	}
+
+	return Code{}, false
+}
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the time, so code depending on it can be tested with a fixed
+// or fake one.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+type fixedClock time.Time
+
+// FixedClock always returns t.
+func FixedClock(t time.Time) Clock {
+	return fixedClock(t)
+}
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+// FakeClock only moves when told to, and by step on every Now call when
+// step is set.
+type FakeClock struct {
+	now  time.Time
+	step time.Duration
+	mu   sync.Mutex
+}
+
+func NewFakeClock(start time.Time, step time.Duration) *FakeClock {
+	return &FakeClock{now: start, step: step}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now
+	c.now = c.now.Add(c.step)
+
+	return now
+}
+
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = t
+}
@@ -0,0 +1,41 @@
+package core_test
+
+import (
+	"HATCH_APP/pkg/core"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixedClock(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := core.FixedClock(now)
+
+	assert.Equal(t, now, clock.Now())
+	assert.Equal(t, now, clock.Now())
+}
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should only move when advanced", func(t *testing.T) {
+		clock := core.NewFakeClock(start, 0)
+
+		assert.Equal(t, start, clock.Now())
+
+		clock.Advance(time.Hour)
+		assert.Equal(t, start.Add(time.Hour), clock.Now())
+
+		clock.Set(start)
+		assert.Equal(t, start, clock.Now())
+	})
+
+	t.Run("should step on every call", func(t *testing.T) {
+		clock := core.NewFakeClock(start, time.Second)
+
+		assert.Equal(t, start, clock.Now())
+		assert.Equal(t, start.Add(time.Second), clock.Now())
+		assert.Equal(t, start.Add(2*time.Second), clock.Now())
+	})
+}
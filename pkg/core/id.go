@@ -1,7 +1,116 @@
 package core
 
-import "github.com/oklog/ulid/v2"
+import (
+	"HATCH_APP/pkg/core/apperr"
+	"database/sql/driver"
+	"fmt"
+	"time"
 
-func NewID() string {
-	return ulid.Make().String()
+	"github.com/oklog/ulid/v2"
+)
+
+var Codes = apperr.NewRegistry("CORE")
+
+var ErrInvalidID = Codes.Register("CORE_INVALID_ID", apperr.TypeValidation,
+	"invalid id",
+	"The id is not a ULID: 26 Crockford base32 characters.")
+
+// ID identifies entities with a ULID, which sorts by creation time. The zero
+// value is the empty id.
+type ID struct {
+	ulid ulid.ULID
+}
+
+var defaultIDs = NewULIDGenerator(SystemClock())
+
+// NewID issues an id from the system clock, code that needs deterministic ids
+// takes an IDGenerator instead.
+func NewID() ID {
+	return defaultIDs.NewID()
+}
+
+// ParseID accepts ULIDs in any case and rejects anything else, including the
+// empty string, with ErrInvalidID.
+func ParseID(s string) (ID, error) {
+	u, err := ulid.ParseStrict(s)
+	if err != nil {
+		return ID{}, ErrInvalidID.Wrap(err).WithDetails(map[string]string{"id": s})
+	}
+
+	return ID{ulid: u}, nil
+}
+
+// MustParseID is ParseID for ids known to be valid, e.g. in tests.
+func MustParseID(s string) ID {
+	id, err := ParseID(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return id
+}
+
+func IsValidID(s string) bool {
+	_, err := ulid.ParseStrict(s)
+	return err == nil
+}
+
+func (id ID) String() string {
+	if id.IsZero() {
+		return ""
+	}
+
+	return id.ulid.String()
+}
+
+func (id ID) IsZero() bool {
+	return id.ulid.IsZero()
+}
+
+// Time is the creation time encoded in the id, with millisecond precision.
+func (id ID) Time() time.Time {
+	return ulid.Time(id.ulid.Time())
+}
+
+func (id ID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+func (id *ID) UnmarshalText(b []byte) error {
+	if len(b) == 0 {
+		*id = ID{}
+		return nil
+	}
+
+	parsed, err := ParseID(string(b))
+	if err != nil {
+		return err
+	}
+
+	*id = parsed
+
+	return nil
+}
+
+func (id *ID) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*id = ID{}
+		return nil
+	case string:
+		return id.UnmarshalText([]byte(v))
+	case []byte:
+		return id.UnmarshalText(v)
+	default:
+		return fmt.Errorf("core: cannot scan %T into ID", src)
+	}
+}
+
+// Value stores the zero id as NULL.
+func (id ID) Value() (driver.Value, error) {
+	if id.IsZero() {
+		return nil, nil
+	}
+
+	return id.String(), nil
 }
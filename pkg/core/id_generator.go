@@ -0,0 +1,79 @@
+package core
+
+import (
+	"crypto/rand"
+	"io"
+	"sync"
+
+	"github.com/oklog/ulid/v2"
+)
+
+type IDGenerator interface {
+	NewID() ID
+}
+
+// ULIDGenerator issues ULIDs stamped by its clock. IDs issued within the
+// same millisecond increment the previous entropy, so they stay sorted.
+type ULIDGenerator struct {
+	clock   Clock
+	entropy io.Reader
+	mu      sync.Mutex
+}
+
+func NewULIDGenerator(clock Clock) *ULIDGenerator {
+	return &ULIDGenerator{
+		clock:   clock,
+		entropy: ulid.Monotonic(rand.Reader, 0),
+	}
+}
+
+// NewSequenceGenerator is a ULIDGenerator with zeroed starting entropy, so
+// with a fake clock the issued ids are the same on every run.
+func NewSequenceGenerator(clock Clock) *ULIDGenerator {
+	return &ULIDGenerator{
+		clock:   clock,
+		entropy: ulid.Monotonic(zeroReader{}, 0),
+	}
+}
+
+func (g *ULIDGenerator) NewID() ID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return ID{ulid: ulid.MustNew(ulid.Timestamp(g.clock.Now()), g.entropy)}
+}
+
+type fixedIDs struct {
+	ids  []ID
+	next int
+	mu   sync.Mutex
+}
+
+// FixedIDs returns ids in order and then keeps returning the last one.
+func FixedIDs(ids ...ID) IDGenerator {
+	if len(ids) == 0 {
+		panic("core: FixedIDs needs at least one id")
+	}
+
+	return &fixedIDs{ids: ids}
+}
+
+func (g *fixedIDs) NewID() ID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	id := g.ids[g.next]
+
+	if g.next < len(g.ids)-1 {
+		g.next++
+	}
+
+	return id
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
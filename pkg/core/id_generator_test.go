@@ -0,0 +1,80 @@
+package core_test
+
+import (
+	"HATCH_APP/pkg/core"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestULIDGenerator(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should stamp ids with the clock", func(t *testing.T) {
+		gen := core.NewULIDGenerator(core.FixedClock(now))
+
+		assert.True(t, now.Equal(gen.NewID().Time()))
+	})
+
+	t.Run("should stay monotonic within the same millisecond", func(t *testing.T) {
+		gen := core.NewULIDGenerator(core.FixedClock(now))
+
+		prev := gen.NewID()
+
+		for range 1000 {
+			id := gen.NewID()
+			require.Greater(t, id.String(), prev.String())
+			prev = id
+		}
+	})
+
+	t.Run("should not hand out duplicates concurrently", func(t *testing.T) {
+		gen := core.NewULIDGenerator(core.SystemClock())
+
+		var (
+			seen = make(map[core.ID]struct{})
+			mu   sync.Mutex
+			wg   sync.WaitGroup
+		)
+
+		for range 8 {
+			wg.Go(func() {
+				for range 500 {
+					id := gen.NewID()
+
+					mu.Lock()
+					seen[id] = struct{}{}
+					mu.Unlock()
+				}
+			})
+		}
+
+		wg.Wait()
+
+		assert.Len(t, seen, 8*500)
+	})
+}
+
+func TestSequenceGenerator(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	a := core.NewSequenceGenerator(core.NewFakeClock(start, 0))
+	b := core.NewSequenceGenerator(core.NewFakeClock(start, 0))
+
+	for range 3 {
+		assert.Equal(t, a.NewID(), b.NewID())
+	}
+}
+
+func TestFixedIDs(t *testing.T) {
+	first, second := core.NewID(), core.NewID()
+
+	gen := core.FixedIDs(first, second)
+
+	assert.Equal(t, first, gen.NewID())
+	assert.Equal(t, second, gen.NewID())
+	assert.Equal(t, second, gen.NewID())
+}
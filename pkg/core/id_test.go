@@ -0,0 +1,107 @@
+package core_test
+
+import (
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/test/apperrtest"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodes(t *testing.T) {
+	apperrtest.AssertRegistries(t, core.Codes)
+}
+
+func TestParseID(t *testing.T) {
+	valid := core.NewID().String()
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "valid", input: valid},
+		{name: "lowercase", input: strings.ToLower(valid)},
+		{name: "empty", input: "", wantErr: true},
+		{name: "too short", input: valid[:25], wantErr: true},
+		{name: "invalid characters", input: "01J9Z3X4Y5Z6A7B8C9D0E1F2GU", wantErr: true},
+		{name: "overflow", input: "8ZZZZZZZZZZZZZZZZZZZZZZZZZ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			id, err := core.ParseID(tc.input)
+
+			if tc.wantErr {
+				require.Error(t, err)
+				assert.True(t, core.ErrInvalidID.Is(err))
+				assert.True(t, id.IsZero())
+				assert.False(t, core.IsValidID(tc.input))
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, valid, id.String())
+		})
+	}
+}
+
+func TestIDTime(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+	id := core.NewID()
+
+	assert.WithinRange(t, id.Time(), before, time.Now())
+}
+
+func TestIDJSON(t *testing.T) {
+	type payload struct {
+		ID core.ID `json:"id"`
+	}
+
+	id := core.NewID()
+
+	body, err := json.Marshal(payload{ID: id})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"`+id.String()+`"}`, string(body))
+
+	var got payload
+	require.NoError(t, json.Unmarshal(body, &got))
+	assert.Equal(t, id, got.ID)
+
+	body, err = json.Marshal(payload{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":""}`, string(body))
+
+	require.Error(t, json.Unmarshal([]byte(`{"id":"nope"}`), &got))
+}
+
+func TestIDSQL(t *testing.T) {
+	id := core.NewID()
+
+	value, err := id.Value()
+	require.NoError(t, err)
+	assert.Equal(t, id.String(), value)
+
+	value, err = core.ID{}.Value()
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	var scanned core.ID
+
+	require.NoError(t, scanned.Scan(id.String()))
+	assert.Equal(t, id, scanned)
+
+	require.NoError(t, scanned.Scan([]byte(id.String())))
+	assert.Equal(t, id, scanned)
+
+	require.NoError(t, scanned.Scan(nil))
+	assert.True(t, scanned.IsZero())
+
+	require.Error(t, scanned.Scan(42))
+}
@@ -0,0 +1,158 @@
+package diff
+
+import (
+	"slices"
+	"strings"
+)
+
+type Op string
+
+const (
+	Equal  Op = "equal"
+	Insert Op = "insert"
+	Delete Op = "delete"
+)
+
+// Enum lists the ops for the API schema.
+func (Op) Enum() []any {
+	return []any{Equal, Insert, Delete}
+}
+
+type Line struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// MaxEdits bounds the work of the Myers search. Inputs differing in more
+// lines than that are diffed as their common prefix and suffix around one
+// block of deletions and insertions.
+const MaxEdits = 1000
+
+// Lines diffs a and b line by line, producing a shortest edit script.
+func Lines(a, b string) []Line {
+	return Strings(splitLines(a), splitLines(b))
+}
+
+func Strings(a, b []string) []Line {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]Line, 0, len(a)+len(b)-prefix-suffix)
+	lines = appendOps(lines, Equal, a[:prefix])
+	lines = append(lines, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	lines = appendOps(lines, Equal, a[len(a)-suffix:])
+
+	return lines
+}
+
+func myers(a, b []string) []Line {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return appendOps(appendOps(nil, Delete, a), Insert, b)
+	}
+
+	maxD := min(n+m, MaxEdits)
+	offset := maxD + 1
+	v := make([]int, 2*offset+1)
+
+	// trace[d] holds the furthest x reached on diagonals -d..d before step d.
+	var trace [][]int
+
+	for d := 0; d <= maxD; d++ {
+		trace = append(trace, slices.Clone(v[offset-d:offset+d+1]))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				return backtrack(trace, a, b)
+			}
+		}
+	}
+
+	return appendOps(appendOps(nil, Delete, a), Insert, b)
+}
+
+func backtrack(trace [][]int, a, b []string) []Line {
+	x, y := len(a), len(b)
+
+	var reversed []Line
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d] }
+
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+
+		prevX := 0
+		if d > 0 {
+			prevX = at(prevK)
+		}
+
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			reversed = append(reversed, Line{Op: Equal, Text: a[x-1]})
+			x--
+			y--
+		}
+
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, Line{Op: Insert, Text: b[y-1]})
+			} else {
+				reversed = append(reversed, Line{Op: Delete, Text: a[x-1]})
+			}
+		}
+
+		x, y = prevX, prevY
+	}
+
+	slices.Reverse(reversed)
+
+	return reversed
+}
+
+func appendOps(lines []Line, op Op, texts []string) []Line {
+	for _, t := range texts {
+		lines = append(lines, Line{Op: op, Text: t})
+	}
+
+	return lines
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
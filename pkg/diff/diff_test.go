@@ -0,0 +1,105 @@
+package diff_test
+
+import (
+	"HATCH_APP/pkg/diff"
+	"math/rand/v2"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []diff.Line
+	}{
+		{
+			name: "should report equal texts",
+			a:    "a\nb\n",
+			b:    "a\nb",
+			want: []diff.Line{{Op: diff.Equal, Text: "a"}, {Op: diff.Equal, Text: "b"}},
+		},
+		{
+			name: "should diff from empty",
+			a:    "",
+			b:    "a\nb",
+			want: []diff.Line{{Op: diff.Insert, Text: "a"}, {Op: diff.Insert, Text: "b"}},
+		},
+		{
+			name: "should find a changed line between common ones",
+			a:    "title\nold line\nend",
+			b:    "title\nnew line\nend",
+			want: []diff.Line{
+				{Op: diff.Equal, Text: "title"},
+				{Op: diff.Delete, Text: "old line"},
+				{Op: diff.Insert, Text: "new line"},
+				{Op: diff.Equal, Text: "end"},
+			},
+		},
+		{
+			name: "should produce a shortest edit script",
+			a:    "a\nb\nc\na\nb\nb\na",
+			b:    "c\nb\na\nb\na\nc",
+			want: []diff.Line{
+				{Op: diff.Delete, Text: "a"},
+				{Op: diff.Delete, Text: "b"},
+				{Op: diff.Equal, Text: "c"},
+				{Op: diff.Insert, Text: "b"},
+				{Op: diff.Equal, Text: "a"},
+				{Op: diff.Equal, Text: "b"},
+				{Op: diff.Delete, Text: "b"},
+				{Op: diff.Equal, Text: "a"},
+				{Op: diff.Insert, Text: "c"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, diff.Lines(tc.a, tc.b))
+		})
+	}
+}
+
+func TestStringsRoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+
+	random := func() []string {
+		lines := make([]string, rnd.IntN(40))
+		for i := range lines {
+			lines[i] = strconv.Itoa(rnd.IntN(5))
+		}
+
+		return lines
+	}
+
+	for range 200 {
+		a, b := random(), random()
+
+		var gotA, gotB []string
+
+		for _, l := range diff.Strings(a, b) {
+			if l.Op != diff.Insert {
+				gotA = append(gotA, l.Text)
+			}
+
+			if l.Op != diff.Delete {
+				gotB = append(gotB, l.Text)
+			}
+		}
+
+		assert.Equal(t, len(a), len(gotA))
+		assert.Equal(t, len(b), len(gotB))
+
+		if len(a) > 0 {
+			assert.Equal(t, a, gotA)
+		}
+
+		if len(b) > 0 {
+			assert.Equal(t, b, gotB)
+		}
+	}
+}
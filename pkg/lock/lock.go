@@ -0,0 +1,63 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotAcquired = errors.New("lock not acquired")
+	ErrNotHeld     = errors.New("lock not held")
+)
+
+// Locker hands out mutually exclusive locks by key. The context returned on
+// acquisition is canceled once the lock is released, lost or its ttl expires,
+// and the lock is released automatically when the parent context is done.
+// A zero ttl holds the lock until it is released.
+type Locker interface {
+	Acquire(ctx context.Context, key string, ttl time.Duration) (context.Context, error)
+	// TryAcquire returns ErrNotAcquired instead of waiting when the lock is held.
+	TryAcquire(ctx context.Context, key string, ttl time.Duration) (context.Context, error)
+	Release(ctx context.Context, key string) error
+}
+
+// WithTTL derives the context handed to lock holders.
+func WithTTL(ctx context.Context, ttl time.Duration) (context.Context, context.CancelFunc) {
+	if ttl > 0 {
+		return context.WithTimeout(ctx, ttl)
+	}
+
+	return context.WithCancel(ctx)
+}
+
+// With runs fn while holding the lock, waiting for it if needed.
+func With(ctx context.Context, l Locker, key string, ttl time.Duration, fn func(ctx context.Context) error) error {
+	lockCtx, err := l.Acquire(ctx, key, ttl)
+	if err != nil {
+		return err
+	}
+
+	return run(ctx, l, key, lockCtx, fn)
+}
+
+// TryWith runs fn only if the lock is free, returning ErrNotAcquired otherwise.
+func TryWith(ctx context.Context, l Locker, key string, ttl time.Duration, fn func(ctx context.Context) error) error {
+	lockCtx, err := l.TryAcquire(ctx, key, ttl)
+	if err != nil {
+		return err
+	}
+
+	return run(ctx, l, key, lockCtx, fn)
+}
+
+func run(ctx context.Context, l Locker, key string, lockCtx context.Context, fn func(ctx context.Context) error) error {
+	err := fn(lockCtx)
+
+	releaseErr := l.Release(context.WithoutCancel(ctx), key)
+	if errors.Is(releaseErr, ErrNotHeld) {
+		releaseErr = nil
+	}
+
+	return errors.Join(err, releaseErr)
+}
@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"HATCH_APP/pkg/lock"
+	"context"
+	"sync"
+	"time"
+)
+
+type held struct {
+	cancel   context.CancelFunc
+	released chan struct{}
+}
+
+// Locker is an in-process lock.Locker for tests and single replica setups.
+type Locker struct {
+	locks map[string]*held
+	mu    sync.Mutex
+}
+
+func NewLocker() *Locker {
+	return &Locker{
+		locks: make(map[string]*held),
+	}
+}
+
+func (l *Locker) Acquire(ctx context.Context, key string, ttl time.Duration) (context.Context, error) {
+	for {
+		lockCtx, err := l.TryAcquire(ctx, key, ttl)
+		if err == nil {
+			return lockCtx, nil
+		}
+
+		l.mu.Lock()
+		h, ok := l.locks[key]
+		l.mu.Unlock()
+
+		if !ok {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-h.released:
+		}
+	}
+}
+
+func (l *Locker) TryAcquire(ctx context.Context, key string, ttl time.Duration) (context.Context, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.locks[key]; ok {
+		return nil, lock.ErrNotAcquired
+	}
+
+	lockCtx, cancel := lock.WithTTL(ctx, ttl)
+
+	h := &held{
+		cancel:   cancel,
+		released: make(chan struct{}),
+	}
+
+	l.locks[key] = h
+
+	go func() {
+		<-lockCtx.Done()
+		l.release(key, h)
+	}()
+
+	return lockCtx, nil
+}
+
+func (l *Locker) Release(_ context.Context, key string) error {
+	l.mu.Lock()
+	h, ok := l.locks[key]
+	l.mu.Unlock()
+
+	if !ok {
+		return lock.ErrNotHeld
+	}
+
+	l.release(key, h)
+
+	return nil
+}
+
+func (l *Locker) release(key string, h *held) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.locks[key] != h {
+		return
+	}
+
+	delete(l.locks, key)
+	h.cancel()
+	close(h.released)
+}
@@ -0,0 +1,14 @@
+package memory_test
+
+import (
+	"HATCH_APP/pkg/lock"
+	"HATCH_APP/pkg/lock/memory"
+	"HATCH_APP/test/locktest"
+	"testing"
+)
+
+func TestLocker(t *testing.T) {
+	locktest.Run(t, func(*testing.T) lock.Locker {
+		return memory.NewLocker()
+	})
+}
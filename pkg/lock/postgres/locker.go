@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"HATCH_APP/pkg/lock"
+	"HATCH_APP/pkg/store/postgres"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const defaultHealthInterval = 5 * time.Second
+
+type held struct {
+	conn   *sql.Conn
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Locker implements lock.Locker with session level advisory locks on hashed
+// keys. Each lock is held on its own connection, so losing that connection
+// releases the lock in Postgres and cancels the lock context here.
+type Locker struct {
+	db             *sqlx.DB
+	locks          map[string]*held
+	healthInterval time.Duration
+	mu             sync.Mutex
+}
+
+func NewLocker(db *sqlx.DB) *Locker {
+	return &Locker{
+		db:             db,
+		locks:          make(map[string]*held),
+		healthInterval: defaultHealthInterval,
+	}
+}
+
+func (l *Locker) Acquire(ctx context.Context, key string, ttl time.Duration) (context.Context, error) {
+	return l.acquire(ctx, key, ttl, true)
+}
+
+func (l *Locker) TryAcquire(ctx context.Context, key string, ttl time.Duration) (context.Context, error) {
+	return l.acquire(ctx, key, ttl, false)
+}
+
+func (l *Locker) Release(ctx context.Context, key string) error {
+	l.mu.Lock()
+	h, ok := l.locks[key]
+	if ok {
+		delete(l.locks, key)
+	}
+	l.mu.Unlock()
+
+	if !ok {
+		return lock.ErrNotHeld
+	}
+
+	h.cancel()
+	<-h.done
+
+	return unlock(ctx, h.conn, key)
+}
+
+func (l *Locker) acquire(ctx context.Context, key string, ttl time.Duration, wait bool) (context.Context, error) {
+	l.mu.Lock()
+	_, taken := l.locks[key]
+	l.mu.Unlock()
+
+	if taken && !wait {
+		return nil, lock.ErrNotAcquired
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, postgres.TranslateError(err)
+	}
+
+	acquired := true
+
+	if wait {
+		_, err = conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, postgres.AdvisoryLockKey(key))
+	} else {
+		err = conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, postgres.AdvisoryLockKey(key)).
+			Scan(&acquired)
+	}
+
+	if err != nil {
+		return nil, errors.Join(postgres.TranslateError(err), discard(conn))
+	}
+
+	if !acquired {
+		return nil, errors.Join(lock.ErrNotAcquired, conn.Close())
+	}
+
+	lockCtx, cancel := lock.WithTTL(ctx, ttl)
+
+	h := &held{
+		conn:   conn,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	l.mu.Lock()
+	l.locks[key] = h
+	l.mu.Unlock()
+
+	go l.watch(lockCtx, key, h)
+
+	return lockCtx, nil
+}
+
+// watch cancels the lock context when the connection dies and releases the
+// lock once the context is done, whatever the reason.
+func (l *Locker) watch(ctx context.Context, key string, h *held) {
+	defer close(h.done)
+
+	ticker := time.NewTicker(l.healthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			l.mu.Lock()
+			current := l.locks[key] == h
+			if current {
+				delete(l.locks, key)
+			}
+			l.mu.Unlock()
+
+			if current {
+				_ = unlock(context.Background(), h.conn, key)
+			}
+
+			return
+		case <-ticker.C:
+			if err := h.conn.PingContext(ctx); err != nil && ctx.Err() == nil {
+				h.cancel()
+			}
+		}
+	}
+}
+
+func unlock(ctx context.Context, conn *sql.Conn, key string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var released bool
+
+	err := conn.QueryRowContext(ctx, `SELECT pg_advisory_unlock($1)`, postgres.AdvisoryLockKey(key)).Scan(&released)
+	if err != nil || !released {
+		// Never hand a connection that may still hold the lock back to the pool.
+		return errors.Join(postgres.TranslateError(err), discard(conn))
+	}
+
+	return conn.Close()
+}
+
+func discard(conn *sql.Conn) error {
+	_ = conn.Raw(func(any) error {
+		return driver.ErrBadConn
+	})
+
+	return conn.Close()
+}
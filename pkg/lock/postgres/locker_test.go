@@ -0,0 +1,38 @@
+package postgres_test
+
+import (
+	"HATCH_APP/pkg/lock"
+	"HATCH_APP/pkg/lock/postgres"
+	"HATCH_APP/test/container"
+	"HATCH_APP/test/locktest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockerIntegration(t *testing.T) {
+	db, teardown := container.SetupPostgres(t)
+	t.Cleanup(teardown)
+
+	locktest.Run(t, func(*testing.T) lock.Locker {
+		return postgres.NewLocker(db)
+	})
+
+	t.Run("should exclude lockers on other replicas", func(t *testing.T) {
+		a := postgres.NewLocker(db)
+		b := postgres.NewLocker(db)
+
+		_, err := a.TryAcquire(t.Context(), "replicas", 0)
+		require.NoError(t, err)
+
+		_, err = b.TryAcquire(t.Context(), "replicas", 0)
+		require.ErrorIs(t, err, lock.ErrNotAcquired)
+
+		require.NoError(t, a.Release(t.Context(), "replicas"))
+
+		_, err = b.TryAcquire(t.Context(), "replicas", 0)
+		require.NoError(t, err)
+
+		require.NoError(t, b.Release(t.Context(), "replicas"))
+	})
+}
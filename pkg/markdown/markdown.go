@@ -0,0 +1,172 @@
+package markdown
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// Version identifies the rendering rules, cached documents must be keyed by
+// it so a policy change is not served stale.
+const Version = "1"
+
+type Heading struct {
+	ID    string `json:"id"`
+	Text  string `json:"text"`
+	Level int    `json:"level"`
+}
+
+// Document is sanitized HTML along with metadata extracted from the source.
+type Document struct {
+	HTML      string    `json:"html"`
+	Headings  []Heading `json:"headings"`
+	WordCount int       `json:"word_count"`
+}
+
+// Renderer turns markdown into HTML safe to embed in a page. Raw HTML in the
+// source is dropped and the output goes through an allowlist policy that
+// strips scripts and event handlers and marks links rel=nofollow.
+type Renderer struct {
+	md     goldmark.Markdown
+	policy *bluemonday.Policy
+}
+
+var headingID = regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)
+
+func NewRenderer() *Renderer {
+	policy := bluemonday.UGCPolicy()
+	policy.RequireNoFollowOnLinks(true)
+	policy.AllowAttrs("id").Matching(headingID).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+
+	return &Renderer{
+		md: goldmark.New(
+			goldmark.WithExtensions(extension.GFM),
+			goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		),
+		policy: policy,
+	}
+}
+
+func (r *Renderer) Markdown(source string) (Document, error) {
+	src := []byte(source)
+	doc := r.md.Parser().Parse(text.NewReader(src))
+
+	var out bytes.Buffer
+	if err := r.md.Renderer().Render(&out, src, doc); err != nil {
+		return Document{}, err
+	}
+
+	headings, words := inspect(doc, src)
+
+	return Document{
+		HTML:      r.policy.Sanitize(out.String()),
+		Headings:  headings,
+		WordCount: words,
+	}, nil
+}
+
+// Plain escapes source, turning blank lines into paragraphs and single line
+// breaks into <br>.
+func (r *Renderer) Plain(source string) Document {
+	var out strings.Builder
+
+	for paragraph := range strings.SplitSeq(strings.ReplaceAll(source, "\r\n", "\n"), "\n\n") {
+		paragraph = strings.Trim(paragraph, "\n")
+		if strings.TrimSpace(paragraph) == "" {
+			continue
+		}
+
+		out.WriteString("<p>")
+		out.WriteString(strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>\n"))
+		out.WriteString("</p>\n")
+	}
+
+	return Document{
+		HTML:      out.String(),
+		Headings:  []Heading{},
+		WordCount: len(strings.Fields(source)),
+	}
+}
+
+// inspect collects the headings and counts the words a reader sees, markup
+// excluded.
+func inspect(doc ast.Node, src []byte) ([]Heading, int) {
+	headings := []Heading{}
+
+	var words strings.Builder
+
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		if n.Type() == ast.TypeBlock {
+			words.WriteByte(' ')
+		}
+
+		switch node := n.(type) {
+		case *ast.Heading:
+			id, _ := node.AttributeString("id")
+			idBytes, _ := id.([]byte)
+
+			headings = append(headings, Heading{
+				Level: node.Level,
+				Text:  inlineText(node, src),
+				ID:    string(idBytes),
+			})
+		case *ast.Text:
+			words.Write(node.Segment.Value(src))
+
+			if node.SoftLineBreak() || node.HardLineBreak() {
+				words.WriteByte(' ')
+			}
+		case *ast.String:
+			words.Write(node.Value)
+		case *ast.AutoLink:
+			words.Write(node.Label(src))
+		case *ast.CodeBlock, *ast.FencedCodeBlock:
+			lines := n.Lines()
+			for i := range lines.Len() {
+				segment := lines.At(i)
+				words.Write(segment.Value(src))
+			}
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return headings, len(strings.Fields(words.String()))
+}
+
+func inlineText(n ast.Node, src []byte) string {
+	var b strings.Builder
+
+	_ = ast.Walk(n, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch node := n.(type) {
+		case *ast.Text:
+			b.Write(node.Segment.Value(src))
+
+			if node.SoftLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(node.Value)
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return strings.TrimSpace(b.String())
+}
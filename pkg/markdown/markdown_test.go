@@ -0,0 +1,63 @@
+package markdown_test
+
+import (
+	"HATCH_APP/pkg/markdown"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRendererMarkdown(t *testing.T) {
+	r := markdown.NewRenderer()
+
+	t.Run("should render headings with metadata", func(t *testing.T) {
+		doc, err := r.Markdown("# Hello *World*\n\nSome `code` and a list:\n\n- one\n- two\n\n## Next step\n\n```\nfmt.Println(1)\n```\n")
+
+		require.NoError(t, err)
+		assert.Contains(t, doc.HTML, `<h1 id="hello-world">Hello <em>World</em></h1>`)
+		assert.Contains(t, doc.HTML, "<li>one</li>")
+		assert.Equal(t, []markdown.Heading{
+			{Level: 1, Text: "Hello World", ID: "hello-world"},
+			{Level: 2, Text: "Next step", ID: "next-step"},
+		}, doc.Headings)
+		assert.Equal(t, 12, doc.WordCount)
+	})
+
+	t.Run("should add nofollow to links", func(t *testing.T) {
+		doc, err := r.Markdown("[site](https://example.com) and https://example.org")
+
+		require.NoError(t, err)
+		assert.Contains(t, doc.HTML, `<a href="https://example.com" rel="nofollow">site</a>`)
+		assert.Contains(t, doc.HTML, `<a href="https://example.org" rel="nofollow">https://example.org</a>`)
+	})
+
+	tests := []struct {
+		name    string
+		source  string
+		removed string
+	}{
+		{name: "should strip scripts", source: "hi <script>alert(1)</script>", removed: "<script"},
+		{name: "should strip event handlers", source: `<img src="x.png" onerror="alert(1)">`, removed: "onerror"},
+		{name: "should strip javascript links", source: "[click](javascript:alert(1))", removed: "javascript:"},
+		{name: "should strip iframes", source: "<iframe src=\"https://example.com\"></iframe>", removed: "<iframe"},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := r.Markdown(tc.source)
+
+			require.NoError(t, err)
+			assert.NotContains(t, doc.HTML, tc.removed)
+		})
+	}
+}
+
+func TestRendererPlain(t *testing.T) {
+	doc := markdown.NewRenderer().Plain("# not a heading\n<b>bold</b>\n\n\nsecond paragraph")
+
+	assert.Equal(t, "<p># not a heading<br>\n&lt;b&gt;bold&lt;/b&gt;</p>\n<p>second paragraph</p>\n", doc.HTML)
+	assert.Empty(t, doc.Headings)
+	assert.Equal(t, 7, doc.WordCount)
+}
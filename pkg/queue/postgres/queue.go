@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/store/postgres"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	DefaultQueue       = "default"
+	DefaultMaxAttempts = 10
+)
+
+var (
+	ErrDuplicatedJob = errors.New("job with the same unique key is already enqueued")
+	ErrEncodePayload = errors.New("encode job payload")
+)
+
+type State string
+
+const (
+	StatePending   State = "pending"
+	StateRunning   State = "running"
+	StateSucceeded State = "succeeded"
+	StateDead      State = "dead"
+)
+
+// Kind names a job type and binds it to its payload, so producers and
+// handlers can't disagree on the payload shape.
+type Kind[T any] string
+
+type Job[T any] struct {
+	CreatedAt   time.Time
+	RunAt       time.Time
+	Payload     T
+	ID          string
+	Kind        string
+	Queue       string
+	Attempt     int
+	MaxAttempts int
+}
+
+type enqueueOptions struct {
+	runAt       time.Time
+	queue       string
+	uniqueKey   *string
+	priority    int
+	maxAttempts int
+}
+
+type EnqueueOption func(*enqueueOptions)
+
+func WithQueue(queue string) EnqueueOption {
+	return func(o *enqueueOptions) {
+		o.queue = queue
+	}
+}
+
+// WithPriority sets the job priority, higher values are fetched first.
+func WithPriority(priority int) EnqueueOption {
+	return func(o *enqueueOptions) {
+		o.priority = priority
+	}
+}
+
+func WithRunAt(runAt time.Time) EnqueueOption {
+	return func(o *enqueueOptions) {
+		o.runAt = runAt
+	}
+}
+
+func WithDelay(delay time.Duration) EnqueueOption {
+	return func(o *enqueueOptions) {
+		o.runAt = time.Now().Add(delay)
+	}
+}
+
+func WithMaxAttempts(attempts int) EnqueueOption {
+	return func(o *enqueueOptions) {
+		o.maxAttempts = attempts
+	}
+}
+
+// WithUniqueKey rejects the job with ErrDuplicatedJob while another job of the
+// same kind and key is pending or running.
+func WithUniqueKey(key string) EnqueueOption {
+	return func(o *enqueueOptions) {
+		o.uniqueKey = &key
+	}
+}
+
+const enqueueJob = `INSERT INTO queue_jobs
+	(id, queue, kind, payload, priority, state, attempts, max_attempts, unique_key, run_at, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, 'pending', 0, $6, $7, $8, $9, $9)
+	ON CONFLICT (kind, unique_key) WHERE unique_key IS NOT NULL AND state IN ('pending', 'running')
+	DO NOTHING`
+
+// Enqueue inserts a job using db, which may be a *sqlx.Tx so the job is only
+// visible once the surrounding transaction commits.
+func Enqueue[T any](
+	ctx context.Context,
+	db sqlx.ExtContext,
+	kind Kind[T],
+	payload T,
+	opts ...EnqueueOption,
+) (string, error) {
+	now := time.Now()
+
+	o := enqueueOptions{
+		queue:       DefaultQueue,
+		maxAttempts: DefaultMaxAttempts,
+		runAt:       now,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrEncodePayload, err)
+	}
+
+	id := core.NewID().String()
+
+	res, err := db.ExecContext(ctx, enqueueJob,
+		id,
+		o.queue,
+		string(kind),
+		body,
+		o.priority,
+		o.maxAttempts,
+		o.uniqueKey,
+		o.runAt,
+		now,
+	)
+	if err != nil {
+		return "", postgres.TranslateError(err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return "", postgres.TranslateError(err)
+	}
+
+	if affected == 0 {
+		return "", ErrDuplicatedJob
+	}
+
+	return id, nil
+}
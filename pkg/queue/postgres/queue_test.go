@@ -0,0 +1,283 @@
+package postgres_test
+
+import (
+	"HATCH_APP/pkg/o11y"
+	queue "HATCH_APP/pkg/queue/postgres"
+	"HATCH_APP/pkg/store/postgres"
+	"HATCH_APP/test/container"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type greetPayload struct {
+	Name string `json:"name"`
+}
+
+const greet queue.Kind[greetPayload] = "test.greet"
+
+type jobRow struct {
+	LastError *string `db:"last_error"`
+	State     string  `db:"state"`
+	Attempts  int     `db:"attempts"`
+}
+
+func findJob(t *testing.T, db *sqlx.DB, id string) jobRow {
+	t.Helper()
+
+	var row jobRow
+
+	err := db.GetContext(t.Context(), &row, `SELECT state, attempts, last_error FROM queue_jobs WHERE id = $1`, id)
+	require.NoError(t, err)
+
+	return row
+}
+
+func startWorker(t *testing.T, db *sqlx.DB, cfg queue.Config, h queue.Handler[greetPayload]) *queue.Worker {
+	t.Helper()
+
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = 10 * time.Millisecond
+	}
+
+	w := queue.NewWorker(db, cfg)
+	queue.Handle(w, greet, h)
+
+	require.NoError(t, w.Start(t.Context()))
+
+	t.Cleanup(func() {
+		require.NoError(t, w.Close(context.Background()))
+	})
+
+	return w
+}
+
+func TestQueueIntegration(t *testing.T) {
+	o11y.InitLogger()
+
+	db, teardown := container.SetupPostgres(t)
+	t.Cleanup(teardown)
+
+	t.Run("should deliver typed payloads", func(t *testing.T) {
+		got := make(chan greetPayload, 1)
+
+		id, err := queue.Enqueue(t.Context(), db, greet, greetPayload{Name: "hatch"}, queue.WithQueue("deliver"))
+		require.NoError(t, err)
+
+		startWorker(t, db, queue.Config{Queue: "deliver"}, func(_ context.Context, job *queue.Job[greetPayload]) error {
+			got <- job.Payload
+			return nil
+		})
+
+		select {
+		case p := <-got:
+			assert.Equal(t, "hatch", p.Name)
+		case <-time.After(5 * time.Second):
+			t.Fatal("job was not delivered")
+		}
+
+		assert.Eventually(t, func() bool {
+			return findJob(t, db, id).State == string(queue.StateSucceeded)
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("should fetch higher priority first and respect run at", func(t *testing.T) {
+		_, err := queue.Enqueue(t.Context(), db, greet, greetPayload{Name: "low"},
+			queue.WithQueue("priority"), queue.WithPriority(1))
+		require.NoError(t, err)
+
+		_, err = queue.Enqueue(t.Context(), db, greet, greetPayload{Name: "high"},
+			queue.WithQueue("priority"), queue.WithPriority(10))
+		require.NoError(t, err)
+
+		_, err = queue.Enqueue(t.Context(), db, greet, greetPayload{Name: "later"},
+			queue.WithQueue("priority"), queue.WithPriority(100), queue.WithDelay(time.Hour))
+		require.NoError(t, err)
+
+		var (
+			mu    sync.Mutex
+			order []string
+		)
+
+		startWorker(t, db, queue.Config{Queue: "priority"}, func(_ context.Context, job *queue.Job[greetPayload]) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			order = append(order, job.Payload.Name)
+
+			return nil
+		})
+
+		assert.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+
+			return len(order) == 2
+		}, 5*time.Second, 10*time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		assert.Equal(t, []string{"high", "low"}, order)
+	})
+
+	t.Run("should retry and move to dead letter after max attempts", func(t *testing.T) {
+		id, err := queue.Enqueue(t.Context(), db, greet, greetPayload{Name: "fail"},
+			queue.WithQueue("retry"), queue.WithMaxAttempts(3))
+		require.NoError(t, err)
+
+		startWorker(t, db, queue.Config{
+			Queue:       "retry",
+			BaseBackoff: time.Millisecond,
+			MaxBackoff:  5 * time.Millisecond,
+		}, func(context.Context, *queue.Job[greetPayload]) error {
+			return errors.New("boom")
+		})
+
+		assert.Eventually(t, func() bool {
+			return findJob(t, db, id).State == string(queue.StateDead)
+		}, 5*time.Second, 10*time.Millisecond)
+
+		job := findJob(t, db, id)
+		assert.Equal(t, 3, job.Attempts)
+		require.NotNil(t, job.LastError)
+		assert.Equal(t, "boom", *job.LastError)
+	})
+
+	t.Run("should keep jobs running past stale after while they heartbeat", func(t *testing.T) {
+		id, err := queue.Enqueue(t.Context(), db, greet, greetPayload{}, queue.WithQueue("heartbeat"))
+		require.NoError(t, err)
+
+		var runs atomic.Int32
+
+		startWorker(t, db, queue.Config{
+			Queue:             "heartbeat",
+			StaleAfter:        100 * time.Millisecond,
+			HeartbeatInterval: 20 * time.Millisecond,
+		}, func(ctx context.Context, _ *queue.Job[greetPayload]) error {
+			runs.Add(1)
+
+			select {
+			case <-time.After(400 * time.Millisecond):
+				return nil
+			case <-ctx.Done():
+				return context.Cause(ctx)
+			}
+		})
+
+		assert.Eventually(t, func() bool {
+			return findJob(t, db, id).State == string(queue.StateSucceeded)
+		}, 5*time.Second, 10*time.Millisecond)
+
+		assert.Equal(t, int32(1), runs.Load())
+	})
+
+	t.Run("should cancel a job rescued while it runs", func(t *testing.T) {
+		id, err := queue.Enqueue(t.Context(), db, greet, greetPayload{}, queue.WithQueue("lease"))
+		require.NoError(t, err)
+
+		causes := make(chan error, 2)
+
+		startWorker(t, db, queue.Config{
+			Queue:             "lease",
+			HeartbeatInterval: 20 * time.Millisecond,
+		}, func(ctx context.Context, job *queue.Job[greetPayload]) error {
+			if job.Attempt > 1 {
+				return nil
+			}
+
+			// Rescue the job as another worker would once it is stale.
+			_, err := db.ExecContext(context.WithoutCancel(ctx),
+				`UPDATE queue_jobs SET state = 'pending', locked_at = NULL WHERE id = $1`, job.ID)
+			require.NoError(t, err)
+
+			<-ctx.Done()
+			causes <- context.Cause(ctx)
+
+			return ctx.Err()
+		})
+
+		select {
+		case cause := <-causes:
+			require.ErrorIs(t, cause, queue.ErrLeaseLost)
+		case <-time.After(5 * time.Second):
+			t.Fatal("rescued job was not canceled")
+		}
+
+		assert.Eventually(t, func() bool {
+			return findJob(t, db, id).State == string(queue.StateSucceeded)
+		}, 5*time.Second, 10*time.Millisecond)
+
+		assert.Equal(t, 2, findJob(t, db, id).Attempts)
+	})
+
+	t.Run("should delete finished jobs past their retention", func(t *testing.T) {
+		id, err := queue.Enqueue(t.Context(), db, greet, greetPayload{}, queue.WithQueue("sweep"))
+		require.NoError(t, err)
+
+		startWorker(t, db, queue.Config{
+			Queue:      "sweep",
+			StaleAfter: 50 * time.Millisecond,
+			Retention:  time.Millisecond,
+		}, func(context.Context, *queue.Job[greetPayload]) error {
+			return nil
+		})
+
+		assert.Eventually(t, func() bool {
+			var count int
+			require.NoError(t, db.GetContext(t.Context(), &count, `SELECT COUNT(*) FROM queue_jobs WHERE id = $1`, id))
+
+			return count == 0
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("should reject duplicated unique keys", func(t *testing.T) {
+		_, err := queue.Enqueue(t.Context(), db, greet, greetPayload{}, queue.WithQueue("unique"),
+			queue.WithUniqueKey("note-1"))
+		require.NoError(t, err)
+
+		_, err = queue.Enqueue(t.Context(), db, greet, greetPayload{}, queue.WithQueue("unique"),
+			queue.WithUniqueKey("note-1"))
+		require.ErrorIs(t, err, queue.ErrDuplicatedJob)
+
+		_, err = queue.Enqueue(t.Context(), db, greet, greetPayload{}, queue.WithQueue("unique"),
+			queue.WithUniqueKey("note-2"))
+		require.NoError(t, err)
+	})
+
+	t.Run("should only enqueue when the transaction commits", func(t *testing.T) {
+		var id string
+
+		err := postgres.RunInTx(db, func(tx *sqlx.Tx) error {
+			var err error
+
+			id, err = queue.Enqueue(t.Context(), tx, greet, greetPayload{}, queue.WithQueue("tx"))
+			require.NoError(t, err)
+
+			return errors.New("rollback")
+		})
+		require.Error(t, err)
+
+		var count int
+		require.NoError(t, db.GetContext(t.Context(), &count, `SELECT COUNT(*) FROM queue_jobs WHERE id = $1`, id))
+		assert.Zero(t, count)
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	base := time.Second
+	maxBackoff := 10 * time.Second
+
+	assert.Equal(t, time.Second, queue.Backoff(1, base, maxBackoff))
+	assert.Equal(t, 2*time.Second, queue.Backoff(2, base, maxBackoff))
+	assert.Equal(t, 8*time.Second, queue.Backoff(4, base, maxBackoff))
+	assert.Equal(t, maxBackoff, queue.Backoff(5, base, maxBackoff))
+	assert.Equal(t, maxBackoff, queue.Backoff(200, base, maxBackoff))
+}
@@ -0,0 +1,529 @@
+package postgres
+
+import (
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/store/postgres"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrWorkerStarted = errors.New("worker already started")
+	ErrDecodePayload = errors.New("decode job payload")
+	// ErrLeaseLost cancels a handler whose job was rescued while it ran, the
+	// job was found stale and another attempt may already run it.
+	ErrLeaseLost = errors.New("job lease lost")
+)
+
+type Handler[T any] func(ctx context.Context, job *Job[T]) error
+
+type Config struct {
+	Queue        string
+	Concurrency  int
+	PollInterval time.Duration
+	// StaleAfter is how long a running job may go without a heartbeat
+	// before it is considered abandoned by a crashed worker and put back in
+	// the queue. Running jobs heartbeat every HeartbeatInterval, so they may
+	// run for longer.
+	StaleAfter        time.Duration
+	HeartbeatInterval time.Duration
+	BaseBackoff       time.Duration
+	MaxBackoff        time.Duration
+	// Retention is how long succeeded jobs are kept, DeadRetention how long
+	// dead ones are for inspection, older ones are deleted.
+	Retention     time.Duration
+	DeadRetention time.Duration
+}
+
+type rawJob struct {
+	CreatedAt   time.Time `db:"created_at"`
+	RunAt       time.Time `db:"run_at"`
+	ID          string    `db:"id"`
+	Kind        string    `db:"kind"`
+	Queue       string    `db:"queue"`
+	Payload     []byte    `db:"payload"`
+	Attempt     int       `db:"attempts"`
+	MaxAttempts int       `db:"max_attempts"`
+}
+
+type handlerFunc func(ctx context.Context, job rawJob) error
+
+const (
+	fetchJob = `UPDATE queue_jobs
+		SET state = 'running', attempts = attempts + 1, locked_at = $3, updated_at = $3
+		WHERE id = (
+			SELECT id FROM queue_jobs
+			WHERE queue = $1 AND kind = ANY($2) AND state = 'pending' AND run_at <= $3
+			ORDER BY priority DESC, run_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, queue, kind, payload, attempts, max_attempts, run_at, created_at`
+	// An attempt owns its job while the job runs with its attempt number,
+	// the updates below do nothing once the job was rescued.
+	heartbeatJob = `UPDATE queue_jobs
+		SET locked_at = $3, updated_at = $3
+		WHERE id = $1 AND attempts = $2 AND state = 'running'`
+	completeJob = `UPDATE queue_jobs
+		SET state = 'succeeded', locked_at = NULL, finished_at = $3, updated_at = $3
+		WHERE id = $1 AND attempts = $2 AND state = 'running'`
+	retryJob = `UPDATE queue_jobs
+		SET state = 'pending', locked_at = NULL, last_error = $3, run_at = $4, updated_at = $5
+		WHERE id = $1 AND attempts = $2 AND state = 'running'`
+	buryJob = `UPDATE queue_jobs
+		SET state = 'dead', locked_at = NULL, last_error = $3, finished_at = $4, updated_at = $4
+		WHERE id = $1 AND attempts = $2 AND state = 'running'`
+	rescueJobs = `UPDATE queue_jobs
+		SET state = 'pending', locked_at = NULL, last_error = 'abandoned by worker', updated_at = $2
+		WHERE queue = $1 AND state = 'running' AND locked_at < $3`
+	sweepJobs = `DELETE FROM queue_jobs
+		WHERE id IN (
+			SELECT id FROM queue_jobs
+			WHERE queue = $1 AND (
+				(state = 'succeeded' AND finished_at < $2) OR
+				(state = 'dead' AND finished_at < $3)
+			)
+			LIMIT $4
+		)`
+)
+
+// sweepBatchSize bounds the jobs deleted per statement, so a large backlog
+// of finished jobs is deleted without holding locks for long.
+const sweepBatchSize = 1000
+
+type Worker struct {
+	db       *sqlx.DB
+	handlers map[string]handlerFunc
+	stop     chan struct{}
+	cancel   context.CancelFunc
+	cfg      Config
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	started  bool
+}
+
+func NewWorker(db *sqlx.DB, cfg Config) *Worker {
+	if cfg.Queue == "" {
+		cfg.Queue = DefaultQueue
+	}
+
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+
+	if cfg.StaleAfter <= 0 {
+		cfg.StaleAfter = 2 * time.Minute
+	}
+
+	if cfg.HeartbeatInterval <= 0 || cfg.HeartbeatInterval >= cfg.StaleAfter {
+		cfg.HeartbeatInterval = cfg.StaleAfter / 4
+	}
+
+	if cfg.BaseBackoff <= 0 {
+		cfg.BaseBackoff = time.Second
+	}
+
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = time.Hour
+	}
+
+	if cfg.Retention <= 0 {
+		cfg.Retention = 24 * time.Hour
+	}
+
+	if cfg.DeadRetention <= 0 {
+		cfg.DeadRetention = 7 * 24 * time.Hour
+	}
+
+	return &Worker{
+		db:       db,
+		cfg:      cfg,
+		handlers: make(map[string]handlerFunc),
+		stop:     make(chan struct{}),
+	}
+}
+
+// Handle registers the handler for a job kind. It must be called before Start.
+func Handle[T any](w *Worker, kind Kind[T], h Handler[T]) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.handlers[string(kind)] = func(ctx context.Context, raw rawJob) error {
+		var payload T
+
+		if err := json.Unmarshal(raw.Payload, &payload); err != nil {
+			return fmt.Errorf("%w: %w", ErrDecodePayload, err)
+		}
+
+		return h(ctx, &Job[T]{
+			ID:          raw.ID,
+			Kind:        raw.Kind,
+			Queue:       raw.Queue,
+			Payload:     payload,
+			Attempt:     raw.Attempt,
+			MaxAttempts: raw.MaxAttempts,
+			RunAt:       raw.RunAt,
+			CreatedAt:   raw.CreatedAt,
+		})
+	}
+}
+
+func (w *Worker) Start(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.started {
+		return ErrWorkerStarted
+	}
+
+	w.started = true
+
+	if len(w.handlers) == 0 {
+		return nil
+	}
+
+	kinds := make([]string, 0, len(w.handlers))
+	for kind := range w.handlers {
+		kinds = append(kinds, kind)
+	}
+
+	// Handlers keep running after Close is called and are only canceled when
+	// the shutdown deadline is reached.
+	ctx, w.cancel = context.WithCancel(ctx)
+
+	for range w.cfg.Concurrency {
+		w.wg.Go(func() {
+			w.poll(ctx, kinds)
+		})
+	}
+
+	w.wg.Go(func() {
+		w.maintain(ctx)
+	})
+
+	return nil
+}
+
+func (w *Worker) Close(ctx context.Context) error {
+	w.mu.Lock()
+	select {
+	case <-w.stop:
+	default:
+		close(w.stop)
+	}
+	cancel := w.cancel
+	w.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		if cancel != nil {
+			cancel()
+		}
+
+		<-done
+
+		return ctx.Err()
+	}
+}
+
+func (w *Worker) poll(ctx context.Context, kinds []string) {
+	log := o11y.LoggerFromContext(ctx).With("queue", w.cfg.Queue)
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		job, err := w.fetch(ctx, kinds)
+		if err != nil {
+			log.ErrorContext(ctx, "queue: fetch failed", "error", err)
+		}
+
+		if job == nil {
+			select {
+			case <-w.stop:
+				return
+			case <-ctx.Done():
+				return
+			case <-time.After(w.cfg.PollInterval):
+			}
+
+			continue
+		}
+
+		w.process(ctx, *job)
+	}
+}
+
+func (w *Worker) fetch(ctx context.Context, kinds []string) (*rawJob, error) {
+	var job rawJob
+
+	err := w.db.QueryRowxContext(ctx, fetchJob, w.cfg.Queue, pq.Array(kinds), time.Now()).StructScan(&job)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, postgres.TranslateError(err)
+	}
+
+	return &job, nil
+}
+
+func (w *Worker) process(ctx context.Context, job rawJob) {
+	log := o11y.LoggerFromContext(ctx).With(
+		"queue", job.Queue,
+		"job_id", job.ID,
+		"job_kind", job.Kind,
+		"attempt", job.Attempt,
+	)
+
+	ctx = o11y.WithLogger(ctx, log)
+
+	start := time.Now()
+
+	w.mu.Lock()
+	handler := w.handlers[job.Kind]
+	w.mu.Unlock()
+
+	handlerCtx, lose := context.WithCancelCause(ctx)
+	defer lose(nil)
+
+	stopHeartbeat := w.heartbeat(handlerCtx, job, lose)
+	err := safeHandle(handlerCtx, handler, job)
+	stopHeartbeat()
+
+	// Bookkeeping must happen even when the handler was canceled by shutdown.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+
+	if err == nil {
+		owned, err := w.settle(ctx, completeJob, job.ID, job.Attempt, now)
+		if err != nil {
+			log.ErrorContext(ctx, "queue: failed to complete job", "error", err)
+			return
+		}
+
+		if !owned {
+			log.WarnContext(ctx, "queue: job was rescued while it ran, dropping its result")
+			return
+		}
+
+		log.InfoContext(ctx, "queue: job succeeded", "duration", time.Since(start))
+
+		return
+	}
+
+	if job.Attempt >= job.MaxAttempts || errors.Is(err, ErrDecodePayload) {
+		owned, dbErr := w.settle(ctx, buryJob, job.ID, job.Attempt, err.Error(), now)
+		if dbErr != nil {
+			log.ErrorContext(ctx, "queue: failed to move job to dead letter", "error", dbErr)
+			return
+		}
+
+		if !owned {
+			log.WarnContext(ctx, "queue: job was rescued while it ran, dropping its result", "error", err)
+			return
+		}
+
+		log.ErrorContext(ctx, "queue: job moved to dead letter", "error", err, "duration", time.Since(start))
+
+		return
+	}
+
+	runAt := now.Add(Backoff(job.Attempt, w.cfg.BaseBackoff, w.cfg.MaxBackoff))
+
+	owned, dbErr := w.settle(ctx, retryJob, job.ID, job.Attempt, err.Error(), runAt, now)
+	if dbErr != nil {
+		log.ErrorContext(ctx, "queue: failed to reschedule job", "error", dbErr)
+		return
+	}
+
+	if !owned {
+		log.WarnContext(ctx, "queue: job was rescued while it ran, dropping its result", "error", err)
+		return
+	}
+
+	log.WarnContext(ctx, "queue: job failed, retrying", "error", err, "run_at", runAt, "duration", time.Since(start))
+}
+
+// settle runs a query ending an attempt and reports whether the attempt still
+// owned its job.
+func (w *Worker) settle(ctx context.Context, query string, args ...any) (bool, error) {
+	res, err := w.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, postgres.TranslateError(err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, postgres.TranslateError(err)
+	}
+
+	return n > 0, nil
+}
+
+// heartbeat extends the lease of job every HeartbeatInterval until the
+// returned func is called. When the job was rescued meanwhile, the handler
+// is canceled with ErrLeaseLost.
+func (w *Worker) heartbeat(ctx context.Context, job rawJob, lose context.CancelCauseFunc) func() {
+	log := o11y.LoggerFromContext(ctx)
+
+	done := make(chan struct{})
+
+	var wg sync.WaitGroup
+
+	wg.Go(func() {
+		ticker := time.NewTicker(w.cfg.HeartbeatInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			owned, err := w.settle(ctx, heartbeatJob, job.ID, job.Attempt, time.Now())
+			if err != nil {
+				log.WarnContext(ctx, "queue: job heartbeat failed", "error", err)
+				continue
+			}
+
+			if !owned {
+				log.ErrorContext(ctx, "queue: job was rescued while it ran, canceling it")
+				lose(ErrLeaseLost)
+
+				return
+			}
+		}
+	})
+
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+// maintain rescues the jobs of crashed workers and deletes the finished jobs
+// past their retention.
+func (w *Worker) maintain(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.StaleAfter / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		w.rescue(ctx)
+		w.sweep(ctx)
+	}
+}
+
+func (w *Worker) rescue(ctx context.Context) {
+	log := o11y.LoggerFromContext(ctx).With("queue", w.cfg.Queue)
+
+	now := time.Now()
+
+	res, err := w.db.ExecContext(ctx, rescueJobs, w.cfg.Queue, now, now.Add(-w.cfg.StaleAfter))
+	if err != nil {
+		log.ErrorContext(ctx, "queue: failed to rescue stale jobs", "error", err)
+		return
+	}
+
+	if n, _ := res.RowsAffected(); n > 0 {
+		log.WarnContext(ctx, "queue: stale jobs rescued", "count", n)
+	}
+}
+
+func (w *Worker) sweep(ctx context.Context) {
+	log := o11y.LoggerFromContext(ctx).With("queue", w.cfg.Queue)
+
+	now := time.Now()
+	total := int64(0)
+
+	for {
+		res, err := w.db.ExecContext(ctx, sweepJobs, w.cfg.Queue,
+			now.Add(-w.cfg.Retention), now.Add(-w.cfg.DeadRetention), sweepBatchSize)
+		if err != nil {
+			log.ErrorContext(ctx, "queue: failed to delete finished jobs", "error", err)
+			return
+		}
+
+		n, _ := res.RowsAffected()
+		total += n
+
+		if n < sweepBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		log.InfoContext(ctx, "queue: finished jobs deleted", "count", total)
+	}
+}
+
+// Backoff returns base * 2^(attempt-1), capped at max.
+func Backoff(attempt int, base, maxBackoff time.Duration) time.Duration {
+	d := base
+
+	for i := 1; i < attempt; i++ {
+		d *= 2
+
+		if d >= maxBackoff || d <= 0 {
+			return maxBackoff
+		}
+	}
+
+	return min(d, maxBackoff)
+}
+
+func safeHandle(ctx context.Context, h handlerFunc, job rawJob) error {
+	var err error
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+
+		err = h(ctx, job)
+	}()
+
+	return err
+}
@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"HATCH_APP/pkg/lock"
+	"context"
+	"errors"
+	"sync"
+)
+
+type Elector interface {
+	IsLeader(ctx context.Context) (bool, error)
+	Close() error
+}
+
+type localElector struct{}
+
+// Local makes every replica a leader, for single instance deployments and tests.
+func Local() Elector {
+	return localElector{}
+}
+
+func (localElector) IsLeader(context.Context) (bool, error) {
+	return true, nil
+}
+
+func (localElector) Close() error {
+	return nil
+}
+
+// LockElector elects the replica holding a distributed lock as leader. The
+// lock is kept until it is lost, e.g. on a dropped connection, or Close.
+type LockElector struct {
+	locker lock.Locker
+	held   context.Context
+	key    string
+	mu     sync.Mutex
+}
+
+func NewLockElector(locker lock.Locker, name string) *LockElector {
+	return &LockElector{
+		locker: locker,
+		key:    "scheduler:leader:" + name,
+	}
+}
+
+func (e *LockElector) IsLeader(context.Context) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.held != nil && e.held.Err() == nil {
+		return true, nil
+	}
+
+	e.held = nil
+
+	// Leadership outlives the job run asking for it.
+	held, err := e.locker.TryAcquire(context.Background(), e.key, 0)
+	if err != nil {
+		if errors.Is(err, lock.ErrNotAcquired) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	e.held = held
+
+	return true, nil
+}
+
+func (e *LockElector) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.held == nil {
+		return nil
+	}
+
+	e.held = nil
+
+	if err := e.locker.Release(context.Background(), e.key); err != nil && !errors.Is(err, lock.ErrNotHeld) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package scheduler_test
+
+import (
+	"HATCH_APP/pkg/lock/memory"
+	"HATCH_APP/pkg/scheduler"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockElector(t *testing.T) {
+	locker := memory.NewLocker()
+
+	leader := scheduler.NewLockElector(locker, "api")
+	follower := scheduler.NewLockElector(locker, "api")
+
+	ok, err := leader.IsLeader(t.Context())
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = follower.IsLeader(t.Context())
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = leader.IsLeader(t.Context())
+	require.NoError(t, err)
+	assert.True(t, ok, "leadership should be kept between runs")
+
+	require.NoError(t, leader.Close())
+
+	ok, err = follower.IsLeader(t.Context())
+	require.NoError(t, err)
+	assert.True(t, ok, "follower should take over once the leader steps down")
+
+	require.NoError(t, follower.Close())
+	require.NoError(t, follower.Close())
+}
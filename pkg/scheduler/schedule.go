@@ -0,0 +1,36 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+type interval time.Duration
+
+func (i interval) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(i))
+}
+
+func Every(d time.Duration) Schedule {
+	return interval(d)
+}
+
+// Parse accepts standard five-field cron expressions and descriptors such as
+// "@hourly" or "@every 15m".
+func Parse(spec string) (Schedule, error) {
+	s, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidSchedule, spec, err)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,214 @@
+package scheduler
+
+import (
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/o11y"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrInvalidJob     = errors.New("invalid job")
+	ErrDuplicatedJob  = errors.New("duplicated job")
+	ErrAlreadyStarted = errors.New("scheduler already started")
+)
+
+type Job struct {
+	Schedule Schedule
+	Run      func(ctx context.Context) error
+	Name     string
+	Timeout  time.Duration
+	Jitter   time.Duration
+	// AllReplicas runs the job on every replica instead of only on the leader.
+	AllReplicas bool
+}
+
+type entry struct {
+	job     Job
+	running atomic.Bool
+}
+
+type Scheduler struct {
+	elector Elector
+	cancel  context.CancelFunc
+	jobs    []*entry
+	loops   sync.WaitGroup
+	runs    sync.WaitGroup
+	mu      sync.Mutex
+	started bool
+}
+
+func New(elector Elector) *Scheduler {
+	if elector == nil {
+		elector = Local()
+	}
+
+	return &Scheduler{
+		elector: elector,
+	}
+}
+
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Schedule == nil || job.Run == nil {
+		return fmt.Errorf("%w: name, schedule and run are required", ErrInvalidJob)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return ErrAlreadyStarted
+	}
+
+	for _, e := range s.jobs {
+		if e.job.Name == job.Name {
+			return fmt.Errorf("%w: %s", ErrDuplicatedJob, job.Name)
+		}
+	}
+
+	s.jobs = append(s.jobs, &entry{job: job})
+
+	return nil
+}
+
+func (s *Scheduler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return ErrAlreadyStarted
+	}
+
+	s.started = true
+
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	for _, e := range s.jobs {
+		s.loops.Add(1)
+
+		go func() {
+			defer s.loops.Done()
+			s.loop(ctx, e)
+		}()
+	}
+
+	return nil
+}
+
+func (s *Scheduler) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		s.loops.Wait()
+		s.runs.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return errors.Join(ctx.Err(), s.elector.Close())
+	}
+
+	return s.elector.Close()
+}
+
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	log := o11y.LoggerFromContext(ctx).With("job", e.job.Name)
+
+	for {
+		now := time.Now()
+		next := e.job.Schedule.Next(now)
+
+		if e.job.Jitter > 0 {
+			next = next.Add(rand.N(e.job.Jitter)) // #nosec G404 -- jitter does not need crypto randomness
+		}
+
+		timer := time.NewTimer(next.Sub(now))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if !e.running.CompareAndSwap(false, true) {
+			log.WarnContext(ctx, "job: skipped, previous run still in progress")
+			continue
+		}
+
+		s.runs.Add(1)
+
+		go func() {
+			defer s.runs.Done()
+			defer e.running.Store(false)
+
+			s.run(ctx, e.job)
+		}()
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	log := o11y.LoggerFromContext(ctx).With("job", job.Name, "run_id", core.NewID())
+
+	if !job.AllReplicas {
+		leader, err := s.elector.IsLeader(ctx)
+		if err != nil {
+			log.ErrorContext(ctx, "job: leader election failed", "error", err)
+			return
+		}
+
+		if !leader {
+			log.DebugContext(ctx, "job: skipped, not the leader")
+			return
+		}
+	}
+
+	if job.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, job.Timeout)
+		defer cancel()
+	}
+
+	ctx = o11y.WithLogger(ctx, log)
+
+	start := time.Now()
+
+	log.InfoContext(ctx, "job: started")
+
+	err := safeRun(ctx, job.Run)
+	if err != nil {
+		log.ErrorContext(ctx, "job: failed", "error", err, "duration", time.Since(start))
+		return
+	}
+
+	log.InfoContext(ctx, "job: finished", "duration", time.Since(start))
+}
+
+func safeRun(ctx context.Context, fn func(ctx context.Context) error) error {
+	var err error
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+
+		err = fn(ctx)
+	}()
+
+	return err
+}
@@ -0,0 +1,185 @@
+package scheduler_test
+
+import (
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/scheduler"
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type follower struct {
+	closed atomic.Bool
+}
+
+func (f *follower) IsLeader(context.Context) (bool, error) {
+	return false, nil
+}
+
+func (f *follower) Close() error {
+	f.closed.Store(true)
+	return nil
+}
+
+func TestSchedulerRegister(t *testing.T) {
+	s := scheduler.New(nil)
+
+	job := scheduler.Job{
+		Name:     "job",
+		Schedule: scheduler.Every(time.Hour),
+		Run:      func(context.Context) error { return nil },
+	}
+
+	require.NoError(t, s.Register(job))
+	require.ErrorIs(t, s.Register(job), scheduler.ErrDuplicatedJob)
+	require.ErrorIs(t, s.Register(scheduler.Job{Name: "no-run"}), scheduler.ErrInvalidJob)
+}
+
+func TestSchedulerRun(t *testing.T) {
+	o11y.InitLogger()
+
+	t.Run("should run interval jobs until closed", func(t *testing.T) {
+		s := scheduler.New(nil)
+
+		var runs atomic.Int32
+
+		require.NoError(t, s.Register(scheduler.Job{
+			Name:     "counter",
+			Schedule: scheduler.Every(5 * time.Millisecond),
+			Run: func(context.Context) error {
+				runs.Add(1)
+				return errors.New("failures are logged, not fatal")
+			},
+		}))
+
+		require.NoError(t, s.Start(t.Context()))
+
+		assert.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, time.Millisecond)
+
+		require.NoError(t, s.Close(t.Context()))
+
+		after := runs.Load()
+		time.Sleep(20 * time.Millisecond)
+		assert.Equal(t, after, runs.Load())
+	})
+
+	t.Run("should not overlap runs of the same job", func(t *testing.T) {
+		s := scheduler.New(nil)
+
+		var running, maxRunning atomic.Int32
+
+		require.NoError(t, s.Register(scheduler.Job{
+			Name:     "slow",
+			Schedule: scheduler.Every(time.Millisecond),
+			Run: func(context.Context) error {
+				n := running.Add(1)
+				defer running.Add(-1)
+
+				if n > maxRunning.Load() {
+					maxRunning.Store(n)
+				}
+
+				time.Sleep(10 * time.Millisecond)
+
+				return nil
+			},
+		}))
+
+		require.NoError(t, s.Start(t.Context()))
+		time.Sleep(50 * time.Millisecond)
+		require.NoError(t, s.Close(t.Context()))
+
+		assert.Equal(t, int32(1), maxRunning.Load())
+	})
+
+	t.Run("should cancel runs exceeding the timeout", func(t *testing.T) {
+		s := scheduler.New(nil)
+
+		canceled := make(chan struct{})
+
+		require.NoError(t, s.Register(scheduler.Job{
+			Name:     "timeout",
+			Schedule: scheduler.Every(time.Millisecond),
+			Timeout:  5 * time.Millisecond,
+			Run: func(ctx context.Context) error {
+				<-ctx.Done()
+
+				select {
+				case canceled <- struct{}{}:
+				default:
+				}
+
+				return ctx.Err()
+			},
+		}))
+
+		require.NoError(t, s.Start(t.Context()))
+
+		select {
+		case <-canceled:
+		case <-time.After(time.Second):
+			t.Fatal("job was not canceled by its timeout")
+		}
+
+		require.NoError(t, s.Close(t.Context()))
+	})
+
+	t.Run("should only run leader jobs on the leader", func(t *testing.T) {
+		elector := &follower{}
+		s := scheduler.New(elector)
+
+		var leaderRuns, replicaRuns atomic.Int32
+
+		require.NoError(t, s.Register(scheduler.Job{
+			Name:     "leader-only",
+			Schedule: scheduler.Every(time.Millisecond),
+			Run: func(context.Context) error {
+				leaderRuns.Add(1)
+				return nil
+			},
+		}))
+
+		require.NoError(t, s.Register(scheduler.Job{
+			Name:        "every-replica",
+			Schedule:    scheduler.Every(time.Millisecond),
+			AllReplicas: true,
+			Run: func(context.Context) error {
+				replicaRuns.Add(1)
+				return nil
+			},
+		}))
+
+		require.NoError(t, s.Start(t.Context()))
+
+		assert.Eventually(t, func() bool { return replicaRuns.Load() > 0 }, time.Second, time.Millisecond)
+
+		require.NoError(t, s.Close(t.Context()))
+
+		assert.Zero(t, leaderRuns.Load())
+		assert.True(t, elector.closed.Load())
+	})
+}
+
+func TestParse(t *testing.T) {
+	from := time.Date(2026, 1, 1, 10, 30, 0, 0, time.UTC)
+
+	hourly, err := scheduler.Parse("@hourly")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 1, 1, 11, 0, 0, 0, time.UTC), hourly.Next(from))
+
+	every, err := scheduler.Parse("@every 15m")
+	require.NoError(t, err)
+	assert.Equal(t, from.Add(15*time.Minute), every.Next(from))
+
+	cron, err := scheduler.Parse("0 3 * * *")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC), cron.Next(from))
+
+	_, err = scheduler.Parse("not a cron")
+	require.ErrorIs(t, err, scheduler.ErrInvalidSchedule)
+}
@@ -0,0 +1,12 @@
+package postgres
+
+import "hash/fnv"
+
+// AdvisoryLockKey hashes a lock name into the bigint key space used by
+// pg_advisory_* functions.
+func AdvisoryLockKey(name string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+
+	return int64(h.Sum64()) // #nosec G115 -- wrapping is fine, only the bit pattern matters
+}
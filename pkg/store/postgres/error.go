@@ -1,9 +1,125 @@
 package postgres
 
 import (
+	"context"
 	"errors"
+	"fmt"
 
-	_ "github.com/lib/pq"
+	"HATCH_APP/pkg/core/apperr"
+	database "HATCH_APP/pkg/store"
+
+	"github.com/lib/pq"
 )
 
 var ErrQueryPreparation = errors.New("query preparation error")
+
+const (
+	sqlStateUniqueViolation     = "23505"
+	sqlStateForeignKeyViolation = "23503"
+	sqlStateCheckViolation      = "23514"
+	sqlStateNotNullViolation    = "23502"
+	sqlStateExclusionViolation  = "23P01"
+	sqlStateStringTooLong       = "22001"
+	sqlStateInvalidText         = "22P02"
+	sqlStateSerialization       = "40001"
+	sqlStateDeadlock            = "40P01"
+	sqlStateQueryCanceled       = "57014"
+	sqlStateLockNotAvailable    = "55P03"
+	sqlStateTooManyConnections  = "53300"
+	sqlStateAdminShutdown       = "57P01"
+	sqlStateCannotConnectNow    = "57P03"
+
+	sqlClassConnectionException = "08"
+)
+
+var Codes = apperr.NewRegistry("STORE")
+
+var (
+	ErrUniqueViolation = Codes.Register("STORE_UNIQUE_VIOLATION", apperr.TypeConflict,
+		"resource already exists",
+		"A unique constraint rejected the write.")
+	ErrForeignKeyViolation = Codes.Register("STORE_FOREIGN_KEY_VIOLATION", apperr.TypeValidation,
+		"referenced resource does not exist",
+		"A foreign key constraint rejected the write.")
+	ErrCheckViolation = Codes.Register("STORE_CHECK_VIOLATION", apperr.TypeValidation,
+		"value violates a constraint",
+		"A check, not null or exclusion constraint rejected the write.")
+	ErrInvalidValue = Codes.Register("STORE_INVALID_VALUE", apperr.TypeValidation,
+		"invalid value",
+		"A value could not be stored in its column, e.g. too long or malformed.")
+	ErrSerializationFailure = Codes.Register("STORE_SERIALIZATION_FAILURE", apperr.TypeConflict,
+		"concurrent update, please retry",
+		"The transaction conflicted with a concurrent one and can be retried.")
+	ErrStatementTimeout = Codes.Register("STORE_TIMEOUT", apperr.TypeTimeout,
+		"datasource operation timed out",
+		"The statement was canceled by a timeout or could not acquire a lock in time.")
+	ErrUnavailable = Codes.Register("STORE_UNAVAILABLE", apperr.TypeUnavailable,
+		"datasource unavailable",
+		"The database refused or lost the connection.")
+)
+
+type ErrorDetails struct {
+	SQLState   string `json:"sql_state"`
+	Constraint string `json:"constraint,omitempty"`
+	Table      string `json:"table,omitempty"`
+	Column     string `json:"column,omitempty"`
+}
+
+// TranslateError maps driver errors into apperr errors so that services can
+// surface them with a meaningful type. Unknown errors are wrapped with
+// database.ErrDatasourceOperation.
+func TranslateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := errors.AsType[*apperr.Error](err); ok {
+		return err
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrStatementTimeout.Wrap(err)
+	}
+
+	pqErr, ok := errors.AsType[*pq.Error](err)
+	if !ok {
+		return fmt.Errorf("%w: %w", database.ErrDatasourceOperation, err)
+	}
+
+	code, ok := mapSQLState(pqErr.Code)
+	if !ok {
+		return fmt.Errorf("%w: %w", database.ErrDatasourceOperation, err)
+	}
+
+	return code.Wrap(err).WithDetails(ErrorDetails{
+		SQLState:   string(pqErr.Code),
+		Constraint: pqErr.Constraint,
+		Table:      pqErr.Table,
+		Column:     pqErr.Column,
+	})
+}
+
+func mapSQLState(state pq.ErrorCode) (apperr.Code, bool) {
+	switch state {
+	case sqlStateUniqueViolation:
+		return ErrUniqueViolation, true
+	case sqlStateForeignKeyViolation:
+		return ErrForeignKeyViolation, true
+	case sqlStateCheckViolation, sqlStateNotNullViolation, sqlStateExclusionViolation:
+		return ErrCheckViolation, true
+	case sqlStateStringTooLong, sqlStateInvalidText:
+		return ErrInvalidValue, true
+	case sqlStateSerialization, sqlStateDeadlock:
+		return ErrSerializationFailure, true
+	case sqlStateQueryCanceled, sqlStateLockNotAvailable:
+		return ErrStatementTimeout, true
+	case sqlStateTooManyConnections, sqlStateAdminShutdown, sqlStateCannotConnectNow:
+		return ErrUnavailable, true
+	}
+
+	if state.Class() == sqlClassConnectionException {
+		return ErrUnavailable, true
+	}
+
+	return apperr.Code{}, false
+}
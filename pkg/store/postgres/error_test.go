@@ -0,0 +1,108 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"HATCH_APP/pkg/core/apperr"
+	database "HATCH_APP/pkg/store"
+	"HATCH_APP/pkg/store/postgres"
+	"HATCH_APP/test/apperrtest"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodes(t *testing.T) {
+	apperrtest.AssertRegistries(t, postgres.Codes)
+}
+
+func TestTranslateError(t *testing.T) {
+	tests := []struct {
+		err      error
+		wantCode apperr.Code
+		name     string
+		wantType apperr.ErrorType
+	}{
+		{
+			name:     "unique violation becomes conflict",
+			err:      &pq.Error{Code: "23505", Constraint: "notes_pkey", Table: "notes"},
+			wantType: apperr.TypeConflict,
+			wantCode: postgres.ErrUniqueViolation,
+		},
+		{
+			name:     "foreign key violation becomes validation",
+			err:      &pq.Error{Code: "23503", Constraint: "note_tags_note_id_fkey"},
+			wantType: apperr.TypeValidation,
+			wantCode: postgres.ErrForeignKeyViolation,
+		},
+		{
+			name:     "check violation becomes validation",
+			err:      fmt.Errorf("exec: %w", &pq.Error{Code: "23514", Constraint: "notes_title_check"}),
+			wantType: apperr.TypeValidation,
+			wantCode: postgres.ErrCheckViolation,
+		},
+		{
+			name:     "serialization failure becomes conflict",
+			err:      &pq.Error{Code: "40001"},
+			wantType: apperr.TypeConflict,
+			wantCode: postgres.ErrSerializationFailure,
+		},
+		{
+			name:     "statement timeout becomes timeout",
+			err:      &pq.Error{Code: "57014"},
+			wantType: apperr.TypeTimeout,
+			wantCode: postgres.ErrStatementTimeout,
+		},
+		{
+			name:     "context deadline becomes timeout",
+			err:      context.DeadlineExceeded,
+			wantType: apperr.TypeTimeout,
+			wantCode: postgres.ErrStatementTimeout,
+		},
+		{
+			name:     "connection exception becomes unavailable",
+			err:      &pq.Error{Code: "08006"},
+			wantType: apperr.TypeUnavailable,
+			wantCode: postgres.ErrUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			err := postgres.TranslateError(tc.err)
+
+			require.Error(t, err)
+			assert.True(t, apperr.IsType(err, tc.wantType))
+			assert.True(t, tc.wantCode.Is(err))
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+
+	t.Run("should keep constraint in details", func(t *testing.T) {
+		err := postgres.TranslateError(&pq.Error{Code: "23505", Constraint: "notes_pkey", Table: "notes"})
+
+		appErr, ok := errors.AsType[*apperr.Error](err)
+		require.True(t, ok)
+		assert.Equal(t, postgres.ErrorDetails{
+			SQLState:   "23505",
+			Constraint: "notes_pkey",
+			Table:      "notes",
+		}, appErr.Details)
+	})
+
+	t.Run("should wrap unknown errors as datasource errors", func(t *testing.T) {
+		err := postgres.TranslateError(errors.New("boom"))
+
+		require.ErrorIs(t, err, database.ErrDatasourceOperation)
+		assert.False(t, apperr.IsType(err, apperr.TypeConflict))
+	})
+
+	t.Run("should return nil for nil", func(t *testing.T) {
+		assert.NoError(t, postgres.TranslateError(nil))
+	})
+}
@@ -0,0 +1,320 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"HATCH_APP/pkg/o11y"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// MaxNotificationSize is the largest payload NOTIFY accepts.
+const MaxNotificationSize = 7999
+
+var (
+	ErrListenerStarted     = errors.New("listener already started")
+	ErrEncodeNotification  = errors.New("encode notification payload")
+	ErrNotificationTooLong = errors.New("notification payload too long")
+)
+
+// Channel names a notification channel and binds it to its payload, so
+// notifiers and subscribers can't disagree on the payload shape.
+type Channel[T any] string
+
+// Handler receives the decoded payload of a notification. Handlers run one at
+// a time off a bounded queue, a slow one delays the others and, once the
+// queue is full, makes the listener drop notifications.
+type Handler[T any] func(ctx context.Context, payload T)
+
+type ListenerConfig struct {
+	// MinReconnectInterval is doubled after each failed attempt to reconnect,
+	// up to MaxReconnectInterval.
+	MinReconnectInterval time.Duration
+	MaxReconnectInterval time.Duration
+	// PingInterval is how often the idle connection is checked, so a dead
+	// one is noticed and replaced.
+	PingInterval time.Duration
+	// QueueSize is how many notifications wait for the handlers before new
+	// ones are dropped.
+	QueueSize int
+}
+
+// Listener receives the notifications of its channels on a dedicated
+// connection, reconnecting and listening again whenever it is lost.
+// Delivery is at most once: notifications sent while it is reconnecting,
+// dropped by a full queue or failed by a handler are lost, the OnMissed hooks
+// run afterwards so subscribers can resync.
+type Listener struct {
+	handlers map[string][]func(ctx context.Context, payload []byte)
+	resyncs  []func(ctx context.Context)
+	conn     *pq.Listener
+	queue    chan *pq.Notification
+	missed   chan struct{}
+	stop     chan struct{}
+	url      string
+	cfg      ListenerConfig
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	started  bool
+}
+
+func NewListener(url string, cfg ListenerConfig) *Listener {
+	if cfg.MinReconnectInterval <= 0 {
+		cfg.MinReconnectInterval = time.Second
+	}
+
+	if cfg.MaxReconnectInterval <= 0 {
+		cfg.MaxReconnectInterval = time.Minute
+	}
+
+	if cfg.PingInterval <= 0 {
+		cfg.PingInterval = 30 * time.Second
+	}
+
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 1024
+	}
+
+	return &Listener{
+		url:      url,
+		cfg:      cfg,
+		handlers: make(map[string][]func(ctx context.Context, payload []byte)),
+		queue:    make(chan *pq.Notification, cfg.QueueSize),
+		missed:   make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+	}
+}
+
+// Subscribe registers a handler for the notifications of channel. It must be
+// called before Start, handlers of a channel run in subscription order.
+func Subscribe[T any](l *Listener, channel Channel[T], h Handler[T]) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.handlers[string(channel)] = append(l.handlers[string(channel)], func(ctx context.Context, raw []byte) {
+		var payload T
+
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			o11y.LoggerFromContext(ctx).ErrorContext(ctx, "listener: failed to decode notification", "error", err)
+			return
+		}
+
+		h(ctx, payload)
+	})
+}
+
+// OnMissed registers fn to run once notifications were lost: after a
+// reconnect, a full queue or a failed handler. It must be called before Start.
+func (l *Listener) OnMissed(fn func(ctx context.Context)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.resyncs = append(l.resyncs, fn)
+}
+
+// Start connects and listens on the subscribed channels, it blocks until the
+// database acknowledged them.
+func (l *Listener) Start(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.started {
+		return ErrListenerStarted
+	}
+
+	l.started = true
+
+	if len(l.handlers) == 0 {
+		return nil
+	}
+
+	log := o11y.LoggerFromContext(ctx)
+
+	l.conn = pq.NewListener(l.url, l.cfg.MinReconnectInterval, l.cfg.MaxReconnectInterval,
+		func(event pq.ListenerEventType, err error) {
+			switch event {
+			case pq.ListenerEventDisconnected:
+				log.WarnContext(ctx, "listener: connection lost", "error", err)
+			case pq.ListenerEventConnectionAttemptFailed:
+				log.WarnContext(ctx, "listener: reconnect failed", "error", err)
+			case pq.ListenerEventReconnected:
+				log.InfoContext(ctx, "listener: reconnected, notifications sent meanwhile were lost")
+			}
+		})
+
+	for channel := range l.handlers {
+		if err := l.conn.Listen(channel); err != nil {
+			_ = l.conn.Close()
+
+			return err
+		}
+	}
+
+	l.wg.Go(func() {
+		l.receive(ctx)
+	})
+
+	l.wg.Go(func() {
+		l.work(ctx)
+	})
+
+	return nil
+}
+
+// Close stops the delivery of notifications, waiting for the running handlers
+// until ctx is done.
+func (l *Listener) Close(ctx context.Context) error {
+	l.mu.Lock()
+	select {
+	case <-l.stop:
+	default:
+		close(l.stop)
+	}
+	l.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		l.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if l.conn == nil {
+		return nil
+	}
+
+	return l.conn.Close()
+}
+
+// receive only queues the notifications, so neither the handlers nor a slow
+// database behind them keep it from pinging and draining the connection.
+func (l *Listener) receive(ctx context.Context) {
+	log := o11y.LoggerFromContext(ctx)
+
+	ping := time.NewTicker(l.cfg.PingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-ping.C:
+			// A failed ping closes the connection, which is then reopened.
+			_ = l.conn.Ping()
+		case n, ok := <-l.conn.Notify:
+			if !ok {
+				return
+			}
+
+			// Sent once reconnected, the lost notifications are unknown.
+			if n == nil {
+				l.miss()
+
+				continue
+			}
+
+			select {
+			case l.queue <- n:
+			default:
+				log.WarnContext(ctx, "listener: queue full, notification dropped", "channel", n.Channel)
+				l.miss()
+			}
+		}
+	}
+}
+
+// miss wakes the worker to run the OnMissed hooks, once for any number of
+// losses it has not caught up with yet.
+func (l *Listener) miss() {
+	select {
+	case l.missed <- struct{}{}:
+	default:
+	}
+}
+
+func (l *Listener) work(ctx context.Context) {
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-l.missed:
+			l.resync(ctx)
+		case n := <-l.queue:
+			l.mu.Lock()
+			handlers := l.handlers[n.Channel]
+			l.mu.Unlock()
+
+			for _, h := range handlers {
+				if !safeRun(ctx, func() { h(ctx, []byte(n.Extra)) }, "channel", n.Channel) {
+					l.miss()
+				}
+			}
+		}
+	}
+}
+
+func (l *Listener) resync(ctx context.Context) {
+	l.mu.Lock()
+	resyncs := l.resyncs
+	l.mu.Unlock()
+
+	for _, fn := range resyncs {
+		safeRun(ctx, func() { fn(ctx) })
+	}
+}
+
+// safeRun runs fn, logging a panic with attrs instead of losing the listener.
+func safeRun(ctx context.Context, fn func(), attrs ...any) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			o11y.LoggerFromContext(ctx).ErrorContext(ctx, "listener: panic recovered",
+				append(attrs, "panic", fmt.Sprint(r), "stack", string(debug.Stack()))...)
+
+			ok = false
+		}
+	}()
+
+	fn()
+
+	return true
+}
+
+// Notify sends payload on channel through db. Inside a transaction the
+// notification is only delivered once it commits, and not at all if it rolls
+// back.
+func Notify[T any](ctx context.Context, db sqlx.ExecerContext, channel Channel[T], payload T) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrEncodeNotification, err)
+	}
+
+	if len(raw) > MaxNotificationSize {
+		return fmt.Errorf("%w: %d bytes on %s", ErrNotificationTooLong, len(raw), channel)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := db.ExecContext(ctx, "SELECT pg_notify($1, $2)", string(channel), string(raw)); err != nil {
+		return TranslateError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,244 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/store/postgres"
+	"HATCH_APP/test/container"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type changePayload struct {
+	ID string `json:"id"`
+}
+
+func startListener(t *testing.T, url string, channel postgres.Channel[changePayload]) <-chan changePayload {
+	t.Helper()
+
+	got := make(chan changePayload, 10)
+
+	l := postgres.NewListener(url, postgres.ListenerConfig{
+		MinReconnectInterval: 10 * time.Millisecond,
+		PingInterval:         50 * time.Millisecond,
+	})
+	postgres.Subscribe(l, channel, func(_ context.Context, p changePayload) {
+		got <- p
+	})
+
+	require.NoError(t, l.Start(t.Context()))
+
+	t.Cleanup(func() {
+		require.NoError(t, l.Close(context.Background()))
+	})
+
+	return got
+}
+
+func receive(t *testing.T, got <-chan changePayload) changePayload {
+	t.Helper()
+
+	select {
+	case p := <-got:
+		return p
+	case <-time.After(5 * time.Second):
+		t.Fatal("notification was not received")
+
+		return changePayload{}
+	}
+}
+
+func TestListenerIntegration(t *testing.T) {
+	o11y.InitLogger()
+
+	db, url, teardown := container.SetupPostgresWithURL(t)
+	t.Cleanup(teardown)
+
+	t.Run("should deliver typed payloads once committed", func(t *testing.T) {
+		const channel postgres.Channel[changePayload] = "test_commit"
+
+		got := startListener(t, url, channel)
+
+		err := postgres.RunInTx(db, func(tx *sqlx.Tx) error {
+			if err := postgres.Notify(t.Context(), tx, channel, changePayload{ID: "rolled-back"}); err != nil {
+				return err
+			}
+
+			return errors.New("rollback")
+		})
+		require.Error(t, err)
+
+		require.NoError(t, postgres.RunInTx(db, func(tx *sqlx.Tx) error {
+			return postgres.Notify(t.Context(), tx, channel, changePayload{ID: "committed"})
+		}))
+
+		assert.Equal(t, "committed", receive(t, got).ID)
+	})
+
+	t.Run("should listen again after reconnecting", func(t *testing.T) {
+		const channel postgres.Channel[changePayload] = "test_reconnect"
+
+		got := startListener(t, url, channel)
+
+		var terminated []bool
+
+		err := db.SelectContext(t.Context(), &terminated, `SELECT pg_terminate_backend(pid) FROM pg_stat_activity
+			WHERE query = 'LISTEN "test_reconnect"'`)
+		require.NoError(t, err)
+		require.Equal(t, []bool{true}, terminated)
+
+		// Notifications sent while reconnecting are lost, so keep sending
+		// until one gets through.
+		assert.Eventually(t, func() bool {
+			require.NoError(t, postgres.Notify(t.Context(), db, channel, changePayload{ID: "after"}))
+
+			select {
+			case p := <-got:
+				return p.ID == "after"
+			case <-time.After(50 * time.Millisecond):
+				return false
+			}
+		}, 10*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("should run the missed hooks once reconnected", func(t *testing.T) {
+		const channel postgres.Channel[changePayload] = "test_resync"
+
+		resynced := make(chan struct{}, 1)
+
+		l := postgres.NewListener(url, postgres.ListenerConfig{
+			MinReconnectInterval: 10 * time.Millisecond,
+			PingInterval:         50 * time.Millisecond,
+		})
+		postgres.Subscribe(l, channel, func(context.Context, changePayload) {})
+		l.OnMissed(func(context.Context) {
+			panic("first hook")
+		})
+		l.OnMissed(func(context.Context) {
+			resynced <- struct{}{}
+		})
+
+		require.NoError(t, l.Start(t.Context()))
+
+		t.Cleanup(func() {
+			require.NoError(t, l.Close(context.Background()))
+		})
+
+		_, err := db.ExecContext(t.Context(), `SELECT pg_terminate_backend(pid) FROM pg_stat_activity
+			WHERE query = 'LISTEN "test_resync"'`)
+		require.NoError(t, err)
+
+		select {
+		case <-resynced:
+		case <-time.After(5 * time.Second):
+			t.Fatal("missed hook did not run")
+		}
+	})
+
+	t.Run("should drop notifications a slow handler can't keep up with", func(t *testing.T) {
+		const channel postgres.Channel[changePayload] = "test_slow"
+
+		release := make(chan struct{})
+		got := make(chan changePayload, 10)
+		missed := make(chan struct{}, 1)
+
+		l := postgres.NewListener(url, postgres.ListenerConfig{
+			MinReconnectInterval: 10 * time.Millisecond,
+			PingInterval:         50 * time.Millisecond,
+			QueueSize:            1,
+		})
+		postgres.Subscribe(l, channel, func(_ context.Context, p changePayload) {
+			<-release
+			got <- p
+		})
+		l.OnMissed(func(context.Context) {
+			select {
+			case missed <- struct{}{}:
+			default:
+			}
+		})
+
+		require.NoError(t, l.Start(t.Context()))
+
+		t.Cleanup(func() {
+			require.NoError(t, l.Close(context.Background()))
+		})
+
+		// The first one blocks the handler, the queue holds one more and
+		// the receive loop, still draining, drops the rest.
+		for range 5 {
+			require.NoError(t, postgres.Notify(t.Context(), db, channel, changePayload{ID: "blocked"}))
+		}
+
+		time.Sleep(100 * time.Millisecond)
+		close(release)
+
+		select {
+		case <-missed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("missed hook did not run")
+		}
+
+		require.NoError(t, postgres.Notify(t.Context(), db, channel, changePayload{ID: "after"}))
+
+		ids := []string{receive(t, got).ID}
+		for ids[len(ids)-1] != "after" {
+			ids = append(ids, receive(t, got).ID)
+		}
+
+		assert.Less(t, len(ids), 6, "every blocked notification was delivered")
+	})
+
+	t.Run("should run the missed hooks when a handler panics", func(t *testing.T) {
+		const channel postgres.Channel[changePayload] = "test_panic"
+
+		got := make(chan changePayload, 1)
+		missed := make(chan struct{}, 1)
+
+		l := postgres.NewListener(url, postgres.ListenerConfig{})
+		postgres.Subscribe(l, channel, func(_ context.Context, p changePayload) {
+			if p.ID == "panic" {
+				panic("handler")
+			}
+
+			got <- p
+		})
+		l.OnMissed(func(context.Context) {
+			select {
+			case missed <- struct{}{}:
+			default:
+			}
+		})
+
+		require.NoError(t, l.Start(t.Context()))
+
+		t.Cleanup(func() {
+			require.NoError(t, l.Close(context.Background()))
+		})
+
+		require.NoError(t, postgres.Notify(t.Context(), db, channel, changePayload{ID: "panic"}))
+
+		select {
+		case <-missed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("missed hook did not run")
+		}
+
+		require.NoError(t, postgres.Notify(t.Context(), db, channel, changePayload{ID: "after"}))
+
+		assert.Equal(t, "after", receive(t, got).ID)
+	})
+
+	t.Run("should refuse payloads over the notify limit", func(t *testing.T) {
+		err := postgres.Notify(t.Context(), db, "test_limit", changePayload{ID: strings.Repeat("x", postgres.MaxNotificationSize)})
+
+		require.ErrorIs(t, err, postgres.ErrNotificationTooLong)
+	})
+}
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4"
+	pgMg "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jmoiron/sqlx"
+)
+
+// Migrate applies every pending up migration from files on a dedicated
+// connection, which is returned to the pool afterwards.
+func Migrate(ctx context.Context, db *sqlx.DB, files fs.FS) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	driver, err := pgMg.WithConnection(ctx, conn, &pgMg.Config{})
+	if err != nil {
+		return errors.Join(err, conn.Close())
+	}
+
+	source, err := iofs.New(files, ".")
+	if err != nil {
+		return errors.Join(err, driver.Close())
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, "postgres", driver)
+	if err != nil {
+		return errors.Join(err, driver.Close())
+	}
+
+	err = m.Up()
+	if errors.Is(err, migrate.ErrNoChange) {
+		err = nil
+	}
+
+	srcErr, dbErr := m.Close()
+
+	return errors.Join(err, srcErr, dbErr)
+}
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ReadRouter picks the database the read-only queries of ctx go to, see
+// connection/postgres.Cluster.
+type ReadRouter interface {
+	Reader(ctx context.Context) *sqlx.DB
+}
+
+type (
+	sessionKey struct{}
+	primaryKey struct{}
+)
+
+type session struct {
+	wrote atomic.Bool
+}
+
+// WithSession starts a session, a request usually, on ctx. Repositories mark
+// its writes so its later reads can be kept off lagging replicas.
+func WithSession(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sessionKey{}, &session{})
+}
+
+// MarkWrite records that the session of ctx wrote, it does nothing outside
+// a session.
+func MarkWrite(ctx context.Context) {
+	if s, ok := ctx.Value(sessionKey{}).(*session); ok {
+		s.wrote.Store(true)
+	}
+}
+
+// Wrote reports whether the session of ctx wrote.
+func Wrote(ctx context.Context) bool {
+	s, ok := ctx.Value(sessionKey{}).(*session)
+
+	return ok && s.wrote.Load()
+}
+
+// WithPrimary sends the reads of ctx to the primary, for reads that must see
+// every commit.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// NeedsPrimary reports whether the reads of ctx must go to the primary, as
+// asked by WithPrimary or, when readYourWrites, because its session wrote.
+func NeedsPrimary(ctx context.Context, readYourWrites bool) bool {
+	if primary, _ := ctx.Value(primaryKey{}).(bool); primary {
+		return true
+	}
+
+	return readYourWrites && Wrote(ctx)
+}
@@ -0,0 +1,37 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"HATCH_APP/pkg/store/postgres"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNeedsPrimary(t *testing.T) {
+	t.Run("should read from replicas until the session writes", func(t *testing.T) {
+		ctx := postgres.WithSession(t.Context())
+
+		assert.False(t, postgres.NeedsPrimary(ctx, true))
+
+		postgres.MarkWrite(ctx)
+
+		assert.True(t, postgres.Wrote(ctx))
+		assert.True(t, postgres.NeedsPrimary(ctx, true))
+		assert.False(t, postgres.NeedsPrimary(ctx, false))
+	})
+
+	t.Run("should ignore writes outside a session", func(t *testing.T) {
+		ctx := context.Background()
+
+		postgres.MarkWrite(ctx)
+
+		assert.False(t, postgres.Wrote(ctx))
+		assert.False(t, postgres.NeedsPrimary(ctx, true))
+	})
+
+	t.Run("should always read from the primary when asked", func(t *testing.T) {
+		assert.True(t, postgres.NeedsPrimary(postgres.WithPrimary(t.Context()), false))
+	})
+}
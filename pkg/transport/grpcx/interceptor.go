@@ -0,0 +1,208 @@
+package grpcx
+
+import (
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/validator"
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	RequestIDHeader      = "x-request-id"
+	AcceptLanguageHeader = "accept-language"
+)
+
+var ErrInvalidPayload = errors.New("invalid payload")
+
+type requestIDCtxKey struct{}
+
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDCtxKey{}).(string)
+	return id
+}
+
+// withRequestID reuses the caller request id when present and echoes it back
+// in the response header.
+func withRequestID(ctx context.Context) context.Context {
+	var id string
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(RequestIDHeader); len(values) > 0 {
+			id = values[0]
+		}
+	}
+
+	if id == "" {
+		id = core.NewID().String()
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, id))
+
+	return context.WithValue(ctx, requestIDCtxKey{}, id)
+}
+
+func withLogger(ctx context.Context, method string) (context.Context, *o11y.Logger) {
+	log := o11y.Log.With(
+		"method", method,
+		"request_id", RequestIDFromContext(ctx),
+	)
+
+	return o11y.WithLogger(ctx, log), log
+}
+
+func logFinished(ctx context.Context, log *o11y.Logger, start time.Time, err error) {
+	log.InfoContext(ctx, "request finished",
+		"code", status.Code(err).String(),
+		"duration", time.Since(start),
+	)
+}
+
+func recovered(ctx context.Context, r any) error {
+	o11y.LoggerFromContext(ctx).ErrorContext(ctx, "panic recovered",
+		"panic", fmt.Sprint(r),
+		"stack", string(debug.Stack()),
+	)
+
+	return status.Error(codes.Internal, "Internal Server Error")
+}
+
+// withValidator puts v in the context, localized from the accept-language
+// metadata like its HTTP counterpart.
+func withValidator(ctx context.Context, v *validator.Validator) context.Context {
+	var acceptLanguage string
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		acceptLanguage = strings.Join(md.Get(AcceptLanguageHeader), ",")
+	}
+
+	return validator.WithValidator(ctx, v.Localized(acceptLanguage))
+}
+
+func unaryRequestID(
+	ctx context.Context,
+	req any,
+	_ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	return handler(withRequestID(ctx), req)
+}
+
+func unaryO11y(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, log := withLogger(ctx, info.FullMethod)
+
+	start := time.Now()
+
+	log.InfoContext(ctx, "request started")
+
+	resp, err := handler(ctx, req)
+
+	logFinished(ctx, log, start, err)
+
+	return resp, err
+}
+
+func unaryRecovery(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	var (
+		resp any
+		err  error
+	)
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ctx, r)
+			}
+		}()
+
+		resp, err = handler(ctx, req)
+	}()
+
+	return resp, err
+}
+
+func unaryValidator(v *validator.Validator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withValidator(ctx, v), req)
+	}
+}
+
+func unaryContext(contexts []func(context.Context) context.Context) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		for _, fn := range contexts {
+			ctx = fn(ctx)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func streamRequestID(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &serverStream{ServerStream: ss, ctx: withRequestID(ss.Context())})
+}
+
+func streamO11y(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, log := withLogger(ss.Context(), info.FullMethod)
+
+	start := time.Now()
+
+	log.InfoContext(ctx, "request started")
+
+	err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+
+	logFinished(ctx, log, start, err)
+
+	return err
+}
+
+func streamRecovery(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	var err error
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ss.Context(), r)
+			}
+		}()
+
+		err = handler(srv, ss)
+	}()
+
+	return err
+}
+
+func streamValidator(v *validator.Validator) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &serverStream{ServerStream: ss, ctx: withValidator(ss.Context(), v)})
+	}
+}
+
+func streamContext(contexts []func(context.Context) context.Context) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+
+		for _, fn := range contexts {
+			ctx = fn(ctx)
+		}
+
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
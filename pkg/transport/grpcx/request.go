@@ -0,0 +1,46 @@
+package grpcx
+
+import (
+	"HATCH_APP/pkg/validator"
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Validate is the gRPC counterpart of httpx.ParseRequest: obj is checked by
+// the validator of the call, so both transports share the same struct tags
+// and localized messages. Failed fields are reported as BadRequest details.
+func Validate(ctx context.Context, obj any) error {
+	err := validator.ValidatorFromContext(ctx).Validate(obj)
+	if err == nil {
+		return nil
+	}
+
+	st := status.New(codes.InvalidArgument, fmt.Sprintf("%s: %s", ErrInvalidPayload.Error(), err.Error()))
+
+	fieldErrs, ok := errors.AsType[validator.Errors](err)
+	if !ok {
+		return st.Err()
+	}
+
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(fieldErrs))
+
+	for _, fe := range fieldErrs {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       fe.Field,
+			Description: fe.Message,
+			Reason:      fe.Rule,
+		})
+	}
+
+	withDetails, detailsErr := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if detailsErr != nil {
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
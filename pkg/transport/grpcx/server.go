@@ -0,0 +1,88 @@
+package grpcx
+
+import (
+	"HATCH_APP/pkg/validator"
+	"context"
+	"errors"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type Server struct {
+	*grpc.Server
+	health *health.Server
+	addr   string
+}
+
+// NewServer derives the context of every call with contexts, the gRPC
+// counterpart of httpx.WithContext.
+func NewServer(port string, v *validator.Validator, contexts ...func(context.Context) context.Context) *Server {
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			unaryRequestID,
+			unaryO11y,
+			unaryRecovery,
+			unaryValidator(v),
+			unaryContext(contexts),
+		),
+		grpc.ChainStreamInterceptor(
+			streamRequestID,
+			streamO11y,
+			streamRecovery,
+			streamValidator(v),
+			streamContext(contexts),
+		),
+	)
+
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(srv, hs)
+
+	return &Server{
+		Server: srv,
+		health: hs,
+		addr:   ":" + port,
+	}
+}
+
+func (s *Server) Start() error {
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(lis)
+}
+
+func (s *Server) Serve(lis net.Listener) error {
+	if err := s.Server.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+
+	return nil
+}
+
+// Close reports NOT_SERVING to health checks, waits for in-flight calls and
+// forces the remaining ones to stop once ctx is done.
+func (s *Server) Close(ctx context.Context) error {
+	s.health.Shutdown()
+
+	done := make(chan struct{})
+
+	go func() {
+		s.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.Stop()
+		<-done
+
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,227 @@
+package grpcx_test
+
+import (
+	"HATCH_APP/pkg/core/apperr"
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/transport/grpcx"
+	"HATCH_APP/pkg/validator"
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type echoParams struct {
+	Value string `json:"value" validate:"required"`
+}
+
+type echoFunc func(ctx context.Context, value string) (*wrapperspb.StringValue, error)
+
+var echoDesc = grpc.ServiceDesc{
+	ServiceName: "test.Echo",
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Echo",
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			req := &wrapperspb.StringValue{}
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+
+			handler := func(ctx context.Context, req any) (any, error) {
+				return srv.(echoFunc)(ctx, req.(*wrapperspb.StringValue).GetValue())
+			}
+
+			return interceptor(ctx, req, &grpc.UnaryServerInfo{Server: srv, FullMethod: "/test.Echo/Echo"}, handler)
+		},
+	}},
+}
+
+type ctxKey struct{}
+
+func setup(t *testing.T, echo echoFunc, contexts ...func(context.Context) context.Context) *grpc.ClientConn {
+	t.Helper()
+
+	o11y.InitLogger()
+
+	srv := grpcx.NewServer("0", validator.New(), contexts...)
+	srv.RegisterService(&echoDesc, echo)
+
+	lis := bufconn.Listen(1024 * 1024)
+
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+		_ = srv.Close(context.Background())
+	})
+
+	return conn
+}
+
+func call(ctx context.Context, conn *grpc.ClientConn, value string, opts ...grpc.CallOption) (string, error) {
+	out := &wrapperspb.StringValue{}
+
+	err := conn.Invoke(ctx, "/test.Echo/Echo", wrapperspb.String(value), out, opts...)
+
+	return out.GetValue(), err
+}
+
+func TestServer(t *testing.T) {
+	t.Run("should serve health checks", func(t *testing.T) {
+		conn := setup(t, nil)
+
+		resp, err := healthpb.NewHealthClient(conn).Check(t.Context(), &healthpb.HealthCheckRequest{})
+
+		require.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+	})
+
+	t.Run("should propagate the request id", func(t *testing.T) {
+		conn := setup(t, func(ctx context.Context, _ string) (*wrapperspb.StringValue, error) {
+			return wrapperspb.String(grpcx.RequestIDFromContext(ctx)), nil
+		})
+
+		var header metadata.MD
+
+		ctx := metadata.AppendToOutgoingContext(t.Context(), grpcx.RequestIDHeader, "req-1")
+
+		got, err := call(ctx, conn, "hi", grpc.Header(&header))
+
+		require.NoError(t, err)
+		assert.Equal(t, "req-1", got)
+		assert.Equal(t, []string{"req-1"}, header.Get(grpcx.RequestIDHeader))
+	})
+
+	t.Run("should generate a request id when missing", func(t *testing.T) {
+		conn := setup(t, func(ctx context.Context, _ string) (*wrapperspb.StringValue, error) {
+			return wrapperspb.String(grpcx.RequestIDFromContext(ctx)), nil
+		})
+
+		got, err := call(t.Context(), conn, "hi")
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, got)
+	})
+
+	t.Run("should derive the call context", func(t *testing.T) {
+		conn := setup(t, func(ctx context.Context, _ string) (*wrapperspb.StringValue, error) {
+			value, _ := ctx.Value(ctxKey{}).(string)
+			return wrapperspb.String(value), nil
+		}, func(ctx context.Context) context.Context {
+			return context.WithValue(ctx, ctxKey{}, "derived")
+		})
+
+		got, err := call(t.Context(), conn, "hi")
+
+		require.NoError(t, err)
+		assert.Equal(t, "derived", got)
+	})
+
+	t.Run("should recover from panics", func(t *testing.T) {
+		conn := setup(t, func(context.Context, string) (*wrapperspb.StringValue, error) {
+			panic("boom")
+		})
+
+		_, err := call(t.Context(), conn, "hi")
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("should reject invalid requests with localized field violations", func(t *testing.T) {
+		conn := setup(t, func(ctx context.Context, value string) (*wrapperspb.StringValue, error) {
+			if err := grpcx.Validate(ctx, echoParams{Value: value}); err != nil {
+				return nil, err
+			}
+
+			return wrapperspb.String(value), nil
+		})
+
+		ctx := metadata.AppendToOutgoingContext(t.Context(), grpcx.AcceptLanguageHeader, "pt-BR")
+
+		_, err := call(ctx, conn, "")
+
+		st := status.Convert(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+
+		require.Len(t, st.Details(), 1)
+		details, ok := st.Details()[0].(*errdetails.BadRequest)
+		require.True(t, ok)
+		require.Len(t, details.GetFieldViolations(), 1)
+		assert.Equal(t, "value", details.GetFieldViolations()[0].GetField())
+		assert.Equal(t, "required", details.GetFieldViolations()[0].GetReason())
+		assert.Equal(t, "value é obrigatório", details.GetFieldViolations()[0].GetDescription())
+	})
+
+	t.Run("should map application errors", func(t *testing.T) {
+		conn := setup(t, func(ctx context.Context, _ string) (*wrapperspb.StringValue, error) {
+			return nil, grpcx.Error(o11y.LoggerFromContext(ctx), apperr.NotFound("note not found").WithCode("NOTE_NOT_FOUND"))
+		})
+
+		_, err := call(t.Context(), conn, "hi")
+
+		st := status.Convert(err)
+		assert.Equal(t, codes.NotFound, st.Code())
+		assert.Equal(t, "note not found", st.Message())
+
+		require.Len(t, st.Details(), 1)
+		info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+		require.True(t, ok)
+		assert.Equal(t, "NOTE_NOT_FOUND", info.GetReason())
+	})
+}
+
+func TestError(t *testing.T) {
+	o11y.InitLogger()
+
+	tests := []struct {
+		err  error
+		name string
+		want codes.Code
+	}{
+		{name: "not found", err: apperr.NotFound("x"), want: codes.NotFound},
+		{name: "validation", err: apperr.Validation("x"), want: codes.InvalidArgument},
+		{name: "conflict", err: apperr.Conflict("x"), want: codes.AlreadyExists},
+		{name: "invalid operation", err: apperr.InvalidOperation("x"), want: codes.FailedPrecondition},
+		{name: "unauthorized", err: apperr.Unauthorized("x", nil), want: codes.Unauthenticated},
+		{name: "forbidden", err: apperr.Forbidden("x"), want: codes.PermissionDenied},
+		{name: "rate limited", err: apperr.RateLimited("x"), want: codes.ResourceExhausted},
+		{name: "unavailable", err: apperr.Unavailable("x", nil), want: codes.Unavailable},
+		{name: "timeout", err: apperr.Timeout("x", nil), want: codes.DeadlineExceeded},
+		{name: "precondition failed", err: apperr.PreconditionFailed("x"), want: codes.FailedPrecondition},
+		{name: "payload too large", err: apperr.PayloadTooLarge("x"), want: codes.ResourceExhausted},
+		{name: "internal", err: apperr.Internal("x", nil), want: codes.Internal},
+		{name: "unknown", err: errors.New("boom"), want: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			err := grpcx.Error(o11y.Log, tc.err)
+
+			assert.Equal(t, tc.want, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package grpcx
+
+import (
+	"HATCH_APP/pkg/core/apperr"
+	"errors"
+	"log/slog"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const errorDomain = "hatch"
+
+// Error is the gRPC counterpart of httpx.WriteError: it logs err and converts
+// it into a status, exposing the app error code as ErrorInfo reason.
+func Error(log *slog.Logger, err error) error {
+	if appErr, ok := errors.AsType[*apperr.Error](err); ok {
+		var originalErrMsg string
+		if appErr.Err != nil {
+			originalErrMsg = appErr.Err.Error()
+		}
+
+		log.Warn("application error",
+			"type", appErr.Type,
+			"code", appErr.Code,
+			"message", appErr.Message,
+			"original_error", originalErrMsg,
+		)
+
+		return appStatus(appErr).Err()
+	}
+
+	log.Error("internal server error", "error", err)
+
+	return status.Error(codes.Internal, "Internal Server Error")
+}
+
+func appStatus(err *apperr.Error) *status.Status {
+	st := status.New(mapCode(err.Type), err.Message)
+
+	if err.Code == "" {
+		return st
+	}
+
+	withDetails, detailsErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: err.Code,
+		Domain: errorDomain,
+	})
+	if detailsErr != nil {
+		return st
+	}
+
+	return withDetails
+}
+
+func mapCode(t apperr.ErrorType) codes.Code {
+	switch t {
+	case apperr.TypeNotFound:
+		return codes.NotFound
+	case apperr.TypeValidation:
+		return codes.InvalidArgument
+	case apperr.TypeConflict:
+		return codes.AlreadyExists
+	case apperr.TypeInvalidOperation:
+		return codes.FailedPrecondition
+	case apperr.TypeUnauthorized:
+		return codes.Unauthenticated
+	case apperr.TypeForbidden:
+		return codes.PermissionDenied
+	case apperr.TypeRateLimited:
+		return codes.ResourceExhausted
+	case apperr.TypeUnavailable:
+		return codes.Unavailable
+	case apperr.TypeTimeout:
+		return codes.DeadlineExceeded
+	case apperr.TypePreconditionFailed:
+		return codes.FailedPrecondition
+	case apperr.TypePayloadTooLarge:
+		return codes.ResourceExhausted
+	default:
+		return codes.Internal
+	}
+}
@@ -34,6 +34,25 @@ type Client struct {
 	client *http.Client
 }
 
+type ClientOption func(*http.Client)
+
+// WithTransport sends the requests through rt.
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(c *http.Client) {
+		c.Transport = rt
+	}
+}
+
+// WithoutRedirects returns redirect responses as they are instead of
+// following them.
+func WithoutRedirects() ClientOption {
+	return func(c *http.Client) {
+		c.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+}
+
 type Request struct {
 	Headers      http.Header
 	CustomClient *http.Client
@@ -42,7 +61,7 @@ type Request struct {
 	Body         []byte
 }
 
-func NewClient(timeout time.Duration) *Client {
+func NewClient(timeout time.Duration, opts ...ClientOption) *Client {
 	to := defaultRequestTimeout
 	if timeout > 0 {
 		to = timeout
@@ -52,6 +71,10 @@ func NewClient(timeout time.Duration) *Client {
 		Timeout: to,
 	}
 
+	for _, opt := range opts {
+		opt(&client)
+	}
+
 	return &Client{
 		client: &client,
 	}
@@ -0,0 +1,35 @@
+package httpx_test
+
+import (
+	"HATCH_APP/pkg/transport/httpx"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientWithoutRedirects(t *testing.T) {
+	followed := false
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/from", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/to", http.StatusFound)
+	})
+	mux.HandleFunc("/to", func(http.ResponseWriter, *http.Request) {
+		followed = true
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	res, err := httpx.NewClient(time.Second, httpx.WithoutRedirects()).Do(t.Context(), httpx.Request{URL: srv.URL + "/from"})
+	require.NoError(t, err)
+
+	_ = res.Body.Close()
+
+	assert.Equal(t, http.StatusFound, res.StatusCode)
+	assert.False(t, followed)
+}
@@ -3,6 +3,7 @@ package httpx
 import (
 	"HATCH_APP/pkg/o11y"
 	"HATCH_APP/pkg/validator"
+	"context"
 	"net/http"
 )
 
@@ -25,8 +26,17 @@ func withO11y(next http.Handler) http.Handler {
 func withValidator(v *validator.Validator) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			ctx := validator.WithValidator(r.Context(), v)
+			ctx := validator.WithValidator(r.Context(), v.Localized(r.Header.Get("Accept-Language")))
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+// WithContext derives the context of every request with fn.
+func WithContext(fn func(context.Context) context.Context) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(fn(r.Context())))
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package openapi
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Route declares an endpoint once for both the router and the spec.
+type Route struct {
+	Handler http.HandlerFunc
+	// Request is the JSON body or a File, Params a struct whose fields carry
+	// path, query or header tags, and Responses maps a status to its body, nil
+	// for none, Binary for a raw stream and EventStream for server-sent
+	// events.
+	Request     any
+	Params      any
+	Responses   map[int]any
+	Method      string
+	Path        string
+	OperationID string
+	Summary     string
+	Tags        []string
+	// Errors lists the statuses answered with the spec error response.
+	Errors []int
+}
+
+// File declares a multipart/form-data request body uploading a file in the
+// Field form field.
+type File struct {
+	Field string
+}
+
+// Binary declares a response body streamed as is, whatever its media type.
+type Binary struct{}
+
+// EventStream declares a text/event-stream response, events are described
+// by the summary of the route.
+type EventStream struct{}
+
+// Mount registers routes under prefix on r and declares them on spec, which
+// may be nil when no documentation is served.
+func Mount(r chi.Router, spec *Spec, prefix string, routes ...Route) {
+	r.Route(prefix, func(r chi.Router) {
+		for _, route := range routes {
+			r.Method(route.Method, route.Path, route.Handler)
+
+			if spec != nil {
+				spec.add(prefix+route.Path, route)
+			}
+		}
+	})
+}
+
+// Missing lists the routes registered on r that the spec does not declare,
+// as "METHOD /path". r may be mounted at or above the spec base path.
+func (s *Spec) Missing(r chi.Routes) ([]string, error) {
+	var missing []string
+
+	err := chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		_, declared := s.Operation(method, route)
+		if !declared {
+			// Routers mounted above the base path include it in their patterns.
+			_, declared = s.Match(method, route)
+		}
+
+		if !declared {
+			missing = append(missing, method+" "+normalizePath(route))
+		}
+
+		return nil
+	})
+
+	sort.Strings(missing)
+
+	return missing, err
+}
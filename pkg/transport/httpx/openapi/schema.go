@@ -0,0 +1,308 @@
+package openapi
+
+import (
+	"HATCH_APP/pkg/core"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Enum is implemented by named types with a closed set of values, e.g.
+// domain.NoteStatus, so their schema lists them.
+type Enum interface {
+	Enum() []any
+}
+
+// JSONShape is implemented by types whose MarshalJSON writes something else
+// than their fields, the schema is then that of the value JSONShape returns.
+type JSONShape interface {
+	JSONShape() any
+}
+
+type Schema struct {
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"-"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Nullable             bool               `json:"-"`
+}
+
+// MarshalJSON writes nullable schemas the OpenAPI 3.1 way, as a type array.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	type plain Schema
+
+	out := struct {
+		*plain
+		Type any `json:"type,omitempty"`
+	}{plain: (*plain)(s)}
+
+	switch {
+	case s.Type == "":
+	case s.Nullable:
+		out.Type = []string{s.Type, "null"}
+	default:
+		out.Type = s.Type
+	}
+
+	return json.Marshal(out)
+}
+
+const ulidPattern = "^[0-7][0-9A-HJKMNP-TV-Za-hjkmnp-tv-z]{25}$"
+
+var (
+	timeType  = reflect.TypeFor[time.Time]()
+	idType    = reflect.TypeFor[core.ID]()
+	enumType  = reflect.TypeFor[Enum]()
+	shapeType = reflect.TypeFor[JSONShape]()
+
+	invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+)
+
+const componentsPrefix = "#/components/schemas/"
+
+// schemaOf returns the schema of t, named structs are stored as components
+// and referenced.
+func (s *Spec) schemaOf(t reflect.Type) *Schema {
+	nullable := false
+
+	for t.Kind() == reflect.Pointer {
+		nullable = true
+		t = t.Elem()
+	}
+
+	if t.Implements(enumType) {
+		values := reflect.Zero(t).Interface().(Enum).Enum()
+
+		return &Schema{Type: "string", Enum: values, Nullable: nullable}
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time", Nullable: nullable}
+	case t == idType:
+		return &Schema{Type: "string", Format: "ulid", Pattern: ulidPattern, Nullable: nullable}
+	case t.Kind() == reflect.Struct && t.Name() != "":
+		return &Schema{Ref: componentsPrefix + s.component(t)}
+	}
+
+	schema := s.inlineSchemaOf(t)
+	schema.Nullable = nullable && schema.Type != ""
+
+	return schema
+}
+
+func (s *Spec) inlineSchemaOf(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+
+		return &Schema{Type: "array", Items: s.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.schemaOf(t.Elem())}
+	case reflect.Struct:
+		return s.structSchema(t)
+	default:
+		// interfaces and anything else accept any value.
+		return &Schema{}
+	}
+}
+
+func (s *Spec) component(t reflect.Type) string {
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+
+	name := invalidNameChars.ReplaceAllString(pkg+"."+t.Name(), "_")
+
+	if _, ok := s.doc.Components.Schemas[name]; ok {
+		return name
+	}
+
+	// Reserve the name first so recursive types terminate.
+	s.doc.Components.Schemas[name] = &Schema{}
+	s.doc.Components.Schemas[name] = s.structSchema(jsonShapeOf(t))
+
+	return name
+}
+
+func jsonShapeOf(t reflect.Type) reflect.Type {
+	if !t.Implements(shapeType) {
+		return t
+	}
+
+	return reflect.TypeOf(reflect.Zero(t).Interface().(JSONShape).JSONShape())
+}
+
+func (s *Spec) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema),
+	}
+
+	s.addFields(schema, t)
+
+	return schema
+}
+
+func (s *Spec) addFields(schema *Schema, t reflect.Type) {
+	for f := range t.Fields() {
+		// Fields of embedded structs are written even when the struct type
+		// is unexported.
+		if !f.IsExported() && (!f.Anonymous || f.Type.Kind() != reflect.Struct) {
+			continue
+		}
+
+		name, ok := jsonName(f)
+		if !ok {
+			continue
+		}
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+
+			if ft.Kind() == reflect.Struct {
+				s.addFields(schema, ft)
+				continue
+			}
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := s.schemaOf(f.Type)
+
+		if applyRules(prop, f.Tag.Get("validate")) {
+			schema.Required = append(schema.Required, name)
+		}
+
+		schema.Properties[name] = prop
+	}
+}
+
+func jsonName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+
+	return name, true
+}
+
+// applyRules translates the validate tag into schema keywords and reports
+// whether the field is required. Rules after dive apply to elements and are
+// ignored.
+func applyRules(schema *Schema, tag string) bool {
+	required := false
+
+	for rule := range strings.SplitSeq(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "dive":
+			return required
+		case "required":
+			required = true
+
+			if schema.Type == "string" && schema.MinLength == nil {
+				schema.MinLength = new(1)
+			}
+		case "oneof":
+			for v := range strings.FieldsSeq(param) {
+				schema.Enum = append(schema.Enum, v)
+			}
+		case "notblank":
+			if schema.Pattern == "" {
+				schema.Pattern = `\S`
+			}
+		case "email":
+			schema.Format = "email"
+		case "url", "uri":
+			schema.Format = "uri"
+		case "uuid":
+			schema.Format = "uuid"
+		case "ulid":
+			schema.Format = "ulid"
+			schema.Pattern = ulidPattern
+		case "len", "min", "max", "gt", "gte", "lt", "lte":
+			applyBound(schema, name, param)
+		}
+	}
+
+	return required
+}
+
+func applyBound(schema *Schema, rule, param string) {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	switch schema.Type {
+	case "string":
+		applyCount(&schema.MinLength, &schema.MaxLength, rule, int(n))
+	case "array":
+		applyCount(&schema.MinItems, &schema.MaxItems, rule, int(n))
+	case "integer", "number":
+		switch rule {
+		case "len":
+			schema.Minimum, schema.Maximum = new(n), new(n)
+		case "min", "gte":
+			schema.Minimum = new(n)
+		case "max", "lte":
+			schema.Maximum = new(n)
+		case "gt":
+			schema.ExclusiveMinimum = new(n)
+		case "lt":
+			schema.ExclusiveMaximum = new(n)
+		}
+	}
+}
+
+func applyCount(minimum, maximum **int, rule string, n int) {
+	switch rule {
+	case "len":
+		*minimum, *maximum = new(n), new(n)
+	case "min", "gte":
+		*minimum = new(n)
+	case "max", "lte":
+		*maximum = new(n)
+	case "gt":
+		*minimum = new(n + 1)
+	case "lt":
+		*maximum = new(n - 1)
+	}
+}
@@ -0,0 +1,333 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const Version = "3.1.0"
+
+type Document struct {
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+	Info       Info                `json:"info"`
+	OpenAPI    string              `json:"openapi"`
+	Servers    []Server            `json:"servers,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// PathItem maps lowercase HTTP methods to operations.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+}
+
+type Parameter struct {
+	Schema   *Schema `json:"schema"`
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+}
+
+type RequestBody struct {
+	Content  map[string]MediaType `json:"content"`
+	Required bool                 `json:"required"`
+}
+
+type Response struct {
+	Content     map[string]MediaType `json:"content,omitempty"`
+	Description string               `json:"description"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+const (
+	jsonContentType      = "application/json"
+	multipartContentType = "multipart/form-data"
+	binaryContentType    = "application/octet-stream"
+	eventStreamType      = "text/event-stream"
+)
+
+// Spec builds the document from the routes declared by the modules.
+type Spec struct {
+	doc         Document
+	errorSchema *Schema
+	body        []byte
+	mu          sync.Mutex
+}
+
+// New creates a spec whose paths are relative to basePath, the error
+// responses of every operation are described by errorResponse.
+func New(info Info, basePath string, errorResponse any) *Spec {
+	s := &Spec{
+		doc: Document{
+			OpenAPI: Version,
+			Info:    info,
+			Servers: []Server{{URL: basePath}},
+			Paths:   make(map[string]PathItem),
+			Components: Components{
+				Schemas: make(map[string]*Schema),
+			},
+		},
+	}
+
+	if errorResponse != nil {
+		s.errorSchema = s.schemaOf(reflect.TypeOf(errorResponse))
+	}
+
+	return s
+}
+
+func (s *Spec) Document() Document {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.doc
+}
+
+// Operation returns the operation declared for method and path, path uses
+// the chi pattern syntax.
+func (s *Spec) Operation(method, path string) (*Operation, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	op, ok := s.doc.Paths[normalizePath(path)][strings.ToLower(method)]
+
+	return op, ok
+}
+
+// Schema resolves a $ref to its component schema.
+func (s *Spec) Schema(ref string) (*Schema, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schema, ok := s.doc.Components.Schemas[strings.TrimPrefix(ref, componentsPrefix)]
+
+	return schema, ok
+}
+
+func (s *Spec) MarshalJSON() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return json.Marshal(s.doc)
+}
+
+func (s *Spec) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		s.mu.Lock()
+		if s.body == nil {
+			s.body, _ = json.Marshal(s.doc)
+		}
+		body := s.body
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", jsonContentType)
+		_, _ = w.Write(body)
+	}
+}
+
+func (s *Spec) add(path string, route Route) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.body = nil
+
+	path = normalizePath(path)
+
+	op := &Operation{
+		OperationID: route.OperationID,
+		Summary:     route.Summary,
+		Tags:        route.Tags,
+		Parameters:  s.parameters(path, route.Params),
+		Responses:   make(map[string]*Response),
+	}
+
+	if route.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  s.content(route.Request),
+		}
+	}
+
+	for status, body := range route.Responses {
+		resp := &Response{Description: http.StatusText(status)}
+
+		if body != nil {
+			resp.Content = s.content(body)
+		}
+
+		op.Responses[statusKey(status)] = resp
+	}
+
+	if s.errorSchema != nil {
+		for _, status := range route.Errors {
+			op.Responses[statusKey(status)] = s.errorResponse(http.StatusText(status))
+		}
+
+		op.Responses["default"] = s.errorResponse("Unexpected error")
+	}
+
+	item, ok := s.doc.Paths[path]
+	if !ok {
+		item = make(PathItem)
+		s.doc.Paths[path] = item
+	}
+
+	item[strings.ToLower(route.Method)] = op
+}
+
+// content describes a body, as JSON unless it is a File, Binary or
+// EventStream.
+func (s *Spec) content(body any) map[string]MediaType {
+	switch b := body.(type) {
+	case File:
+		return map[string]MediaType{
+			multipartContentType: {Schema: &Schema{
+				Type:       "object",
+				Properties: map[string]*Schema{b.Field: binarySchema()},
+				Required:   []string{b.Field},
+			}},
+		}
+	case Binary:
+		return map[string]MediaType{
+			binaryContentType: {Schema: binarySchema()},
+		}
+	case EventStream:
+		return map[string]MediaType{
+			eventStreamType: {Schema: &Schema{Type: "string"}},
+		}
+	}
+
+	return map[string]MediaType{
+		jsonContentType: {Schema: s.schemaOf(reflect.TypeOf(body))},
+	}
+}
+
+func binarySchema() *Schema {
+	return &Schema{Type: "string", Format: "binary"}
+}
+
+func (s *Spec) errorResponse(description string) *Response {
+	return &Response{
+		Description: description,
+		Content: map[string]MediaType{
+			jsonContentType: {Schema: s.errorSchema},
+		},
+	}
+}
+
+// parameters declares every {param} of path, typed by the path tags of
+// params when present, plus its query and header fields.
+func (s *Spec) parameters(path string, params any) []*Parameter {
+	declared := make(map[string]*Parameter)
+
+	var out []*Parameter
+
+	if params != nil {
+		t := reflect.TypeOf(params)
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+
+		for f := range t.Fields() {
+			for _, in := range []string{"path", "query", "header"} {
+				name := f.Tag.Get(in)
+				if name == "" {
+					continue
+				}
+
+				schema := s.schemaOf(f.Type)
+
+				p := &Parameter{
+					Name:     name,
+					In:       in,
+					Schema:   schema,
+					Required: applyRules(schema, f.Tag.Get("validate")) || in == "path",
+				}
+
+				if in == "path" {
+					declared[name] = p
+					continue
+				}
+
+				out = append(out, p)
+			}
+		}
+	}
+
+	var pathParams []*Parameter
+
+	for _, name := range pathParamNames(path) {
+		p, ok := declared[name]
+		if !ok {
+			p = &Parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"},
+			}
+		}
+
+		pathParams = append(pathParams, p)
+	}
+
+	return append(pathParams, out...)
+}
+
+func pathParamNames(path string) []string {
+	var names []string
+
+	for segment := range strings.SplitSeq(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			name, _, _ := strings.Cut(segment[1:len(segment)-1], ":")
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// normalizePath makes chi patterns and spec paths comparable.
+func normalizePath(path string) string {
+	path = strings.TrimSuffix(path, "/*")
+
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+
+	if path == "" {
+		return "/"
+	}
+
+	return path
+}
+
+func statusKey(status int) string {
+	return strconv.Itoa(status)
+}
@@ -0,0 +1,265 @@
+package openapi_test
+
+import (
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/transport/httpx/openapi"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type errorResponse struct {
+	Details any    `json:"details,omitempty"`
+	Message string `json:"message"`
+}
+
+type color string
+
+func (color) Enum() []any {
+	return []any{"red", "blue"}
+}
+
+type createRequest struct {
+	UpdatedAt *time.Time        `json:"updated_at"`
+	Labels    map[string]string `json:"labels"`
+	Ref       core.ID           `json:"ref"`
+	ParentID  string            `json:"parent_id" validate:"omitempty,ulid"`
+	Title     string            `json:"title"    validate:"required,gt=0,lte=200"`
+	Kind      string            `json:"kind"     validate:"omitempty,oneof=plain markdown"`
+	Color     color             `json:"color"`
+	Ignored   string            `json:"-"`
+	Tags      []string          `json:"tags"     validate:"max=10,dive,required"`
+	Priority  int               `json:"priority" validate:"gte=1,lt=5"`
+}
+
+type createResponse struct {
+	Data []*createRequest `json:"data"`
+}
+
+type params struct {
+	ID     string `path:"id"       validate:"len=26"`
+	Limit  int    `query:"limit"   validate:"min=1,max=100"`
+	Tenant string `header:"X-Tenant" validate:"required"`
+}
+
+// shaped writes an extra field through MarshalJSON.
+type shaped struct {
+	Name string `json:"name"`
+}
+
+type shapedPlain shaped
+
+type shapedJSON struct {
+	shapedPlain
+	Derived bool `json:"derived"`
+}
+
+func (s shaped) MarshalJSON() ([]byte, error) {
+	return json.Marshal(shapedJSON{shapedPlain: shapedPlain(s), Derived: true})
+}
+
+func (shaped) JSONShape() any {
+	return shapedJSON{}
+}
+
+func noop(http.ResponseWriter, *http.Request) {}
+
+func newSpec(t *testing.T) (*openapi.Spec, chi.Router) {
+	t.Helper()
+
+	spec := openapi.New(openapi.Info{Title: "test", Version: "1"}, "/api", errorResponse{})
+	r := chi.NewRouter()
+
+	openapi.Mount(r, spec, "/v1/items",
+		openapi.Route{
+			Method:      http.MethodPost,
+			Path:        "/",
+			Handler:     noop,
+			OperationID: "createItem",
+			Request:     createRequest{},
+			Responses:   map[int]any{http.StatusCreated: createResponse{}},
+			Errors:      []int{http.StatusBadRequest},
+		},
+		openapi.Route{
+			Method:      http.MethodGet,
+			Path:        "/{id}",
+			Handler:     noop,
+			OperationID: "getItem",
+			Params:      params{},
+			Responses:   map[int]any{http.StatusNoContent: nil},
+		},
+		openapi.Route{
+			Method:      http.MethodPut,
+			Path:        "/{id}/file",
+			Handler:     noop,
+			OperationID: "uploadItemFile",
+			Request:     openapi.File{Field: "file"},
+			Responses:   map[int]any{http.StatusOK: openapi.Binary{}},
+		},
+		openapi.Route{
+			Method:      http.MethodGet,
+			Path:        "/stream",
+			Handler:     noop,
+			OperationID: "streamItems",
+			Responses:   map[int]any{http.StatusOK: openapi.EventStream{}},
+		},
+	)
+
+	return spec, r
+}
+
+func TestSpec(t *testing.T) {
+	t.Run("should generate schemas from json and validate tags", func(t *testing.T) {
+		spec, _ := newSpec(t)
+
+		schema, ok := spec.Schema("#/components/schemas/openapi_test.createRequest")
+		require.True(t, ok)
+
+		assert.Equal(t, []string{"title"}, schema.Required)
+		assert.NotContains(t, schema.Properties, "Ignored")
+
+		title := schema.Properties["title"]
+		assert.Equal(t, "string", title.Type)
+		assert.Equal(t, 1, *title.MinLength)
+		assert.Equal(t, 200, *title.MaxLength)
+
+		assert.Equal(t, []any{"plain", "markdown"}, schema.Properties["kind"].Enum)
+		assert.Equal(t, []any{"red", "blue"}, schema.Properties["color"].Enum)
+
+		tags := schema.Properties["tags"]
+		assert.Equal(t, "array", tags.Type)
+		assert.Equal(t, 10, *tags.MaxItems)
+		assert.Nil(t, tags.Items.MinLength, "rules after dive apply to elements")
+
+		priority := schema.Properties["priority"]
+		assert.Equal(t, "integer", priority.Type)
+		assert.InDelta(t, 1, *priority.Minimum, 0)
+		assert.InDelta(t, 5, *priority.ExclusiveMaximum, 0)
+
+		updatedAt := schema.Properties["updated_at"]
+		assert.Equal(t, "date-time", updatedAt.Format)
+		assert.True(t, updatedAt.Nullable)
+
+		assert.Equal(t, "object", schema.Properties["labels"].Type)
+
+		assert.Equal(t, "ulid", schema.Properties["ref"].Format)
+		assert.Regexp(t, schema.Properties["ref"].Pattern, core.NewID().String())
+		assert.Equal(t, schema.Properties["ref"].Pattern, schema.Properties["parent_id"].Pattern)
+	})
+
+	t.Run("should document what json shapes write", func(t *testing.T) {
+		spec := openapi.New(openapi.Info{Title: "test", Version: "1"}, "/api", errorResponse{})
+
+		openapi.Mount(chi.NewRouter(), spec, "/v1/shaped", openapi.Route{
+			Method:      http.MethodGet,
+			Path:        "/",
+			Handler:     noop,
+			OperationID: "getShaped",
+			Responses:   map[int]any{http.StatusOK: shaped{}},
+		})
+
+		schema, ok := spec.Schema("#/components/schemas/openapi_test.shaped")
+		require.True(t, ok)
+
+		assert.Contains(t, schema.Properties, "name")
+		assert.Contains(t, schema.Properties, "derived")
+	})
+
+	t.Run("should declare operations with parameters and error responses", func(t *testing.T) {
+		spec, _ := newSpec(t)
+
+		op, ok := spec.Operation(http.MethodPost, "/v1/items/")
+		require.True(t, ok)
+		assert.Equal(t, "createItem", op.OperationID)
+		require.NotNil(t, op.RequestBody)
+		assert.Contains(t, op.Responses, "201")
+		assert.Contains(t, op.Responses, "400")
+		assert.Contains(t, op.Responses, "default")
+
+		op, ok = spec.Operation(http.MethodGet, "/v1/items/{id}")
+		require.True(t, ok)
+		require.Len(t, op.Parameters, 3)
+
+		assert.Equal(t, "id", op.Parameters[0].Name)
+		assert.Equal(t, "path", op.Parameters[0].In)
+		assert.True(t, op.Parameters[0].Required)
+		assert.Equal(t, 26, *op.Parameters[0].Schema.MinLength)
+
+		assert.Equal(t, "query", op.Parameters[1].In)
+		assert.False(t, op.Parameters[1].Required)
+		assert.Equal(t, "header", op.Parameters[2].In)
+		assert.True(t, op.Parameters[2].Required)
+
+		assert.Nil(t, op.Responses["204"].Content)
+	})
+
+	t.Run("should declare file uploads and binary responses", func(t *testing.T) {
+		spec, _ := newSpec(t)
+
+		op, ok := spec.Operation(http.MethodPut, "/v1/items/{id}/file")
+		require.True(t, ok)
+
+		upload := op.RequestBody.Content["multipart/form-data"].Schema
+		require.NotNil(t, upload)
+		assert.Equal(t, []string{"file"}, upload.Required)
+		assert.Equal(t, "binary", upload.Properties["file"].Format)
+
+		download := op.Responses["200"].Content["application/octet-stream"].Schema
+		require.NotNil(t, download)
+		assert.Equal(t, "string", download.Type)
+		assert.Equal(t, "binary", download.Format)
+
+		op, ok = spec.Operation(http.MethodGet, "/v1/items/stream")
+		require.True(t, ok)
+
+		stream := op.Responses["200"].Content["text/event-stream"].Schema
+		require.NotNil(t, stream)
+		assert.Equal(t, "string", stream.Type)
+	})
+
+	t.Run("should serve the document as json", func(t *testing.T) {
+		spec, _ := newSpec(t)
+
+		rec := httptest.NewRecorder()
+		spec.Handler()(rec, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+
+		var doc map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+
+		assert.Equal(t, openapi.Version, doc["openapi"])
+		assert.Contains(t, doc["paths"], "/v1/items")
+		assert.Contains(t, doc["paths"], "/v1/items/{id}")
+
+		schemas := doc["components"].(map[string]any)["schemas"].(map[string]any)
+		createRequest := schemas["openapi_test.createRequest"].(map[string]any)
+		updatedAt := createRequest["properties"].(map[string]any)["updated_at"].(map[string]any)
+		assert.Equal(t, []any{"string", "null"}, updatedAt["type"])
+	})
+
+	t.Run("should report routes missing from the spec", func(t *testing.T) {
+		spec, r := newSpec(t)
+
+		missing, err := spec.Missing(r)
+		require.NoError(t, err)
+		assert.Empty(t, missing)
+
+		root := chi.NewRouter()
+		root.Mount("/api", r)
+
+		missing, err = spec.Missing(root)
+		require.NoError(t, err)
+		assert.Empty(t, missing)
+
+		r.Delete("/v1/items/{id}", noop)
+
+		missing, err = spec.Missing(r)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"DELETE /v1/items/{id}"}, missing)
+	})
+}
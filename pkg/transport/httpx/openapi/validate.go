@@ -0,0 +1,543 @@
+package openapi
+
+import (
+	"HATCH_APP/pkg/core/apperr"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var Codes = apperr.NewRegistry("OPENAPI")
+
+var (
+	ErrInvalidRequest = Codes.Register("OPENAPI_INVALID_REQUEST", apperr.TypeValidation,
+		"request does not match the API contract",
+		"A path, query, header or body value violates the OpenAPI document, see details.")
+	ErrInvalidResponse = Codes.Register("OPENAPI_INVALID_RESPONSE", apperr.TypeInternal,
+		"response does not match the API contract",
+		"A handler answered with a status or body the OpenAPI document does not declare.")
+	ErrRequestTooLarge = Codes.Register("OPENAPI_REQUEST_TOO_LARGE", apperr.TypePayloadTooLarge,
+		"request body is too large",
+		"A JSON body exceeds the size the validation middleware buffers, see OPENAPI_MAX_BODY_SIZE.")
+)
+
+// FieldError locates a single contract violation. Field is a JSON pointer
+// for bodies and the parameter name otherwise.
+type FieldError struct {
+	In      string `json:"in"`
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+type MatchedRoute struct {
+	Operation  *Operation
+	PathParams map[string]string
+	Path       string
+}
+
+// Match finds the operation serving method and the request path, which
+// includes the server base path.
+func (s *Spec) Match(method, path string) (*MatchedRoute, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	base := ""
+	if len(s.doc.Servers) > 0 {
+		base = strings.TrimSuffix(s.doc.Servers[0].URL, "/")
+	}
+
+	rel, ok := strings.CutPrefix(path, base)
+	if !ok {
+		return nil, false
+	}
+
+	segments := splitPath(normalizePath(rel))
+
+	var (
+		best      *MatchedRoute
+		bestScore = -1
+	)
+
+	for template, item := range s.doc.Paths {
+		op, ok := item[strings.ToLower(method)]
+		if !ok {
+			continue
+		}
+
+		params, score, ok := matchTemplate(splitPath(template), segments)
+		if !ok || score <= bestScore {
+			continue
+		}
+
+		best = &MatchedRoute{Operation: op, PathParams: params, Path: template}
+		bestScore = score
+	}
+
+	return best, best != nil
+}
+
+// matchTemplate scores literal segments so /notes/trash wins over /notes/{id}.
+func matchTemplate(template, segments []string) (map[string]string, int, bool) {
+	if len(template) != len(segments) {
+		return nil, 0, false
+	}
+
+	params := make(map[string]string)
+	score := 0
+
+	for i, part := range template {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			name, _, _ := strings.Cut(part[1:len(part)-1], ":")
+			params[name] = segments[i]
+
+			continue
+		}
+
+		if part != segments[i] {
+			return nil, 0, false
+		}
+
+		score++
+	}
+
+	return params, score, true
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// ValidateRequest checks r against its operation and restores the body so
+// handlers can read it again. Requests without an operation are accepted.
+// Only JSON bodies are buffered, up to maxBodySize bytes; multipart and
+// binary ones are left to the handler and its own limits.
+func (s *Spec) ValidateRequest(w http.ResponseWriter, r *http.Request, maxBodySize int64) error {
+	route, ok := s.Match(r.Method, r.URL.Path)
+	if !ok {
+		return nil
+	}
+
+	v := s.newValidation()
+
+	for _, p := range route.Operation.Parameters {
+		var values []string
+
+		switch p.In {
+		case "path":
+			if value, ok := route.PathParams[p.Name]; ok {
+				values = []string{value}
+			}
+		case "query":
+			values = r.URL.Query()[p.Name]
+		case "header":
+			values = r.Header.Values(p.Name)
+		}
+
+		v.parameter(p, values)
+	}
+
+	if body := route.Operation.RequestBody; body != nil {
+		if bufferedBody(body.Content, r.Header.Get("Content-Type")) {
+			raw, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+			if _, ok := errors.AsType[*http.MaxBytesError](err); ok {
+				return ErrRequestTooLarge.Wrap(err).WithDetails(map[string]int64{"max": maxBodySize})
+			}
+
+			if err != nil {
+				return ErrInvalidRequest.Wrap(err)
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(raw))
+
+			v.body(body.Content, r.Header.Get("Content-Type"), raw, body.Required)
+		} else {
+			// Uploads are streamed by the handler, only their media type is
+			// checked here.
+			v.mediaType(body.Content, r.Header.Get("Content-Type"))
+		}
+	}
+
+	if len(v.errs) > 0 {
+		return ErrInvalidRequest.New().WithDetails(v.errs)
+	}
+
+	return nil
+}
+
+// bufferedBody reports whether the request body is JSON the operation
+// accepts, anything else is only checked by its media type.
+func bufferedBody(content map[string]MediaType, contentType string) bool {
+	if _, ok := content[jsonContentType]; !ok {
+		return false
+	}
+
+	if contentType == "" {
+		return true
+	}
+
+	mt, _, err := mime.ParseMediaType(contentType)
+
+	return err == nil && mt == jsonContentType
+}
+
+// ValidateResponse checks a response produced for r against the contract.
+func (s *Spec) ValidateResponse(r *http.Request, status int, header http.Header, body []byte) error {
+	route, ok := s.Match(r.Method, r.URL.Path)
+	if !ok {
+		return nil
+	}
+
+	resp, ok := route.Operation.Responses[strconv.Itoa(status)]
+	if !ok && status >= http.StatusBadRequest {
+		resp, ok = route.Operation.Responses["default"]
+	}
+
+	if !ok {
+		return ErrInvalidResponse.New().WithDetails([]FieldError{{
+			In:      "status",
+			Field:   strconv.Itoa(status),
+			Rule:    "declared",
+			Message: fmt.Sprintf("status %d is not declared for %s %s", status, r.Method, route.Path),
+		}})
+	}
+
+	v := s.newValidation()
+
+	if len(resp.Content) == 0 {
+		if len(body) > 0 {
+			v.fail("body", "", "empty", "response must not have a body")
+		}
+	} else {
+		v.body(resp.Content, header.Get("Content-Type"), body, true)
+	}
+
+	if len(v.errs) > 0 {
+		return ErrInvalidResponse.New().WithDetails(v.errs)
+	}
+
+	return nil
+}
+
+type validation struct {
+	spec *Spec
+	errs []FieldError
+	in   string
+}
+
+func (s *Spec) newValidation() *validation {
+	return &validation{spec: s}
+}
+
+func (v *validation) fail(in, field, rule, message string) {
+	v.errs = append(v.errs, FieldError{In: in, Field: field, Rule: rule, Message: message})
+}
+
+func (v *validation) parameter(p *Parameter, values []string) {
+	v.in = p.In
+
+	if len(values) == 0 {
+		if p.Required {
+			v.fail(p.In, p.Name, "required", "value is required")
+		}
+
+		return
+	}
+
+	schema := v.spec.resolve(p.Schema)
+
+	if schema.Type == "array" {
+		items := make([]any, 0, len(values))
+
+		for _, raw := range values {
+			items = append(items, coerce(v.spec.resolve(schema.Items), raw))
+		}
+
+		v.value(schema, items, p.Name)
+
+		return
+	}
+
+	v.value(schema, coerce(schema, values[0]), p.Name)
+}
+
+// coerce converts a raw parameter into the JSON value its schema expects,
+// leaving it as a string when it does not parse so the type check fails.
+func coerce(schema *Schema, raw string) any {
+	switch schema.Type {
+	case "integer", "number":
+		if n, err := strconv.ParseFloat(raw, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b
+		}
+	}
+
+	return raw
+}
+
+func (v *validation) mediaType(content map[string]MediaType, contentType string) {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if _, ok := content[mt]; err == nil && ok {
+		return
+	}
+
+	for declared := range content {
+		v.fail("body", "", "content_type", "content type must be "+declared)
+		return
+	}
+}
+
+func (v *validation) body(content map[string]MediaType, contentType string, raw []byte, required bool) {
+	v.in = "body"
+
+	media, ok := content[jsonContentType]
+	if !ok {
+		// Binary bodies are streamed as is.
+		return
+	}
+
+	if len(bytes.TrimSpace(raw)) == 0 {
+		if required {
+			v.fail("body", "", "required", "body is required")
+		}
+
+		return
+	}
+
+	if contentType != "" {
+		if mt, _, err := mime.ParseMediaType(contentType); err == nil && mt != jsonContentType {
+			v.fail("body", "", "content_type", "content type must be "+jsonContentType)
+			return
+		}
+	}
+
+	var value any
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	if err := dec.Decode(&value); err != nil {
+		v.fail("body", "", "json", "body is not valid JSON: "+err.Error())
+		return
+	}
+
+	v.value(media.Schema, value, "")
+}
+
+func (v *validation) value(schema *Schema, value any, field string) {
+	schema = v.spec.resolve(schema)
+
+	if value == nil {
+		if !schema.Nullable && schema.Type != "" {
+			v.fail(v.in, field, "type", "value must not be null")
+		}
+
+		return
+	}
+
+	if n, ok := value.(json.Number); ok {
+		f, err := n.Float64()
+		if err != nil {
+			v.fail(v.in, field, "type", "invalid number")
+			return
+		}
+
+		value = f
+	}
+
+	if !v.checkType(schema, value, field) {
+		return
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		v.fail(v.in, field, "enum", fmt.Sprintf("value must be one of %v", schema.Enum))
+	}
+
+	switch val := value.(type) {
+	case string:
+		v.checkString(schema, val, field)
+	case float64:
+		v.checkNumber(schema, val, field)
+	case []any:
+		v.checkArray(schema, val, field)
+	case map[string]any:
+		v.checkObject(schema, val, field)
+	}
+}
+
+func (v *validation) checkType(schema *Schema, value any, field string) bool {
+	var ok bool
+
+	switch schema.Type {
+	case "":
+		return true
+	case "string":
+		_, ok = value.(string)
+	case "integer":
+		f, isNumber := value.(float64)
+		ok = isNumber && f == float64(int64(f))
+	case "number":
+		_, ok = value.(float64)
+	case "boolean":
+		_, ok = value.(bool)
+	case "array":
+		_, ok = value.([]any)
+	case "object":
+		_, ok = value.(map[string]any)
+	}
+
+	if !ok {
+		v.fail(v.in, field, "type", "value must be of type "+schema.Type)
+	}
+
+	return ok
+}
+
+func (v *validation) checkString(schema *Schema, s, field string) {
+	length := utf8.RuneCountInString(s)
+
+	if schema.MinLength != nil && length < *schema.MinLength {
+		v.fail(v.in, field, "minLength", fmt.Sprintf("value must be at least %d characters", *schema.MinLength))
+	}
+
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		v.fail(v.in, field, "maxLength", fmt.Sprintf("value must be at most %d characters", *schema.MaxLength))
+	}
+
+	if schema.Pattern != "" {
+		if re, err := regexp.Compile(schema.Pattern); err == nil && !re.MatchString(s) {
+			v.fail(v.in, field, "pattern", "value must match "+schema.Pattern)
+		}
+	}
+
+	if schema.Format != "" && !validFormat(schema.Format, s) {
+		v.fail(v.in, field, "format", "value must be a valid "+schema.Format)
+	}
+}
+
+func (v *validation) checkNumber(schema *Schema, n float64, field string) {
+	if schema.Minimum != nil && n < *schema.Minimum {
+		v.fail(v.in, field, "minimum", fmt.Sprintf("value must be >= %v", *schema.Minimum))
+	}
+
+	if schema.Maximum != nil && n > *schema.Maximum {
+		v.fail(v.in, field, "maximum", fmt.Sprintf("value must be <= %v", *schema.Maximum))
+	}
+
+	if schema.ExclusiveMinimum != nil && n <= *schema.ExclusiveMinimum {
+		v.fail(v.in, field, "exclusiveMinimum", fmt.Sprintf("value must be > %v", *schema.ExclusiveMinimum))
+	}
+
+	if schema.ExclusiveMaximum != nil && n >= *schema.ExclusiveMaximum {
+		v.fail(v.in, field, "exclusiveMaximum", fmt.Sprintf("value must be < %v", *schema.ExclusiveMaximum))
+	}
+}
+
+func (v *validation) checkArray(schema *Schema, items []any, field string) {
+	if schema.MinItems != nil && len(items) < *schema.MinItems {
+		v.fail(v.in, field, "minItems", fmt.Sprintf("must have at least %d items", *schema.MinItems))
+	}
+
+	if schema.MaxItems != nil && len(items) > *schema.MaxItems {
+		v.fail(v.in, field, "maxItems", fmt.Sprintf("must have at most %d items", *schema.MaxItems))
+	}
+
+	if schema.Items == nil {
+		return
+	}
+
+	for i, item := range items {
+		v.value(schema.Items, item, v.child(field, strconv.Itoa(i)))
+	}
+}
+
+func (v *validation) checkObject(schema *Schema, obj map[string]any, field string) {
+	for _, name := range schema.Required {
+		if _, ok := obj[name]; !ok {
+			v.fail(v.in, v.child(field, name), "required", "value is required")
+		}
+	}
+
+	for name, value := range obj {
+		if prop, ok := schema.Properties[name]; ok {
+			v.value(prop, value, v.child(field, name))
+			continue
+		}
+
+		if schema.AdditionalProperties != nil {
+			v.value(schema.AdditionalProperties, value, v.child(field, name))
+		}
+	}
+}
+
+// child builds JSON pointers for bodies and dotted names for parameters.
+func (v *validation) child(field, name string) string {
+	if v.in != "body" {
+		return field + "." + name
+	}
+
+	name = strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+
+	return field + "/" + name
+}
+
+func (s *Spec) resolve(schema *Schema) *Schema {
+	if schema == nil {
+		return &Schema{}
+	}
+
+	for schema.Ref != "" {
+		resolved, ok := s.doc.Components.Schemas[strings.TrimPrefix(schema.Ref, componentsPrefix)]
+		if !ok {
+			return &Schema{}
+		}
+
+		schema = resolved
+	}
+
+	return schema
+}
+
+func inEnum(enum []any, value any) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validFormat(format, s string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339Nano, s)
+		return err == nil
+	case "email":
+		_, err := mail.ParseAddress(s)
+		return err == nil
+	case "uri":
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != ""
+	case "uuid":
+		return uuidPattern.MatchString(s)
+	default:
+		return true
+	}
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
@@ -0,0 +1,253 @@
+package openapi_test
+
+import (
+	"HATCH_APP/pkg/core/apperr"
+	"HATCH_APP/pkg/transport/httpx/openapi"
+	"HATCH_APP/test/apperrtest"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fieldErrors(t *testing.T, err error) []openapi.FieldError {
+	t.Helper()
+
+	appErr, ok := errors.AsType[*apperr.Error](err)
+	require.True(t, ok, "expected an app error, got %v", err)
+
+	details, ok := appErr.Details.([]openapi.FieldError)
+	require.True(t, ok)
+
+	return details
+}
+
+func TestCodes(t *testing.T) {
+	apperrtest.AssertRegistries(t, openapi.Codes)
+}
+
+func TestSpecMatch(t *testing.T) {
+	spec, _ := newSpec(t)
+
+	route, ok := spec.Match(http.MethodGet, "/api/v1/items/abc")
+	require.True(t, ok)
+	assert.Equal(t, "/v1/items/{id}", route.Path)
+	assert.Equal(t, map[string]string{"id": "abc"}, route.PathParams)
+
+	_, ok = spec.Match(http.MethodPost, "/api/v1/items/")
+	assert.True(t, ok)
+
+	_, ok = spec.Match(http.MethodPut, "/api/v1/items/abc")
+	assert.False(t, ok)
+
+	_, ok = spec.Match(http.MethodGet, "/v1/items/abc")
+	assert.False(t, ok, "paths are relative to the server base path")
+}
+
+func TestSpecValidateRequest(t *testing.T) {
+	spec, _ := newSpec(t)
+
+	tests := []struct {
+		header http.Header
+		name   string
+		method string
+		target string
+		body   string
+		want   []openapi.FieldError
+	}{
+		{
+			name:   "valid body",
+			method: http.MethodPost,
+			target: "/api/v1/items",
+			body:   `{"title":"note","priority":2,"tags":["a"],"color":"red","updated_at":null,"ref":"01J9Z3X4Y5Z6A7B8C9D0E1F2G3"}`,
+		},
+		{
+			name:   "missing required field and bound violations",
+			method: http.MethodPost,
+			target: "/api/v1/items",
+			body:   `{"priority":9,"kind":"rich","color":"green"}`,
+			want: []openapi.FieldError{
+				{In: "body", Field: "/title", Rule: "required"},
+				{In: "body", Field: "/kind", Rule: "enum"},
+				{In: "body", Field: "/color", Rule: "enum"},
+				{In: "body", Field: "/priority", Rule: "exclusiveMaximum"},
+			},
+		},
+		{
+			name:   "wrong types",
+			method: http.MethodPost,
+			target: "/api/v1/items",
+			body:   `{"title":1,"priority":1.5,"tags":"a","ref":"nope"}`,
+			want: []openapi.FieldError{
+				{In: "body", Field: "/ref", Rule: "pattern"},
+				{In: "body", Field: "/title", Rule: "type"},
+				{In: "body", Field: "/priority", Rule: "type"},
+				{In: "body", Field: "/tags", Rule: "type"},
+			},
+		},
+		{
+			name:   "malformed json",
+			method: http.MethodPost,
+			target: "/api/v1/items",
+			body:   `{"title":`,
+			want:   []openapi.FieldError{{In: "body", Rule: "json"}},
+		},
+		{
+			name:   "missing body",
+			method: http.MethodPost,
+			target: "/api/v1/items",
+			want:   []openapi.FieldError{{In: "body", Rule: "required"}},
+		},
+		{
+			name:   "valid params",
+			method: http.MethodGet,
+			target: "/api/v1/items/01J9Z3X4Y5Z6A7B8C9D0E1F2G3?limit=10",
+			header: http.Header{"X-Tenant": {"acme"}},
+		},
+		{
+			name:   "invalid params",
+			method: http.MethodGet,
+			target: "/api/v1/items/short?limit=abc",
+			want: []openapi.FieldError{
+				{In: "path", Field: "id", Rule: "minLength"},
+				{In: "query", Field: "limit", Rule: "type"},
+				{In: "header", Field: "X-Tenant", Rule: "required"},
+			},
+		},
+		{
+			name:   "file upload",
+			method: http.MethodPut,
+			target: "/api/v1/items/01J9Z3X4Y5Z6A7B8C9D0E1F2G3/file",
+			header: http.Header{"Content-Type": {"multipart/form-data; boundary=x"}},
+			body:   "--x--",
+		},
+		{
+			name:   "file upload that is not multipart",
+			method: http.MethodPut,
+			target: "/api/v1/items/01J9Z3X4Y5Z6A7B8C9D0E1F2G3/file",
+			header: http.Header{"Content-Type": {"application/json"}},
+			body:   `{}`,
+			want:   []openapi.FieldError{{In: "body", Rule: "content_type"}},
+		},
+		{
+			name:   "undeclared route",
+			method: http.MethodGet,
+			target: "/api/livez",
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			for k, v := range tc.header {
+				req.Header[k] = v
+			}
+
+			err := spec.ValidateRequest(httptest.NewRecorder(), req, 1<<20)
+
+			if tc.want == nil {
+				require.NoError(t, err)
+
+				body, _ := io.ReadAll(req.Body)
+				assert.Equal(t, tc.body, string(body), "body must be readable by handlers")
+
+				return
+			}
+
+			require.True(t, openapi.ErrInvalidRequest.Is(err))
+
+			got := fieldErrors(t, err)
+			for i := range got {
+				got[i].Message = ""
+			}
+
+			assert.ElementsMatch(t, tc.want, got)
+		})
+	}
+}
+
+func TestSpecValidateRequestBodySize(t *testing.T) {
+	spec, _ := newSpec(t)
+
+	t.Run("should reject json bodies over the limit", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/items", strings.NewReader(`{"title":"note"}`))
+
+		err := spec.ValidateRequest(httptest.NewRecorder(), req, 4)
+
+		assert.True(t, openapi.ErrRequestTooLarge.Is(err))
+		assert.True(t, apperr.IsPayloadTooLarge(err))
+	})
+
+	t.Run("should not buffer uploads", func(t *testing.T) {
+		body := "--x\r\n" + strings.Repeat("a", 64) + "\r\n--x--"
+
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/items/01J9Z3X4Y5Z6A7B8C9D0E1F2G3/file", strings.NewReader(body))
+		req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+
+		require.NoError(t, spec.ValidateRequest(httptest.NewRecorder(), req, 4))
+
+		got, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.Equal(t, body, string(got))
+	})
+
+	t.Run("should not buffer bodies of another content type", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/items", strings.NewReader(`title=note`))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		err := spec.ValidateRequest(httptest.NewRecorder(), req, 4)
+
+		require.True(t, openapi.ErrInvalidRequest.Is(err))
+		assert.Equal(t, "content_type", fieldErrors(t, err)[0].Rule)
+	})
+}
+
+func TestSpecValidateResponse(t *testing.T) {
+	spec, _ := newSpec(t)
+
+	header := http.Header{"Content-Type": {"application/json"}}
+	create := httptest.NewRequest(http.MethodPost, "/api/v1/items", nil)
+	get := httptest.NewRequest(http.MethodGet, "/api/v1/items/01J9Z3X4Y5Z6A7B8C9D0E1F2G3", nil)
+
+	t.Run("should accept declared responses", func(t *testing.T) {
+		require.NoError(t, spec.ValidateResponse(create, http.StatusCreated, header, []byte(`{"data":[{"title":"a"}]}`)))
+		require.NoError(t, spec.ValidateResponse(create, http.StatusBadRequest, header, []byte(`{"message":"bad"}`)))
+		require.NoError(t, spec.ValidateResponse(get, http.StatusNoContent, nil, nil))
+	})
+
+	t.Run("should not check binary bodies", func(t *testing.T) {
+		file := httptest.NewRequest(http.MethodPut, "/api/v1/items/01J9Z3X4Y5Z6A7B8C9D0E1F2G3/file", nil)
+
+		require.NoError(t, spec.ValidateResponse(file, http.StatusOK, http.Header{"Content-Type": {"image/png"}}, []byte{0x89, 'P'}))
+		require.NoError(t, spec.ValidateResponse(file, http.StatusOK, http.Header{"Content-Type": {"text/plain"}}, nil))
+	})
+
+	t.Run("should reject undeclared statuses", func(t *testing.T) {
+		err := spec.ValidateResponse(create, http.StatusOK, header, []byte(`{}`))
+
+		require.True(t, openapi.ErrInvalidResponse.Is(err))
+		assert.Equal(t, "declared", fieldErrors(t, err)[0].Rule)
+	})
+
+	t.Run("should reject bodies that drift from the schema", func(t *testing.T) {
+		err := spec.ValidateResponse(create, http.StatusCreated, header, []byte(`{"data":[{"priority":"high"}]}`))
+
+		require.True(t, openapi.ErrInvalidResponse.Is(err))
+		assert.ElementsMatch(t, []string{"/data/0/title", "/data/0/priority"}, []string{
+			fieldErrors(t, err)[0].Field,
+			fieldErrors(t, err)[1].Field,
+		})
+	})
+
+	t.Run("should reject bodies on empty responses", func(t *testing.T) {
+		err := spec.ValidateResponse(get, http.StatusNoContent, header, []byte(`{}`))
+
+		require.True(t, openapi.ErrInvalidResponse.Is(err))
+	})
+}
@@ -0,0 +1,88 @@
+package httpx
+
+import (
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/transport/httpx/openapi"
+	"bytes"
+	"net/http"
+)
+
+// DefaultOpenAPIMaxBodySize bounds the JSON bodies buffered for validation
+// when OpenAPIValidation.MaxBodySize is not set.
+const DefaultOpenAPIMaxBodySize = 1 << 20
+
+type OpenAPIValidation struct {
+	// OnResponseError is called with responses that drift from the contract,
+	// they are replaced by a 500 when it is nil.
+	OnResponseError func(r *http.Request, err error)
+	// Responses buffers every response to check it against the spec, it is
+	// meant for tests.
+	Responses bool
+	// MaxBodySize bounds the JSON request bodies buffered for validation, in
+	// bytes. Larger ones are rejected with a 413.
+	MaxBodySize int64
+}
+
+// WithOpenAPIValidation rejects requests whose path, query, headers or body
+// violate the spec. Routes the spec does not declare are left untouched.
+func WithOpenAPIValidation(spec *openapi.Spec, cfg OpenAPIValidation) func(next http.Handler) http.Handler {
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = DefaultOpenAPIMaxBodySize
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			log := o11y.LoggerFromContext(r.Context()).With("middleware", "OpenAPIValidation")
+
+			if err := spec.ValidateRequest(w, r, cfg.MaxBodySize); err != nil {
+				WriteError(log, w, err)
+				return
+			}
+
+			if !cfg.Responses {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rec := &responseRecorder{ResponseWriter: w}
+
+			next.ServeHTTP(rec, r)
+
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+
+			if err := spec.ValidateResponse(r, rec.status, w.Header(), rec.body.Bytes()); err != nil {
+				if cfg.OnResponseError == nil {
+					WriteError(log, w, err)
+					return
+				}
+
+				cfg.OnResponseError(r, err)
+			}
+
+			w.WriteHeader(rec.status)
+			_, _ = w.Write(rec.body.Bytes())
+		})
+	}
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	body   bytes.Buffer
+	status int
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+
+	return r.body.Write(b)
+}
@@ -0,0 +1,126 @@
+package httpx_test
+
+import (
+	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/transport/httpx"
+	"HATCH_APP/pkg/transport/httpx/openapi"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type echoRequest struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type echoResponse struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func newRouter(t *testing.T, cfg httpx.OpenAPIValidation, handler http.HandlerFunc) http.Handler {
+	t.Helper()
+
+	o11y.InitLogger()
+
+	spec := openapi.New(openapi.Info{Title: "test", Version: "1"}, "", httpx.ErrorResponse{})
+
+	r := chi.NewRouter()
+	r.Use(httpx.WithOpenAPIValidation(spec, cfg))
+
+	openapi.Mount(r, spec, "/echo", openapi.Route{
+		Method:      http.MethodPost,
+		Path:        "/",
+		Handler:     handler,
+		OperationID: "echo",
+		Request:     echoRequest{},
+		Responses:   map[int]any{http.StatusOK: echoResponse{}},
+		Errors:      []int{http.StatusBadRequest},
+	})
+
+	return r
+}
+
+func serve(h http.Handler, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(body)))
+
+	return rec
+}
+
+func TestWithOpenAPIValidation(t *testing.T) {
+	t.Run("should reject invalid requests with details", func(t *testing.T) {
+		called := false
+
+		h := newRouter(t, httpx.OpenAPIValidation{}, func(http.ResponseWriter, *http.Request) {
+			called = true
+		})
+
+		rec := serve(h, `{}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"OPENAPI_INVALID_REQUEST"`)
+		assert.Contains(t, rec.Body.String(), `"field":"/name"`)
+		assert.False(t, called)
+	})
+
+	t.Run("should pass valid requests through", func(t *testing.T) {
+		h := newRouter(t, httpx.OpenAPIValidation{}, func(w http.ResponseWriter, r *http.Request) {
+			req, err := httpx.ParseRequest[echoRequest](w, r)
+			require.NoError(t, err)
+
+			httpx.WriteOKResponse(w, echoResponse(*req))
+		})
+
+		rec := serve(h, `{"name":"hatch"}`)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"name":"hatch"}`, rec.Body.String())
+	})
+
+	t.Run("should reject bodies over the size limit", func(t *testing.T) {
+		called := false
+
+		h := newRouter(t, httpx.OpenAPIValidation{MaxBodySize: 8}, func(http.ResponseWriter, *http.Request) {
+			called = true
+		})
+
+		rec := serve(h, `{"name":"hatch"}`)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"OPENAPI_REQUEST_TOO_LARGE"`)
+		assert.False(t, called)
+	})
+
+	t.Run("should replace drifting responses", func(t *testing.T) {
+		h := newRouter(t, httpx.OpenAPIValidation{Responses: true}, func(w http.ResponseWriter, _ *http.Request) {
+			httpx.WriteOKResponse(w, map[string]int{"name": 1})
+		})
+
+		rec := serve(h, `{"name":"hatch"}`)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"OPENAPI_INVALID_RESPONSE"`)
+	})
+
+	t.Run("should report drifting responses to the hook", func(t *testing.T) {
+		var reported error
+
+		h := newRouter(t, httpx.OpenAPIValidation{
+			Responses:       true,
+			OnResponseError: func(_ *http.Request, err error) { reported = err },
+		}, func(w http.ResponseWriter, _ *http.Request) {
+			httpx.WriteCreatedResponse(w, echoResponse{Name: "hatch"})
+		})
+
+		rec := serve(h, `{"name":"hatch"}`)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		require.Error(t, reported)
+		assert.True(t, openapi.ErrInvalidResponse.Is(reported))
+	})
+}
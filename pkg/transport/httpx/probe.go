@@ -2,6 +2,7 @@ package httpx
 
 import (
 	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/transport/httpx/openapi"
 	"context"
 	"net/http"
 	"time"
@@ -12,11 +13,17 @@ import (
 
 type External struct {
 	DB *sqlx.DB
+	// OpenAPI is served at /api/openapi.json when set.
+	OpenAPI *openapi.Spec
 }
 
 func registerProbes(r *chi.Mux, ext External) {
 	r.Get("/api/livez", livenessRoute())
 	r.Get("/api/readyz", readinessRoute(ext))
+
+	if ext.OpenAPI != nil {
+		r.Get("/api/openapi.json", ext.OpenAPI.Handler())
+	}
 }
 
 func livenessRoute() http.HandlerFunc {
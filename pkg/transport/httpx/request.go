@@ -1,6 +1,8 @@
 package httpx
 
 import (
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/core/apperr"
 	"HATCH_APP/pkg/validator"
 	"encoding/json"
 	"errors"
@@ -29,9 +31,15 @@ func ParseRequest[T any](w http.ResponseWriter, r *http.Request) (*T, error) {
 	val := validator.ValidatorFromContext(r.Context())
 
 	if err := val.Validate(obj); err != nil {
-		WriteResponse(w, http.StatusBadRequest, map[string]string{
-			"message": fmt.Sprintf("%s: %s", ErrInvalidPayload.Error(), err.Error()),
-		})
+		resp := ErrorResponse{
+			Message: fmt.Sprintf("%s: %s", ErrInvalidPayload.Error(), err.Error()),
+		}
+
+		if fieldErrs, ok := errors.AsType[validator.Errors](err); ok {
+			resp.Details = fieldErrs
+		}
+
+		WriteResponse(w, http.StatusBadRequest, resp)
 
 		return nil, err
 	}
@@ -49,6 +57,20 @@ func GetPathParam(r *http.Request, key string) (string, error) {
 	return param, nil
 }
 
+// PathID parses the key path param as a core.ID and answers 400 when it is
+// malformed, so services are never hit with invalid ids.
+func PathID(w http.ResponseWriter, r *http.Request, key string) (core.ID, error) {
+	id, err := core.ParseID(r.PathValue(key))
+	if err != nil {
+		appErr, _ := errors.AsType[*apperr.Error](err)
+		writeAppError(w, appErr)
+
+		return core.ID{}, err
+	}
+
+	return id, nil
+}
+
 func GetQueryParam(r *http.Request, key string) (string, error) {
 	param := r.URL.Query().Get(key)
 
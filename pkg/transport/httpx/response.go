@@ -91,6 +91,18 @@ func mapStatus(t apperr.ErrorType) int {
 		return http.StatusBadRequest
 	case apperr.TypeUnauthorized:
 		return http.StatusUnauthorized
+	case apperr.TypeForbidden:
+		return http.StatusForbidden
+	case apperr.TypeRateLimited:
+		return http.StatusTooManyRequests
+	case apperr.TypeUnavailable:
+		return http.StatusServiceUnavailable
+	case apperr.TypeTimeout:
+		return http.StatusGatewayTimeout
+	case apperr.TypePreconditionFailed:
+		return http.StatusPreconditionFailed
+	case apperr.TypePayloadTooLarge:
+		return http.StatusRequestEntityTooLarge
 	default:
 		return http.StatusInternalServerError
 	}
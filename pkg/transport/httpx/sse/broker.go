@@ -0,0 +1,178 @@
+package sse
+
+import (
+	"HATCH_APP/pkg/core/apperr"
+	"slices"
+	"sync"
+	"time"
+)
+
+var Codes = apperr.NewRegistry("SSE")
+
+var ErrStreamClosed = Codes.Register("SSE_STREAM_CLOSED", apperr.TypeUnavailable,
+	"event stream closed",
+	"The server is shutting down, reconnect with Last-Event-ID to resume the stream elsewhere.")
+
+// Event is one message of a stream, clients resume after its ID.
+type Event struct {
+	ID   string
+	Type string
+	Data []byte
+	// Audience restricts the event to the subscribers of the same audience.
+	Audience string
+}
+
+type Config struct {
+	// ReplaySize is the number of latest events kept to resume streams.
+	ReplaySize int
+	// BufferSize is the number of events a subscriber may lag behind, it is
+	// dropped beyond and resumes with Last-Event-ID once reconnected.
+	BufferSize int
+	// Heartbeat is the interval of the comments keeping idle streams and the
+	// proxies in front of them alive.
+	Heartbeat time.Duration
+	// WriteTimeout bounds each write, so dead clients are dropped.
+	WriteTimeout time.Duration
+}
+
+// Broker fans events out to the streams of the process.
+type Broker struct {
+	subs   map[*subscriber]struct{}
+	replay []Event
+	cfg    Config
+	mu     sync.Mutex
+	closed bool
+}
+
+type subscriber struct {
+	events   chan Event
+	done     chan struct{}
+	audience string
+}
+
+func NewBroker(cfg Config) *Broker {
+	if cfg.ReplaySize <= 0 {
+		cfg.ReplaySize = 1000
+	}
+
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 64
+	}
+
+	if cfg.Heartbeat <= 0 {
+		cfg.Heartbeat = 15 * time.Second
+	}
+
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = 10 * time.Second
+	}
+
+	return &Broker{
+		subs: make(map[*subscriber]struct{}),
+		cfg:  cfg,
+	}
+}
+
+// Publish never blocks, subscribers lagging more than BufferSize events
+// behind are dropped.
+func (b *Broker) Publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	if len(b.replay) == b.cfg.ReplaySize {
+		b.replay = slices.Delete(b.replay, 0, 1)
+	}
+
+	b.replay = append(b.replay, event)
+
+	for sub := range b.subs {
+		if sub.audience != event.Audience {
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+		default:
+			b.drop(sub)
+		}
+	}
+}
+
+// Close ends every stream and refuses new ones, it is meant to be registered
+// with http.Server.RegisterOnShutdown as Shutdown waits for streams forever.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+
+	for sub := range b.subs {
+		b.drop(sub)
+	}
+}
+
+// subscribe returns the events of audience published after lastEventID,
+// resumed is false when it fell out of the replay buffer.
+func (b *Broker) subscribe(audience, lastEventID string) (*subscriber, []Event, bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, nil, false, ErrStreamClosed.New()
+	}
+
+	missed, resumed := b.missed(audience, lastEventID)
+
+	sub := &subscriber{
+		events:   make(chan Event, b.cfg.BufferSize),
+		done:     make(chan struct{}),
+		audience: audience,
+	}
+
+	b.subs[sub] = struct{}{}
+
+	return sub, missed, resumed, nil
+}
+
+// missed must be called with mu held.
+func (b *Broker) missed(audience, lastEventID string) ([]Event, bool) {
+	if lastEventID == "" {
+		return nil, true
+	}
+
+	i := slices.IndexFunc(b.replay, func(e Event) bool { return e.ID == lastEventID })
+	if i < 0 {
+		return nil, false
+	}
+
+	var missed []Event
+
+	for _, e := range b.replay[i+1:] {
+		if e.Audience == audience {
+			missed = append(missed, e)
+		}
+	}
+
+	return missed, true
+}
+
+func (b *Broker) unsubscribe(sub *subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.drop(sub)
+}
+
+// drop must be called with mu held.
+func (b *Broker) drop(sub *subscriber) {
+	if _, ok := b.subs[sub]; !ok {
+		return
+	}
+
+	delete(b.subs, sub)
+	close(sub.done)
+}
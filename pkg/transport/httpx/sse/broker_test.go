@@ -0,0 +1,176 @@
+package sse_test
+
+import (
+	"HATCH_APP/pkg/transport/httpx/sse"
+	"HATCH_APP/test/apperrtest"
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodes(t *testing.T) {
+	apperrtest.AssertRegistries(t, sse.Codes)
+}
+
+type client struct {
+	res    *http.Response
+	reader *bufio.Reader
+}
+
+// connect opens a stream of audience on a server backed by broker.
+func connect(t *testing.T, broker *sse.Broker, audience, lastEventID string) *client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := broker.Stream(w, r, audience); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	res, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = res.Body.Close() })
+
+	return &client{res: res, reader: bufio.NewReader(res.Body)}
+}
+
+// next returns the next message, comments included, without its trailing
+// blank line.
+func (c *client) next(t *testing.T) string {
+	t.Helper()
+
+	var lines []string
+
+	for {
+		line, err := c.reader.ReadString('\n')
+		require.NoError(t, err)
+
+		if line == "\n" {
+			return strings.Join(lines, "\n")
+		}
+
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+}
+
+func event(n int, audience string) sse.Event {
+	return sse.Event{
+		ID:       strconv.Itoa(n),
+		Type:     "note.created",
+		Data:     []byte(`{"n":` + strconv.Itoa(n) + `}`),
+		Audience: audience,
+	}
+}
+
+// waitSubscribed publishes a probe until the stream receives it, so events
+// published afterwards can't race the subscription.
+func waitSubscribed(t *testing.T, broker *sse.Broker, c *client, audience string) {
+	t.Helper()
+
+	broker.Publish(sse.Event{ID: "probe", Type: "probe", Data: []byte("{}"), Audience: audience})
+	assert.Equal(t, "id: probe\nevent: probe\ndata: {}", c.next(t))
+}
+
+func TestBrokerStream(t *testing.T) {
+	t.Run("should stream the events of the audience", func(t *testing.T) {
+		broker := sse.NewBroker(sse.Config{})
+		c := connect(t, broker, "alice", "")
+
+		assert.Equal(t, "text/event-stream", c.res.Header.Get("Content-Type"))
+		waitSubscribed(t, broker, c, "alice")
+
+		broker.Publish(event(1, "bob"))
+		broker.Publish(event(2, "alice"))
+
+		assert.Equal(t, "id: 2\nevent: note.created\ndata: {\"n\":2}", c.next(t))
+	})
+
+	t.Run("should split multiline data", func(t *testing.T) {
+		broker := sse.NewBroker(sse.Config{})
+		c := connect(t, broker, "", "")
+		waitSubscribed(t, broker, c, "")
+
+		broker.Publish(sse.Event{ID: "1", Data: []byte("a\nb")})
+
+		assert.Equal(t, "id: 1\ndata: a\ndata: b", c.next(t))
+	})
+
+	t.Run("should resume after the last event id", func(t *testing.T) {
+		broker := sse.NewBroker(sse.Config{ReplaySize: 10})
+
+		for n := range 4 {
+			broker.Publish(event(n+1, "alice"))
+		}
+
+		broker.Publish(event(5, "bob"))
+
+		c := connect(t, broker, "alice", "2")
+
+		assert.Equal(t, "id: 3\nevent: note.created\ndata: {\"n\":3}", c.next(t))
+		assert.Equal(t, "id: 4\nevent: note.created\ndata: {\"n\":4}", c.next(t))
+		waitSubscribed(t, broker, c, "alice")
+	})
+
+	t.Run("should ask for a reset when the last event id is gone", func(t *testing.T) {
+		broker := sse.NewBroker(sse.Config{ReplaySize: 2})
+
+		for n := range 3 {
+			broker.Publish(event(n+1, "alice"))
+		}
+
+		c := connect(t, broker, "alice", "1")
+
+		assert.Equal(t, "event: reset\ndata: {}", c.next(t))
+		waitSubscribed(t, broker, c, "alice")
+	})
+
+	t.Run("should send heartbeats", func(t *testing.T) {
+		broker := sse.NewBroker(sse.Config{Heartbeat: 10 * time.Millisecond})
+		c := connect(t, broker, "", "")
+
+		assert.Equal(t, ": heartbeat", c.next(t))
+	})
+
+	t.Run("should drop lagging subscribers", func(t *testing.T) {
+		broker := sse.NewBroker(sse.Config{BufferSize: 1})
+		c := connect(t, broker, "", "")
+		waitSubscribed(t, broker, c, "")
+
+		// The stream is not read, so the buffer and the connection fill up.
+		for n := range 10_000 {
+			broker.Publish(event(n, ""))
+		}
+
+		_, err := c.reader.Discard(1 << 30)
+		assert.Error(t, err)
+	})
+
+	t.Run("should end streams and refuse new ones once closed", func(t *testing.T) {
+		broker := sse.NewBroker(sse.Config{})
+		c := connect(t, broker, "", "")
+		waitSubscribed(t, broker, c, "")
+
+		broker.Close()
+
+		_, err := c.reader.ReadString('\n')
+		assert.Error(t, err)
+
+		refused := connect(t, broker, "", "")
+		assert.Equal(t, http.StatusServiceUnavailable, refused.res.StatusCode)
+	})
+}
@@ -0,0 +1,101 @@
+package sse
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ResetEvent is sent instead of the missed events when Last-Event-ID fell
+// out of the replay buffer, clients must reload their state.
+const ResetEvent = "reset"
+
+// Stream serves the events of audience on w until the client goes away or
+// the broker is closed. An error is only returned when nothing was written.
+func (b *Broker) Stream(w http.ResponseWriter, r *http.Request, audience string) error {
+	sub, missed, resumed, err := b.subscribe(audience, r.Header.Get("Last-Event-ID"))
+	if err != nil {
+		return err
+	}
+
+	defer b.unsubscribe(sub)
+
+	rc := http.NewResponseController(w)
+
+	// The server timeouts are sized for regular requests, the stream
+	// deadlines are extended on each write instead.
+	_ = rc.SetReadDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	write := func(buf []byte) bool {
+		_ = rc.SetWriteDeadline(time.Now().Add(b.cfg.WriteTimeout))
+
+		if _, err := w.Write(buf); err != nil {
+			return false
+		}
+
+		return rc.Flush() == nil
+	}
+
+	var head bytes.Buffer
+
+	if !resumed {
+		// Events without data are not dispatched by EventSource.
+		writeEvent(&head, Event{Type: ResetEvent, Data: []byte("{}")})
+	}
+
+	for _, e := range missed {
+		writeEvent(&head, e)
+	}
+
+	if !write(head.Bytes()) {
+		return nil
+	}
+
+	heartbeat := time.NewTicker(b.cfg.Heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		var buf bytes.Buffer
+
+		select {
+		case <-r.Context().Done():
+			return nil
+		case <-sub.done:
+			// Dropped for lagging or closed, the client reconnects and
+			// resumes.
+			return nil
+		case e := <-sub.events:
+			writeEvent(&buf, e)
+		case <-heartbeat.C:
+			buf.WriteString(": heartbeat\n\n")
+		}
+
+		if !write(buf.Bytes()) {
+			return nil
+		}
+	}
+}
+
+func writeEvent(w io.Writer, e Event) {
+	if e.ID != "" {
+		fmt.Fprintf(w, "id: %s\n", e.ID)
+	}
+
+	if e.Type != "" {
+		fmt.Fprintf(w, "event: %s\n", e.Type)
+	}
+
+	for line := range strings.SplitSeq(string(e.Data), "\n") {
+		fmt.Fprintf(w, "data: %s\n", line)
+	}
+
+	fmt.Fprint(w, "\n")
+}
@@ -0,0 +1,29 @@
+package validator
+
+import (
+	"strings"
+)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return e.Message
+}
+
+// Errors is what Validate returns when fields fail their rules.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+
+	for _, fe := range e {
+		msgs = append(msgs, fe.Message)
+	}
+
+	return strings.Join(msgs, ", ")
+}
@@ -0,0 +1,275 @@
+package validator
+
+import (
+	"cmp"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const DefaultLocale = "en"
+
+// fallbackKey is the message of tags without one of their own.
+const fallbackKey = "default"
+
+// Size rules read differently for strings, collections and numbers, so their
+// messages are keyed by tag and kind, e.g. "max.string".
+var sizeRules = map[string]bool{
+	"len": true, "min": true, "max": true,
+	"gt": true, "gte": true, "lt": true, "lte": true,
+}
+
+type catalog struct {
+	messages map[string]map[string]string
+	mu       sync.RWMutex
+}
+
+func newCatalog() *catalog {
+	c := &catalog{messages: make(map[string]map[string]string)}
+
+	for locale, msgs := range builtinMessages {
+		c.messages[locale] = make(map[string]string, len(msgs))
+
+		for key, msg := range msgs {
+			c.messages[locale][key] = msg
+		}
+	}
+
+	return c
+}
+
+func (c *catalog) add(key string, messages map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for locale, msg := range messages {
+		if c.messages[locale] == nil {
+			c.messages[locale] = make(map[string]string)
+		}
+
+		c.messages[locale][key] = msg
+	}
+}
+
+func (c *catalog) render(locale string, fe validator.FieldError) string {
+	tag := fe.Tag()
+	keys := []string{tag}
+
+	if sizeRules[tag] {
+		keys = []string{tag + "." + sizeKind(fe.Kind()), tag}
+	}
+
+	param := fe.Param()
+	if tag == "oneof" {
+		param = strings.Join(strings.Fields(param), ", ")
+	}
+
+	msg := c.lookup(locale, keys)
+
+	return strings.NewReplacer("{field}", fieldPath(fe.Namespace()), "{param}", param).Replace(msg)
+}
+
+func (c *catalog) lookup(locale string, keys []string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, key := range append(keys, fallbackKey) {
+		for _, l := range []string{locale, DefaultLocale} {
+			if msg, ok := c.messages[l][key]; ok {
+				return msg
+			}
+		}
+	}
+
+	return "{field} is invalid"
+}
+
+// match picks the supported locale that best fits an Accept-Language
+// header, trying each language by quality and then its base language.
+func (c *catalog) match(acceptLanguage string) string {
+	c.mu.RLock()
+	locales := slices.Sorted(maps.Keys(c.messages))
+	c.mu.RUnlock()
+
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		if i := slices.IndexFunc(locales, func(l string) bool {
+			return strings.EqualFold(l, tag)
+		}); i >= 0 {
+			return locales[i]
+		}
+
+		base, _, _ := strings.Cut(tag, "-")
+
+		if i := slices.IndexFunc(locales, func(l string) bool {
+			lBase, _, _ := strings.Cut(l, "-")
+			return strings.EqualFold(lBase, base)
+		}); i >= 0 {
+			return locales[i]
+		}
+	}
+
+	return DefaultLocale
+}
+
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var langs []weighted
+
+	for part := range strings.SplitSeq(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+
+			q = parsed
+		}
+
+		if q > 0 {
+			langs = append(langs, weighted{tag: tag, q: q})
+		}
+	}
+
+	slices.SortStableFunc(langs, func(a, b weighted) int {
+		return cmp.Compare(b.q, a.q)
+	})
+
+	tags := make([]string, 0, len(langs))
+
+	for _, l := range langs {
+		tags = append(tags, l.tag)
+	}
+
+	return tags
+}
+
+func sizeKind(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "items"
+	default:
+		return "number"
+	}
+}
+
+var builtinMessages = map[string]map[string]string{
+	"en": {
+		fallbackKey:     "{field} is invalid",
+		"required":      "{field} is required",
+		"email":         "{field} must be a valid email address",
+		"url":           "{field} must be a valid URL",
+		"uri":           "{field} must be a valid URI",
+		"uuid":          "{field} must be a valid UUID",
+		"oneof":         "{field} must be one of {param}",
+		"len.string":    "{field} must be exactly {param} characters",
+		"len.items":     "{field} must contain exactly {param} items",
+		"len.number":    "{field} must be {param}",
+		"min.string":    "{field} must be at least {param} characters",
+		"min.items":     "{field} must contain at least {param} items",
+		"min.number":    "{field} must be at least {param}",
+		"max.string":    "{field} must be at most {param} characters",
+		"max.items":     "{field} must contain at most {param} items",
+		"max.number":    "{field} must be at most {param}",
+		"gt.string":     "{field} must be longer than {param} characters",
+		"gt.items":      "{field} must contain more than {param} items",
+		"gt.number":     "{field} must be greater than {param}",
+		"gte.string":    "{field} must be at least {param} characters",
+		"gte.items":     "{field} must contain at least {param} items",
+		"gte.number":    "{field} must be at least {param}",
+		"lt.string":     "{field} must be shorter than {param} characters",
+		"lt.items":      "{field} must contain fewer than {param} items",
+		"lt.number":     "{field} must be less than {param}",
+		"lte.string":    "{field} must be at most {param} characters",
+		"lte.items":     "{field} must contain at most {param} items",
+		"lte.number":    "{field} must be at most {param}",
+		"eqfield":       "{field} must match {param}",
+		"nefield":       "{field} must differ from {param}",
+		"required_with": "{field} is required when {param} is set",
+	},
+	"pt-BR": {
+		fallbackKey:     "{field} é inválido",
+		"required":      "{field} é obrigatório",
+		"email":         "{field} deve ser um e-mail válido",
+		"url":           "{field} deve ser uma URL válida",
+		"uri":           "{field} deve ser uma URI válida",
+		"uuid":          "{field} deve ser um UUID válido",
+		"oneof":         "{field} deve ser um de {param}",
+		"len.string":    "{field} deve ter exatamente {param} caracteres",
+		"len.items":     "{field} deve conter exatamente {param} itens",
+		"len.number":    "{field} deve ser {param}",
+		"min.string":    "{field} deve ter no mínimo {param} caracteres",
+		"min.items":     "{field} deve conter no mínimo {param} itens",
+		"min.number":    "{field} deve ser no mínimo {param}",
+		"max.string":    "{field} deve ter no máximo {param} caracteres",
+		"max.items":     "{field} deve conter no máximo {param} itens",
+		"max.number":    "{field} deve ser no máximo {param}",
+		"gt.string":     "{field} deve ter mais de {param} caracteres",
+		"gt.items":      "{field} deve conter mais de {param} itens",
+		"gt.number":     "{field} deve ser maior que {param}",
+		"gte.string":    "{field} deve ter no mínimo {param} caracteres",
+		"gte.items":     "{field} deve conter no mínimo {param} itens",
+		"gte.number":    "{field} deve ser no mínimo {param}",
+		"lt.string":     "{field} deve ter menos de {param} caracteres",
+		"lt.items":      "{field} deve conter menos de {param} itens",
+		"lt.number":     "{field} deve ser menor que {param}",
+		"lte.string":    "{field} deve ter no máximo {param} caracteres",
+		"lte.items":     "{field} deve conter no máximo {param} itens",
+		"lte.number":    "{field} deve ser no máximo {param}",
+		"eqfield":       "{field} deve ser igual a {param}",
+		"nefield":       "{field} deve ser diferente de {param}",
+		"required_with": "{field} é obrigatório quando {param} é informado",
+	},
+	"es": {
+		fallbackKey:     "{field} no es válido",
+		"required":      "{field} es obligatorio",
+		"email":         "{field} debe ser un correo electrónico válido",
+		"url":           "{field} debe ser una URL válida",
+		"uri":           "{field} debe ser una URI válida",
+		"uuid":          "{field} debe ser un UUID válido",
+		"oneof":         "{field} debe ser uno de {param}",
+		"len.string":    "{field} debe tener exactamente {param} caracteres",
+		"len.items":     "{field} debe contener exactamente {param} elementos",
+		"len.number":    "{field} debe ser {param}",
+		"min.string":    "{field} debe tener al menos {param} caracteres",
+		"min.items":     "{field} debe contener al menos {param} elementos",
+		"min.number":    "{field} debe ser al menos {param}",
+		"max.string":    "{field} debe tener como máximo {param} caracteres",
+		"max.items":     "{field} debe contener como máximo {param} elementos",
+		"max.number":    "{field} debe ser como máximo {param}",
+		"gt.string":     "{field} debe tener más de {param} caracteres",
+		"gt.items":      "{field} debe contener más de {param} elementos",
+		"gt.number":     "{field} debe ser mayor que {param}",
+		"gte.string":    "{field} debe tener al menos {param} caracteres",
+		"gte.items":     "{field} debe contener al menos {param} elementos",
+		"gte.number":    "{field} debe ser al menos {param}",
+		"lt.string":     "{field} debe tener menos de {param} caracteres",
+		"lt.items":      "{field} debe contener menos de {param} elementos",
+		"lt.number":     "{field} debe ser menor que {param}",
+		"lte.string":    "{field} debe tener como máximo {param} caracteres",
+		"lte.items":     "{field} debe contener como máximo {param} elementos",
+		"lte.number":    "{field} debe ser como máximo {param}",
+		"eqfield":       "{field} debe coincidir con {param}",
+		"nefield":       "{field} debe ser distinto de {param}",
+		"required_with": "{field} es obligatorio cuando {param} está presente",
+	},
+}
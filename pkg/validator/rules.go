@@ -0,0 +1,64 @@
+package validator
+
+import (
+	"HATCH_APP/pkg/core"
+	"regexp"
+	"strings"
+)
+
+type rule struct {
+	fn       func(FieldLevel) bool
+	messages map[string]string
+	tag      string
+}
+
+var builtinRules = []rule{
+	{
+		tag: "notblank",
+		fn: func(fl FieldLevel) bool {
+			return strings.TrimSpace(fl.Field().String()) != ""
+		},
+		messages: map[string]string{
+			"en":    "{field} must not be blank",
+			"pt-BR": "{field} não pode estar em branco",
+			"es":    "{field} no puede estar en blanco",
+		},
+	},
+	{
+		tag: "ulid",
+		fn: func(fl FieldLevel) bool {
+			return core.IsValidID(fl.Field().String())
+		},
+		messages: map[string]string{
+			"en":    "{field} must be a valid ULID",
+			"pt-BR": "{field} deve ser um ULID válido",
+			"es":    "{field} debe ser un ULID válido",
+		},
+	},
+	{
+		tag: "safe_markdown",
+		fn: func(fl FieldLevel) bool {
+			return IsSafeMarkdown(fl.Field().String())
+		},
+		messages: map[string]string{
+			"en":    "{field} must not contain scripts, embeds or event handlers",
+			"pt-BR": "{field} não pode conter scripts, embeds ou handlers de eventos",
+			"es":    "{field} no puede contener scripts, embeds ni manejadores de eventos",
+		},
+	},
+}
+
+var (
+	unsafeTag     = regexp.MustCompile(`(?i)<\s*/?\s*(script|iframe|object|embed|style|link|meta|base|form)\b`)
+	eventHandler  = regexp.MustCompile(`(?i)<[^>]*\son[a-z]+\s*=`)
+	unsafeLinkURL = regexp.MustCompile(`(?i)(\]\(\s*<?|<|\b(href|src)\s*=\s*["']?)\s*(javascript|vbscript|data)\s*:`)
+)
+
+// IsSafeMarkdown rejects markdown carrying active content: script-like tags,
+// inline event handlers and javascript:, vbscript: or data: links. It is an
+// input check, rendered output is still sanitized.
+func IsSafeMarkdown(s string) bool {
+	return !unsafeTag.MatchString(s) &&
+		!eventHandler.MatchString(s) &&
+		!unsafeLinkURL.MatchString(s)
+}
@@ -1,26 +1,111 @@
 package validator
 
 import (
+	"HATCH_APP/pkg/core"
 	"context"
 	"errors"
-	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
 )
 
+type (
+	FieldLevel  = validator.FieldLevel
+	StructLevel = validator.StructLevel
+)
+
 type Validator struct {
 	validator *validator.Validate
+	messages  *catalog
+	locale    string
 }
 
 type ctxKey struct{}
 
 func New() *Validator {
+	v := validator.New()
+
+	// Errors name fields the way clients send them.
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+		switch name {
+		case "-":
+			return ""
+		case "":
+			return field.Name
+		default:
+			return name
+		}
+	})
+
+	// core.ID fields are validated as their string form, so required and
+	// ulid work on them too.
+	v.RegisterCustomTypeFunc(func(field reflect.Value) any {
+		id, _ := field.Interface().(core.ID)
+		return id.String()
+	}, core.ID{})
+
+	val := &Validator{
+		validator: v,
+		messages:  newCatalog(),
+		locale:    DefaultLocale,
+	}
+
+	for _, r := range builtinRules {
+		if err := val.RegisterRule(r.tag, r.fn, r.messages); err != nil {
+			panic(err)
+		}
+	}
+
+	return val
+}
+
+// RegisterRule adds a field rule usable in validate tags. messages holds the
+// message template per locale, {field} and {param} are filled in on failure.
+func (v *Validator) RegisterRule(tag string, fn func(FieldLevel) bool, messages map[string]string) error {
+	if err := v.validator.RegisterValidation(tag, fn); err != nil {
+		return err
+	}
+
+	v.messages.add(tag, messages)
+
+	return nil
+}
+
+// RegisterStructRule runs fn after the field rules of every given type.
+// Failures are reported with sl.ReportError and translated by their tag, so
+// the tag needs messages registered with RegisterMessages.
+func (v *Validator) RegisterStructRule(fn func(StructLevel), types ...any) {
+	v.validator.RegisterStructValidation(fn, types...)
+}
+
+// RegisterMessages sets the templates of a tag without a field rule, for
+// tags reported by struct rules.
+func (v *Validator) RegisterMessages(tag string, messages map[string]string) {
+	v.messages.add(tag, messages)
+}
+
+// Localized returns a validator with the same rules that writes messages in
+// the best supported match of an Accept-Language header.
+func (v *Validator) Localized(acceptLanguage string) *Validator {
+	locale := v.messages.match(acceptLanguage)
+	if locale == v.locale {
+		return v
+	}
+
 	return &Validator{
-		validator: validator.New(),
+		validator: v.validator,
+		messages:  v.messages,
+		locale:    locale,
 	}
 }
 
+func (v *Validator) Locale() string {
+	return v.locale
+}
+
 func (v *Validator) Validate(s any) error {
 	err := v.validator.Struct(s)
 
@@ -28,9 +113,23 @@ func (v *Validator) Validate(s any) error {
 		return nil
 	}
 
-	errs := v.unwrapValidationErr(err)
+	valErrs, ok := errors.AsType[validator.ValidationErrors](err)
+	if !ok {
+		return err
+	}
+
+	errs := make(Errors, 0, len(valErrs))
 
-	return errors.New(strings.Join(errs, ", "))
+	for _, vErr := range valErrs {
+		errs = append(errs, FieldError{
+			Field:   fieldPath(vErr.Namespace()),
+			Rule:    vErr.Tag(),
+			Param:   vErr.Param(),
+			Message: v.messages.render(v.locale, vErr),
+		})
+	}
+
+	return errs
 }
 
 func WithValidator(ctx context.Context, val *Validator) context.Context {
@@ -46,21 +145,13 @@ func ValidatorFromContext(ctx context.Context) *Validator {
 	return val
 }
 
-func (v *Validator) unwrapValidationErr(err error) []string {
-	var valErr *validator.ValidationErrors
-	if !errors.As(err, &valErr) {
-		return []string{err.Error()}
-	}
-
-	errs := *valErr
-
-	reasonsWrapper := make([]string, 0, len(errs))
-
-	for _, vErr := range errs {
-		reason := fmt.Sprintf("field `%s` does not satisfy %s rule", vErr.Field(), vErr.Tag())
-
-		reasonsWrapper = append(reasonsWrapper, reason)
+// fieldPath drops the struct name go-playground puts in front of every
+// namespace.
+func fieldPath(namespace string) string {
+	_, path, ok := strings.Cut(namespace, ".")
+	if !ok {
+		return namespace
 	}
 
-	return reasonsWrapper
+	return path
 }
@@ -0,0 +1,182 @@
+package validator_test
+
+import (
+	"HATCH_APP/pkg/core"
+	"HATCH_APP/pkg/validator"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatorULID(t *testing.T) {
+	type request struct {
+		ID    core.ID `validate:"required"`
+		RawID string  `validate:"omitempty,ulid"`
+	}
+
+	v := validator.New()
+
+	assert.NoError(t, v.Validate(request{ID: core.NewID(), RawID: core.NewID().String()}))
+	assert.NoError(t, v.Validate(request{ID: core.NewID()}))
+	assert.Error(t, v.Validate(request{}), "zero ids are not present")
+	assert.Error(t, v.Validate(request{ID: core.NewID(), RawID: "not-an-id"}))
+}
+
+func TestValidatorMessages(t *testing.T) {
+	type request struct {
+		Title  string   `json:"title"  validate:"required,notblank,max=5"`
+		Tags   []string `json:"tags"   validate:"max=2"`
+		Status string   `json:"status" validate:"omitempty,oneof=active archived"`
+		Limit  int      `json:"limit"  validate:"gte=0,lte=100"`
+	}
+
+	tests := []struct {
+		name   string
+		locale string
+		req    request
+		want   []validator.FieldError
+	}{
+		{
+			name: "should name fields by json tag and fill params",
+			req:  request{Title: "too long", Tags: []string{"a", "b", "c"}, Status: "gone", Limit: 101},
+			want: []validator.FieldError{
+				{Field: "title", Rule: "max", Param: "5", Message: "title must be at most 5 characters"},
+				{Field: "tags", Rule: "max", Param: "2", Message: "tags must contain at most 2 items"},
+				{Field: "status", Rule: "oneof", Param: "active archived", Message: "status must be one of active, archived"},
+				{Field: "limit", Rule: "lte", Param: "100", Message: "limit must be at most 100"},
+			},
+		},
+		{
+			name: "should reject blank strings",
+			req:  request{Title: "  \t"},
+			want: []validator.FieldError{
+				{Field: "title", Rule: "notblank", Message: "title must not be blank"},
+			},
+		},
+		{
+			name:   "should translate to the requested locale",
+			locale: "pt-BR,pt;q=0.9,en;q=0.8",
+			req:    request{},
+			want: []validator.FieldError{
+				{Field: "title", Rule: "required", Message: "title é obrigatório"},
+			},
+		},
+		{
+			name:   "should fall back to the base language",
+			locale: "es-AR",
+			req:    request{Limit: -1, Title: "ok"},
+			want: []validator.FieldError{
+				{Field: "limit", Rule: "gte", Param: "0", Message: "limit debe ser al menos 0"},
+			},
+		},
+		{
+			name:   "should use english for unsupported locales",
+			locale: "de-DE, fr;q=0.5",
+			req:    request{},
+			want: []validator.FieldError{
+				{Field: "title", Rule: "required", Message: "title is required"},
+			},
+		},
+	}
+
+	v := validator.New()
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			err := v.Localized(tc.locale).Validate(tc.req)
+
+			errs, ok := errors.AsType[validator.Errors](err)
+			require.True(t, ok, "got %v", err)
+			assert.Equal(t, validator.Errors(tc.want), errs)
+		})
+	}
+}
+
+func TestValidatorLocalized(t *testing.T) {
+	v := validator.New()
+
+	assert.Equal(t, validator.DefaultLocale, v.Locale())
+	assert.Equal(t, "pt-BR", v.Localized("pt").Locale())
+	assert.Equal(t, "es", v.Localized("en;q=0.1, es;q=0.9").Locale())
+	assert.Equal(t, "en", v.Localized("es;q=0, *").Locale())
+	assert.Same(t, v, v.Localized(""))
+}
+
+func TestValidatorRegisterRule(t *testing.T) {
+	type request struct {
+		Slug string `json:"slug" validate:"slug"`
+	}
+
+	v := validator.New()
+
+	require.NoError(t, v.RegisterRule("slug", func(fl validator.FieldLevel) bool {
+		return !strings.ContainsAny(fl.Field().String(), " /")
+	}, map[string]string{
+		"en": "{field} must be a slug",
+	}))
+
+	require.NoError(t, v.Validate(request{Slug: "hello-world"}))
+
+	err := v.Localized("pt-BR").Validate(request{Slug: "hello world"})
+	require.Error(t, err)
+	assert.Equal(t, "slug must be a slug", err.Error(), "missing locales fall back to english")
+}
+
+func TestValidatorRegisterStructRule(t *testing.T) {
+	type window struct {
+		From int `json:"from"`
+		To   int `json:"to"`
+	}
+
+	v := validator.New()
+
+	v.RegisterMessages("after_from", map[string]string{
+		"en": "{field} must be after {param}",
+	})
+	v.RegisterStructRule(func(sl validator.StructLevel) {
+		w, _ := sl.Current().Interface().(window)
+
+		if w.To < w.From {
+			sl.ReportError(w.To, "to", "To", "after_from", "from")
+		}
+	}, window{})
+
+	require.NoError(t, v.Validate(window{From: 1, To: 2}))
+
+	err := v.Validate(window{From: 2, To: 1})
+
+	errs, ok := errors.AsType[validator.Errors](err)
+	require.True(t, ok)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "to", errs[0].Field)
+	assert.Equal(t, "to must be after from", errs[0].Message)
+}
+
+func TestIsSafeMarkdown(t *testing.T) {
+	tests := []struct {
+		input string
+		safe  bool
+	}{
+		{input: "# Title\n\nSome *text* and a [link](https://example.com).", safe: true},
+		{input: "inline `<script>` in code is still flagged", safe: false},
+		{input: "<script>alert(1)</script>", safe: false},
+		{input: "<IFRAME src=x>", safe: false},
+		{input: `<img src="x" onerror="alert(1)">`, safe: false},
+		{input: "[click](javascript:alert(1))", safe: false},
+		{input: "<javascript:alert(1)>", safe: false},
+		{input: "![img](data:text/html;base64,xxx)", safe: false},
+		{input: "the word javascript: on its own is fine", safe: true},
+		{input: "a < b and c > d", safe: true},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.input, func(t *testing.T) {
+			assert.Equal(t, tc.safe, validator.IsSafeMarkdown(tc.input))
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package apperrtest
+
+import (
+	"HATCH_APP/pkg/core/apperr"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var codeFormat = regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)+$`)
+
+func AssertRegistries(t *testing.T, registries ...*apperr.Registry) {
+	t.Helper()
+
+	if len(registries) == 0 {
+		registries = apperr.Registries()
+	}
+
+	seen := make(map[string]string)
+
+	for _, r := range registries {
+		for _, c := range r.Codes() {
+			if owner, ok := seen[c.ID]; ok {
+				assert.Failf(t, "duplicated error code",
+					"code %s registered by %s and %s", c.ID, owner, r.Module())
+			}
+
+			seen[c.ID] = r.Module()
+
+			assert.Regexp(t, codeFormat, c.ID, "code %s is not UPPER_SNAKE_CASE", c.ID)
+			assert.True(t, strings.HasPrefix(c.ID, r.Module()+"_"),
+				"code %s must be prefixed by module %s", c.ID, r.Module())
+			assert.NotEmpty(t, c.Type, "code %s has no type", c.ID)
+			assert.NotEmpty(t, strings.TrimSpace(c.Message), "code %s has no default message", c.ID)
+			assert.NotEmpty(t, strings.TrimSpace(c.Doc), "code %s is not documented", c.ID)
+		}
+	}
+}
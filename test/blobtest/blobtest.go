@@ -0,0 +1,111 @@
+package blobtest
+
+import (
+	"HATCH_APP/pkg/blob"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Run checks the behavior every blob.Store implementation must provide.
+func Run(t *testing.T, newStore func(t *testing.T) blob.Store) {
+	t.Helper()
+
+	read := func(t *testing.T, s blob.Store, key string) (string, blob.Object) {
+		t.Helper()
+
+		r, obj, err := s.Get(t.Context(), key)
+		require.NoError(t, err)
+
+		defer func() {
+			_ = r.Close()
+		}()
+
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+
+		return string(content), obj
+	}
+
+	t.Run("should get what was put", func(t *testing.T) {
+		s := newStore(t)
+
+		require.NoError(t, s.Put(t.Context(), "notes/1/a b.txt", strings.NewReader("hello"), 5, "text/plain"))
+
+		content, obj := read(t, s, "notes/1/a b.txt")
+
+		assert.Equal(t, "hello", content)
+		assert.Equal(t, blob.Object{Key: "notes/1/a b.txt", Size: 5}, obj)
+	})
+
+	t.Run("should replace existing objects", func(t *testing.T) {
+		s := newStore(t)
+
+		require.NoError(t, s.Put(t.Context(), "key", strings.NewReader("first"), 5, ""))
+		require.NoError(t, s.Put(t.Context(), "key", strings.NewReader("second"), 6, ""))
+
+		content, _ := read(t, s, "key")
+
+		assert.Equal(t, "second", content)
+	})
+
+	t.Run("should store empty objects", func(t *testing.T) {
+		s := newStore(t)
+
+		require.NoError(t, s.Put(t.Context(), "empty", bytes.NewReader(nil), 0, ""))
+
+		content, obj := read(t, s, "empty")
+
+		assert.Empty(t, content)
+		assert.Zero(t, obj.Size)
+	})
+
+	t.Run("should return not found for absent keys", func(t *testing.T) {
+		s := newStore(t)
+
+		_, _, err := s.Get(t.Context(), "absent")
+
+		require.ErrorIs(t, err, blob.ErrNotFound)
+	})
+
+	t.Run("should delete objects and ignore absent ones", func(t *testing.T) {
+		s := newStore(t)
+
+		require.NoError(t, s.Put(t.Context(), "key", strings.NewReader("value"), 5, ""))
+		require.NoError(t, s.Delete(t.Context(), "key"))
+		require.NoError(t, s.Delete(t.Context(), "key"))
+
+		_, _, err := s.Get(t.Context(), "key")
+
+		require.ErrorIs(t, err, blob.ErrNotFound)
+	})
+
+	t.Run("should reject readers of another size", func(t *testing.T) {
+		s := newStore(t)
+
+		err := s.Put(t.Context(), "short", strings.NewReader("abc"), 5, "")
+		require.ErrorIs(t, err, blob.ErrSizeMismatch)
+
+		err = s.Put(t.Context(), "long", strings.NewReader("abcdef"), 5, "")
+		require.ErrorIs(t, err, blob.ErrSizeMismatch)
+
+		_, _, err = s.Get(t.Context(), "short")
+		require.ErrorIs(t, err, blob.ErrNotFound)
+
+		_, _, err = s.Get(t.Context(), "long")
+		require.ErrorIs(t, err, blob.ErrNotFound)
+	})
+
+	t.Run("should reject keys escaping the store", func(t *testing.T) {
+		s := newStore(t)
+
+		for _, key := range []string{"", "/abs", "../up", "a/../b", "a//b", `a\b`} {
+			err := s.Put(t.Context(), key, strings.NewReader("x"), 1, "")
+			require.ErrorIs(t, err, blob.ErrInvalidKey, key)
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package cachetest
+
+import (
+	"HATCH_APP/pkg/cache"
+	"HATCH_APP/pkg/o11y"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Run checks the behavior every cache.Cache implementation must provide.
+// advance moves the implementation clock forward to expire keys.
+func Run(t *testing.T, newCache func(t *testing.T) cache.Cache, advance func(d time.Duration)) {
+	t.Helper()
+
+	o11y.InitLogger()
+
+	t.Run("should return miss for absent keys", func(t *testing.T) {
+		c := newCache(t)
+
+		_, err := c.Get(t.Context(), "absent")
+
+		require.ErrorIs(t, err, cache.ErrMiss)
+	})
+
+	t.Run("should get what was set", func(t *testing.T) {
+		c := newCache(t)
+
+		require.NoError(t, c.Set(t.Context(), "key", []byte("value"), time.Minute))
+
+		v, err := c.Get(t.Context(), "key")
+
+		require.NoError(t, err)
+		assert.Equal(t, []byte("value"), v)
+	})
+
+	t.Run("should delete keys", func(t *testing.T) {
+		c := newCache(t)
+
+		require.NoError(t, c.Set(t.Context(), "a", []byte("1"), time.Minute))
+		require.NoError(t, c.Set(t.Context(), "b", []byte("2"), time.Minute))
+
+		require.NoError(t, c.Delete(t.Context(), "a", "b", "absent"))
+
+		_, err := c.Get(t.Context(), "a")
+		require.ErrorIs(t, err, cache.ErrMiss)
+
+		_, err = c.Get(t.Context(), "b")
+		require.ErrorIs(t, err, cache.ErrMiss)
+	})
+
+	t.Run("should expire keys after ttl", func(t *testing.T) {
+		c := newCache(t)
+
+		require.NoError(t, c.Set(t.Context(), "key", []byte("value"), 50*time.Millisecond))
+
+		advance(100 * time.Millisecond)
+
+		_, err := c.Get(t.Context(), "key")
+		require.ErrorIs(t, err, cache.ErrMiss)
+	})
+
+	t.Run("should load once for concurrent misses", func(t *testing.T) {
+		c := newCache(t)
+
+		var loads atomic.Int32
+
+		load := func(context.Context) ([]byte, error) {
+			loads.Add(1)
+			time.Sleep(50 * time.Millisecond)
+
+			return []byte("loaded"), nil
+		}
+
+		var wg sync.WaitGroup
+
+		for range 10 {
+			wg.Go(func() {
+				v, err := c.GetOrLoad(t.Context(), "key", time.Minute, load)
+				assert.NoError(t, err)
+				assert.Equal(t, []byte("loaded"), v)
+			})
+		}
+
+		wg.Wait()
+
+		assert.Equal(t, int32(1), loads.Load())
+
+		v, err := c.Get(t.Context(), "key")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("loaded"), v)
+	})
+
+	t.Run("should keep loading for waiters when the first caller is cancelled", func(t *testing.T) {
+		c := newCache(t)
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		var loadErr atomic.Value
+
+		load := func(ctx context.Context) ([]byte, error) {
+			close(started)
+			<-release
+
+			if err := ctx.Err(); err != nil {
+				loadErr.Store(err)
+
+				return nil, err
+			}
+
+			return []byte("loaded"), nil
+		}
+
+		ctx, cancel := context.WithCancel(t.Context())
+
+		var wg sync.WaitGroup
+
+		wg.Go(func() {
+			_, err := c.GetOrLoad(ctx, "key", time.Minute, load)
+			assert.ErrorIs(t, err, context.Canceled)
+		})
+
+		<-started
+
+		wg.Go(func() {
+			v, err := c.GetOrLoad(t.Context(), "key", time.Minute, load)
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("loaded"), v)
+		})
+
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+
+		wg.Wait()
+
+		assert.Nil(t, loadErr.Load())
+	})
+
+	t.Run("should not cache load errors", func(t *testing.T) {
+		c := newCache(t)
+
+		loadErr := errors.New("source down")
+
+		_, err := c.GetOrLoad(t.Context(), "key", time.Minute, func(context.Context) ([]byte, error) {
+			return nil, loadErr
+		})
+		require.ErrorIs(t, err, loadErr)
+
+		_, err = c.Get(t.Context(), "key")
+		require.ErrorIs(t, err, cache.ErrMiss)
+	})
+}
@@ -16,6 +16,14 @@ import (
 )
 
 func SetupPostgres(t *testing.T) (*sqlx.DB, func()) {
+	db, _, teardown := SetupPostgresWithURL(t)
+
+	return db, teardown
+}
+
+// SetupPostgresWithURL also returns the connection string, for code opening
+// connections of its own.
+func SetupPostgresWithURL(t *testing.T) (*sqlx.DB, string, func()) {
 	ctx := context.Background()
 
 	container, err := pg.Run(ctx,
@@ -62,7 +70,7 @@ func SetupPostgres(t *testing.T) (*sqlx.DB, func()) {
 		}
 	}
 
-	return db, teardown
+	return db, connStr, teardown
 }
 
 func runMigrations(t *testing.T, db *sqlx.DB) {
@@ -1,10 +1,14 @@
 package httptest
 
 import (
+	"HATCH_APP/pkg/core/apperr"
 	"HATCH_APP/pkg/o11y"
+	"HATCH_APP/pkg/transport/httpx"
+	"HATCH_APP/pkg/transport/httpx/openapi"
 	"HATCH_APP/pkg/validator"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -49,6 +53,24 @@ func Run(t *testing.T, handler http.HandlerFunc, tc Case) {
 	}
 }
 
+// Contract validates requests and responses against spec and fails the test
+// when a handler answers outside of it.
+func Contract(t *testing.T, spec *openapi.Spec) func(http.Handler) http.Handler {
+	t.Helper()
+
+	return httpx.WithOpenAPIValidation(spec, httpx.OpenAPIValidation{
+		Responses: true,
+		OnResponseError: func(r *http.Request, err error) {
+			var details any
+			if appErr, ok := errors.AsType[*apperr.Error](err); ok {
+				details = appErr.Details
+			}
+
+			t.Errorf("%s %s: %v: %+v", r.Method, r.URL.Path, err, details)
+		},
+	})
+}
+
 func Init() {
 	o11y.InitLogger()
 }
@@ -0,0 +1,152 @@
+package locktest
+
+import (
+	"HATCH_APP/pkg/lock"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Run checks the behavior every lock.Locker implementation must provide.
+func Run(t *testing.T, newLocker func(t *testing.T) lock.Locker) {
+	t.Helper()
+
+	t.Run("should not acquire a held lock", func(t *testing.T) {
+		l := newLocker(t)
+
+		_, err := l.TryAcquire(t.Context(), "held", 0)
+		require.NoError(t, err)
+
+		_, err = l.TryAcquire(t.Context(), "held", 0)
+		require.ErrorIs(t, err, lock.ErrNotAcquired)
+
+		_, err = l.TryAcquire(t.Context(), "other", 0)
+		require.NoError(t, err)
+
+		require.NoError(t, l.Release(t.Context(), "held"))
+		require.NoError(t, l.Release(t.Context(), "other"))
+	})
+
+	t.Run("should acquire again after release", func(t *testing.T) {
+		l := newLocker(t)
+
+		lockCtx, err := l.TryAcquire(t.Context(), "released", 0)
+		require.NoError(t, err)
+
+		require.NoError(t, l.Release(t.Context(), "released"))
+		require.ErrorIs(t, lockCtx.Err(), context.Canceled)
+
+		_, err = l.TryAcquire(t.Context(), "released", 0)
+		require.NoError(t, err)
+
+		require.NoError(t, l.Release(t.Context(), "released"))
+	})
+
+	t.Run("should return not held when releasing a free lock", func(t *testing.T) {
+		l := newLocker(t)
+
+		require.ErrorIs(t, l.Release(t.Context(), "free"), lock.ErrNotHeld)
+	})
+
+	t.Run("should wait for the lock to be released", func(t *testing.T) {
+		l := newLocker(t)
+
+		_, err := l.Acquire(t.Context(), "wait", 0)
+		require.NoError(t, err)
+
+		acquired := make(chan error, 1)
+
+		go func() {
+			_, err := l.Acquire(t.Context(), "wait", 0)
+			acquired <- err
+		}()
+
+		select {
+		case <-acquired:
+			t.Fatal("lock acquired while held")
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		require.NoError(t, l.Release(t.Context(), "wait"))
+
+		select {
+		case err := <-acquired:
+			require.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("lock was not acquired after release")
+		}
+
+		require.NoError(t, l.Release(t.Context(), "wait"))
+	})
+
+	t.Run("should stop waiting when the context is done", func(t *testing.T) {
+		l := newLocker(t)
+
+		_, err := l.Acquire(t.Context(), "canceled", 0)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err = l.Acquire(ctx, "canceled", 0)
+		require.Error(t, err)
+
+		require.NoError(t, l.Release(t.Context(), "canceled"))
+	})
+
+	t.Run("should release the lock when the ttl expires", func(t *testing.T) {
+		l := newLocker(t)
+
+		lockCtx, err := l.TryAcquire(t.Context(), "ttl", 50*time.Millisecond)
+		require.NoError(t, err)
+
+		<-lockCtx.Done()
+
+		assert.Eventually(t, func() bool {
+			_, err := l.TryAcquire(t.Context(), "ttl", 0)
+			return err == nil
+		}, 5*time.Second, 10*time.Millisecond)
+
+		require.NoError(t, l.Release(t.Context(), "ttl"))
+	})
+
+	t.Run("should release the lock when the parent context is done", func(t *testing.T) {
+		l := newLocker(t)
+
+		ctx, cancel := context.WithCancel(t.Context())
+
+		_, err := l.TryAcquire(ctx, "parent", 0)
+		require.NoError(t, err)
+
+		cancel()
+
+		assert.Eventually(t, func() bool {
+			_, err := l.TryAcquire(t.Context(), "parent", 0)
+			return err == nil
+		}, 5*time.Second, 10*time.Millisecond)
+
+		require.NoError(t, l.Release(t.Context(), "parent"))
+	})
+
+	t.Run("should run fn while holding the lock", func(t *testing.T) {
+		l := newLocker(t)
+
+		err := lock.With(t.Context(), l, "with", 0, func(ctx context.Context) error {
+			_, err := l.TryAcquire(ctx, "with", 0)
+			assert.ErrorIs(t, err, lock.ErrNotAcquired)
+
+			return lock.TryWith(ctx, l, "with", 0, func(context.Context) error {
+				return nil
+			})
+		})
+		require.ErrorIs(t, err, lock.ErrNotAcquired)
+
+		_, err = l.TryAcquire(t.Context(), "with", 0)
+		require.NoError(t, err)
+
+		require.NoError(t, l.Release(t.Context(), "with"))
+	})
+}